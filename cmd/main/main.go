@@ -1,12 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/avian-digital-forensics/timeline-investigator/cmd"
@@ -26,6 +28,9 @@ func run(ctx context.Context, args []string, stdout io.Writer) error {
 	// Get cli arguments
 	flags := flag.NewFlagSet(args[0], flag.ExitOnError)
 	cfgPath := flags.String("cfg", "/configs/config.yml", "filepath for the config")
+	migrate := flags.Bool("migrate", false, "reindex the indices from older index-templates and exit")
+	createUser := flags.String("create-user", "", "create a user with the email and the password from stdin, and exit")
+	name := flags.String("name", "", "name of the user to create")
 	if err := flags.Parse(args[1:]); err != nil {
 		return err
 	}
@@ -35,6 +40,29 @@ func run(ctx context.Context, args []string, stdout io.Writer) error {
 		return err
 	}
 
+	// The indices are migrated while the API isn't running
+	if *migrate {
+		if err := server.Migrate(ctx, cfg.MainAPI); err != nil {
+			return fmt.Errorf("unable to migrate the indices: %v", err)
+		}
+		fmt.Fprintln(stdout, "the indices are migrated")
+		return nil
+	}
+
+	// The first users are created before the admins can create them
+	if *createUser != "" {
+		password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("unable to read the password: %v", err)
+		}
+		id, err := server.CreateUser(ctx, cfg.MainAPI, *createUser, *name, strings.TrimRight(password, "\r\n"))
+		if err != nil {
+			return fmt.Errorf("unable to create the user: %v", err)
+		}
+		fmt.Fprintf(stdout, "the user is created with the id %s\n", id)
+		return nil
+	}
+
 	// init the api-server
 	apiServer := server.New(ctx)
 
@@ -2,10 +2,14 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/avian-digital-forensics/timeline-investigator/configs"
@@ -14,6 +18,7 @@ import (
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/fscrawler"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
 
 	"github.com/pacedotdev/oto/otohttp"
@@ -34,35 +39,127 @@ func New(ctx context.Context) *Server {
 	}
 }
 
+// Migrate puts the index-templates and reindexes the indices
+// that were created with an older version of the templates,
+// it's made while the server isn't running
+func Migrate(ctx context.Context, cfg *configs.MainAPI) error {
+	db, err := datastore.NewService(cfg.DB.URLs...)
+	if err != nil {
+		return err
+	}
+	if err := db.PutIndexTemplates(ctx); err != nil {
+		return err
+	}
+	return db.MigrateIndices(ctx)
+}
+
+// CreateUser creates a user with the local authentication-provider and
+// returns the id of it, it's used to create the first admin of the system
+func CreateUser(ctx context.Context, cfg *configs.MainAPI, email, name, password string) (string, error) {
+	db, err := datastore.NewService(cfg.DB.URLs...)
+	if err != nil {
+		return "", err
+	}
+	if err := db.PutIndexTemplates(ctx); err != nil {
+		return "", err
+	}
+
+	auth, err := newAuthentication(ctx, cfg.Authentication, db)
+	if err != nil {
+		return "", err
+	}
+	manager, ok := auth.(authentication.UserManager)
+	if !ok {
+		return "", errors.New("the users are managed by the authentication-provider")
+	}
+
+	user, err := manager.CreateUser(ctx, email, name, password)
+	if err != nil {
+		return "", err
+	}
+	return user.UID, nil
+}
+
 // Initialize the server
 func (srv *Server) Initialize(cfg *configs.MainAPI) error {
-	auth, err := authentication.New(srv.ctx, cfg.Authentication.CredentialsFile, cfg.Authentication.APIKey)
+	db, err := datastore.NewService(cfg.DB.URLs...)
 	if err != nil {
 		return err
 	}
+	if err := db.PutIndexTemplates(srv.ctx); err != nil {
+		return err
+	}
+	if err := db.MigrateCaseFiles(srv.ctx); err != nil {
+		return err
+	}
 
-	db, err := datastore.NewService(cfg.DB.URLs...)
+	auth, err := newAuthentication(srv.ctx, cfg.Authentication, db)
 	if err != nil {
 		return err
 	}
 
-	filestore, err := filestore.New(cfg.Filestore.BasePath)
+	filestore, err := newFilestore(cfg.Filestore, db)
 	if err != nil {
 		return err
 	}
 
-	// Connect to fscrawler for indexing files
-	fs := fscrawler.New(cfg.Indexing.FSCrawlerURL)
-	if ok, err := fs.Ping(srv.ctx); !ok {
-		if err != nil {
-			return fmt.Errorf("no pong from fscrawler: %v", err)
-		}
-		return errors.New("fscrawler: not healthy")
+	indexer, err := newIndexer(srv.ctx, cfg.Indexing, db)
+	if err != nil {
+		return err
 	}
 
-	// Set the base-path for the oto-server
+	caseService := services.NewCaseService(db, auth, cfg.Admins...)
+	fileService := services.NewFileService(db, filestore, caseService, indexer)
+
+	// Large files are uploaded with resumable
+	// uploads, next to the oto-router
+	stagingPath := cfg.Filestore.StagingPath
+	if stagingPath == "" && cfg.Filestore.BasePath != "" {
+		stagingPath = filepath.Join(cfg.Filestore.BasePath, ".uploads")
+	} else if stagingPath == "" {
+		// The object storage has no base-path
+		stagingPath = filepath.Join(os.TempDir(), "ti-uploads")
+	}
+	uploadHandler, err := services.NewUploadHandler(db, fileService, caseService, stagingPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Filestore.MaxUploadSize > 0 {
+		uploadHandler.MaxSize = cfg.Filestore.MaxUploadSize << 20
+	}
+	if cfg.Filestore.UploadExpiry > 0 {
+		uploadHandler.Expiry = time.Duration(cfg.Filestore.UploadExpiry) * time.Hour
+	}
+	if cfg.Network.ReadTimeout > 0 {
+		uploadHandler.ReadTimeout = time.Duration(cfg.Network.ReadTimeout) * time.Second
+	}
+	http.Handle(uploadHandler.Basepath, uploadHandler)
+
+	// Abandoned uploads are removed in the background
+	go uploadHandler.Run(srv.ctx, time.Hour)
+
+	// Files are streamed with range-requests
+	downloadHandler := services.NewDownloadHandler(db, filestore, caseService)
+	if cfg.Network.WriteTimeout > 0 {
+		downloadHandler.WriteTimeout = time.Duration(cfg.Network.WriteTimeout) * time.Second
+	}
+	http.Handle(downloadHandler.Basepath, downloadHandler)
+
+	// Files are processed in the background
+	// by the workers for the processing-jobs
+	workers := cfg.Indexing.Workers
+	if workers == 0 {
+		workers = 2
+	}
+	linkService := services.NewLinkService(db, caseService)
+	suggestionService := services.NewSuggestionService(db, caseService, linkService)
+	processService := services.NewProcessService(db, fileService, caseService, suggestionService, workers)
+	go processService.Run(srv.ctx)
+
+	// Set the base-path for the oto-server, and
+	// authorize the requests before they reach it
 	srv.router.Basepath = "/api/"
-	http.Handle("/api/", srv.router)
+	http.Handle("/api/", services.NewAuthorizer(srv.router, caseService))
 
 	// endpoint for for gke-healthchecks
 	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -71,14 +168,18 @@ func (srv *Server) Initialize(cfg *configs.MainAPI) error {
 	})
 
 	// Register the services
-	caseService := services.NewCaseService(db, auth)
 	api.RegisterCaseService(srv.router, caseService)
 	api.RegisterEventService(srv.router, services.NewEventService(db, caseService))
-	api.RegisterLinkService(srv.router, services.NewLinkService(db, caseService))
-	api.RegisterFileService(srv.router, services.NewFileService(db, filestore, caseService, fs))
+	api.RegisterLinkService(srv.router, linkService)
+	api.RegisterFileService(srv.router, fileService)
+	api.RegisterProcessService(srv.router, processService)
+	api.RegisterSuggestionService(srv.router, suggestionService)
 	api.RegisterEntityService(srv.router, services.NewEntityService(db, caseService))
 	api.RegisterPersonService(srv.router, services.NewPersonService(db, caseService))
 	api.RegisterSearchService(srv.router, services.NewSearchService(db, caseService))
+	api.RegisterTokenService(srv.router, services.NewTokenService(db, caseService))
+	api.RegisterAdminService(srv.router, services.NewAdminService(db, auth, filestore, caseService))
+	api.RegisterAuthService(srv.router, services.NewAuthService(auth))
 
 	// Only create the TestService if it is a test-run
 	if cfg.Test.Run {
@@ -88,6 +189,100 @@ func (srv *Server) Initialize(cfg *configs.MainAPI) error {
 	return nil
 }
 
+// newAuthentication creates the authentication-service
+// for the provider specified in the config
+func newAuthentication(ctx context.Context, cfg *configs.AuthConfig, db datastore.Service) (authentication.Service, error) {
+	switch cfg.Provider {
+	case "", authentication.ProviderFirebase:
+		return authentication.New(ctx, cfg.CredentialsFile, cfg.APIKey)
+	case authentication.ProviderOIDC:
+		if cfg.OIDC == nil || cfg.OIDC.IssuerURL == "" {
+			return nil, errors.New("authentication: oidc.issuer_url is required")
+		}
+		return authentication.NewOIDC(ctx, cfg.OIDC.IssuerURL, cfg.OIDC.ClientID)
+	case authentication.ProviderLocal:
+		if cfg.Local == nil {
+			return nil, errors.New("authentication: local.signing_key is required")
+		}
+		ttl := time.Duration(cfg.Local.TokenTTL) * time.Minute
+		return authentication.NewLocal(db, cfg.Local.SigningKey, ttl)
+	}
+	return nil, fmt.Errorf("authentication: unknown provider %q", cfg.Provider)
+}
+
+// newFilestore creates the filestore for the backend specified
+// in the config, with encryption if master-keys are specified
+func newFilestore(cfg *configs.FilestoreConfig, db datastore.Service) (filestore.Service, error) {
+	var store filestore.Service
+	var err error
+	switch cfg.Backend {
+	case "", "local":
+		store, err = filestore.New(cfg.BasePath)
+	case "s3":
+		if cfg.S3 == nil {
+			return nil, errors.New("filestore: s3.bucket is required")
+		}
+		store, err = filestore.NewS3(filestore.S3Options{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			PathStyle:       cfg.S3.PathStyle,
+			Timeout:         time.Duration(cfg.S3.Timeout) * time.Second,
+		})
+	default:
+		return nil, fmt.Errorf("filestore: unknown backend %q", cfg.Backend)
+	}
+	if err != nil || cfg.Encryption == nil || len(cfg.Encryption.MasterKeys) == 0 {
+		return store, err
+	}
+
+	var masterKeys []filestore.MasterKey
+	for _, masterKey := range cfg.Encryption.MasterKeys {
+		parts := strings.SplitN(masterKey, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.New("filestore: master-keys must be formatted as id:base64-key")
+		}
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("filestore: invalid master-key %q: %v", parts[0], err)
+		}
+		masterKeys = append(masterKeys, filestore.MasterKey{ID: parts[0], Key: key})
+	}
+	return filestore.NewEncrypted(store, db, filestore.EncryptedOptions{
+		MasterKeys:       masterKeys,
+		AllowUnencrypted: cfg.Encryption.AllowUnencrypted,
+	})
+}
+
+// newIndexer creates the indexer for the processed
+// files, either fscrawler (default) or native
+func newIndexer(ctx context.Context, cfg *configs.IndexingConfig, db datastore.Service) (indexer.Indexer, error) {
+	switch cfg.Indexer {
+	case "", "fscrawler":
+		// Connect to fscrawler for indexing files
+		fs := fscrawler.New(cfg.FSCrawlerURL)
+		if ok, err := fs.Ping(ctx); !ok {
+			if err != nil {
+				return nil, fmt.Errorf("no pong from fscrawler: %v", err)
+			}
+			return nil, errors.New("fscrawler: not healthy")
+		}
+		return indexer.NewFSCrawler(fs), nil
+	case "native":
+		native := indexer.NewNative(db)
+		if cfg.MaxExpandedSize > 0 {
+			native.MaxExpandedSize = cfg.MaxExpandedSize << 20
+		}
+		if cfg.MaxEntries > 0 {
+			native.MaxEntries = cfg.MaxEntries
+		}
+		return native, nil
+	}
+	return nil, fmt.Errorf("indexing: unknown indexer %q", cfg.Indexer)
+}
+
 // Run the server
 func (srv *Server) Run(cfg *configs.MainAPI) error {
 	// Create the http-server
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"html/template"
 	"io"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -241,7 +240,7 @@ func run(ctx context.Context, args []string, stdout io.Writer) error {
 			bodyReader = decodedBody
 		}
 
-		respBodyBytes, err := ioutil.ReadAll(bodyReader)
+		respBodyBytes, err := io.ReadAll(bodyReader)
 		if err != nil {
 			savedPage.Error = fmt.Sprintf("Failed to read response body: %s", err.Error())
 			tmpl.Execute(w, savedPage)
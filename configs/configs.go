@@ -21,6 +21,10 @@ type MainAPI struct {
 	Authentication *AuthConfig      `yaml:"authentication"`
 	Filestore      *FilestoreConfig `yaml:"filestore"`
 	Indexing       *IndexingConfig  `yaml:"indexing"`
+
+	// Admins are the emails of the system-administrators,
+	// users can also be admins with an "admin"-claim
+	Admins []string `yaml:"admins" envconfig:"ADMINS"`
 }
 
 // NetworkConfig has the http-configuration
@@ -38,11 +42,28 @@ type DBConfig struct {
 	URLs []string `yaml:"urls"`
 }
 
-// AuthConfig holds information for accessing
-// the firebase authentication-service
+// AuthConfig holds information for the authentication-service,
+// the provider is either firebase (default), oidc or local
 type AuthConfig struct {
-	CredentialsFile string `yaml:"credentials_file" envconfig:"AUTH_CREDENTIALS_FILE"`
-	APIKey          string `yaml:"api_key" envconfig:"AUTH_API_KEY"`
+	Provider        string           `yaml:"provider" envconfig:"AUTH_PROVIDER"`
+	CredentialsFile string           `yaml:"credentials_file" envconfig:"AUTH_CREDENTIALS_FILE"`
+	APIKey          string           `yaml:"api_key" envconfig:"AUTH_API_KEY"`
+	OIDC            *OIDCConfig      `yaml:"oidc"`
+	Local           *LocalAuthConfig `yaml:"local"`
+}
+
+// OIDCConfig holds information for verifying
+// tokens from an OpenID Connect-provider
+type OIDCConfig struct {
+	IssuerURL string `yaml:"issuer_url" envconfig:"AUTH_OIDC_ISSUER_URL"`
+	ClientID  string `yaml:"client_id" envconfig:"AUTH_OIDC_CLIENT_ID"`
+}
+
+// LocalAuthConfig holds information for the
+// local authentication-provider
+type LocalAuthConfig struct {
+	SigningKey string `yaml:"signing_key" envconfig:"AUTH_SIGNING_KEY"`
+	TokenTTL   int    `yaml:"token_ttl"` // minutes
 }
 
 // TestConfig holds the settings for testing the TI-API
@@ -51,14 +72,71 @@ type TestConfig struct {
 	Secret string `yaml:"secret" envconfig:"TEST_SECRET"`
 }
 
-// FilestoreConfig holds information for the filestore
+// FilestoreConfig holds information for the filestore,
+// the backend is either local (default) or s3
 type FilestoreConfig struct {
-	BasePath string `yaml:"base_path"`
+	Backend  string    `yaml:"backend" envconfig:"FILESTORE_BACKEND"`
+	BasePath string    `yaml:"base_path"`
+	S3       *S3Config `yaml:"s3"`
+
+	// Encryption encrypts the files at rest
+	// if any master-keys are specified
+	Encryption *EncryptionConfig `yaml:"encryption"`
+
+	// StagingPath is where resumable uploads are
+	// stored until they are complete, defaults
+	// to .uploads in the base-path
+	StagingPath string `yaml:"staging_path"`
+
+	// MaxUploadSize is the largest resumable upload,
+	// defaults to 100 GB
+	MaxUploadSize int64 `yaml:"max_upload_size"` // megabytes
+
+	// UploadExpiry is how long an abandoned resumable
+	// upload is kept, defaults to 24 hours
+	UploadExpiry int `yaml:"upload_expiry"` // hours
+}
+
+// S3Config holds information for
+// an S3-compatible object storage
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint" envconfig:"S3_ENDPOINT"`
+	Region          string `yaml:"region" envconfig:"S3_REGION"`
+	Bucket          string `yaml:"bucket" envconfig:"S3_BUCKET"`
+	AccessKeyID     string `yaml:"access_key_id" envconfig:"S3_ACCESS_KEY_ID"`
+	SecretAccessKey string `yaml:"secret_access_key" envconfig:"S3_SECRET_ACCESS_KEY"`
+	PathStyle       bool   `yaml:"path_style"`
+	Timeout         int    `yaml:"timeout"` // seconds
+}
+
+// EncryptionConfig holds the master-keys that wrap the
+// data-keys for the cases, the keys are "id:base64-key"
+// with 32-byte keys. The first key wraps new data-keys,
+// the rest are old keys that are still needed to unwrap
+type EncryptionConfig struct {
+	MasterKeys []string `yaml:"master_keys" envconfig:"ENCRYPTION_MASTER_KEYS"`
+
+	// AllowUnencrypted reads the files that were stored before
+	// the encryption was enabled, the files that aren't
+	// encrypted are rejected when it's off (the default)
+	AllowUnencrypted bool `yaml:"allow_unencrypted" envconfig:"ENCRYPTION_ALLOW_UNENCRYPTED"`
 }
 
-// IndexingConfig holds information for indexers
+// IndexingConfig holds information for indexers,
+// the indexer is either fscrawler (default) or native
 type IndexingConfig struct {
+	Indexer      string `yaml:"indexer" envconfig:"INDEXING_INDEXER"`
 	FSCrawlerURL string `yaml:"fscrawler_url"`
+
+	// Workers is the number of processing-jobs
+	// that are processed at the same time
+	Workers int `yaml:"workers" envconfig:"INDEXING_WORKERS"`
+
+	// MaxExpandedSize and MaxEntries limit what the native
+	// indexer expands from the containers in an uploaded file,
+	// defaults to 20 GB and 100000 files
+	MaxExpandedSize int64 `yaml:"max_expanded_size"` // megabytes
+	MaxEntries      int   `yaml:"max_entries"`
 }
 
 func readYAML(path string, cfg *Config) error {
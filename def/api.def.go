@@ -25,6 +25,17 @@ type CaseService interface {
 	// Keywords lists all the keywords for the case
 	Keywords(CaseKeywordsRequest) CaseKeywordsResponse
 
+	// InvestigatorsAdd invites investigators to the case
+	InvestigatorsAdd(CaseInvestigatorsAddRequest) CaseInvestigatorsAddResponse
+
+	// InvestigatorsRemove removes investigators from the case,
+	// the last owner of the case cannot be removed
+	InvestigatorsRemove(CaseInvestigatorsRemoveRequest) CaseInvestigatorsRemoveResponse
+
+	// RoleSet sets the role for an investigator in the case,
+	// the last owner of the case cannot be demoted
+	RoleSet(CaseRoleSetRequest) CaseRoleSetResponse
+
 	// Authenticate is a middleware
 	// in the http-handler
 	//
@@ -100,10 +111,18 @@ type FileService interface {
 	// New uploads a file to the backend
 	New(FileNewRequest) FileNewResponse
 
-	// Open opens a file
+	// List lists the files in a case, a page at a time
+	List(FileListRequest) FileListResponse
+
+	// Open opens a file (base64 encoded), files
+	// larger than 10 MB cannot be opened, they
+	// are streamed from the download-handler
 	Open(FileOpenRequest) FileOpenResponse
 
 	// Process processes a file
+	//
+	// NOTE : The file is processed in the request,
+	// large files should be processed with the ProcessService
 	Process(FileProcessRequest) FileProcessResponse
 
 	// Processed gets information for a processed file
@@ -125,6 +144,13 @@ type FileService interface {
 	// KeywordsRemove from a file
 	KeywordsRemove(KeywordsRemoveRequest) KeywordsRemoveResponse
 
+	// Custody returns the chain of custody for a file
+	Custody(FileCustodyRequest) FileCustodyResponse
+
+	// Verify verifies the stored hashes
+	// against the file in the filestore
+	Verify(FileVerifyRequest) FileVerifyResponse
+
 	// Authenticate is a middleware
 	// in the http-handler
 	//
@@ -187,6 +213,52 @@ type PersonService interface {
 	Authenticate(*http.Request) context.Context
 }
 
+// ProcessService is the API to process
+// files in the background with processing-jobs
+type ProcessService interface {
+	// Start starts a new processing-job for files in a case
+	Start(ProcessStartRequest) ProcessStartResponse
+
+	// Jobs gets the processing-jobs for a case
+	Jobs(ProcessJobsRequest) ProcessJobsResponse
+
+	// Abort aborts a processing-job,
+	// the files that aren't processed are skipped
+	Abort(ProcessAbortRequest) ProcessAbortResponse
+
+	// Pause pauses a processing-job
+	Pause(ProcessPauseRequest) ProcessPauseResponse
+
+	// Resume resumes a paused processing-job
+	Resume(ProcessResumeRequest) ProcessResumeResponse
+
+	// Authenticate is a middleware
+	// in the http-handler
+	//
+	// NOTE : Only for Go-servers
+	Authenticate(*http.Request) context.Context
+}
+
+// SuggestionService is the API to review the persons
+// and events that are suggested from the processed files
+type SuggestionService interface {
+	// List lists the suggestions for a case
+	List(SuggestionListRequest) SuggestionListResponse
+
+	// Accept accepts suggestions, the suggested
+	// persons and events are created in the case
+	Accept(SuggestionAcceptRequest) SuggestionAcceptResponse
+
+	// Reject rejects suggestions
+	Reject(SuggestionRejectRequest) SuggestionRejectResponse
+
+	// Authenticate is a middleware
+	// in the http-handler
+	//
+	// NOTE : Only for Go-servers
+	Authenticate(*http.Request) context.Context
+}
+
 // SearchService is the API to handle
 // searches in the Timeline-Investigator
 type SearchService interface {
@@ -203,6 +275,83 @@ type SearchService interface {
 	Authenticate(*http.Request) context.Context
 }
 
+// AdminService is the API for system-administrators
+// to manage every case in the system
+type AdminService interface {
+	// Cases lists every case in the system
+	Cases(AdminCasesRequest) AdminCasesResponse
+
+	// Transfer transfers the ownership of a case
+	// to another user, for example when an
+	// investigator has left the organization
+	Transfer(AdminTransferRequest) AdminTransferResponse
+
+	// Users lists the users with
+	// their memberships in the cases
+	Users(AdminUsersRequest) AdminUsersResponse
+
+	// Delete deletes a case
+	// without being an owner of it
+	Delete(AdminDeleteRequest) AdminDeleteResponse
+
+	// RotateKey rotates the data-key that encrypts
+	// the files in a case, new files are encrypted
+	// with the new key
+	RotateKey(AdminRotateKeyRequest) AdminRotateKeyResponse
+
+	// CreateUser creates a user that signs in with a
+	// password, only with the local authentication-provider
+	CreateUser(AdminCreateUserRequest) AdminCreateUserResponse
+
+	// DeleteUser deletes a user, only
+	// with the local authentication-provider
+	DeleteUser(AdminDeleteUserRequest) AdminDeleteUserResponse
+
+	// ResetPassword sets a new password for a user,
+	// only with the local authentication-provider
+	ResetPassword(AdminResetPasswordRequest) AdminResetPasswordResponse
+
+	// Authenticate is a middleware
+	// in the http-handler
+	//
+	// NOTE : Only for Go-servers
+	Authenticate(*http.Request) context.Context
+}
+
+// TokenService is the API to handle API-tokens,
+// used by scripts to access cases without a user
+type TokenService interface {
+	// Create creates a new API-token for cases owned by the user,
+	// the token is only returned once and cannot be retrieved again
+	Create(TokenCreateRequest) TokenCreateResponse
+
+	// List lists the API-tokens created by the user
+	List(TokenListRequest) TokenListResponse
+
+	// Revoke revokes an API-token
+	Revoke(TokenRevokeRequest) TokenRevokeResponse
+
+	// Authenticate is a middleware
+	// in the http-handler
+	//
+	// NOTE : Only for Go-servers
+	Authenticate(*http.Request) context.Context
+}
+
+// AuthService is the API to sign in users
+// with the local authentication-provider
+type AuthService interface {
+	// SignIn signs in the user with email
+	// and password and returns a token
+	SignIn(AuthSignInRequest) AuthSignInResponse
+
+	// Authenticate is a middleware
+	// in the http-handler
+	//
+	// NOTE : Only for Go-servers
+	Authenticate(*http.Request) context.Context
+}
+
 // TestService is used for testing-purposes
 type TestService interface {
 	// CreateUser creates a test-user in Firebase
@@ -252,13 +401,33 @@ type Case struct {
 	// example: ["sja@avian.dk", "jis@avian.dk"]
 	Investigators []string
 
-	// Files that exists in the case
+	// Roles of the investigators in the case
+	Roles []Role
+
+	// Files that exists in the case, only the
+	// first 100 uploaded files are included when
+	// the case is read (see CaseGetResponse)
 	Files []File
 
 	// Processes that exists in the case
 	Processes []Process
 }
 
+// Role is the role an investigator
+// has in a specific case
+type Role struct {
+	// Email of the investigator
+	//
+	// example: "sja@avian.dk"
+	Email string
+
+	// Name of the role, "owner", "editor",
+	// "viewer" or "auditor"
+	//
+	// example: "editor"
+	Name string
+}
+
 // CaseNewRequest is the input-object
 // for creating a new case
 type CaseNewRequest struct {
@@ -305,6 +474,19 @@ type CaseGetRequest struct {
 // for getting a specified case
 type CaseGetResponse struct {
 	Case Case
+
+	// FilesTotal is the number of files in the
+	// case, Case.Files only has the first page
+	//
+	// example: 250
+	FilesTotal int
+
+	// FilesNextCursor gets the next page of the files
+	// with FileService.List, it's empty if Case.Files
+	// has all the files in the case
+	//
+	// example: "WzE2MTA2MTY0MDAsImYxIl0"
+	FilesNextCursor string
 }
 
 // CaseUpdateRequest is the input-object
@@ -337,6 +519,13 @@ type CaseUpdateRequest struct {
 	//
 	// example: 1257894000
 	ToDate int64
+
+	// Version of the case when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // CaseUpdateResponse is the output-object
@@ -366,12 +555,29 @@ type CaseListRequest struct {
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
 	UserID string
+
+	// ListOptions for the page of cases, they
+	// can be sorted by "created", "updated" and
+	// "name", and filtered by "creatorID"
+	ListOptions ListOptions
 }
 
 // CaseListResponse is the output-object for
 // listing cases for a specified user
 type CaseListResponse struct {
 	Cases []Case
+
+	// Total number of cases
+	// that matches the filters
+	//
+	// example: 25
+	Total int
+
+	// NextCursor gets the next page,
+	// it's empty on the last page
+	//
+	// example: "WzE2MTA2MTY0MDAsImYxIl0"
+	NextCursor string
 }
 
 // CaseKeywordsRequest is the input-object
@@ -393,6 +599,84 @@ type CaseKeywordsResponse struct {
 	Keywords []string
 }
 
+// CaseInvestigatorsAddRequest is the input-object
+// for inviting investigators to a case
+type CaseInvestigatorsAddRequest struct {
+	// ID of the case to invite
+	// the investigators to
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// Emails of the investigators
+	// to invite to the case
+	//
+	// example: ["sja@avian.dk", "jis@avian.dk"]
+	Emails []string
+
+	// Role for the investigators in the case,
+	// "owner", "editor", "viewer" or "auditor"
+	// (defaults to "editor")
+	//
+	// example: "viewer"
+	Role string
+}
+
+// CaseInvestigatorsAddResponse is the output-object
+// for inviting investigators to a case
+type CaseInvestigatorsAddResponse struct {
+	Updated Case
+}
+
+// CaseInvestigatorsRemoveRequest is the input-object
+// for removing investigators from a case
+type CaseInvestigatorsRemoveRequest struct {
+	// ID of the case to remove
+	// the investigators from
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// Emails of the investigators
+	// to remove from the case
+	//
+	// example: ["jis@avian.dk"]
+	Emails []string
+}
+
+// CaseInvestigatorsRemoveResponse is the output-object
+// for removing investigators from a case
+type CaseInvestigatorsRemoveResponse struct {
+	Updated Case
+}
+
+// CaseRoleSetRequest is the input-object
+// for setting the role of an investigator
+type CaseRoleSetRequest struct {
+	// ID of the case
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// Email of the investigator
+	// to set the role for
+	//
+	// example: "jis@avian.dk"
+	Email string
+
+	// Role for the investigator in the case,
+	// "owner", "editor", "viewer" or "auditor"
+	//
+	// example: "auditor"
+	Role string
+}
+
+// CaseRoleSetResponse is the output-object
+// for setting the role of an investigator
+type CaseRoleSetResponse struct {
+	Updated Case
+}
+
 // Entity is an object that can be
 // of different types. For example,
 // organization or location
@@ -501,6 +785,13 @@ type EntityUpdateRequest struct {
 	// Custom is a free form with key-value pairs
 	// specified by the user.
 	Custom map[string]interface{}
+
+	// Version of the entity when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // EntityUpdateResponse is the output-object
@@ -555,12 +846,29 @@ type EntityListRequest struct {
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
 	CaseID string
+
+	// ListOptions for the page of entities, they
+	// can be sorted by "created", "title" and
+	// "type", and filtered by "type" and "keyword"
+	ListOptions ListOptions
 }
 
 // EntityListResponse is the output-object
 // for updating an existing entity
 type EntityListResponse struct {
 	Entities []Entity
+
+	// Total number of entities
+	// that matches the filters
+	//
+	// example: 250
+	Total int
+
+	// NextCursor gets the next page,
+	// it's empty on the last page
+	//
+	// example: "WzE2MTA2MTY0MDAsImYxIl0"
+	NextCursor string
 }
 
 // EntityTypesRequest is the input-object
@@ -685,6 +993,13 @@ type EventUpdateRequest struct {
 	//
 	// example: 1257894000
 	ToDate int64
+
+	// Version of the event when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // EventUpdateResponse is the output-object
@@ -738,12 +1053,30 @@ type EventListRequest struct {
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
 	CaseID string
+
+	// ListOptions for the page of events, they can
+	// be sorted by "created", "date" (the FromDate)
+	// and "importance", and filtered by "importance"
+	// and "keyword"
+	ListOptions ListOptions
 }
 
 // EventListResponse is the output-object
 // for listing all existing events for a case
 type EventListResponse struct {
 	Events []Event
+
+	// Total number of events
+	// that matches the filters
+	//
+	// example: 250
+	Total int
+
+	// NextCursor gets the next page,
+	// it's empty on the last page
+	//
+	// example: "WzE2MTA2MTY0MDAsImYxIl0"
+	NextCursor string
 }
 
 // File holds information
@@ -786,166 +1119,538 @@ type File struct {
 	//
 	// example: ["healthy", "green"]
 	Keywords []string
-}
 
-// FileNewRequest is the input-object
-// for creating a new file
-type FileNewRequest struct {
-	// CaseID of the case to upload the file
+	// MD5 is the hex-encoded md5-hash
+	// of the file, computed at upload
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
+	// example: "9e107d9d372bb6826bd81d3542a419d6"
+	MD5 string
 
-	// Name of the file
+	// SHA1 is the hex-encoded sha1-hash
+	// of the file, computed at upload
 	//
-	// example: "text-file.txt"
-	Name string
+	// example: "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12"
+	SHA1 string
 
-	// Description of the file
+	// SHA256 is the hex-encoded sha256-hash
+	// of the file, computed at upload
 	//
-	// example: "This file contains evidence"
-	Description string
+	// example: "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592"
+	SHA256 string
 
-	// Mime is the mime-type of the file
-	// (decided by frontend)
+	// UploaderID is the ID of the user
+	// that uploaded the file
 	//
-	// example: "@file/plain"
-	Mime string
+	// example: "5a9c1e2d3b4f4e6f8a7b6c5d4e3f2a1b"
+	UploaderID string
 
-	// Data of the file (base64 encoded)
+	// UploaderEmail is the email of
+	// the user that uploaded the file
 	//
-	// example: "iVBORw0KGgoAAAANSUhEUgAAA1IAAAEeCA......."
-	Data string
-}
-
-// FileNewResponse is the output-object
-// for creating a new file
-type FileNewResponse struct {
-	New File
+	// example: "sja@avian.dk"
+	UploaderEmail string
 }
 
-// FileProcessRequest is the input-object
-// for processing a file in a case
-type FileProcessRequest struct {
-	// ID of the file to process
+// ProcessedDocument is the text and metadata
+// extracted from a processed file, or from
+// a file in a processed container
+type ProcessedDocument struct {
+	// ID of the document, it is the same as the
+	// FileID unless the document was extracted
+	// from a container
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
 	ID string
 
-	// CaseID of the case to process the file in
+	// FileID of the uploaded file
+	// the document is from
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
-}
-
-// FileProcessResponse is the output-object
-// for processing a file in a case
-type FileProcessResponse struct {
-	Processed File
-}
+	FileID string
 
-// FileProcessedRequest is the input-object
-// for getting a processed file in a case
-type FileProcessedRequest struct {
-	// ID of the processed file
+	// ParentID is the ID of the document for the
+	// container the document was extracted from,
+	// empty for the uploaded file
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
-	ID string
+	ParentID string
 
-	// CaseID of the case to the processed file
+	// ParentPath is the path of the container
+	// the document was extracted from
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
-}
+	// example: "/evidence.zip/inbox.mbox"
+	ParentPath string
 
-// FileProcessedResponse is the output-object
-// for get a processed file in a case
-type FileProcessedResponse struct {
-	ID        string
-	Processed interface{}
-}
+	// Depth of the document in the containers,
+	// 0 for the uploaded file
+	//
+	// example: 2
+	Depth int
 
-// FileProcessesRequest is the input-object
-// for getting a Processes file in a case
-type FileProcessesRequest struct {
-	// CaseID of the case to the get all the processes
+	// Path of the document in the uploaded file
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
-}
+	// example: "/evidence.zip/inbox.mbox/12.eml"
+	Path string
 
-// FileProcessesResponse is the output-object
-// for get a Processes file in a case
-type FileProcessesResponse struct {
-	Processes interface{}
-}
+	// Name of the file
+	//
+	// example: "12.eml"
+	Name string
 
-// FileOpenRequest is the input-object
-// for opening a file in a case
-type FileOpenRequest struct {
-	// ID of the file to open
+	// Extension of the file
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	ID string
+	// example: "eml"
+	Extension string
 
-	// CaseID of the case to open the file
+	// ContentType of the file
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
-}
+	// example: "message/rfc822"
+	ContentType string
 
-// FileOpenResponse is the output-object
-// for opening a file in a case
-type FileOpenResponse struct {
-	// Data contains the b64-encoded
-	// data for the file
+	// Size of the file in bytes
 	//
-	// example: "c2FtcGxlCmRhdGEKMQ=="
-	Data string
-}
+	// example: 450060
+	Size int64
 
-// FileUpdateRequest is the input-object
-// for updating a files information
-type FileUpdateRequest struct {
-	// ID of the file to update
+	// Content is the extracted text
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	ID string
+	// example: "The invoice is attached."
+	Content string
 
-	// CaseID of the case where the file
-	// to update belongs
+	// Title of the document, the
+	// subject for emails
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
+	// example: "Invoice"
+	Title string
 
-	// Description of the file
+	// Author of the document, the
+	// sender for emails
 	//
-	// example: "This file contains evidence"
-	Description string
-}
+	// example: "Jane Doe <jane@example.com>"
+	Author string
 
-// FileUpdateResponse is the output-object
-// for updating a files information
-type FileUpdateResponse struct {
-	Updated File
-}
+	// Modifier is who last modified the document
+	//
+	// example: "John Smith"
+	Modifier string
 
-// FileDeleteRequest is the input-object
-// for deleting a file
-type FileDeleteRequest struct {
-	// ID of the file to delete
+	// Keywords for the document
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	ID string
+	// example: ["invoice", "bank"]
+	Keywords []string
 
-	// CaseID of the case where the file
-	// to delete belongs
+	// Language of the content, if
+	// it was detected by the indexer
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
-}
+	// example: "en"
+	Language string
 
-// FileDeleteResponse is the output-object
+	// Pages is the number of pages,
+	// 0 if it isn't known
+	//
+	// example: 12
+	Pages int
+
+	// CreatorTool is the application
+	// or camera that created the document
+	//
+	// example: "Microsoft Word"
+	CreatorTool string
+
+	// CreatedAt is the unix-timestamp for when the
+	// document was created, from its metadata
+	//
+	// example: 1610708400
+	CreatedAt int64
+
+	// ModifiedAt is the unix-timestamp for when the
+	// document was modified, or when the email was sent
+	//
+	// example: 1610708400
+	ModifiedAt int64
+
+	// FileModifiedAt is the unix-timestamp for when
+	// the file was last modified, as recorded by
+	// the container it was extracted from
+	//
+	// example: 1610708400
+	FileModifiedAt int64
+
+	// IndexedAt is the unix-timestamp
+	// for when the document was indexed
+	//
+	// example: 1610708400
+	IndexedAt int64
+
+	// Metadata is the other metadata
+	// extracted from the document
+	//
+	// example: {"to": "john@example.com"}
+	Metadata map[string]string
+
+	// Container is true if files
+	// were extracted from the document
+	//
+	// example: true
+	Container bool
+
+	// Children is the number of files in the container
+	//
+	// example: 12
+	Children int
+
+	// ContainerError is the reason
+	// the container couldn't be expanded
+	//
+	// example: "RAR-archives are not supported"
+	ContainerError string
+
+	// Error is the reason the content
+	// couldn't be extracted
+	//
+	// example: "cannot extract application/pdf from broken.pdf"
+	Error string
+}
+
+// CustodyEvent is an entry in the chain
+// of custody for a file, that records
+// who did what with the file and when
+type CustodyEvent struct {
+	Base
+
+	// FileID is the ID of the file
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	FileID string
+
+	// Action that was performed on the file,
+	// "uploaded", "opened", "processed",
+	// "updated", "deleted" or "verified"
+	//
+	// example: "uploaded"
+	Action string
+
+	// UserID of the user who performed the action
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	UserID string
+
+	// UserEmail of the user who performed the action
+	//
+	// example: "sja@avian.dk"
+	UserEmail string
+
+	// UserName of the user who performed the action
+	//
+	// example: "Simon"
+	UserName string
+
+	// Details about the action
+	//
+	// example: "sha256 d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592"
+	Details string
+}
+
+// FileCustodyRequest is the input-object
+// for getting the chain of custody for a file
+type FileCustodyRequest struct {
+	// ID of the file
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case where the file belongs
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileCustodyResponse is the output-object
+// for getting the chain of custody for a file
+type FileCustodyResponse struct {
+	// Events in the chain of custody,
+	// the oldest event first
+	Events []CustodyEvent
+}
+
+// FileVerifyRequest is the input-object
+// for verifying the hashes of a file
+type FileVerifyRequest struct {
+	// ID of the file to verify
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case where the file belongs
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileVerifyResponse is the output-object
+// for verifying the hashes of a file
+type FileVerifyResponse struct {
+	// Verified is true if every
+	// stored hash matches the file
+	//
+	// example: true
+	Verified bool
+
+	// Mismatches are the hashes that
+	// doesn't match the file
+	//
+	// example: ["sha256"]
+	Mismatches []string
+
+	File File
+}
+
+// FileNewRequest is the input-object
+// for creating a new file
+type FileNewRequest struct {
+	// CaseID of the case to upload the file
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+
+	// Name of the file
+	//
+	// example: "text-file.txt"
+	Name string
+
+	// Description of the file
+	//
+	// example: "This file contains evidence"
+	Description string
+
+	// Mime is the mime-type of the file
+	// (decided by frontend)
+	//
+	// example: "@file/plain"
+	Mime string
+
+	// Data of the file (base64 encoded)
+	//
+	// example: "iVBORw0KGgoAAAANSUhEUgAAA1IAAAEeCA......."
+	Data string
+}
+
+// FileNewResponse is the output-object
+// for creating a new file
+type FileNewResponse struct {
+	New File
+}
+
+// FileProcessRequest is the input-object
+// for processing a file in a case
+type FileProcessRequest struct {
+	// ID of the file to process
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case to process the file in
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileProcessResponse is the output-object
+// for processing a file in a case
+type FileProcessResponse struct {
+	Processed File
+}
+
+// FileProcessedRequest is the input-object
+// for getting a processed file in a case
+type FileProcessedRequest struct {
+	// ID of the processed file
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case to the processed file
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileProcessedResponse is the output-object
+// for get a processed file in a case
+type FileProcessedResponse struct {
+	ID        string
+	Processed ProcessedDocument
+}
+
+// FileListRequest is the input-object
+// for listing the files in a case
+type FileListRequest struct {
+	// CaseID of the case to list the files in
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+
+	// ListOptions for the page of files, they can be
+	// sorted by "date" (the default), "name" and "size",
+	// the files are filtered by the fields of the request
+	// below instead of the Filters
+	ListOptions ListOptions
+
+	// Mime only lists the files with the mime-type,
+	// "image/*" lists every image
+	//
+	// example: "text/plain"
+	Mime string
+
+	// State only lists the files that are
+	// "processed" or "unprocessed"
+	//
+	// example: "processed"
+	State string
+
+	// Keyword only lists the files with the keyword
+	//
+	// example: "healthy"
+	Keyword string
+
+	// UploadedFrom only lists the files uploaded
+	// after the unix-timestamp
+	//
+	// example: 1100127600
+	UploadedFrom int64
+
+	// UploadedTo only lists the files uploaded
+	// before the unix-timestamp
+	//
+	// example: 1257894000
+	UploadedTo int64
+
+	// Uploader only lists the files uploaded
+	// by the user, the ID or the email
+	//
+	// example: "sja@avian.dk"
+	Uploader string
+
+	// MinSize only lists the files that are
+	// at least the size in bytes
+	//
+	// example: 1024
+	MinSize int
+
+	// MaxSize only lists the files that are
+	// at most the size in bytes
+	//
+	// example: 1048576
+	MaxSize int
+}
+
+// FileListResponse is the output-object
+// for listing the files in a case
+type FileListResponse struct {
+	// Total number of files
+	// that matches the filters
+	//
+	// example: 250
+	Total int
+
+	// Files for the requested page
+	Files []File
+
+	// NextCursor gets the next page,
+	// it's empty on the last page
+	//
+	// example: "WzE2MTA2MTY0MDAsImYxIl0"
+	NextCursor string
+}
+
+// FileProcessesRequest is the input-object
+// for getting a Processes file in a case
+type FileProcessesRequest struct {
+	// CaseID of the case to the get all the processes
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileProcessesResponse is the output-object
+// for get a Processes file in a case
+type FileProcessesResponse struct {
+	Processes []ProcessedDocument
+}
+
+// FileOpenRequest is the input-object
+// for opening a file in a case
+type FileOpenRequest struct {
+	// ID of the file to open
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case to open the file
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileOpenResponse is the output-object
+// for opening a file in a case
+type FileOpenResponse struct {
+	// Data contains the b64-encoded
+	// data for the file
+	//
+	// example: "c2FtcGxlCmRhdGEKMQ=="
+	Data string
+}
+
+// FileUpdateRequest is the input-object
+// for updating a files information
+type FileUpdateRequest struct {
+	// ID of the file to update
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case where the file
+	// to update belongs
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+
+	// Description of the file
+	//
+	// example: "This file contains evidence"
+	Description string
+
+	// Version of the file when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
+}
+
+// FileUpdateResponse is the output-object
+// for updating a files information
+type FileUpdateResponse struct {
+	Updated File
+}
+
+// FileDeleteRequest is the input-object
+// for deleting a file
+type FileDeleteRequest struct {
+	// ID of the file to delete
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case where the file
+	// to delete belongs
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// FileDeleteResponse is the output-object
 // for deleting a file
 type FileDeleteResponse struct{}
 
@@ -1050,6 +1755,13 @@ type LinkAddRequest struct {
 	//
 	// example: ["7a1713b0249d477d92f5e10124a59861"]
 	FileIDs []string
+
+	// Version of the link when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // LinkAddResponse is the output-object
@@ -1090,6 +1802,13 @@ type LinkRemoveRequest struct {
 	//
 	// example: ["7a1713b0249d477d92f5e10124a59861"]
 	FileIDs []string
+
+	// Version of the link when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // LinkRemoveResponse is the output-object
@@ -1277,6 +1996,13 @@ type PersonUpdateRequest struct {
 	// Custom is a free form with key-value pairs
 	// specified by the user.
 	Custom map[string]interface{}
+
+	// Version of the person when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // PersonUpdateResponse is the output-object
@@ -1332,98 +2058,380 @@ type PersonListRequest struct {
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
 	CaseID string
+
+	// ListOptions for the page of persons, they can
+	// be sorted by "created", "firstName", "lastName"
+	// and "emailAddress", and filtered by
+	// "emailAddress" and "keyword"
+	ListOptions ListOptions
 }
 
 // PersonListResponse is the output-object
 // for listing all persons for a case
 type PersonListResponse struct {
 	Persons []Person
+
+	// Total number of persons
+	// that matches the filters
+	//
+	// example: 250
+	Total int
+
+	// NextCursor gets the next page,
+	// it's empty on the last page
+	//
+	// example: "WzE2MTA2MTY0MDAsImYxIl0"
+	NextCursor string
 }
 
-// Process holds information about
-// a job that processes data to app
-type Process struct {
-	Base
+// Process holds information about
+// a job that processes data to app
+type Process struct {
+	Base
+
+	// CaseID of the case the job belongs to
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+
+	// CreatorID is the ID of the user
+	// who started the job
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CreatorID string
+
+	// CreatorEmail is the email of
+	// the user who started the job
+	//
+	// example: "sja@avian.dk"
+	CreatorEmail string
+
+	// Status of the job, "queued", "running",
+	// "paused", "aborted", "completed" or "failed"
+	//
+	// example: "running"
+	Status string
+
+	// Progress of the job in percent
+	//
+	// example: 50
+	Progress int
+
+	// StartedAt is the unix-timestamp for
+	// when the job was started by a worker
+	//
+	// example: 1257894000
+	StartedAt int64
+
+	// FinishedAt is the unix-timestamp
+	// for when the job was finished
+	//
+	// example: 1257894000
+	FinishedAt int64
+
+	// Files for the process
+	Files []ProcessFile
+}
+
+// ProcessFile is the status
+// of a file in a processing-job
+type ProcessFile struct {
+	// FileID of the file to process
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	FileID string
+
+	// Status of the file, "queued", "running", "retrying",
+	// "paused", "aborted", "completed" or "failed"
+	//
+	// example: "completed"
+	Status string
+
+	// Attempts to process the file
+	//
+	// example: 1
+	Attempts int
+
+	// Error from the last attempt
+	//
+	// example: "fscrawler is not available"
+	Error string
+
+	// NextAttemptAt is the unix-timestamp
+	// for when the file is retried
+	//
+	// example: 1257894000
+	NextAttemptAt int64
+
+	// StartedAt is the unix-timestamp for
+	// when the last attempt was started
+	//
+	// example: 1257894000
+	StartedAt int64
+
+	// FinishedAt is the unix-timestamp for
+	// when the last attempt was finished
+	//
+	// example: 1257894000
+	FinishedAt int64
+}
+
+// ProcessStartRequest is the input-object
+// for starting a processing-job
+type ProcessStartRequest struct {
+	// CaseID of the case to start
+	// the processing for
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+
+	// FileIDs of the files to process
+	//
+	// example: ["7a1713b0249d477d92f5e10124a59861", "7a1713b0249d477d92f5e10124a59861"]
+	FileIDs []string
+}
+
+// ProcessStartResponse is the output-object
+// for starting a processing-job
+type ProcessStartResponse struct {
+	Started Process
+}
+
+// ProcessJobsRequest is the input-object
+// for getting all processing-jobs for a case
+type ProcessJobsRequest struct {
+	// CaseID of the case to get
+	// the processing-jobs for
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// ProcessJobsResponse is the output-object
+// for getting all processing-jobs for a case
+type ProcessJobsResponse struct {
+	Processes []Process
+}
+
+// ProcessAbortRequest is the input-object
+// for aborting a processing-job
+type ProcessAbortRequest struct {
+	// ID of the processing-job to abort
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case the processing-job belongs to
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// ProcessAbortResponse is the output-object
+// for aborting a processing-job
+type ProcessAbortResponse struct {
+	Aborted Process
+}
+
+// ProcessPauseRequest is the input-object
+// for pausing a processing-job
+type ProcessPauseRequest struct {
+	// ID of the processing-job to pause
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case the processing-job belongs to
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// ProcessPauseResponse is the output-object
+// for pausing a processing-job
+type ProcessPauseResponse struct {
+	Paused Process
+}
+
+// ProcessResumeRequest is the input-object
+// for resuming a paused processing-job
+type ProcessResumeRequest struct {
+	// ID of the processing-job to resume
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// CaseID of the case the processing-job belongs to
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// ProcessResumeResponse is the output-object
+// for resuming a paused processing-job
+type ProcessResumeResponse struct {
+	Resumed Process
+}
+
+// Suggestion is a person or an event that is suggested
+// from a processed file, it is created in the case
+// when an investigator accepts it
+type Suggestion struct {
+	Base
+
+	// Type of the suggestion, "person" or "event"
+	//
+	// example: "event"
+	Type string
+
+	// Status of the suggestion,
+	// "pending", "accepted" or "rejected"
+	//
+	// example: "pending"
+	Status string
+
+	// Source is what the suggestion was extracted
+	// from, "email" for the headers in emails, or
+	// "metadata" for the dates in the metadata
+	//
+	// example: "email"
+	Source string
+
+	// Field is the field in the source that has the
+	// value, the email-header or the metadata-field
+	// in the processed document, like meta.created
+	//
+	// example: "Date"
+	Field string
+
+	// FileID of the processed file
+	// the suggestion is from
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	FileID string
+
+	// DocumentID of the processed document, it is
+	// the same as the FileID unless the document
+	// was extracted from a container
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	DocumentID string
+
+	// Path of the document in the file
+	//
+	// example: "/inbox.mbox/12.eml"
+	Path string
+
+	// Person that is suggested
+	Person Person
+
+	// Event that is suggested
+	Event Event
+
+	// PersonIDs of the existing persons
+	// to link the suggested event to
+	//
+	// example: ["7a1713b0249d477d92f5e10124a59861"]
+	PersonIDs []string
+
+	// PersonSuggestionIDs of the suggested persons to
+	// link the suggested event to, they are accepted
+	// with the event unless they are rejected
+	//
+	// example: ["7a1713b0249d477d92f5e10124a59861"]
+	PersonSuggestionIDs []string
+
+	// AcceptedID is the ID of the person
+	// or event that was created
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	AcceptedID string
 
-	// Files for the process
-	Files []string
+	// ReviewerEmail is the email of the
+	// investigator who reviewed the suggestion
+	//
+	// example: "sja@avian.dk"
+	ReviewerEmail string
+
+	// ReviewedAt is the unix-timestamp for
+	// when the suggestion was reviewed
+	//
+	// example: 1257894000
+	ReviewedAt int64
 }
 
-// ProcessStartRequest is the input-object
-// for starting a processing-job
-type ProcessStartRequest struct {
-	// CaseID of the case to start
-	// the processing for
+// SuggestionListRequest is the input-object
+// for listing the suggestions for a case
+type SuggestionListRequest struct {
+	// CaseID of the case to list
+	// the suggestions for
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
 	CaseID string
 
-	// FileIDs of the files to process
+	// Type of the suggestions to list,
+	// every type is listed if it's empty
 	//
-	// example: ["7a1713b0249d477d92f5e10124a59861", "7a1713b0249d477d92f5e10124a59861"]
-	FileIDs []string
-}
+	// example: "person"
+	Type string
 
-// ProcessStartResponse is the output-object
-// for starting a processing-job
-type ProcessStartResponse struct {
-	Started Process
-}
+	// Status of the suggestions to list,
+	// every status is listed if it's empty
+	//
+	// example: "pending"
+	Status string
 
-// ProcessJobsRequest is the input-object
-// for getting all processing-jobs for a case
-type ProcessJobsRequest struct {
-	// CaseID of the case to get
-	// the processing-jobs for
+	// FileID of the file to list the suggestions
+	// for, every file is listed if it's empty
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
+	FileID string
 }
 
-// ProcessJobsResponse is the output-object
-// for getting all processing-jobs for a case
-type ProcessJobsResponse struct {
-	Processes []Process
+// SuggestionListResponse is the output-object
+// for listing the suggestions for a case
+type SuggestionListResponse struct {
+	Suggestions []Suggestion
 }
 
-// ProcessAbortRequest is the input-object
-// for aborting a processing-job
-type ProcessAbortRequest struct {
-	// ID of the processing-job to abort
+// SuggestionAcceptRequest is the input-object
+// for accepting suggestions
+type SuggestionAcceptRequest struct {
+	// CaseID of the suggestions
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
-	ID string
+	CaseID string
 
-	// CaseID of the case the processing-job belongs to
+	// IDs of the suggestions to accept
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
+	// example: ["7a1713b0249d477d92f5e10124a59861"]
+	IDs []string
 }
 
-// ProcessAbortResponse is the output-object
-// for aborting a processing-job
-type ProcessAbortResponse struct {
-	Aborted Process
+// SuggestionAcceptResponse is the output-object
+// for accepting suggestions
+type SuggestionAcceptResponse struct {
+	Accepted []Suggestion
 }
 
-// ProcessPauseRequest is the input-object
-// for pausing a processing-job
-type ProcessPauseRequest struct {
-	// ID of the processing-job to pause
+// SuggestionRejectRequest is the input-object
+// for rejecting suggestions
+type SuggestionRejectRequest struct {
+	// CaseID of the suggestions
 	//
 	// example: "7a1713b0249d477d92f5e10124a59861"
-	ID string
+	CaseID string
 
-	// CaseID of the case the processing-job belongs to
+	// IDs of the suggestions to reject
 	//
-	// example: "7a1713b0249d477d92f5e10124a59861"
-	CaseID string
+	// example: ["7a1713b0249d477d92f5e10124a59861"]
+	IDs []string
 }
 
-// ProcessPauseResponse is the output-object
-// for pausing a processing-job
-type ProcessPauseResponse struct {
-	Paused Process
+// SuggestionRejectResponse is the output-object
+// for rejecting suggestions
+type SuggestionRejectResponse struct {
+	Rejected []Suggestion
 }
 
 // Keyword represents a keyword
@@ -1478,6 +2486,13 @@ type KeywordsAddRequest struct {
 	//
 	// example: ["healthy", "green"]
 	Keywords []string
+
+	// Version of the object when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // KeywordsAddResponse is the output-object
@@ -1509,6 +2524,13 @@ type KeywordsRemoveRequest struct {
 	//
 	// example: ["healthy", "green"]
 	Keywords []string
+
+	// Version of the object when it was read, the
+	// update fails with a conflict if it has been
+	// changed since (it's not checked if empty)
+	//
+	// example: "12-1"
+	Version string
 }
 
 // KeywordsRemoveResponse is the output-object
@@ -1534,12 +2556,43 @@ type SearchTimespanRequest struct {
 	//
 	// example: 1257894000
 	ToDate int64
+
+	// ListOptions for the page of events, they are
+	// sorted by "date" (the FromDate) and cannot
+	// be filtered
+	ListOptions ListOptions
+
+	// IncludeFiles will also return the files
+	// that were processed, or has extracted
+	// document-dates, within the timespan. The
+	// files are only returned with the first page
+	//
+	// example: true
+	IncludeFiles bool
 }
 
 // SearchTimespanResponse is the output-object
 // for searching items
 type SearchTimespanResponse struct {
+	// Total number of events
+	// within the timespan
+	//
+	// example: 250
+	Total int
+
+	// Events within the timespan
+	// for the requested page
 	Events []Event
+
+	// NextCursor gets the next page of
+	// events, it's empty on the last page
+	//
+	// example: "WzExMDAxMjc2MDAwMDAsImYxIl0"
+	NextCursor string
+
+	// Files within the timespan, only on the
+	// first page if IncludeFiles was requested
+	Files []File
 }
 
 // SearchTextRequest is the input-object
@@ -1563,7 +2616,7 @@ type SearchTextResponse struct {
 	Entities  []Entity
 	Persons   []Person
 	Files     []File
-	Processed interface{}
+	Processed []ProcessedDocument
 }
 
 // Base model for the database
@@ -1590,6 +2643,386 @@ type Base struct {
 	//
 	// example: 0
 	DeletedAt int64
+
+	// Version of the object, send it back when
+	// updating the object to only update it if it
+	// hasn't been changed since it was read
+	//
+	// example: "12-1"
+	Version string
+}
+
+// ListOptions are the options for listing
+// items a page at a time, the response has
+// the total number of items that matches the
+// filters and the cursor for the next page
+type ListOptions struct {
+	// PageSize is the number of items
+	// to get per page (defaults to 100)
+	//
+	// example: 100
+	PageSize int
+
+	// Cursor is the NextCursor from the previous
+	// page, empty to get the first page
+	//
+	// example: ""
+	Cursor string
+
+	// SortBy is the field to sort the items by,
+	// "created" (the default) or a field for the
+	// items, see the list-request for the items
+	//
+	// example: "created"
+	SortBy string
+
+	// SortOrder for the items,
+	// "asc" or "desc" (defaults to "asc")
+	//
+	// example: "asc"
+	SortOrder string
+
+	// Filters only lists the items where the
+	// fields has the values, see the list-request
+	// for the fields that can be filtered
+	//
+	// example: {"keyword": "healthy"}
+	Filters map[string]string
+}
+
+// AdminCasesRequest is the input-object
+// for listing every case in the system
+type AdminCasesRequest struct{}
+
+// AdminCasesResponse is the output-object
+// for listing every case in the system
+type AdminCasesResponse struct {
+	Cases []Case
+}
+
+// AdminTransferRequest is the input-object
+// for transferring the ownership of a case
+type AdminTransferRequest struct {
+	// ID of the case to transfer
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+
+	// UserID of the new owner
+	//
+	// example: "8b1713b0249d477d92f5e10124a59862"
+	UserID string
+
+	// Email of the new owner, it's required when the
+	// authentication-provider cannot look up the users (oidc)
+	//
+	// example: "jis@avian.dk"
+	Email string
+
+	// RemoveEmail is the email of an investigator to remove
+	// from the case, for example the previous owner (optional)
+	//
+	// example: "sja@avian.dk"
+	RemoveEmail string
+}
+
+// AdminTransferResponse is the output-object
+// for transferring the ownership of a case
+type AdminTransferResponse struct {
+	Transferred Case
+}
+
+// AdminUsersRequest is the input-object
+// for listing the users in the system
+type AdminUsersRequest struct{}
+
+// AdminUsersResponse is the output-object
+// for listing the users in the system
+type AdminUsersResponse struct {
+	Users []UserMemberships
+}
+
+// UserMemberships holds the cases
+// a user is an investigator in
+type UserMemberships struct {
+	// Email of the user
+	//
+	// example: "sja@avian.dk"
+	Email string
+
+	// Memberships in the cases
+	Memberships []Membership
+}
+
+// Membership is the role of
+// a user in a specific case
+type Membership struct {
+	// CaseID is the ID of the case
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+
+	// CaseName is the name of the case
+	//
+	// example: "Case 1"
+	CaseName string
+
+	// Role of the user in the case
+	//
+	// example: "owner"
+	Role string
+}
+
+// AdminDeleteRequest is the input-object
+// for force-deleting a case
+type AdminDeleteRequest struct {
+	// ID of the case to delete
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+}
+
+// AdminDeleteResponse is the output-object
+// for force-deleting a case
+type AdminDeleteResponse struct{}
+
+// AdminRotateKeyRequest is the input-object
+// for rotating the data-key for a case
+type AdminRotateKeyRequest struct {
+	// ID of the case to rotate the key for
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+}
+
+// AdminRotateKeyResponse is the output-object
+// for rotating the data-key for a case
+type AdminRotateKeyResponse struct {
+	// Version of the new data-key
+	//
+	// example: 2
+	Version int
+}
+
+// AdminCreateUserRequest is the input-object
+// for creating a user
+type AdminCreateUserRequest struct {
+	// Email of the user
+	//
+	// example: "sja@avian.dk"
+	Email string
+
+	// Name of the user
+	//
+	// example: "Simon Jansson"
+	Name string
+
+	// Password of the user,
+	// at least 12 characters
+	//
+	// example: "correct horse battery"
+	Password string
+}
+
+// AdminCreateUserResponse is the output-object
+// for creating a user
+type AdminCreateUserResponse struct {
+	// ID of the created user
+	//
+	// example: "8b1713b0249d477d92f5e10124a59862"
+	ID string
+}
+
+// AdminDeleteUserRequest is the input-object
+// for deleting a user
+type AdminDeleteUserRequest struct {
+	// ID of the user to delete
+	//
+	// example: "8b1713b0249d477d92f5e10124a59862"
+	ID string
+}
+
+// AdminDeleteUserResponse is the output-object
+// for deleting a user
+type AdminDeleteUserResponse struct{}
+
+// AdminResetPasswordRequest is the input-object
+// for setting a new password for a user
+type AdminResetPasswordRequest struct {
+	// ID of the user
+	//
+	// example: "8b1713b0249d477d92f5e10124a59862"
+	ID string
+
+	// Password is the new password,
+	// at least 12 characters
+	//
+	// example: "staple battery horse"
+	Password string
+}
+
+// AdminResetPasswordResponse is the output-object
+// for setting a new password for a user
+type AdminResetPasswordResponse struct{}
+
+// Token is a long-lived API-token that
+// has access to specific cases with a role
+type Token struct {
+	Base
+
+	// Name of the token
+	//
+	// example: "Nightly ingestion"
+	Name string
+
+	// CreatorID is the user-id of the user
+	// who created the token
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CreatorID string
+
+	// CreatorEmail is the email of the user
+	// who created the token
+	//
+	// example: "sja@avian.dk"
+	CreatorEmail string
+
+	// Prefix is the start of the token,
+	// to be able to recognize it
+	//
+	// example: "ti_3ZkqM1"
+	Prefix string
+
+	// CaseIDs are the IDs of the
+	// cases the token has access to
+	//
+	// example: ["7a1713b0249d477d92f5e10124a59861"]
+	CaseIDs []string
+
+	// Role the token has in the cases,
+	// "editor", "viewer" or "auditor"
+	//
+	// example: "editor"
+	Role string
+
+	// ExpiresAt is the unix-date when the token
+	// expires (0 means that it never expires)
+	//
+	// example: 0
+	ExpiresAt int64
+
+	// RevokedAt is the unix-date when the token was revoked
+	//
+	// example: 0
+	RevokedAt int64
+
+	// LastUsedAt is the unix-date when the
+	// token was last used, the usage is
+	// recorded at most once a minute
+	//
+	// example: 1257894000
+	LastUsedAt int64
+
+	// UseCount is the number of requests made
+	// with the token, the requests since the
+	// usage was recorded are counted with the
+	// next request after a minute
+	//
+	// example: 42
+	UseCount int
+}
+
+// TokenCreateRequest is the input-object
+// for creating an API-token
+type TokenCreateRequest struct {
+	// Name of the token
+	//
+	// example: "Nightly ingestion"
+	Name string
+
+	// CaseIDs are the IDs of the
+	// cases the token has access to
+	//
+	// example: ["7a1713b0249d477d92f5e10124a59861"]
+	CaseIDs []string
+
+	// Role the token has in the cases,
+	// "editor", "viewer" or "auditor"
+	//
+	// example: "editor"
+	Role string
+
+	// ExpiresAt is the unix-date when the token
+	// expires (0 means that it never expires)
+	//
+	// example: 0
+	ExpiresAt int64
+}
+
+// TokenCreateResponse is the output-object
+// for creating an API-token
+type TokenCreateResponse struct {
+	// Token to use in the authorization-header,
+	// it is only returned once
+	//
+	// example: "ti_3ZkqM1...."
+	Token string
+
+	New Token
+}
+
+// TokenListRequest is the input-object
+// for listing API-tokens
+type TokenListRequest struct {
+	// CaseID to only list the
+	// tokens for a specific case
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	CaseID string
+}
+
+// TokenListResponse is the output-object
+// for listing API-tokens
+type TokenListResponse struct {
+	Tokens []Token
+}
+
+// TokenRevokeRequest is the input-object
+// for revoking an API-token
+type TokenRevokeRequest struct {
+	// ID of the token to revoke
+	//
+	// example: "7a1713b0249d477d92f5e10124a59861"
+	ID string
+}
+
+// TokenRevokeResponse is the output-object
+// for revoking an API-token
+type TokenRevokeResponse struct {
+	Revoked Token
+}
+
+// AuthSignInRequest is the input-object
+// for signing in a user
+type AuthSignInRequest struct {
+	// Email of the user
+	//
+	// example: "sja@avian.dk"
+	Email string
+
+	// Password of the user
+	//
+	// example: "supersecret"
+	Password string
+}
+
+// AuthSignInResponse is the output-object
+// for signing in a user
+type AuthSignInResponse struct {
+	// Token for the signed in user
+	//
+	// example: "er324235tt...."
+	Token string
 }
 
 // TestCreateUserRequest is the input-object
@@ -1657,4 +3090,10 @@ type User struct {
 	PhotoURL    string
 	ProviderID  string
 	UID         string
+
+	// Admin is true if the user
+	// is a system-administrator
+	//
+	// example: false
+	Admin bool
 }
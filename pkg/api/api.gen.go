@@ -11,6 +11,41 @@ import (
 	context "context"
 )
 
+// AdminService is the API for system-administrators to manage every case in the
+// system
+type AdminService interface {
+	// Authenticate is a middleware in the http-handler
+	Authenticate(context.Context, *http.Request) (context.Context, error)
+	// Cases lists every case in the system
+	Cases(context.Context, AdminCasesRequest) (*AdminCasesResponse, error)
+	// CreateUser creates a user that signs in with a password, only with the local
+	// authentication-provider
+	CreateUser(context.Context, AdminCreateUserRequest) (*AdminCreateUserResponse, error)
+	// Delete deletes a case without being an owner of it
+	Delete(context.Context, AdminDeleteRequest) (*AdminDeleteResponse, error)
+	// DeleteUser deletes a user, only with the local authentication-provider
+	DeleteUser(context.Context, AdminDeleteUserRequest) (*AdminDeleteUserResponse, error)
+	// ResetPassword sets a new password for a user, only with the local
+	// authentication-provider
+	ResetPassword(context.Context, AdminResetPasswordRequest) (*AdminResetPasswordResponse, error)
+	// RotateKey rotates the data-key that encrypts the files in a case, new files are
+	// encrypted with the new key
+	RotateKey(context.Context, AdminRotateKeyRequest) (*AdminRotateKeyResponse, error)
+	// Transfer transfers the ownership of a case to another user, for example when an
+	// investigator has left the organization
+	Transfer(context.Context, AdminTransferRequest) (*AdminTransferResponse, error)
+	// Users lists the users with their memberships in the cases
+	Users(context.Context, AdminUsersRequest) (*AdminUsersResponse, error)
+}
+
+// AuthService is the API to sign in users with the local authentication-provider
+type AuthService interface {
+	// Authenticate is a middleware in the http-handler
+	Authenticate(context.Context, *http.Request) (context.Context, error)
+	// SignIn signs in the user with email and password and returns a token
+	SignIn(context.Context, AuthSignInRequest) (*AuthSignInResponse, error)
+}
+
 // CaseService is the API to handle cases
 type CaseService interface {
 	// Authenticate is a middleware in the http-handler
@@ -19,12 +54,20 @@ type CaseService interface {
 	Delete(context.Context, CaseDeleteRequest) (*CaseDeleteResponse, error)
 	// Get returns the requested case
 	Get(context.Context, CaseGetRequest) (*CaseGetResponse, error)
+	// InvestigatorsAdd invites investigators to the case
+	InvestigatorsAdd(context.Context, CaseInvestigatorsAddRequest) (*CaseInvestigatorsAddResponse, error)
+	// InvestigatorsRemove removes investigators from the case, the last owner of the
+	// case cannot be removed
+	InvestigatorsRemove(context.Context, CaseInvestigatorsRemoveRequest) (*CaseInvestigatorsRemoveResponse, error)
 	// Keywords lists all the keywords for the case
 	Keywords(context.Context, CaseKeywordsRequest) (*CaseKeywordsResponse, error)
 	// List the cases for a specified user
 	List(context.Context, CaseListRequest) (*CaseListResponse, error)
 	// New creates a new case
 	New(context.Context, CaseNewRequest) (*CaseNewResponse, error)
+	// RoleSet sets the role for an investigator in the case, the last owner of the
+	// case cannot be demoted
+	RoleSet(context.Context, CaseRoleSetRequest) (*CaseRoleSetResponse, error)
 	// Update updates the specified case
 	Update(context.Context, CaseUpdateRequest) (*CaseUpdateResponse, error)
 }
@@ -75,17 +118,22 @@ type EventService interface {
 type FileService interface {
 	// Authenticate is a middleware in the http-handler
 	Authenticate(context.Context, *http.Request) (context.Context, error)
+	// Custody returns the chain of custody for a file
+	Custody(context.Context, FileCustodyRequest) (*FileCustodyResponse, error)
 	// Delete deletes the specified file
 	Delete(context.Context, FileDeleteRequest) (*FileDeleteResponse, error)
 	// KeywordsAdd to a file
 	KeywordsAdd(context.Context, KeywordsAddRequest) (*KeywordsAddResponse, error)
 	// KeywordsRemove from a file
 	KeywordsRemove(context.Context, KeywordsRemoveRequest) (*KeywordsRemoveResponse, error)
+	// List lists the files in a case, a page at a time
+	List(context.Context, FileListRequest) (*FileListResponse, error)
 	// New uploads a file to the backend
 	New(context.Context, FileNewRequest) (*FileNewResponse, error)
-	// Open opens a file
+	// Open opens a file (base64 encoded), files larger than 10 MB cannot be opened,
+	// they are streamed from the download-handler
 	Open(context.Context, FileOpenRequest) (*FileOpenResponse, error)
-	// Process processes a file
+	// Process processes a file large files should be processed with the ProcessService
 	Process(context.Context, FileProcessRequest) (*FileProcessResponse, error)
 	// Processed gets information for a processed file
 	Processed(context.Context, FileProcessedRequest) (*FileProcessedResponse, error)
@@ -93,6 +141,8 @@ type FileService interface {
 	Processes(context.Context, FileProcessesRequest) (*FileProcessesResponse, error)
 	// Update updates the information for a file
 	Update(context.Context, FileUpdateRequest) (*FileUpdateResponse, error)
+	// Verify verifies the stored hashes against the file in the filestore
+	Verify(context.Context, FileVerifyRequest) (*FileVerifyResponse, error)
 }
 
 // LinkService is a API for creating links between objects
@@ -131,6 +181,23 @@ type PersonService interface {
 	Update(context.Context, PersonUpdateRequest) (*PersonUpdateResponse, error)
 }
 
+// ProcessService is the API to process files in the background with
+// processing-jobs
+type ProcessService interface {
+	// Abort aborts a processing-job, the files that aren't processed are skipped
+	Abort(context.Context, ProcessAbortRequest) (*ProcessAbortResponse, error)
+	// Authenticate is a middleware in the http-handler
+	Authenticate(context.Context, *http.Request) (context.Context, error)
+	// Jobs gets the processing-jobs for a case
+	Jobs(context.Context, ProcessJobsRequest) (*ProcessJobsResponse, error)
+	// Pause pauses a processing-job
+	Pause(context.Context, ProcessPauseRequest) (*ProcessPauseResponse, error)
+	// Resume resumes a paused processing-job
+	Resume(context.Context, ProcessResumeRequest) (*ProcessResumeResponse, error)
+	// Start starts a new processing-job for files in a case
+	Start(context.Context, ProcessStartRequest) (*ProcessStartResponse, error)
+}
+
 // SearchService is the API to handle searches in the Timeline-Investigator
 type SearchService interface {
 	// Authenticate is a middleware in the http-handler
@@ -141,6 +208,20 @@ type SearchService interface {
 	SearchWithTimespan(context.Context, SearchTimespanRequest) (*SearchTimespanResponse, error)
 }
 
+// SuggestionService is the API to review the persons and events that are suggested
+// from the processed files
+type SuggestionService interface {
+	// Accept accepts suggestions, the suggested persons and events are created in the
+	// case
+	Accept(context.Context, SuggestionAcceptRequest) (*SuggestionAcceptResponse, error)
+	// Authenticate is a middleware in the http-handler
+	Authenticate(context.Context, *http.Request) (context.Context, error)
+	// List lists the suggestions for a case
+	List(context.Context, SuggestionListRequest) (*SuggestionListResponse, error)
+	// Reject rejects suggestions
+	Reject(context.Context, SuggestionRejectRequest) (*SuggestionRejectResponse, error)
+}
+
 // TestService is used for testing-purposes
 type TestService interface {
 	// CreateUser creates a test-user in Firebase
@@ -149,6 +230,293 @@ type TestService interface {
 	DeleteUser(context.Context, TestDeleteUserRequest) (*TestDeleteUserResponse, error)
 }
 
+// TokenService is the API to handle API-tokens, used by scripts to access cases
+// without a user
+type TokenService interface {
+	// Authenticate is a middleware in the http-handler
+	Authenticate(context.Context, *http.Request) (context.Context, error)
+	// Create creates a new API-token for cases owned by the user, the token is only
+	// returned once and cannot be retrieved again
+	Create(context.Context, TokenCreateRequest) (*TokenCreateResponse, error)
+	// List lists the API-tokens created by the user
+	List(context.Context, TokenListRequest) (*TokenListResponse, error)
+	// Revoke revokes an API-token
+	Revoke(context.Context, TokenRevokeRequest) (*TokenRevokeResponse, error)
+}
+
+type adminServiceServer struct {
+	server       *otohttp.Server
+	adminService AdminService
+	test         bool
+}
+
+// Register adds the AdminService to the otohttp.Server.
+func RegisterAdminService(server *otohttp.Server, adminService AdminService) {
+	handler := &adminServiceServer{
+		server:       server,
+		adminService: adminService,
+	}
+
+	server.Register("AdminService", "Cases", handler.handleCases)
+	server.Register("AdminService", "CreateUser", handler.handleCreateUser)
+	server.Register("AdminService", "Delete", handler.handleDelete)
+	server.Register("AdminService", "DeleteUser", handler.handleDeleteUser)
+	server.Register("AdminService", "ResetPassword", handler.handleResetPassword)
+	server.Register("AdminService", "RotateKey", handler.handleRotateKey)
+	server.Register("AdminService", "Transfer", handler.handleTransfer)
+	server.Register("AdminService", "Users", handler.handleUsers)
+}
+
+func (s *adminServiceServer) handleCases(w http.ResponseWriter, r *http.Request) {
+	var request AdminCasesRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.Cases(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var request AdminCreateUserRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.CreateUser(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	var request AdminDeleteRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.Delete(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	var request AdminDeleteUserRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.DeleteUser(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request AdminResetPasswordRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.ResetPassword(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleRotateKey(w http.ResponseWriter, r *http.Request) {
+	var request AdminRotateKeyRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.RotateKey(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleTransfer(w http.ResponseWriter, r *http.Request) {
+	var request AdminTransferRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.Transfer(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *adminServiceServer) handleUsers(w http.ResponseWriter, r *http.Request) {
+	var request AdminUsersRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.adminService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.adminService.Users(ctx, request)
+	if err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AdminService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+type authServiceServer struct {
+	server      *otohttp.Server
+	authService AuthService
+	test        bool
+}
+
+// Register adds the AuthService to the otohttp.Server.
+func RegisterAuthService(server *otohttp.Server, authService AuthService) {
+	handler := &authServiceServer{
+		server:      server,
+		authService: authService,
+	}
+
+	server.Register("AuthService", "SignIn", handler.handleSignIn)
+}
+
+func (s *authServiceServer) handleSignIn(w http.ResponseWriter, r *http.Request) {
+	var request AuthSignInRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("AuthService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.authService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("AuthService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.authService.SignIn(ctx, request)
+	if err != nil {
+		log.Printf("AuthService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("AuthService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 type caseServiceServer struct {
 	server      *otohttp.Server
 	caseService CaseService
@@ -164,9 +532,12 @@ func RegisterCaseService(server *otohttp.Server, caseService CaseService) {
 
 	server.Register("CaseService", "Delete", handler.handleDelete)
 	server.Register("CaseService", "Get", handler.handleGet)
+	server.Register("CaseService", "InvestigatorsAdd", handler.handleInvestigatorsAdd)
+	server.Register("CaseService", "InvestigatorsRemove", handler.handleInvestigatorsRemove)
 	server.Register("CaseService", "Keywords", handler.handleKeywords)
 	server.Register("CaseService", "List", handler.handleList)
 	server.Register("CaseService", "New", handler.handleNew)
+	server.Register("CaseService", "RoleSet", handler.handleRoleSet)
 	server.Register("CaseService", "Update", handler.handleUpdate)
 }
 
@@ -222,6 +593,58 @@ func (s *caseServiceServer) handleGet(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *caseServiceServer) handleInvestigatorsAdd(w http.ResponseWriter, r *http.Request) {
+	var request CaseInvestigatorsAddRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.caseService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.caseService.InvestigatorsAdd(ctx, request)
+	if err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *caseServiceServer) handleInvestigatorsRemove(w http.ResponseWriter, r *http.Request) {
+	var request CaseInvestigatorsRemoveRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.caseService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.caseService.InvestigatorsRemove(ctx, request)
+	if err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 func (s *caseServiceServer) handleKeywords(w http.ResponseWriter, r *http.Request) {
 	var request CaseKeywordsRequest
 	if err := otohttp.Decode(r, &request); err != nil {
@@ -300,6 +723,32 @@ func (s *caseServiceServer) handleNew(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *caseServiceServer) handleRoleSet(w http.ResponseWriter, r *http.Request) {
+	var request CaseRoleSetRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.caseService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.caseService.RoleSet(ctx, request)
+	if err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("CaseService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 func (s *caseServiceServer) handleUpdate(w http.ResponseWriter, r *http.Request) {
 	var request CaseUpdateRequest
 	if err := otohttp.Decode(r, &request); err != nil {
@@ -774,19 +1223,74 @@ func RegisterFileService(server *otohttp.Server, fileService FileService) {
 		fileService: fileService,
 	}
 
+	server.Register("FileService", "Custody", handler.handleCustody)
 	server.Register("FileService", "Delete", handler.handleDelete)
 	server.Register("FileService", "KeywordsAdd", handler.handleKeywordsAdd)
 	server.Register("FileService", "KeywordsRemove", handler.handleKeywordsRemove)
+	server.Register("FileService", "List", handler.handleList)
 	server.Register("FileService", "New", handler.handleNew)
 	server.Register("FileService", "Open", handler.handleOpen)
 	server.Register("FileService", "Process", handler.handleProcess)
 	server.Register("FileService", "Processed", handler.handleProcessed)
 	server.Register("FileService", "Processes", handler.handleProcesses)
 	server.Register("FileService", "Update", handler.handleUpdate)
+	server.Register("FileService", "Verify", handler.handleVerify)
+}
+
+func (s *fileServiceServer) handleCustody(w http.ResponseWriter, r *http.Request) {
+	var request FileCustodyRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.fileService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.fileService.Custody(ctx, request)
+	if err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *fileServiceServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	var request FileDeleteRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.fileService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.fileService.Delete(ctx, request)
+	if err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
 }
 
-func (s *fileServiceServer) handleDelete(w http.ResponseWriter, r *http.Request) {
-	var request FileDeleteRequest
+func (s *fileServiceServer) handleKeywordsAdd(w http.ResponseWriter, r *http.Request) {
+	var request KeywordsAddRequest
 	if err := otohttp.Decode(r, &request); err != nil {
 		log.Printf("FileService : %s", err.Error())
 		s.server.OnErr(w, r, err)
@@ -798,7 +1302,7 @@ func (s *fileServiceServer) handleDelete(w http.ResponseWriter, r *http.Request)
 		s.server.OnErr(w, r, err)
 		return
 	}
-	response, err := s.fileService.Delete(ctx, request)
+	response, err := s.fileService.KeywordsAdd(ctx, request)
 	if err != nil {
 		log.Printf("FileService : %s", err.Error())
 		s.server.OnErr(w, r, err)
@@ -811,8 +1315,8 @@ func (s *fileServiceServer) handleDelete(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func (s *fileServiceServer) handleKeywordsAdd(w http.ResponseWriter, r *http.Request) {
-	var request KeywordsAddRequest
+func (s *fileServiceServer) handleKeywordsRemove(w http.ResponseWriter, r *http.Request) {
+	var request KeywordsRemoveRequest
 	if err := otohttp.Decode(r, &request); err != nil {
 		log.Printf("FileService : %s", err.Error())
 		s.server.OnErr(w, r, err)
@@ -824,7 +1328,7 @@ func (s *fileServiceServer) handleKeywordsAdd(w http.ResponseWriter, r *http.Req
 		s.server.OnErr(w, r, err)
 		return
 	}
-	response, err := s.fileService.KeywordsAdd(ctx, request)
+	response, err := s.fileService.KeywordsRemove(ctx, request)
 	if err != nil {
 		log.Printf("FileService : %s", err.Error())
 		s.server.OnErr(w, r, err)
@@ -837,8 +1341,8 @@ func (s *fileServiceServer) handleKeywordsAdd(w http.ResponseWriter, r *http.Req
 	}
 }
 
-func (s *fileServiceServer) handleKeywordsRemove(w http.ResponseWriter, r *http.Request) {
-	var request KeywordsRemoveRequest
+func (s *fileServiceServer) handleList(w http.ResponseWriter, r *http.Request) {
+	var request FileListRequest
 	if err := otohttp.Decode(r, &request); err != nil {
 		log.Printf("FileService : %s", err.Error())
 		s.server.OnErr(w, r, err)
@@ -850,7 +1354,7 @@ func (s *fileServiceServer) handleKeywordsRemove(w http.ResponseWriter, r *http.
 		s.server.OnErr(w, r, err)
 		return
 	}
-	response, err := s.fileService.KeywordsRemove(ctx, request)
+	response, err := s.fileService.List(ctx, request)
 	if err != nil {
 		log.Printf("FileService : %s", err.Error())
 		s.server.OnErr(w, r, err)
@@ -1019,6 +1523,32 @@ func (s *fileServiceServer) handleUpdate(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+func (s *fileServiceServer) handleVerify(w http.ResponseWriter, r *http.Request) {
+	var request FileVerifyRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.fileService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.fileService.Verify(ctx, request)
+	if err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("FileService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 type linkServiceServer struct {
 	server      *otohttp.Server
 	linkService LinkService
@@ -1373,6 +1903,156 @@ func (s *personServiceServer) handleUpdate(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+type processServiceServer struct {
+	server         *otohttp.Server
+	processService ProcessService
+	test           bool
+}
+
+// Register adds the ProcessService to the otohttp.Server.
+func RegisterProcessService(server *otohttp.Server, processService ProcessService) {
+	handler := &processServiceServer{
+		server:         server,
+		processService: processService,
+	}
+	server.Register("ProcessService", "Abort", handler.handleAbort)
+
+	server.Register("ProcessService", "Jobs", handler.handleJobs)
+	server.Register("ProcessService", "Pause", handler.handlePause)
+	server.Register("ProcessService", "Resume", handler.handleResume)
+	server.Register("ProcessService", "Start", handler.handleStart)
+}
+
+func (s *processServiceServer) handleAbort(w http.ResponseWriter, r *http.Request) {
+	var request ProcessAbortRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.processService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.processService.Abort(ctx, request)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *processServiceServer) handleJobs(w http.ResponseWriter, r *http.Request) {
+	var request ProcessJobsRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.processService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.processService.Jobs(ctx, request)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *processServiceServer) handlePause(w http.ResponseWriter, r *http.Request) {
+	var request ProcessPauseRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.processService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.processService.Pause(ctx, request)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *processServiceServer) handleResume(w http.ResponseWriter, r *http.Request) {
+	var request ProcessResumeRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.processService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.processService.Resume(ctx, request)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *processServiceServer) handleStart(w http.ResponseWriter, r *http.Request) {
+	var request ProcessStartRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.processService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.processService.Start(ctx, request)
+	if err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("ProcessService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 type searchServiceServer struct {
 	server        *otohttp.Server
 	searchService SearchService
@@ -1442,6 +2122,102 @@ func (s *searchServiceServer) handleSearchWithTimespan(w http.ResponseWriter, r
 	}
 }
 
+type suggestionServiceServer struct {
+	server            *otohttp.Server
+	suggestionService SuggestionService
+	test              bool
+}
+
+// Register adds the SuggestionService to the otohttp.Server.
+func RegisterSuggestionService(server *otohttp.Server, suggestionService SuggestionService) {
+	handler := &suggestionServiceServer{
+		server:            server,
+		suggestionService: suggestionService,
+	}
+	server.Register("SuggestionService", "Accept", handler.handleAccept)
+
+	server.Register("SuggestionService", "List", handler.handleList)
+	server.Register("SuggestionService", "Reject", handler.handleReject)
+}
+
+func (s *suggestionServiceServer) handleAccept(w http.ResponseWriter, r *http.Request) {
+	var request SuggestionAcceptRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.suggestionService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.suggestionService.Accept(ctx, request)
+	if err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *suggestionServiceServer) handleList(w http.ResponseWriter, r *http.Request) {
+	var request SuggestionListRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.suggestionService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.suggestionService.List(ctx, request)
+	if err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *suggestionServiceServer) handleReject(w http.ResponseWriter, r *http.Request) {
+	var request SuggestionRejectRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.suggestionService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.suggestionService.Reject(ctx, request)
+	if err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("SuggestionService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 type testServiceServer struct {
 	server      *otohttp.Server
 	testService TestService
@@ -1454,52 +2230,152 @@ func RegisterTestService(server *otohttp.Server, testService TestService) {
 		server:      server,
 		testService: testService,
 	}
-	server.Register("TestService", "CreateUser", handler.handleCreateUser)
-	server.Register("TestService", "DeleteUser", handler.handleDeleteUser)
+	server.Register("TestService", "CreateUser", handler.handleCreateUser)
+	server.Register("TestService", "DeleteUser", handler.handleDeleteUser)
+}
+
+func (s *testServiceServer) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var request TestCreateUserRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("TestService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx := r.Context()
+	response, err := s.testService.CreateUser(ctx, request)
+	if err != nil {
+		log.Printf("TestService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("TestService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *testServiceServer) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	var request TestDeleteUserRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("TestService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx := r.Context()
+	response, err := s.testService.DeleteUser(ctx, request)
+	if err != nil {
+		log.Printf("TestService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("TestService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+type tokenServiceServer struct {
+	server       *otohttp.Server
+	tokenService TokenService
+	test         bool
+}
+
+// Register adds the TokenService to the otohttp.Server.
+func RegisterTokenService(server *otohttp.Server, tokenService TokenService) {
+	handler := &tokenServiceServer{
+		server:       server,
+		tokenService: tokenService,
+	}
+
+	server.Register("TokenService", "Create", handler.handleCreate)
+	server.Register("TokenService", "List", handler.handleList)
+	server.Register("TokenService", "Revoke", handler.handleRevoke)
+}
+
+func (s *tokenServiceServer) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var request TokenCreateRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		log.Printf("TokenService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	ctx, err := s.tokenService.Authenticate(r.Context(), r)
+	if err != nil {
+		log.Printf("TokenService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.tokenService.Create(ctx, request)
+	if err != nil {
+		log.Printf("TokenService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		log.Printf("TokenService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
 }
 
-func (s *testServiceServer) handleCreateUser(w http.ResponseWriter, r *http.Request) {
-	var request TestCreateUserRequest
+func (s *tokenServiceServer) handleList(w http.ResponseWriter, r *http.Request) {
+	var request TokenListRequest
 	if err := otohttp.Decode(r, &request); err != nil {
-		log.Printf("TestService : %s", err.Error())
+		log.Printf("TokenService : %s", err.Error())
 		s.server.OnErr(w, r, err)
 		return
 	}
-	ctx := r.Context()
-	response, err := s.testService.CreateUser(ctx, request)
+	ctx, err := s.tokenService.Authenticate(r.Context(), r)
 	if err != nil {
-		log.Printf("TestService : %s", err.Error())
+		log.Printf("TokenService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.tokenService.List(ctx, request)
+	if err != nil {
+		log.Printf("TokenService : %s", err.Error())
 		s.server.OnErr(w, r, err)
 		return
 	}
 	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
-		log.Printf("TestService : %s", err.Error())
+		log.Printf("TokenService : %s", err.Error())
 		s.server.OnErr(w, r, err)
 		return
 	}
 }
 
-func (s *testServiceServer) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
-	var request TestDeleteUserRequest
+func (s *tokenServiceServer) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	var request TokenRevokeRequest
 	if err := otohttp.Decode(r, &request); err != nil {
-		log.Printf("TestService : %s", err.Error())
+		log.Printf("TokenService : %s", err.Error())
 		s.server.OnErr(w, r, err)
 		return
 	}
-	ctx := r.Context()
-	response, err := s.testService.DeleteUser(ctx, request)
+	ctx, err := s.tokenService.Authenticate(r.Context(), r)
 	if err != nil {
-		log.Printf("TestService : %s", err.Error())
+		log.Printf("TokenService : %s", err.Error())
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.tokenService.Revoke(ctx, request)
+	if err != nil {
+		log.Printf("TokenService : %s", err.Error())
 		s.server.OnErr(w, r, err)
 		return
 	}
 	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
-		log.Printf("TestService : %s", err.Error())
+		log.Printf("TokenService : %s", err.Error())
 		s.server.OnErr(w, r, err)
 		return
 	}
 }
 
+// AdminCasesRequest is the input-object for listing every case in the system
+type AdminCasesRequest struct {
+}
+
 // Base model for the database
 type Base struct {
 	// ID is the identifier for the object
@@ -1510,6 +2386,17 @@ type Base struct {
 	UpdatedAt int64 `json:"updatedAt"`
 	// DeletedAt - when the object was deleted
 	DeletedAt int64 `json:"deletedAt"`
+	// Version of the object, send it back when updating the object to only update it
+	// if it hasn't been changed since it was read
+	Version string `json:"version"`
+}
+
+// Role is the role an investigator has in a specific case
+type Role struct {
+	// Email of the investigator
+	Email string `json:"email"`
+	// Name of the role, "owner", "editor", "viewer" or "auditor"
+	Name string `json:"name"`
 }
 
 // File holds information about an uploaded file
@@ -1529,13 +2416,57 @@ type File struct {
 	ProcessedAt int64 `json:"processedAt"`
 	// The keywords for the file
 	Keywords []string `json:"keywords"`
+	// MD5 is the hex-encoded md5-hash of the file, computed at upload
+	MD5 string `json:"mD5"`
+	// SHA1 is the hex-encoded sha1-hash of the file, computed at upload
+	SHA1 string `json:"sHA1"`
+	// SHA256 is the hex-encoded sha256-hash of the file, computed at upload
+	SHA256 string `json:"sHA256"`
+	// UploaderID is the ID of the user that uploaded the file
+	UploaderID string `json:"uploaderID"`
+	// UploaderEmail is the email of the user that uploaded the file
+	UploaderEmail string `json:"uploaderEmail"`
+}
+
+// ProcessFile is the status of a file in a processing-job
+type ProcessFile struct {
+	// FileID of the file to process
+	FileID string `json:"fileID"`
+	// Status of the file, "queued", "running", "retrying", "paused", "aborted",
+	// "completed" or "failed"
+	Status string `json:"status"`
+	// Attempts to process the file
+	Attempts int `json:"attempts"`
+	// Error from the last attempt
+	Error string `json:"error"`
+	// NextAttemptAt is the unix-timestamp for when the file is retried
+	NextAttemptAt int64 `json:"nextAttemptAt"`
+	// StartedAt is the unix-timestamp for when the last attempt was started
+	StartedAt int64 `json:"startedAt"`
+	// FinishedAt is the unix-timestamp for when the last attempt was finished
+	FinishedAt int64 `json:"finishedAt"`
 }
 
 // Process holds information about a job that processes data to app
 type Process struct {
 	Base
+	// CaseID of the case the job belongs to
+	CaseID string `json:"caseID"`
+	// CreatorID is the ID of the user who started the job
+	CreatorID string `json:"creatorID"`
+	// CreatorEmail is the email of the user who started the job
+	CreatorEmail string `json:"creatorEmail"`
+	// Status of the job, "queued", "running", "paused", "aborted", "completed" or
+	// "failed"
+	Status string `json:"status"`
+	// Progress of the job in percent
+	Progress int `json:"progress"`
+	// StartedAt is the unix-timestamp for when the job was started by a worker
+	StartedAt int64 `json:"startedAt"`
+	// FinishedAt is the unix-timestamp for when the job was finished
+	FinishedAt int64 `json:"finishedAt"`
 	// Files for the process
-	Files []string `json:"files"`
+	Files []ProcessFile `json:"files"`
 }
 
 // Case is an object to hold data for a specific investigation
@@ -1553,12 +2484,162 @@ type Case struct {
 	ToDate int64 `json:"toDate"`
 	// Investigators of the case (users who has access to the case)
 	Investigators []string `json:"investigators"`
-	// Files that exists in the case
+	// Roles of the investigators in the case
+	Roles []Role `json:"roles"`
+	// Files that exists in the case, only the first 100 uploaded files are included
+	// when the case is read (see CaseGetResponse)
 	Files []File `json:"files"`
 	// Processes that exists in the case
 	Processes []Process `json:"processes"`
 }
 
+// AdminCasesResponse is the output-object for listing every case in the system
+type AdminCasesResponse struct {
+	Cases []Case `json:"cases"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminCreateUserRequest is the input-object for creating a user
+type AdminCreateUserRequest struct {
+	// Email of the user
+	Email string `json:"email"`
+	// Name of the user
+	Name string `json:"name"`
+	// Password of the user, at least 12 characters
+	Password string `json:"password"`
+}
+
+// AdminCreateUserResponse is the output-object for creating a user
+type AdminCreateUserResponse struct {
+	// ID of the created user
+	ID string `json:"id"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminDeleteRequest is the input-object for force-deleting a case
+type AdminDeleteRequest struct {
+	// ID of the case to delete
+	ID string `json:"id"`
+}
+
+// AdminDeleteResponse is the output-object for force-deleting a case
+type AdminDeleteResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminDeleteUserRequest is the input-object for deleting a user
+type AdminDeleteUserRequest struct {
+	// ID of the user to delete
+	ID string `json:"id"`
+}
+
+// AdminDeleteUserResponse is the output-object for deleting a user
+type AdminDeleteUserResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminResetPasswordRequest is the input-object for setting a new password for a
+// user
+type AdminResetPasswordRequest struct {
+	// ID of the user
+	ID string `json:"id"`
+	// Password is the new password, at least 12 characters
+	Password string `json:"password"`
+}
+
+// AdminResetPasswordResponse is the output-object for setting a new password for a
+// user
+type AdminResetPasswordResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminRotateKeyRequest is the input-object for rotating the data-key for a case
+type AdminRotateKeyRequest struct {
+	// ID of the case to rotate the key for
+	ID string `json:"id"`
+}
+
+// AdminRotateKeyResponse is the output-object for rotating the data-key for a case
+type AdminRotateKeyResponse struct {
+	// Version of the new data-key
+	Version int `json:"version"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminTransferRequest is the input-object for transferring the ownership of a
+// case
+type AdminTransferRequest struct {
+	// ID of the case to transfer
+	ID string `json:"id"`
+	// UserID of the new owner
+	UserID string `json:"userID"`
+	// Email of the new owner, it's required when the authentication-provider cannot
+	// look up the users (oidc)
+	Email string `json:"email"`
+	// RemoveEmail is the email of an investigator to remove from the case, for example
+	// the previous owner (optional)
+	RemoveEmail string `json:"removeEmail"`
+}
+
+// AdminTransferResponse is the output-object for transferring the ownership of a
+// case
+type AdminTransferResponse struct {
+	Transferred Case `json:"transferred"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AdminUsersRequest is the input-object for listing the users in the system
+type AdminUsersRequest struct {
+}
+
+// Membership is the role of a user in a specific case
+type Membership struct {
+	// CaseID is the ID of the case
+	CaseID string `json:"caseID"`
+	// CaseName is the name of the case
+	CaseName string `json:"caseName"`
+	// Role of the user in the case
+	Role string `json:"role"`
+}
+
+// UserMemberships holds the cases a user is an investigator in
+type UserMemberships struct {
+	// Email of the user
+	Email string `json:"email"`
+	// Memberships in the cases
+	Memberships []Membership `json:"memberships"`
+}
+
+// AdminUsersResponse is the output-object for listing the users in the system
+type AdminUsersResponse struct {
+	Users []UserMemberships `json:"users"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// AuthSignInRequest is the input-object for signing in a user
+type AuthSignInRequest struct {
+	// Email of the user
+	Email string `json:"email"`
+	// Password of the user
+	Password string `json:"password"`
+}
+
+// AuthSignInResponse is the output-object for signing in a user
+type AuthSignInResponse struct {
+	// Token for the signed in user
+	Token string `json:"token"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 // CaseDeleteRequest is the input-object for deleting an existing case
 type CaseDeleteRequest struct {
 	// ID of the case to delete
@@ -1580,6 +2661,49 @@ type CaseGetRequest struct {
 // CaseGetResponse is the output-object for getting a specified case
 type CaseGetResponse struct {
 	Case Case `json:"case"`
+	// FilesTotal is the number of files in the case, Case.Files only has the first
+	// page
+	FilesTotal int `json:"filesTotal"`
+	// FilesNextCursor gets the next page of the files with FileService.List, it's
+	// empty if Case.Files has all the files in the case
+	FilesNextCursor string `json:"filesNextCursor"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// CaseInvestigatorsAddRequest is the input-object for inviting investigators to a
+// case
+type CaseInvestigatorsAddRequest struct {
+	// ID of the case to invite the investigators to
+	ID string `json:"id"`
+	// Emails of the investigators to invite to the case
+	Emails []string `json:"emails"`
+	// Role for the investigators in the case, "owner", "editor", "viewer" or "auditor"
+	// (defaults to "editor")
+	Role string `json:"role"`
+}
+
+// CaseInvestigatorsAddResponse is the output-object for inviting investigators to
+// a case
+type CaseInvestigatorsAddResponse struct {
+	Updated Case `json:"updated"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// CaseInvestigatorsRemoveRequest is the input-object for removing investigators
+// from a case
+type CaseInvestigatorsRemoveRequest struct {
+	// ID of the case to remove the investigators from
+	ID string `json:"id"`
+	// Emails of the investigators to remove from the case
+	Emails []string `json:"emails"`
+}
+
+// CaseInvestigatorsRemoveResponse is the output-object for removing investigators
+// from a case
+type CaseInvestigatorsRemoveResponse struct {
+	Updated Case `json:"updated"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -1598,15 +2722,40 @@ type CaseKeywordsResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// ListOptions are the options for listing items a page at a time, the response has
+// the total number of items that matches the filters and the cursor for the next
+// page
+type ListOptions struct {
+	// PageSize is the number of items to get per page (defaults to 100)
+	PageSize int `json:"pageSize"`
+	// Cursor is the NextCursor from the previous page, empty to get the first page
+	Cursor string `json:"cursor"`
+	// SortBy is the field to sort the items by, "created" (the default) or a field for
+	// the items, see the list-request for the items
+	SortBy string `json:"sortBy"`
+	// SortOrder for the items, "asc" or "desc" (defaults to "asc")
+	SortOrder string `json:"sortOrder"`
+	// Filters only lists the items where the fields has the values, see the
+	// list-request for the fields that can be filtered
+	Filters map[string]string `json:"filters"`
+}
+
 // CaseListRequest is the input-object for listing cases for a specified user
 type CaseListRequest struct {
 	// UserID of the user to list cases for
 	UserID string `json:"userID"`
+	// ListOptions for the page of cases, they can be sorted by "created", "updated"
+	// and "name", and filtered by "creatorID"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // CaseListResponse is the output-object for listing cases for a specified user
 type CaseListResponse struct {
 	Cases []Case `json:"cases"`
+	// Total number of cases that matches the filters
+	Total int `json:"total"`
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -1630,6 +2779,23 @@ type CaseNewResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// CaseRoleSetRequest is the input-object for setting the role of an investigator
+type CaseRoleSetRequest struct {
+	// ID of the case
+	ID string `json:"id"`
+	// Email of the investigator to set the role for
+	Email string `json:"email"`
+	// Role for the investigator in the case, "owner", "editor", "viewer" or "auditor"
+	Role string `json:"role"`
+}
+
+// CaseRoleSetResponse is the output-object for setting the role of an investigator
+type CaseRoleSetResponse struct {
+	Updated Case `json:"updated"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 // CaseUpdateRequest is the input-object for updating an existing case
 type CaseUpdateRequest struct {
 	// ID of the case to update
@@ -1642,6 +2808,9 @@ type CaseUpdateRequest struct {
 	FromDate int64 `json:"fromDate"`
 	// ToDate is the unix-date for the end of the primary timespan for the case
 	ToDate int64 `json:"toDate"`
+	// Version of the case when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // CaseUpdateResponse is the output-object for updating an existing case
@@ -1651,6 +2820,25 @@ type CaseUpdateResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// CustodyEvent is an entry in the chain of custody for a file, that records who
+// did what with the file and when
+type CustodyEvent struct {
+	Base
+	// FileID is the ID of the file
+	FileID string `json:"fileID"`
+	// Action that was performed on the file, "uploaded", "opened", "processed",
+	// "updated", "deleted" or "verified"
+	Action string `json:"action"`
+	// UserID of the user who performed the action
+	UserID string `json:"userID"`
+	// UserEmail of the user who performed the action
+	UserEmail string `json:"userEmail"`
+	// UserName of the user who performed the action
+	UserName string `json:"userName"`
+	// Details about the action
+	Details string `json:"details"`
+}
+
 // Entity is an object that can be of different types. For example, organization or
 // location
 type Entity struct {
@@ -1723,11 +2911,18 @@ type EntityGetResponse struct {
 type EntityListRequest struct {
 	// CaseID of the case to list the entities for
 	CaseID string `json:"caseID"`
+	// ListOptions for the page of entities, they can be sorted by "created", "title"
+	// and "type", and filtered by "type" and "keyword"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // EntityListResponse is the output-object for updating an existing entity
 type EntityListResponse struct {
 	Entities []Entity `json:"entities"`
+	// Total number of entities that matches the filters
+	Total int `json:"total"`
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -1740,6 +2935,9 @@ type KeywordsAddRequest struct {
 	CaseID string `json:"caseID"`
 	// The keywords to add
 	Keywords []string `json:"keywords"`
+	// Version of the object when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // KeywordsAddResponse is the output-object for adding keywords to an object
@@ -1758,6 +2956,9 @@ type KeywordsRemoveRequest struct {
 	CaseID string `json:"caseID"`
 	// The keywords to remove
 	Keywords []string `json:"keywords"`
+	// Version of the object when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // KeywordsRemoveResponse is the output-object for removing keywords from an object
@@ -1793,6 +2994,9 @@ type EntityUpdateRequest struct {
 	Type string `json:"type"`
 	// Custom is a free form with key-value pairs specified by the user.
 	Custom map[string]interface{} `json:"custom"`
+	// Version of the entity when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // EntityUpdateResponse is the output-object for updating an existing entity
@@ -1871,12 +3075,19 @@ type EventGetResponse struct {
 type EventListRequest struct {
 	// CaseID to list the events for
 	CaseID string `json:"caseID"`
+	// ListOptions for the page of events, they can be sorted by "created", "date" (the
+	// FromDate) and "importance", and filtered by "importance" and "keyword"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // EventListResponse is the output-object for listing all existing events for a
 // case
 type EventListResponse struct {
 	Events []Event `json:"events"`
+	// Total number of events that matches the filters
+	Total int `json:"total"`
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -1895,6 +3106,9 @@ type EventUpdateRequest struct {
 	FromDate int64 `json:"fromDate"`
 	// ToDate is the unix-timestamp of when the event finished
 	ToDate int64 `json:"toDate"`
+	// Version of the event when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // EventUpdateResponse is the output-object for updating an existing event
@@ -1904,6 +3118,24 @@ type EventUpdateResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// FileCustodyRequest is the input-object for getting the chain of custody for a
+// file
+type FileCustodyRequest struct {
+	// ID of the file
+	ID string `json:"id"`
+	// CaseID of the case where the file belongs
+	CaseID string `json:"caseID"`
+}
+
+// FileCustodyResponse is the output-object for getting the chain of custody for a
+// file
+type FileCustodyResponse struct {
+	// Events in the chain of custody, the oldest event first
+	Events []CustodyEvent `json:"events"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 // FileDeleteRequest is the input-object for deleting a file
 type FileDeleteRequest struct {
 	// ID of the file to delete
@@ -1918,6 +3150,44 @@ type FileDeleteResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// FileListRequest is the input-object for listing the files in a case
+type FileListRequest struct {
+	// CaseID of the case to list the files in
+	CaseID string `json:"caseID"`
+	// ListOptions for the page of files, they can be sorted by "date" (the default),
+	// "name" and "size", the files are filtered by the fields of the request below
+	// instead of the Filters
+	ListOptions ListOptions `json:"listOptions"`
+	// Mime only lists the files with the mime-type, "image/*" lists every image
+	Mime string `json:"mime"`
+	// State only lists the files that are "processed" or "unprocessed"
+	State string `json:"state"`
+	// Keyword only lists the files with the keyword
+	Keyword string `json:"keyword"`
+	// UploadedFrom only lists the files uploaded after the unix-timestamp
+	UploadedFrom int64 `json:"uploadedFrom"`
+	// UploadedTo only lists the files uploaded before the unix-timestamp
+	UploadedTo int64 `json:"uploadedTo"`
+	// Uploader only lists the files uploaded by the user, the ID or the email
+	Uploader string `json:"uploader"`
+	// MinSize only lists the files that are at least the size in bytes
+	MinSize int `json:"minSize"`
+	// MaxSize only lists the files that are at most the size in bytes
+	MaxSize int `json:"maxSize"`
+}
+
+// FileListResponse is the output-object for listing the files in a case
+type FileListResponse struct {
+	// Total number of files that matches the filters
+	Total int `json:"total"`
+	// Files for the requested page
+	Files []File `json:"files"`
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 // FileNewRequest is the input-object for creating a new file
 type FileNewRequest struct {
 	// CaseID of the case to upload the file
@@ -1978,10 +3248,74 @@ type FileProcessedRequest struct {
 	CaseID string `json:"caseID"`
 }
 
+// ProcessedDocument is the text and metadata extracted from a processed file,
+// or from a file in a processed container
+type ProcessedDocument struct {
+	// ID of the document, it is the same as the FileID unless the document was
+	// extracted from a container
+	ID string `json:"id"`
+	// FileID of the uploaded file the document is from
+	FileID string `json:"fileID"`
+	// ParentID is the ID of the document for the container the document was extracted
+	// from, empty for the uploaded file
+	ParentID string `json:"parentID"`
+	// ParentPath is the path of the container the document was extracted from
+	ParentPath string `json:"parentPath"`
+	// Depth of the document in the containers, 0 for the uploaded file
+	Depth int `json:"depth"`
+	// Path of the document in the uploaded file
+	Path string `json:"path"`
+	// Name of the file
+	Name string `json:"name"`
+	// Extension of the file
+	Extension string `json:"extension"`
+	// ContentType of the file
+	ContentType string `json:"contentType"`
+	// Size of the file in bytes
+	Size int64 `json:"size"`
+	// Content is the extracted text
+	Content string `json:"content"`
+	// Title of the document, the subject for emails
+	Title string `json:"title"`
+	// Author of the document, the sender for emails
+	Author string `json:"author"`
+	// Modifier is who last modified the document
+	Modifier string `json:"modifier"`
+	// Keywords for the document
+	Keywords []string `json:"keywords"`
+	// Language of the content, if it was detected by the indexer
+	Language string `json:"language"`
+	// Pages is the number of pages, 0 if it isn't known
+	Pages int `json:"pages"`
+	// CreatorTool is the application or camera that created the document
+	CreatorTool string `json:"creatorTool"`
+	// CreatedAt is the unix-timestamp for when the document was created, from its
+	// metadata
+	CreatedAt int64 `json:"createdAt"`
+	// ModifiedAt is the unix-timestamp for when the document was modified, or when the
+	// email was sent
+	ModifiedAt int64 `json:"modifiedAt"`
+	// FileModifiedAt is the unix-timestamp for when the file was last modified,
+	// as recorded by the container it was extracted from
+	FileModifiedAt int64 `json:"fileModifiedAt"`
+	// IndexedAt is the unix-timestamp for when the document was indexed
+	IndexedAt int64 `json:"indexedAt"`
+	// Metadata is the other metadata extracted from the document
+	Metadata map[string]string `json:"metadata"`
+	// Container is true if files were extracted from the document
+	Container bool `json:"container"`
+	// Children is the number of files in the container
+	Children int `json:"children"`
+	// ContainerError is the reason the container couldn't be expanded
+	ContainerError string `json:"containerError"`
+	// Error is the reason the content couldn't be extracted
+	Error string `json:"error"`
+}
+
 // FileProcessedResponse is the output-object for get a processed file in a case
 type FileProcessedResponse struct {
-	ID        string      `json:"id"`
-	Processed interface{} `json:"processed"`
+	ID        string            `json:"id"`
+	Processed ProcessedDocument `json:"processed"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -1994,7 +3328,7 @@ type FileProcessesRequest struct {
 
 // FileProcessesResponse is the output-object for get a Processes file in a case
 type FileProcessesResponse struct {
-	Processes interface{} `json:"processes"`
+	Processes []ProcessedDocument `json:"processes"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -2007,6 +3341,9 @@ type FileUpdateRequest struct {
 	CaseID string `json:"caseID"`
 	// Description of the file
 	Description string `json:"description"`
+	// Version of the file when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // FileUpdateResponse is the output-object for updating a files information
@@ -2016,6 +3353,25 @@ type FileUpdateResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// FileVerifyRequest is the input-object for verifying the hashes of a file
+type FileVerifyRequest struct {
+	// ID of the file to verify
+	ID string `json:"id"`
+	// CaseID of the case where the file belongs
+	CaseID string `json:"caseID"`
+}
+
+// FileVerifyResponse is the output-object for verifying the hashes of a file
+type FileVerifyResponse struct {
+	// Verified is true if every stored hash matches the file
+	Verified bool `json:"verified"`
+	// Mismatches are the hashes that doesn't match the file
+	Mismatches []string `json:"mismatches"`
+	File       File     `json:"file"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 // Keyword represents a keyword in used for a case
 type Keyword struct {
 	// Name of the keyword
@@ -2081,6 +3437,9 @@ type LinkAddRequest struct {
 	EntityIDs []string `json:"entityIDs"`
 	// FileIDs of the files to be added to the link
 	FileIDs []string `json:"fileIDs"`
+	// Version of the link when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // LinkAddResponse is the output-object for linking objects with an event
@@ -2160,6 +3519,9 @@ type LinkRemoveRequest struct {
 	EntityIDs []string `json:"entityIDs"`
 	// FileIDs of the files to be removed from the link
 	FileIDs []string `json:"fileIDs"`
+	// Version of the link when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // LinkRemoveResponse is the output-object for removing linked objects from a link
@@ -2230,11 +3592,19 @@ type PersonGetResponse struct {
 type PersonListRequest struct {
 	// CaseID of the case to listen all persons
 	CaseID string `json:"caseID"`
+	// ListOptions for the page of persons, they can be sorted by "created",
+	// "firstName", "lastName" and "emailAddress", and filtered by "emailAddress" and
+	// "keyword"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // PersonListResponse is the output-object for listing all persons for a case
 type PersonListResponse struct {
 	Persons []Person `json:"persons"`
+	// Total number of persons that matches the filters
+	Total int `json:"total"`
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -2259,6 +3629,9 @@ type PersonUpdateRequest struct {
 	TelephoneNo string `json:"telephoneNo"`
 	// Custom is a free form with key-value pairs specified by the user.
 	Custom map[string]interface{} `json:"custom"`
+	// Version of the person when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // PersonUpdateResponse is the output-object for updating an existing person
@@ -2279,6 +3652,8 @@ type ProcessAbortRequest struct {
 // ProcessAbortResponse is the output-object for aborting a processing-job
 type ProcessAbortResponse struct {
 	Aborted Process `json:"aborted"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
 }
 
 // ProcessJobsRequest is the input-object for getting all processing-jobs for a
@@ -2292,6 +3667,8 @@ type ProcessJobsRequest struct {
 // case
 type ProcessJobsResponse struct {
 	Processes []Process `json:"processes"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
 }
 
 // ProcessPauseRequest is the input-object for pausing a processing-job
@@ -2305,6 +3682,23 @@ type ProcessPauseRequest struct {
 // ProcessPauseResponse is the output-object for pausing a processing-job
 type ProcessPauseResponse struct {
 	Paused Process `json:"paused"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// ProcessResumeRequest is the input-object for resuming a paused processing-job
+type ProcessResumeRequest struct {
+	// ID of the processing-job to resume
+	ID string `json:"id"`
+	// CaseID of the case the processing-job belongs to
+	CaseID string `json:"caseID"`
+}
+
+// ProcessResumeResponse is the output-object for resuming a paused processing-job
+type ProcessResumeResponse struct {
+	Resumed Process `json:"resumed"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
 }
 
 // ProcessStartRequest is the input-object for starting a processing-job
@@ -2318,6 +3712,8 @@ type ProcessStartRequest struct {
 // ProcessStartResponse is the output-object for starting a processing-job
 type ProcessStartResponse struct {
 	Started Process `json:"started"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
 }
 
 // SearchTextRequest is the input-object for searching items
@@ -2330,11 +3726,11 @@ type SearchTextRequest struct {
 
 // SearchTextResponse is the output-object for searching items
 type SearchTextResponse struct {
-	Events    []Event     `json:"events"`
-	Entities  []Entity    `json:"entities"`
-	Persons   []Person    `json:"persons"`
-	Files     []File      `json:"files"`
-	Processed interface{} `json:"processed"`
+	Events    []Event             `json:"events"`
+	Entities  []Entity            `json:"entities"`
+	Persons   []Person            `json:"persons"`
+	Files     []File              `json:"files"`
+	Processed []ProcessedDocument `json:"processed"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -2347,11 +3743,114 @@ type SearchTimespanRequest struct {
 	FromDate int64 `json:"fromDate"`
 	// ToDate is the unix-timestamp of where the timespan finishes
 	ToDate int64 `json:"toDate"`
+	// ListOptions for the page of events, they are sorted by "date" (the FromDate) and
+	// cannot be filtered
+	ListOptions ListOptions `json:"listOptions"`
+	// IncludeFiles will also return the files that were processed, or has extracted
+	// document-dates, within the timespan. The files are only returned with the first
+	// page
+	IncludeFiles bool `json:"includeFiles"`
 }
 
 // SearchTimespanResponse is the output-object for searching items
 type SearchTimespanResponse struct {
+	// Total number of events within the timespan
+	Total int `json:"total"`
+	// Events within the timespan for the requested page
 	Events []Event `json:"events"`
+	// NextCursor gets the next page of events, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
+	// Files within the timespan, only on the first page if IncludeFiles was requested
+	Files []File `json:"files"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// Suggestion is a person or an event that is suggested from a processed file,
+// it is created in the case when an investigator accepts it
+type Suggestion struct {
+	Base
+	// Type of the suggestion, "person" or "event"
+	Type string `json:"type"`
+	// Status of the suggestion, "pending", "accepted" or "rejected"
+	Status string `json:"status"`
+	// Source is what the suggestion was extracted from, "email" for the headers in
+	// emails, or "metadata" for the dates in the metadata
+	Source string `json:"source"`
+	// Field is the field in the source that has the value, the email-header or the
+	// metadata-field in the processed document, like meta.created
+	Field string `json:"field"`
+	// FileID of the processed file the suggestion is from
+	FileID string `json:"fileID"`
+	// DocumentID of the processed document, it is the same as the FileID unless the
+	// document was extracted from a container
+	DocumentID string `json:"documentID"`
+	// Path of the document in the file
+	Path string `json:"path"`
+	// Person that is suggested
+	Person Person `json:"person"`
+	// Event that is suggested
+	Event Event `json:"event"`
+	// PersonIDs of the existing persons to link the suggested event to
+	PersonIDs []string `json:"personIDs"`
+	// PersonSuggestionIDs of the suggested persons to link the suggested event to,
+	// they are accepted with the event unless they are rejected
+	PersonSuggestionIDs []string `json:"personSuggestionIDs"`
+	// AcceptedID is the ID of the person or event that was created
+	AcceptedID string `json:"acceptedID"`
+	// ReviewerEmail is the email of the investigator who reviewed the suggestion
+	ReviewerEmail string `json:"reviewerEmail"`
+	// ReviewedAt is the unix-timestamp for when the suggestion was reviewed
+	ReviewedAt int64 `json:"reviewedAt"`
+}
+
+// SuggestionAcceptRequest is the input-object for accepting suggestions
+type SuggestionAcceptRequest struct {
+	// CaseID of the suggestions
+	CaseID string `json:"caseID"`
+	// IDs of the suggestions to accept
+	IDs []string `json:"iDs"`
+}
+
+// SuggestionAcceptResponse is the output-object for accepting suggestions
+type SuggestionAcceptResponse struct {
+	Accepted []Suggestion `json:"accepted"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// SuggestionListRequest is the input-object for listing the suggestions for a case
+type SuggestionListRequest struct {
+	// CaseID of the case to list the suggestions for
+	CaseID string `json:"caseID"`
+	// Type of the suggestions to list, every type is listed if it's empty
+	Type string `json:"type"`
+	// Status of the suggestions to list, every status is listed if it's empty
+	Status string `json:"status"`
+	// FileID of the file to list the suggestions for, every file is listed if it's
+	// empty
+	FileID string `json:"fileID"`
+}
+
+// SuggestionListResponse is the output-object for listing the suggestions for a
+// case
+type SuggestionListResponse struct {
+	Suggestions []Suggestion `json:"suggestions"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// SuggestionRejectRequest is the input-object for rejecting suggestions
+type SuggestionRejectRequest struct {
+	// CaseID of the suggestions
+	CaseID string `json:"caseID"`
+	// IDs of the suggestions to reject
+	IDs []string `json:"iDs"`
+}
+
+// SuggestionRejectResponse is the output-object for rejecting suggestions
+type SuggestionRejectResponse struct {
+	Rejected []Suggestion `json:"rejected"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
@@ -2392,6 +3891,82 @@ type TestDeleteUserResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+// Token is a long-lived API-token that has access to specific cases with a role
+type Token struct {
+	Base
+	// Name of the token
+	Name string `json:"name"`
+	// CreatorID is the user-id of the user who created the token
+	CreatorID string `json:"creatorID"`
+	// CreatorEmail is the email of the user who created the token
+	CreatorEmail string `json:"creatorEmail"`
+	// Prefix is the start of the token, to be able to recognize it
+	Prefix string `json:"prefix"`
+	// CaseIDs are the IDs of the cases the token has access to
+	CaseIDs []string `json:"caseIDs"`
+	// Role the token has in the cases, "editor", "viewer" or "auditor"
+	Role string `json:"role"`
+	// ExpiresAt is the unix-date when the token expires (0 means that it never
+	// expires)
+	ExpiresAt int64 `json:"expiresAt"`
+	// RevokedAt is the unix-date when the token was revoked
+	RevokedAt int64 `json:"revokedAt"`
+	// LastUsedAt is the unix-date when the token was last used, the usage is recorded
+	// at most once a minute
+	LastUsedAt int64 `json:"lastUsedAt"`
+	// UseCount is the number of requests made with the token, the requests since the
+	// usage was recorded are counted with the next request after a minute
+	UseCount int `json:"useCount"`
+}
+
+// TokenCreateRequest is the input-object for creating an API-token
+type TokenCreateRequest struct {
+	// Name of the token
+	Name string `json:"name"`
+	// CaseIDs are the IDs of the cases the token has access to
+	CaseIDs []string `json:"caseIDs"`
+	// Role the token has in the cases, "editor", "viewer" or "auditor"
+	Role string `json:"role"`
+	// ExpiresAt is the unix-date when the token expires (0 means that it never
+	// expires)
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// TokenCreateResponse is the output-object for creating an API-token
+type TokenCreateResponse struct {
+	// Token to use in the authorization-header, it is only returned once
+	Token string `json:"token"`
+	New   Token  `json:"new"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// TokenListRequest is the input-object for listing API-tokens
+type TokenListRequest struct {
+	// CaseID to only list the tokens for a specific case
+	CaseID string `json:"caseID"`
+}
+
+// TokenListResponse is the output-object for listing API-tokens
+type TokenListResponse struct {
+	Tokens []Token `json:"tokens"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+// TokenRevokeRequest is the input-object for revoking an API-token
+type TokenRevokeRequest struct {
+	// ID of the token to revoke
+	ID string `json:"id"`
+}
+
+// TokenRevokeResponse is the output-object for revoking an API-token
+type TokenRevokeResponse struct {
+	Revoked Token `json:"revoked"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 // User holds information for a user in the timeline-investigator
 type User struct {
 	DisplayName string `json:"displayName"`
@@ -2400,4 +3975,6 @@ type User struct {
 	PhotoURL    string `json:"photoURL"`
 	ProviderID  string `json:"providerID"`
 	UID         string `json:"uID"`
+	// Admin is true if the user is a system-administrator
+	Admin bool `json:"admin"`
 }
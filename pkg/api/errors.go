@@ -23,8 +23,23 @@ var (
 	// ErrInvalidImportance is when the importance for an event is invalid
 	ErrInvalidImportance = errors.New("importance must be a number between 1 - 5")
 
+	// ErrLastInvestigator is used when trying to remove
+	// the last investigator from a case
+	ErrLastInvestigator = errors.New("cannot remove the last investigator of the case")
+
+	// ErrLastOwner is used when trying to remove or
+	// demote the last owner of a case
+	ErrLastOwner = errors.New("cannot remove or demote the last owner of the case")
+
+	// ErrInvalidRole is used when trying to use a non existing role
+	ErrInvalidRole = errors.New("invalid role - use owner, editor, viewer or auditor")
+
 	// ErrInvalidEntityType is an error occuring when trying to use a non existing entity-type
 	ErrInvalidEntityType = errors.New("invalid entity-type - list all available entity-types with: EntityService.Types")
+
+	// ErrConflict is used when an update is made with a version of
+	// the object, and the object has been changed since that version
+	ErrConflict = errors.New("conflict - the object has been changed since it was read")
 )
 
 // Error wraps an error with an internal-error
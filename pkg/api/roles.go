@@ -0,0 +1,28 @@
+package api
+
+const (
+	// RoleOwner can do everything in a case,
+	// including managing the investigators
+	RoleOwner = "owner"
+
+	// RoleEditor can create, update and
+	// delete the evidence in a case
+	RoleEditor = "editor"
+
+	// RoleViewer can only browse a case
+	RoleViewer = "viewer"
+
+	// RoleAuditor can only see the case, the files and
+	// their chain of custody, and verify the hashes of the
+	// files - used for auditing the handling of the evidence
+	RoleAuditor = "auditor"
+)
+
+// ValidRole returns true if the role exists
+func ValidRole(role string) bool {
+	switch role {
+	case RoleOwner, RoleEditor, RoleViewer, RoleAuditor:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package authentication
+
+import (
+	"crypto"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway is the allowed clock-skew when validating the times of a token
+const leeway = time.Minute
+
+// claims are the claims in the payload of a JWT
+type claims map[string]interface{}
+
+// hmacMethods are the signing-algorithms for the tokens
+// from the local-provider, signed with the signing-key
+var hmacMethods = []string{jwt.SigningMethodHS256.Alg()}
+
+// publicKeyMethods are the signing-algorithms for the
+// tokens from an OIDC-provider, signed with its keys
+var publicKeyMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+}
+
+// parseHMAC verifies a HS256-token and its claims, the
+// issuer and audience are only validated if specified
+func parseHMAC(token string, key []byte, issuer, audience string) (claims, error) {
+	return parse(token, hmacMethods, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, issuer, audience)
+}
+
+// parsePublicKey verifies a RS256, RS384, RS512 or ES256-token and its
+// claims, the key is looked up by the key-id in the header of the token
+func parsePublicKey(token string, key func(kid string) (crypto.PublicKey, error), issuer, audience string) (claims, error) {
+	return parse(token, publicKeyMethods, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return key(kid)
+	}, issuer, audience)
+}
+
+// parse verifies the signature of the token with one of the
+// methods, and validates the times, issuer and audience
+func parse(token string, methods []string, key jwt.Keyfunc, issuer, audience string) (claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+
+	c := jwt.MapClaims{}
+	if _, err := jwt.NewParser(options...).ParseWithClaims(token, c, key); err != nil {
+		return nil, err
+	}
+	return claims(c), nil
+}
+
+// string returns the claim as a string
+func (c claims) string(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// bool returns the claim as a bool, some providers
+// (like AWS Cognito) has the bool-claims as strings
+func (c claims) bool(name string) bool {
+	switch value := c[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+// signHMAC signs the claims as a HS256-token
+func signHMAC(c claims, key []byte) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims(c)).SignedString(key)
+}
@@ -0,0 +1,301 @@
+package authentication
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// localIssuer is the issuer of the tokens from the local-provider
+	localIssuer = "timeline-investigator"
+
+	// custom-tokens are exchanged for an id-token
+	// and are only valid for a short time
+	customTokenTTL = 5 * time.Minute
+
+	// pbkdf2Iterations is the number of iterations when hashing passwords
+	pbkdf2Iterations = 100000
+)
+
+// local signs the tokens with a configured
+// key and stores the users in the datastore
+type local struct {
+	db       datastore.Service
+	key      []byte
+	tokenTTL time.Duration
+}
+
+// NewLocal creates an authentication service that doesn't
+// depend on any external provider, the tokens are signed
+// with the signing-key and are valid for the tokenTTL
+func NewLocal(db datastore.Service, signingKey string, tokenTTL time.Duration) (Service, error) {
+	if len(signingKey) < 32 {
+		return nil, errors.New("the signing-key must be at least 32 characters")
+	}
+	if tokenTTL <= 0 {
+		tokenTTL = time.Hour
+	}
+	return &local{db: db, key: []byte(signingKey), tokenTTL: tokenTTL}, nil
+}
+
+// Verify validates the token
+func (s *local) Verify(ctx context.Context, idToken string) error {
+	if _, err := s.verify(idToken, "id"); err != nil {
+		return fmt.Errorf("failed to get user id: %v", err)
+	}
+	return nil
+}
+
+// Create creates a test-user
+func (s *local) Create(ctx context.Context, uid, email, name, password string) (*User, error) {
+	if uid == "" {
+		uid = uuid.New().String()
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &datastore.User{
+		UID:          uid,
+		Email:        email,
+		DisplayName:  name,
+		PasswordHash: hash,
+		// Set testClaims - so we know that the user was created from a test
+		CustomClaims: map[string]interface{}{"TestAt": time.Now(), "Test": true},
+	}
+	if err := s.db.CreateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	return localUser(user), nil
+}
+
+// CreateUser creates a user without the test-claims,
+// it's used by the admins to add users to the system
+func (s *local) CreateUser(ctx context.Context, email, name, password string) (*User, error) {
+	if len(password) < MinPasswordLength {
+		return nil, ErrWeakPassword
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &datastore.User{
+		UID:          uuid.New().String(),
+		Email:        email,
+		DisplayName:  name,
+		PasswordHash: hash,
+	}
+	if err := s.db.CreateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	return localUser(user), nil
+}
+
+// SetPassword sets a new password for the user
+func (s *local) SetPassword(ctx context.Context, uid, password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrWeakPassword
+	}
+
+	user, err := s.db.GetUserByID(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %v", err)
+	}
+
+	user.PasswordHash, err = hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.db.UpdateUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %v", err)
+	}
+	return nil
+}
+
+// DeleteUser deletes the user
+func (s *local) DeleteUser(ctx context.Context, uid string) error {
+	if _, err := s.db.GetUserByID(ctx, uid); err != nil {
+		return fmt.Errorf("failed to get user: %v", err)
+	}
+	return s.db.DeleteUser(ctx, uid)
+}
+
+// SignIn signs in the user with email and password
+func (s *local) SignIn(ctx context.Context, email, password string) (string, error) {
+	user, err := s.db.GetUserByEmail(ctx, email)
+	if err != nil {
+		// hash the password anyway to not reveal
+		// which users that exists by the response-time
+		hashPassword(password)
+		return "", errors.New("invalid email or password")
+	}
+	if !checkPassword(user.PasswordHash, password) {
+		return "", errors.New("invalid email or password")
+	}
+	return s.sign(user.UID, "id", s.tokenTTL)
+}
+
+// GetCustomToken creates a custom-token for the user
+func (s *local) GetCustomToken(ctx context.Context, uid string) (string, error) {
+	if _, err := s.db.GetUserByID(ctx, uid); err != nil {
+		return "", fmt.Errorf("failed to get user: %v", err)
+	}
+	return s.sign(uid, "custom", customTokenTTL)
+}
+
+// VerifyCustomToken exchanges the custom-token for an id-token
+func (s *local) VerifyCustomToken(ctx context.Context, customToken string) (string, error) {
+	c, err := s.verify(customToken, "custom")
+	if err != nil {
+		return "", err
+	}
+	return s.sign(c.string("sub"), "id", s.tokenTTL)
+}
+
+// Delete deletes the user
+func (s *local) Delete(ctx context.Context, uid string) error {
+	return s.db.DeleteUser(ctx, uid)
+}
+
+// GetUserID returns the id of the user in the token
+func (s *local) GetUserID(ctx context.Context, idToken string) (string, error) {
+	c, err := s.verify(idToken, "id")
+	if err != nil {
+		return "", err
+	}
+	return c.string("sub"), nil
+}
+
+// GetUserByID returns the user with the id
+func (s *local) GetUserByID(ctx context.Context, uid string) (*User, error) {
+	user, err := s.db.GetUserByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return localUser(user), nil
+}
+
+// GetUserByToken returns the user in the token
+func (s *local) GetUserByToken(ctx context.Context, idToken string) (*User, error) {
+	uid, err := s.GetUserID(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUserByID(ctx, uid)
+}
+
+// DeleteUserByID deletes the user
+func (s *local) DeleteUserByID(ctx context.Context, uid string) error {
+	return s.Delete(ctx, uid)
+}
+
+// sign creates a token of the type for the user
+func (s *local) sign(uid, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return signHMAC(claims{
+		"iss":  localIssuer,
+		"sub":  uid,
+		"typ":  tokenType,
+		"iat":  now.Unix(),
+		"exp":  now.Add(ttl).Unix(),
+		"jti":  uuid.New().String(),
+		"auth": ProviderLocal,
+	}, s.key)
+}
+
+// verify verifies the token and that it is of the type
+func (s *local) verify(token, tokenType string) (claims, error) {
+	if len(token) == 0 {
+		return nil, errors.New("token is empty")
+	}
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	c, err := parseHMAC(token, s.key, localIssuer, "")
+	if err != nil {
+		return nil, err
+	}
+	if c.string("typ") != tokenType {
+		return nil, fmt.Errorf("expected a %s-token", tokenType)
+	}
+	if c.string("sub") == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return c, nil
+}
+
+func localUser(user *datastore.User) *User {
+	// The local users are created by the admins or with
+	// the secret for the test-service, so their emails are trusted
+	return &User{
+		UID:           user.UID,
+		Email:         user.Email,
+		EmailVerified: true,
+		DisplayName:   user.DisplayName,
+		ProviderID:    ProviderLocal,
+		CustomClaims:  user.CustomClaims,
+	}
+}
+
+// hashPassword hashes the password with PBKDF2-SHA256 and
+// a random salt, in the format: pbkdf2-sha256$iterations$salt$hash
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %v", err)
+	}
+
+	hash, err := pbkdf2.Key(sha256.New, password, salt, pbkdf2Iterations, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %v", err)
+	}
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s",
+		pbkdf2Iterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// checkPassword checks the password against the hash
+func checkPassword(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	actual, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(hash))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(actual, hash) == 1
+}
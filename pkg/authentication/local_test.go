@@ -0,0 +1,220 @@
+package authentication_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+
+	"github.com/matryer/is"
+)
+
+// testDB stores the users in memory
+type testDB struct {
+	datastore.Service
+	users map[string]*datastore.User
+}
+
+func (db testDB) CreateUser(ctx context.Context, user *datastore.User) error {
+	db.users[user.UID] = user
+	return nil
+}
+
+func (db testDB) GetUserByID(ctx context.Context, uid string) (*datastore.User, error) {
+	if user, ok := db.users[uid]; ok {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (db testDB) GetUserByEmail(ctx context.Context, email string) (*datastore.User, error) {
+	for _, user := range db.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (db testDB) UpdateUser(ctx context.Context, user *datastore.User) error {
+	db.users[user.UID] = user
+	return nil
+}
+
+func (db testDB) DeleteUser(ctx context.Context, uid string) error {
+	delete(db.users, uid)
+	return nil
+}
+
+func TestLocal(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	db := testDB{users: make(map[string]*datastore.User)}
+	signingKey := strings.Repeat("k", 32)
+
+	_, err := authentication.NewLocal(db, "too-short", time.Hour)
+	is.True(err != nil)
+
+	authService, err := authentication.NewLocal(db, signingKey, time.Hour)
+	is.NoErr(err)
+
+	user, err := authService.Create(ctx, "user-1", "sja@avian.dk", "Simon", "supersecret")
+	is.NoErr(err)
+	is.True(!strings.Contains(db.users[user.UID].PasswordHash, "supersecret"))
+
+	// Sign in with the password
+	_, err = authService.SignIn(ctx, "sja@avian.dk", "wrong-password")
+	is.True(err != nil)
+	idToken, err := authService.SignIn(ctx, "sja@avian.dk", "supersecret")
+	is.NoErr(err)
+
+	usr, err := authService.GetUserByToken(ctx, idToken)
+	is.NoErr(err)
+	is.Equal(usr.UID, "user-1")
+	is.Equal(usr.Email, "sja@avian.dk")
+	is.Equal(usr.CustomClaims["Test"], true)
+
+	// Custom-tokens are exchanged for id-tokens
+	customToken, err := authService.GetCustomToken(ctx, user.UID)
+	is.NoErr(err)
+	is.True(authService.Verify(ctx, customToken) != nil)
+	idToken, err = authService.VerifyCustomToken(ctx, customToken)
+	is.NoErr(err)
+	is.NoErr(authService.Verify(ctx, idToken))
+
+	// Tokens signed with another key
+	otherService, err := authentication.NewLocal(db, strings.Repeat("x", 32), time.Hour)
+	is.NoErr(err)
+	otherToken, err := otherService.SignIn(ctx, "sja@avian.dk", "supersecret")
+	is.NoErr(err)
+	is.True(authService.Verify(ctx, otherToken) != nil)
+
+	// Unsigned, expired and HS512-tokens
+	sign := func(alg string, claims map[string]interface{}) string {
+		header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+		payload, _ := json.Marshal(claims)
+		signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		var mac hash.Hash
+		switch alg {
+		case "HS256":
+			mac = hmac.New(sha256.New, []byte(signingKey))
+		case "HS512":
+			mac = hmac.New(sha512.New, []byte(signingKey))
+		default:
+			return signed + "."
+		}
+		mac.Write([]byte(signed))
+		return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	}
+	claims := map[string]interface{}{
+		"iss": "timeline-investigator",
+		"sub": user.UID,
+		"typ": "id",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	is.NoErr(authService.Verify(ctx, sign("HS256", claims)))
+	is.True(authService.Verify(ctx, sign("none", claims)) != nil)
+	is.True(authService.Verify(ctx, sign("HS512", claims)) != nil)
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	is.True(authService.Verify(ctx, sign("HS256", claims)) != nil)
+	delete(claims, "exp")
+	is.True(authService.Verify(ctx, sign("HS256", claims)) != nil)
+
+	is.NoErr(authService.Delete(ctx, user.UID))
+	_, err = authService.GetUserByToken(ctx, idToken)
+	is.True(err != nil)
+}
+
+// TestLocalPasswordHash checks the password-hashes
+// against the PBKDF2-HMAC-SHA256 vectors in RFC 7914
+func TestLocalPasswordHash(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	db := testDB{users: make(map[string]*datastore.User)}
+	authService, err := authentication.NewLocal(db, strings.Repeat("k", 32), time.Hour)
+	is.NoErr(err)
+
+	for _, vector := range []struct {
+		password, salt string
+		iterations     int
+		key            string
+	}{
+		{
+			password:   "passwd",
+			salt:       "salt",
+			iterations: 1,
+			key:        "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password:   "Password",
+			salt:       "NaCl",
+			iterations: 80000,
+			key:        "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	} {
+		key, err := hex.DecodeString(vector.key)
+		is.NoErr(err)
+		db.users["user-1"] = &datastore.User{
+			UID:   "user-1",
+			Email: "sja@avian.dk",
+			PasswordHash: fmt.Sprintf("pbkdf2-sha256$%d$%s$%s",
+				vector.iterations,
+				base64.RawStdEncoding.EncodeToString([]byte(vector.salt)),
+				base64.RawStdEncoding.EncodeToString(key),
+			),
+		}
+		_, err = authService.SignIn(ctx, "sja@avian.dk", vector.password)
+		is.NoErr(err)
+		_, err = authService.SignIn(ctx, "sja@avian.dk", vector.password+"x")
+		is.True(err != nil)
+	}
+}
+
+func TestLocalUserManager(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	db := testDB{users: make(map[string]*datastore.User)}
+	authService, err := authentication.NewLocal(db, strings.Repeat("k", 32), time.Hour)
+	is.NoErr(err)
+	manager, ok := authService.(authentication.UserManager)
+	is.True(ok)
+
+	_, err = manager.CreateUser(ctx, "sja@avian.dk", "Simon", "short")
+	is.True(errors.Is(err, authentication.ErrWeakPassword))
+
+	// Users created by the admins aren't test-users
+	user, err := manager.CreateUser(ctx, "sja@avian.dk", "Simon", "correct horse battery")
+	is.NoErr(err)
+	is.True(user.UID != "")
+	is.Equal(user.CustomClaims, nil)
+	_, err = authService.SignIn(ctx, "sja@avian.dk", "correct horse battery")
+	is.NoErr(err)
+
+	// Reset the password
+	is.True(errors.Is(manager.SetPassword(ctx, user.UID, "short"), authentication.ErrWeakPassword))
+	is.NoErr(manager.SetPassword(ctx, user.UID, "staple battery horse"))
+	_, err = authService.SignIn(ctx, "sja@avian.dk", "correct horse battery")
+	is.True(err != nil)
+	_, err = authService.SignIn(ctx, "sja@avian.dk", "staple battery horse")
+	is.NoErr(err)
+
+	is.NoErr(manager.DeleteUser(ctx, user.UID))
+	is.True(manager.DeleteUser(ctx, user.UID) != nil)
+	_, err = authService.SignIn(ctx, "sja@avian.dk", "staple battery horse")
+	is.True(err != nil)
+}
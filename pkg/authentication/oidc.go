@@ -0,0 +1,277 @@
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oidc verifies tokens issued by an OpenID Connect provider,
+// users are managed by the provider and not by the TI-API
+type oidc struct {
+	issuer   string
+	audience string
+	jwksURL  string
+	client   *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	refreshed time.Time
+}
+
+// minRefreshInterval is the minimum time between refreshing the
+// keys, so that unknown key-ids cannot flood the provider
+const minRefreshInterval = time.Minute
+
+// NewOIDC creates an authentication service that verifies the
+// tokens against the keys published by the OpenID Connect-issuer
+// (for example Keycloak or Azure AD), the audience is the client-id
+// the tokens must be issued for
+func NewOIDC(ctx context.Context, issuerURL, audience string) (Service, error) {
+	s := &oidc{
+		issuer:   strings.TrimSuffix(issuerURL, "/"),
+		audience: audience,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+
+	// Discover where the keys are published
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURL string `json:"jwks_uri"`
+	}
+	if err := s.get(ctx, s.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover the oidc-provider: %v", err)
+	}
+	if discovery.JWKSURL == "" {
+		return nil, errors.New("the oidc-provider has no jwks_uri")
+	}
+	if discovery.Issuer != "" {
+		s.issuer = discovery.Issuer
+	}
+	s.jwksURL = discovery.JWKSURL
+
+	if err := s.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Verify validates the token
+func (s *oidc) Verify(ctx context.Context, idToken string) error {
+	_, err := s.verify(ctx, idToken)
+	return err
+}
+
+// GetUserID returns the subject of the token
+func (s *oidc) GetUserID(ctx context.Context, idToken string) (string, error) {
+	c, err := s.verify(ctx, idToken)
+	if err != nil {
+		return "", err
+	}
+	return c.string("sub"), nil
+}
+
+// GetUserByToken returns the user from the claims in the token
+func (s *oidc) GetUserByToken(ctx context.Context, idToken string) (*User, error) {
+	c, err := s.verify(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	name := c.string("name")
+	if name == "" {
+		name = c.string("preferred_username")
+	}
+	// The email is only verified if the provider says so, some
+	// providers lets the users set the email to any address
+	email := c.string("email")
+	verified := email != "" && c.bool("email_verified")
+	if email == "" {
+		// Azure AD has the email as the upn for some accounts,
+		// it's never treated as verified
+		email = c.string("upn")
+	}
+
+	return &User{
+		UID:           c.string("sub"),
+		Email:         email,
+		EmailVerified: verified,
+		DisplayName:   name,
+		PhoneNumber:   c.string("phone_number"),
+		PhotoURL:      c.string("picture"),
+		ProviderID:    s.issuer,
+		CustomClaims:  c,
+	}, nil
+}
+
+// Users are managed by the oidc-provider
+func (s *oidc) Create(ctx context.Context, uid, email, name, password string) (*User, error) {
+	return nil, ErrNotSupported
+}
+
+// SignIn is done against the oidc-provider
+func (s *oidc) SignIn(ctx context.Context, email, password string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *oidc) GetCustomToken(ctx context.Context, uid string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *oidc) VerifyCustomToken(ctx context.Context, customToken string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (s *oidc) Delete(ctx context.Context, uid string) error { return ErrNotSupported }
+
+func (s *oidc) GetUserByID(ctx context.Context, uid string) (*User, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *oidc) DeleteUserByID(ctx context.Context, uid string) error { return ErrNotSupported }
+
+// verify verifies the signature and the claims of the token
+func (s *oidc) verify(ctx context.Context, idToken string) (claims, error) {
+	if len(idToken) == 0 {
+		return nil, errors.New("token is empty")
+	}
+	idToken = strings.TrimPrefix(idToken, "Bearer ")
+
+	return parsePublicKey(idToken, func(kid string) (crypto.PublicKey, error) {
+		return s.key(ctx, kid)
+	}, s.issuer, s.audience)
+}
+
+// key returns the key with the id, the keys are
+// refreshed if the provider has rotated its keys
+func (s *oidc) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	refreshed := s.refreshed
+	s.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if time.Since(refreshed) < minRefreshInterval {
+		return nil, fmt.Errorf("unknown signing-key: %q", kid)
+	}
+
+	if err := s.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing-key: %q", kid)
+}
+
+// jwk is a JSON Web Key
+type jwk struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// refreshKeys gets the signing-keys from the provider
+func (s *oidc) refreshKeys(ctx context.Context) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := s.get(ctx, s.jwksURL, &set); err != nil {
+		return fmt.Errorf("failed to get the signing-keys: %v", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// skip keys that we cannot use
+			continue
+		}
+		keys[k.KeyID] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.refreshed = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// publicKey decodes the public key of the jwk
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := func(value string) (*big.Int, error) {
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(data), nil
+	}
+
+	switch k.KeyType {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Curve)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key-type: %s", k.KeyType)
+}
+
+func (s *oidc) get(ctx context.Context, url string, dest interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response: %v", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,130 @@
+package authentication_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
+
+	"github.com/matryer/is"
+)
+
+func TestOIDC(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	is.NoErr(err)
+
+	// Serve the discovery-document and the keys
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   server.URL,
+			"jwks_uri": server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key-1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	authService, err := authentication.NewOIDC(ctx, server.URL, "ti-api")
+	is.NoErr(err)
+
+	unsigned := func(alg string, claims map[string]interface{}) string {
+		header, _ := json.Marshal(map[string]string{"alg": alg, "kid": "key-1"})
+		payload, _ := json.Marshal(claims)
+		return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	}
+	sign := func(claims map[string]interface{}) string {
+		signed := unsigned("RS256", claims)
+		digest := sha256.Sum256([]byte(signed))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		is.NoErr(err)
+		return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	claims := map[string]interface{}{
+		"iss":                server.URL,
+		"aud":                []string{"ti-api"},
+		"sub":                "user-1",
+		"preferred_username": "Simon",
+		"upn":                "sja@avian.dk",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	}
+
+	user, err := authService.GetUserByToken(ctx, "Bearer "+sign(claims))
+	is.NoErr(err)
+	is.Equal(user.UID, "user-1")
+	is.Equal(user.Email, "sja@avian.dk")
+	is.Equal(user.DisplayName, "Simon")
+	is.True(!user.EmailVerified) // the upn is never verified
+
+	// The email is only verified if the provider has verified it
+	claims["email"] = "admin@avian.dk"
+	user, err = authService.GetUserByToken(ctx, "Bearer "+sign(claims))
+	is.NoErr(err)
+	is.Equal(user.Email, "admin@avian.dk")
+	is.True(!user.EmailVerified)
+
+	claims["email_verified"] = true
+	user, err = authService.GetUserByToken(ctx, "Bearer "+sign(claims))
+	is.NoErr(err)
+	is.True(user.EmailVerified)
+
+	// Expired tokens
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	is.True(authService.Verify(ctx, sign(claims)) != nil)
+
+	// Tokens for another audience
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	claims["aud"] = "another-api"
+	is.True(authService.Verify(ctx, sign(claims)) != nil)
+
+	// Tokens from another issuer
+	claims["aud"] = "ti-api"
+	claims["iss"] = "https://evil.com"
+	is.True(authService.Verify(ctx, sign(claims)) != nil)
+
+	// Tampered tokens
+	claims["iss"] = server.URL
+	token := sign(claims)
+	is.NoErr(authService.Verify(ctx, token))
+	is.True(authService.Verify(ctx, token[:len(token)-4]+"AAAA") != nil)
+
+	// Unsigned tokens
+	is.True(authService.Verify(ctx, unsigned("none", claims)+".") != nil)
+
+	// Tokens signed with the public key as a HS256-secret
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	is.NoErr(err)
+	for _, secret := range [][]byte{public, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})} {
+		signed := unsigned("HS256", claims)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		is.True(authService.Verify(ctx, signed+"."+base64.RawURLEncoding.EncodeToString(mac.Sum(nil))) != nil)
+	}
+}
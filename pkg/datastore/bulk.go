@@ -0,0 +1,153 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v7/esutil"
+)
+
+// The actions for the bulk-operations
+const (
+	BulkIndex  = "index"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// bulkWorkers is the number of workers for the bulk-indexer,
+// the operations are flushed when the bulk-request is done
+const bulkWorkers = 2
+
+// BulkOperation is a document to index, update or delete with
+// the other operations in Bulk. The Document for an update is
+// the body of the update, like a script with an upsert
+type BulkOperation struct {
+	Action   string
+	Index    string
+	ID       string
+	Document interface{}
+}
+
+// BulkFailure is an operation that failed in Bulk
+type BulkFailure struct {
+	Operation BulkOperation
+	Err       error
+}
+
+// BulkError is returned by Bulk if any of the operations failed,
+// the other operations are still made. It has the failed operations
+// so they can be reported or retried one by one, the operations
+// that conflicted with another change fail with ErrConflict
+type BulkError struct {
+	Failures []BulkFailure
+
+	// Errs are the errors for the flushes that failed,
+	// the operations in them aren't known by the indexer
+	Errs []error
+}
+
+func (e *BulkError) Error() string {
+	var errs []string
+	for _, failure := range e.Failures {
+		errs = append(errs, fmt.Sprintf("%s %s/%s: %v", failure.Operation.Action, failure.Operation.Index, failure.Operation.ID, failure.Err))
+	}
+	for _, err := range e.Errs {
+		errs = append(errs, err.Error())
+	}
+	return fmt.Sprintf("%d bulk-operations failed: %s", len(e.Failures)+len(e.Errs), strings.Join(errs, "; "))
+}
+
+// KeywordIndex returns the index for the keywords in the case
+func (svc) KeywordIndex(caseID string) string { return indexKeyword + "-" + caseID }
+
+// Bulk indexes, updates and deletes the documents with the bulk-indexer,
+// the indices are refreshed once when the operations are made instead
+// of for every document. A document that is deleted or updated but
+// doesn't exist isn't a failure, it's deleted already
+func (s svc) Bulk(ctx context.Context, operations []BulkOperation) error {
+	if len(operations) == 0 {
+		return nil
+	}
+
+	// The failures are reported by the workers
+	var mu sync.Mutex
+	bulkErr := &BulkError{}
+	fail := func(operation BulkOperation, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		bulkErr.Failures = append(bulkErr.Failures, BulkFailure{Operation: operation, Err: err})
+	}
+
+	indexer, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
+		Client:     s.es,
+		NumWorkers: bulkWorkers,
+		Refresh:    "true",
+		OnError: func(ctx context.Context, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			bulkErr.Errs = append(bulkErr.Errs, err)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create bulk-indexer: %v", err)
+	}
+
+	for _, operation := range operations {
+		operation := operation
+		item := esutil.BulkIndexerItem{
+			Index:      operation.Index,
+			Action:     operation.Action,
+			DocumentID: operation.ID,
+			OnFailure: func(ctx context.Context, item esutil.BulkIndexerItem, res esutil.BulkIndexerResponseItem, err error) {
+				if err == nil && operation.Action != BulkIndex && res.Status == http.StatusNotFound {
+					return
+				}
+				if err == nil && res.Status == http.StatusConflict {
+					err = fmt.Errorf("%w: %s", ErrConflict, res.Error.Reason)
+				} else if err == nil {
+					err = fmt.Errorf("[%d] %s: %s", res.Status, res.Error.Type, res.Error.Reason)
+				}
+				fail(operation, err)
+			},
+		}
+
+		switch operation.Action {
+		case BulkIndex:
+			dataJSON, err := documentJSON(operation.Index, operation.Document)
+			if err != nil {
+				fail(operation, err)
+				continue
+			}
+			item.Body = bytes.NewReader(dataJSON)
+		case BulkUpdate:
+			dataJSON, err := json.Marshal(operation.Document)
+			if err != nil {
+				fail(operation, err)
+				continue
+			}
+			item.Body = bytes.NewReader(dataJSON)
+		case BulkDelete:
+		default:
+			fail(operation, fmt.Errorf("invalid action %q", operation.Action))
+			continue
+		}
+
+		if err := indexer.Add(ctx, item); err != nil {
+			fail(operation, err)
+		}
+	}
+
+	if err := indexer.Close(ctx); err != nil {
+		return fmt.Errorf("cannot close bulk-indexer: %v", err)
+	}
+
+	if len(bulkErr.Failures) > 0 || len(bulkErr.Errs) > 0 {
+		return bulkErr
+	}
+	return nil
+}
@@ -3,14 +3,21 @@ package datastore
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore/internal"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
@@ -20,18 +27,92 @@ const (
 	indexCase    = "cases"
 	indexEntity  = "entities"
 	indexEvent   = "events"
+	indexFile    = "files"
 	indexLink    = "links"
 	indexPerson  = "persons"
 	indexProcess = "processes"
 	indexKeyword = "keywords"
+	indexUser    = "users"
+	indexToken   = "tokens"
+	indexUpload  = "uploads"
+	indexCustody = "custody"
+	indexDataKey = "datakeys"
+	indexJob     = "jobs"
+
+	indexSuggestion = "suggestions"
+	indexMigration  = "migrations"
 )
 
+// migrationCaseFiles is the ID of the document in the migrations-
+// index that marks that the files in the cases have been moved
+const migrationCaseFiles = "case-files"
+
+// templatePrefix is the prefix for the names of the index-templates
+const templatePrefix = "timeline-investigator-"
+
+// User is a user for the local authentication-provider
+type User struct {
+	UID          string                 `json:"uid"`
+	Email        string                 `json:"email"`
+	DisplayName  string                 `json:"displayName"`
+	PasswordHash string                 `json:"passwordHash"`
+	CustomClaims map[string]interface{} `json:"customClaims,omitempty"`
+	CreatedAt    int64                  `json:"createdAt"`
+}
+
+// Token is an API-token with the hash of the secret,
+// the secret itself is never stored
+type Token struct {
+	api.Token
+	Hash string `json:"hash"`
+}
+
+// Upload is a session for a resumable upload,
+// the received bytes are staged on disk
+type Upload struct {
+	ID          string `json:"id"`
+	CaseID      string `json:"caseID"`
+	CreatorID   string `json:"creatorID"`
+	Name        string `json:"name"`
+	Mime        string `json:"mime"`
+	Description string `json:"description"`
+	Length      int64  `json:"length"`
+	Offset      int64  `json:"offset"`
+	FileID      string `json:"fileID,omitempty"`
+	CreatedAt   int64  `json:"createdAt"`
+	UpdatedAt   int64  `json:"updatedAt"`
+}
+
+// Page is a page of a sorted list, the Sort is the name of
+// the field in the API, and the Cursor is the cursor for the
+// next page from the previous page, or empty for the first page
+type Page struct {
+	Size   int
+	Sort   string
+	Order  string
+	Cursor string
+}
+
+// FileFilter filters the files in a list,
+// the fields that aren't set aren't filtered
+type FileFilter struct {
+	Mime         string
+	Processed    *bool
+	Keyword      string
+	UploadedFrom int64
+	UploadedTo   int64
+	Uploader     string
+	MinSize      int
+	MaxSize      int
+}
+
 // Service is the interface for the datastore
 type Service interface {
 	// Case-methods
 	CreateCase(ctx context.Context, caze *api.Case) error
 	UpdateCase(ctx context.Context, caze *api.Case) error
-	GetCasesByEmail(ctx context.Context, email string) ([]api.Case, error)
+	ListCasesByEmail(ctx context.Context, email string, filters map[string]string, page Page) ([]api.Case, int, string, error)
+	GetCases(ctx context.Context) ([]api.Case, error)
 	GetCase(ctx context.Context, id string) (*api.Case, error)
 	DeleteCase(ctx context.Context, id string) error
 
@@ -42,7 +123,9 @@ type Service interface {
 	GetEventByID(ctx context.Context, caseID, eventID string) (*api.Event, error)
 	GetEventsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Event, error)
 	GetEvents(ctx context.Context, caseID string) ([]api.Event, error)
+	ListEvents(ctx context.Context, caseID string, filters map[string]string, page Page) ([]api.Event, int, string, error)
 	SearchEvents(ctx context.Context, caseID, prefix string) ([]api.Event, error)
+	SearchEventsByTimespan(ctx context.Context, caseID string, fromDate, toDate int64, page Page) ([]api.Event, int, string, error)
 
 	// Entity-methods
 	CreateEntity(ctx context.Context, caseID string, entity *api.Entity) error
@@ -51,6 +134,7 @@ type Service interface {
 	GetEntityByID(ctx context.Context, caseID, entityID string) (*api.Entity, error)
 	GetEntitiesByIDs(ctx context.Context, caseID string, ids []string) ([]api.Entity, error)
 	GetEntities(ctx context.Context, caseID string) ([]api.Entity, error)
+	ListEntities(ctx context.Context, caseID string, filters map[string]string, page Page) ([]api.Entity, int, string, error)
 	SearchEntities(ctx context.Context, caseID, prefix string) ([]api.Entity, error)
 
 	// File-methods
@@ -59,8 +143,16 @@ type Service interface {
 	DeleteFile(ctx context.Context, caseID, fileID string) error
 	GetFileByID(ctx context.Context, caseID, fileID string) (*api.File, error)
 	GetFilesByIDs(ctx context.Context, caseID string, ids []string) ([]api.File, error)
+	GetFiles(ctx context.Context, caseID string) ([]api.File, error)
+	ListFiles(ctx context.Context, caseID string, filter FileFilter, page Page) ([]api.File, int, string, error)
+	GetFilesByPath(ctx context.Context, caseID, path string) ([]api.File, error)
+	GetFilesProcessedWithin(ctx context.Context, caseID string, fromDate, toDate int64) ([]api.File, error)
 	SearchFiles(ctx context.Context, caseID, prefix string) ([]api.File, error)
 
+	// Custody-methods
+	CreateCustodyEvent(ctx context.Context, caseID string, event *api.CustodyEvent) error
+	GetCustodyEvents(ctx context.Context, caseID, fileID string) ([]api.CustodyEvent, error)
+
 	// Link-methods
 	CreateLink(ctx context.Context, caseID string, link *api.Link) error
 	UpdateLink(ctx context.Context, caseID string, link *api.Link) error
@@ -75,6 +167,7 @@ type Service interface {
 	GetPersonByID(ctx context.Context, caseID, personID string) (*api.Person, error)
 	GetPersonsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Person, error)
 	GetPersons(ctx context.Context, caseID string) ([]api.Person, error)
+	ListPersons(ctx context.Context, caseID string, filters map[string]string, page Page) ([]api.Person, int, string, error)
 	SearchPersons(ctx context.Context, caseID, prefix string) ([]api.Person, error)
 
 	// Keyword-methods
@@ -85,15 +178,66 @@ type Service interface {
 	GetKeywords(ctx context.Context, caseID string) ([]string, error)
 	SearchKeywords(ctx context.Context, caseID, name string) ([]api.Keyword, error)
 
+	AddKeywordIDs(ctx context.Context, caseID, field, id string, names []string) error
+	RemoveKeywordIDs(ctx context.Context, caseID, field, id string, names []string) error
+
+	// Bulk-methods
+	Bulk(ctx context.Context, operations []BulkOperation) error
+	KeywordIndex(caseID string) string
+
 	// Process-methods
 	ProcessIndex(caseID string) string
-	GetProcessedFiles(ctx context.Context, caseID string) (interface{}, error)
-	GetProcessedFile(ctx context.Context, caseID, id string) (interface{}, error)
-	GetProcessedFilesByIDs(ctx context.Context, caseID string, ids []string) (interface{}, error)
-	SearchProcessedFiles(ctx context.Context, caseID, wildcard string) (interface{}, error)
-	// CreateProcess(ctx context.Context, process *api.Process) error
-	// UpdateProcess(ctx context.Context, process *api.Process) error
-	// GetProcess(ctx context.Context, id string) (*api.Process, error)
+	IndexDocument(ctx context.Context, index, id string, document interface{}) error
+	GetProcessedFiles(ctx context.Context, caseID string) ([]api.ProcessedDocument, error)
+	GetProcessedFile(ctx context.Context, caseID, id string) (*api.ProcessedDocument, error)
+	GetProcessedFilesByIDs(ctx context.Context, caseID string, ids []string) ([]api.ProcessedDocument, error)
+	SearchProcessedFiles(ctx context.Context, caseID, wildcard string) ([]api.ProcessedDocument, error)
+	GetProcessedFileIDsByTimespan(ctx context.Context, caseID string, fromDate, toDate int64) ([]string, error)
+	CreateProcess(ctx context.Context, process *api.Process) error
+	UpdateProcess(ctx context.Context, process *api.Process) error
+	GetProcess(ctx context.Context, id string) (*api.Process, error)
+	GetProcesses(ctx context.Context, caseID string) ([]api.Process, error)
+	GetProcessesByStatus(ctx context.Context, statuses ...string) ([]api.Process, error)
+	GetProcessedDocumentsByFile(ctx context.Context, caseID, fileID string) ([]api.ProcessedDocument, error)
+
+	// Suggestion-methods
+	CreateSuggestion(ctx context.Context, caseID string, suggestion *api.Suggestion) error
+	UpdateSuggestion(ctx context.Context, caseID string, suggestion *api.Suggestion) error
+	GetSuggestionsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Suggestion, error)
+	GetSuggestions(ctx context.Context, caseID string) ([]api.Suggestion, error)
+
+	// User-methods
+	CreateUser(ctx context.Context, user *User) error
+	GetUserByID(ctx context.Context, uid string) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	UpdateUser(ctx context.Context, user *User) error
+	DeleteUser(ctx context.Context, uid string) error
+
+	// Token-methods
+	CreateToken(ctx context.Context, token *Token) error
+	UpdateToken(ctx context.Context, token *Token) error
+	GetToken(ctx context.Context, id string) (*Token, error)
+	GetTokenByHash(ctx context.Context, hash string) (*Token, error)
+	GetTokensByCreator(ctx context.Context, creatorID string) ([]Token, error)
+	GetTokensByCase(ctx context.Context, caseID string) ([]Token, error)
+	RecordTokenUsage(ctx context.Context, id string, count int) error
+
+	// Upload-methods
+	CreateUpload(ctx context.Context, upload *Upload) error
+	UpdateUpload(ctx context.Context, upload *Upload) error
+	GetUpload(ctx context.Context, id string) (*Upload, error)
+	GetCompletedUploads(ctx context.Context, before int64) ([]Upload, error)
+	DeleteUpload(ctx context.Context, id string) error
+
+	// DataKey-methods
+	GetDataKeys(ctx context.Context, identifier string) ([]filestore.DataKey, error)
+	CreateDataKey(ctx context.Context, key *filestore.DataKey) error
+	UpdateDataKey(ctx context.Context, key *filestore.DataKey) error
+
+	// Index-methods
+	PutIndexTemplates(ctx context.Context) error
+	MigrateIndices(ctx context.Context) error
+	MigrateCaseFiles(ctx context.Context) error
 }
 
 type svc struct {
@@ -116,25 +260,142 @@ func NewService(elasticURLs ...string) (Service, error) {
 func (s svc) CreateCase(ctx context.Context, caze *api.Case) error {
 	caze.ID = internal.NewID()
 	caze.CreatedAt = time.Now().Unix()
-	if err := s.save(ctx, indexCase, caze.ID, caze); err != nil {
-		return fmt.Errorf("failed to save Case : %v", err)
+	if err := s.saveCase(ctx, caze); err != nil {
+		return fmt.Errorf("failed to save Case : %w", err)
+	}
+	if err := s.newIndex(ctx, indexFile+"-"+caze.ID, nil); err != nil {
+		return fmt.Errorf("failed to create files-index for Case : %v", err)
 	}
-	if err := s.newIndex(ctx, indexKeyword+"-"+caze.ID); err != nil {
+	if err := s.newIndex(ctx, indexKeyword+"-"+caze.ID, nil); err != nil {
 		return fmt.Errorf("failed to create keywords-index for Case : %v", err)
 	}
+	if err := s.newIndex(ctx, indexEvent+"-"+caze.ID, nil); err != nil {
+		return fmt.Errorf("failed to create events-index for Case : %v", err)
+	}
 	return nil
 }
 
 func (s svc) UpdateCase(ctx context.Context, caze *api.Case) error {
 	caze.UpdatedAt = time.Now().Unix()
-	if err := s.save(ctx, indexCase, caze.ID, caze); err != nil {
-		return fmt.Errorf("failed to save Case : %v", err)
+	if err := s.saveCase(ctx, caze); err != nil {
+		return fmt.Errorf("failed to save Case : %w", err)
+	}
+	return nil
+}
+
+// saveCase saves the case without the files,
+// they are saved in the files-index for the case
+func (s svc) saveCase(ctx context.Context, caze *api.Case) error {
+	stored := *caze
+	stored.Files = nil
+	version, err := s.saveVersion(ctx, indexCase, caze.ID, caze.Version, &stored)
+	if err != nil {
+		return err
+	}
+	caze.Version = version
+	return nil
+}
+
+// MigrateCaseFiles moves the files in the case-documents, from before
+// the files were saved in the files-index for the case, to the index.
+// It's done at startup until it has been finished once, which is marked
+// in the migrations-index. It can be done again if it's interrupted
+// since the files that already are in the index are kept
+func (s svc) MigrateCaseFiles(ctx context.Context) error {
+	migrated, err := s.migrated(ctx, migrationCaseFiles)
+	if err != nil {
+		return fmt.Errorf("cannot get the migration for the case-files: %v", err)
+	}
+	if migrated {
+		return nil
+	}
+
+	// The cases-index doesn't exist before the first case is created
+	if err := s.ensureIndex(ctx, indexCase); err != nil {
+		return fmt.Errorf("failed to create cases-index : %v", err)
+	}
+
+	cases, err := s.GetCases(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get cases to migrate: %v", err)
+	}
+
+	for i := range cases {
+		caze := &cases[i]
+		index := indexFile + "-" + caze.ID
+		if err := s.ensureIndex(ctx, index); err != nil {
+			return fmt.Errorf("failed to create files-index for Case %s : %v", caze.ID, err)
+		}
+		if len(caze.Files) == 0 {
+			continue
+		}
+
+		for _, file := range caze.Files {
+			if err := s.create(ctx, index, file.ID, file); err != nil && !errors.Is(err, errExists) {
+				return fmt.Errorf("failed to migrate File %s in Case %s : %v", file.ID, caze.ID, err)
+			}
+		}
+		if err := s.saveCase(ctx, caze); err != nil {
+			return fmt.Errorf("failed to save migrated Case %s : %v", caze.ID, err)
+		}
+	}
+
+	migration := map[string]int64{"migratedAt": time.Now().Unix()}
+	if err := s.save(ctx, indexMigration, migrationCaseFiles, migration); err != nil {
+		return fmt.Errorf("failed to mark the case-files as migrated : %v", err)
 	}
 	return nil
 }
 
-// Returns all cases with a investigator with the given email.
-func (s svc) GetCasesByEmail(ctx context.Context, email string) ([]api.Case, error) {
+// migrated returns true if the migration with the
+// ID is marked as finished in the migrations-index
+func (s svc) migrated(ctx context.Context, id string) (bool, error) {
+	res, err := s.es.Exists(indexMigration, id, s.es.Exists.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("Cannot get response: %v", err)
+	}
+	res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("[%s] cannot get migration %s", res.Status(), id)
+	}
+}
+
+// caseFields are the fields the cases can be sorted and filtered by
+var caseFields = listFields{
+	sort: map[string]string{
+		"created": "createdAt",
+		"updated": "updatedAt",
+		"name":    "name.keyword",
+	},
+	filters: map[string]string{
+		"creatorID": "creatorID.keyword",
+	},
+}
+
+// ListCasesByEmail returns a page of the cases that the email is an
+// investigator in, the total number of them and the cursor for the next page
+func (s svc) ListCasesByEmail(ctx context.Context, email string, filters map[string]string, page Page) ([]api.Case, int, string, error) {
+	investigator := internal.Must{Term: map[string]string{"investigators.keyword": email}}
+	search, next, err := s.list(ctx, indexCase, caseFields, filters, page, investigator)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var cases []api.Case
+	if err := decodeHits(search.Hits.Hits, &cases); err != nil {
+		return nil, 0, "", fmt.Errorf("Cases %v", err)
+	}
+	return cases, search.Hits.Total.Value, next, nil
+}
+
+// Returns every case in the system
+func (s svc) GetCases(ctx context.Context) ([]api.Case, error) {
 	search, err := s.search(ctx, indexCase)
 	if err != nil {
 		return nil, err
@@ -151,12 +412,7 @@ func (s svc) GetCasesByEmail(ctx context.Context, email string) ([]api.Case, err
 		if err := json.Unmarshal(source, &caze); err != nil {
 			return nil, fmt.Errorf("Case json.Unmarshal: %v", err)
 		}
-
-		for _, investigator := range caze.Investigators {
-			if investigator == email {
-				cases = append(cases, caze)
-			}
-		}
+		cases = append(cases, caze)
 	}
 
 	return cases, nil
@@ -179,73 +435,71 @@ func (s svc) GetCase(ctx context.Context, id string) (*api.Case, error) {
 // Deletes the case with the given ID.
 // Returns an error if no such case exists.
 func (s svc) DeleteCase(ctx context.Context, id string) error {
-	caze, err := s.GetCase(ctx, id)
-	if err != nil {
+	if _, err := s.GetCase(ctx, id); err != nil {
 		return fmt.Errorf("Cannot find case: %w", err)
 	}
 
-	events, err := s.GetEvents(ctx, id)
-	if err == nil {
-		for _, event := range events {
-			s.DeleteEvent(ctx, id, event.ID)
-		}
-	}
-
-	entities, err := s.GetEntities(ctx, id)
-	if err == nil {
-		for _, entity := range entities {
-			s.DeleteEntity(ctx, id, entity.ID)
-		}
-	}
-
-	for _, file := range caze.Files {
-		s.DeleteEvent(ctx, id, file.ID)
-	}
-
-	links, err := s.GetLinks(ctx, id)
-	if err == nil {
-		for _, link := range links {
-			s.DeleteLink(ctx, id, link.ID)
-		}
-	}
-
-	persons, err := s.GetPersons(ctx, id)
-	if err == nil {
-		for _, person := range persons {
-			s.DeletePerson(ctx, id, person.ID)
+	// The objects in the case are deleted in bulk, the case
+	// is kept if any of them couldn't be found or deleted so
+	// the deletion can be retried. The indices that don't
+	// exist are ignored, the case has no objects of that type
+	var operations []BulkOperation
+	all := internal.QueryRequest{Query: internal.Query{MatchAll: struct{}{}}}
+	for _, index := range []string{
+		indexEvent + "-" + id,
+		indexEntity + "-" + id,
+		indexFile + "-" + id,
+		indexLink + "-" + id,
+		indexPerson + "-" + id,
+		s.KeywordIndex(id),
+		indexSuggestion + "-" + id,
+	} {
+		err := s.scroll(ctx, index, all, func(hits []internal.Hit) error {
+			for _, hit := range hits {
+				operations = append(operations, BulkOperation{Action: BulkDelete, Index: index, ID: hit.ID})
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Error finding the objects in %s: %w", index, err)
 		}
 	}
 
-	keywords, err := s.GetKeywords(ctx, id)
-	if err == nil {
-		for _, keyword := range keywords {
-			s.DeleteKeyword(ctx, id, keyword)
-		}
+	if err := s.Bulk(ctx, operations); err != nil {
+		return fmt.Errorf("Error deleting the objects in the case: %w", err)
 	}
 
-	err = s.delete(ctx, indexCase, id)
+	err := s.delete(ctx, indexCase, id)
 	if err != nil {
 		return fmt.Errorf("Error deleting case: %w", err)
 	}
 	return nil
 }
 
+// CreateEvent saves the event in the case, the event gets a new ID if
+// it doesn't have one, an accepted suggestion gives it the same ID
 func (s svc) CreateEvent(ctx context.Context, caseID string, event *api.Event) error {
-	event.ID = internal.NewID()
+	if event.ID == "" {
+		event.ID = internal.NewID()
+	}
 	event.CreatedAt = time.Now().Unix()
 	index := fmt.Sprintf("%s-%s", indexEvent, caseID)
-	if err := s.save(ctx, index, event.ID, event); err != nil {
-		return fmt.Errorf("failed to save ); : %v", err)
+	version, err := s.saveVersion(ctx, index, event.ID, "", event)
+	if err != nil {
+		return fmt.Errorf("failed to save Event : %w", err)
 	}
+	event.Version = version
 	return nil
 }
 
 func (s svc) UpdateEvent(ctx context.Context, caseID string, event *api.Event) error {
 	event.UpdatedAt = time.Now().Unix()
 	index := fmt.Sprintf("%s-%s", indexEvent, caseID)
-	if err := s.save(ctx, index, event.ID, event); err != nil {
-		return fmt.Errorf("failed to save ); : %v", err)
+	version, err := s.saveVersion(ctx, index, event.ID, event.Version, event)
+	if err != nil {
+		return fmt.Errorf("failed to save Event : %w", err)
 	}
+	event.Version = version
 	return nil
 }
 
@@ -304,6 +558,34 @@ func (s svc) GetEvents(ctx context.Context, caseID string) ([]api.Event, error)
 	return events, nil
 }
 
+// eventFields are the fields the events can be sorted and filtered by
+var eventFields = listFields{
+	sort: map[string]string{
+		"created":    "createdAt",
+		"date":       "fromDate",
+		"importance": "importance",
+	},
+	filters: map[string]string{
+		"importance": "importance",
+		"keyword":    "keywords.keyword",
+	},
+}
+
+// ListEvents returns a page of the events in the case, the
+// total number of them and the cursor for the next page
+func (s svc) ListEvents(ctx context.Context, caseID string, filters map[string]string, page Page) ([]api.Event, int, string, error) {
+	search, next, err := s.list(ctx, indexEvent+"-"+caseID, eventFields, filters, page)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var events []api.Event
+	if err := decodeHits(search.Hits.Hits, &events); err != nil {
+		return nil, 0, "", fmt.Errorf("Events %v", err)
+	}
+	return events, search.Hits.Total.Value, next, nil
+}
+
 func (s svc) SearchEvents(ctx context.Context, caseID, prefix string) ([]api.Event, error) {
 	search, err := s.searchWithPrefix(ctx, indexKeyword+"-"+caseID, "description", prefix)
 	if err != nil {
@@ -317,6 +599,38 @@ func (s svc) SearchEvents(ctx context.Context, caseID, prefix string) ([]api.Eve
 	return events, nil
 }
 
+// SearchEventsByTimespan returns a page of the events in the case
+// that overlaps the timespan, sorted by their fromDate. Returns the
+// total number of events within the timespan and the cursor for the
+// next page. The dates are compared without a format, so the indices
+// from before the templates, with the dates mapped as numbers, match
+// the same events as the indices with the dates in epoch_second
+func (s svc) SearchEventsByTimespan(ctx context.Context, caseID string, fromDate, toDate int64, page Page) ([]api.Event, int, string, error) {
+	// an event overlaps the timespan if it starts
+	// before the timespan ends and finishes after it starts
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Bool: &internal.Bool{
+				Filter: []internal.Must{
+					{Range: map[string]internal.Range{"fromDate": {Lte: toDate}}},
+					{Range: map[string]internal.Range{"toDate": {Gte: fromDate}}},
+				},
+			},
+		},
+	}
+
+	search, next, err := s.searchAfter(ctx, indexEvent+"-"+caseID, query, page, "fromDate")
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("cannot search in events-document: %v", err)
+	}
+
+	events := []api.Event{}
+	if err := decodeHits(search.Hits.Hits, &events); err != nil {
+		return nil, 0, "", fmt.Errorf("Events %v", err)
+	}
+	return events, search.Hits.Total.Value, next, nil
+}
+
 func (s svc) DeleteEvent(ctx context.Context, caseID, eventID string) error {
 	index := fmt.Sprintf("%s-%s", indexEvent, caseID)
 	if err := s.delete(ctx, index, eventID); err != nil {
@@ -329,18 +643,22 @@ func (s svc) CreateEntity(ctx context.Context, caseID string, entity *api.Entity
 	entity.ID = internal.NewID()
 	entity.CreatedAt = time.Now().Unix()
 	index := fmt.Sprintf("%s-%s", indexEntity, caseID)
-	if err := s.save(ctx, index, entity.ID, entity); err != nil {
-		return fmt.Errorf("failed to save entity : %v", err)
+	version, err := s.saveVersion(ctx, index, entity.ID, "", entity)
+	if err != nil {
+		return fmt.Errorf("failed to save entity : %w", err)
 	}
+	entity.Version = version
 	return nil
 }
 
 func (s svc) UpdateEntity(ctx context.Context, caseID string, entity *api.Entity) error {
 	entity.UpdatedAt = time.Now().Unix()
 	index := fmt.Sprintf("%s-%s", indexEntity, caseID)
-	if err := s.save(ctx, index, entity.ID, entity); err != nil {
-		return fmt.Errorf("failed to save entity : %v", err)
+	version, err := s.saveVersion(ctx, index, entity.ID, entity.Version, entity)
+	if err != nil {
+		return fmt.Errorf("failed to save entity : %w", err)
 	}
+	entity.Version = version
 	return nil
 }
 
@@ -399,6 +717,34 @@ func (s svc) GetEntities(ctx context.Context, caseID string) ([]api.Entity, erro
 	return entities, nil
 }
 
+// entityFields are the fields the entities can be sorted and filtered by
+var entityFields = listFields{
+	sort: map[string]string{
+		"created": "createdAt",
+		"title":   "title.keyword",
+		"type":    "type.keyword",
+	},
+	filters: map[string]string{
+		"type":    "type.keyword",
+		"keyword": "keywords.keyword",
+	},
+}
+
+// ListEntities returns a page of the entities in the case,
+// the total number of them and the cursor for the next page
+func (s svc) ListEntities(ctx context.Context, caseID string, filters map[string]string, page Page) ([]api.Entity, int, string, error) {
+	search, next, err := s.list(ctx, indexEntity+"-"+caseID, entityFields, filters, page)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var entities []api.Entity
+	if err := decodeHits(search.Hits.Hits, &entities); err != nil {
+		return nil, 0, "", fmt.Errorf("Entities %v", err)
+	}
+	return entities, search.Hits.Total.Value, next, nil
+}
+
 func (s svc) DeleteEntity(ctx context.Context, caseID, entityID string) error {
 	index := fmt.Sprintf("%s-%s", indexEntity, caseID)
 	if err := s.delete(ctx, index, entityID); err != nil {
@@ -420,22 +766,30 @@ func (s svc) SearchEntities(ctx context.Context, caseID, prefix string) ([]api.E
 	return entities, nil
 }
 
+// CreatePerson saves the person in the case, the person gets a new ID if
+// it doesn't have one, an accepted suggestion gives it the same ID
 func (s svc) CreatePerson(ctx context.Context, caseID string, person *api.Person) error {
-	person.ID = internal.NewID()
+	if person.ID == "" {
+		person.ID = internal.NewID()
+	}
 	person.CreatedAt = time.Now().Unix()
 	index := fmt.Sprintf("%s-%s", indexPerson, caseID)
-	if err := s.save(ctx, index, person.ID, person); err != nil {
-		return fmt.Errorf("failed to save Person : %v", err)
+	version, err := s.saveVersion(ctx, index, person.ID, "", person)
+	if err != nil {
+		return fmt.Errorf("failed to save Person : %w", err)
 	}
+	person.Version = version
 	return nil
 }
 
 func (s svc) UpdatePerson(ctx context.Context, caseID string, person *api.Person) error {
 	person.UpdatedAt = time.Now().Unix()
 	index := fmt.Sprintf("%s-%s", indexPerson, caseID)
-	if err := s.save(ctx, index, person.ID, person); err != nil {
-		return fmt.Errorf("failed to save Person : %v", err)
+	version, err := s.saveVersion(ctx, index, person.ID, person.Version, person)
+	if err != nil {
+		return fmt.Errorf("failed to save Person : %w", err)
 	}
+	person.Version = version
 	return nil
 }
 
@@ -494,6 +848,35 @@ func (s svc) GetPersons(ctx context.Context, caseID string) ([]api.Person, error
 	return persons, nil
 }
 
+// personFields are the fields the persons can be sorted and filtered by
+var personFields = listFields{
+	sort: map[string]string{
+		"created":      "createdAt",
+		"firstName":    "firstName.keyword",
+		"lastName":     "lastName.keyword",
+		"emailAddress": "emailAddress.keyword",
+	},
+	filters: map[string]string{
+		"emailAddress": "emailAddress.keyword",
+		"keyword":      "keywords.keyword",
+	},
+}
+
+// ListPersons returns a page of the persons in the case, the
+// total number of them and the cursor for the next page
+func (s svc) ListPersons(ctx context.Context, caseID string, filters map[string]string, page Page) ([]api.Person, int, string, error) {
+	search, next, err := s.list(ctx, indexPerson+"-"+caseID, personFields, filters, page)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var persons []api.Person
+	if err := decodeHits(search.Hits.Hits, &persons); err != nil {
+		return nil, 0, "", fmt.Errorf("Persons %v", err)
+	}
+	return persons, search.Hits.Total.Value, next, nil
+}
+
 func (s svc) DeletePerson(ctx context.Context, caseID, personID string) error {
 	index := fmt.Sprintf("%s-%s", indexPerson, caseID)
 	if err := s.delete(ctx, index, personID); err != nil {
@@ -554,111 +937,272 @@ func (s svc) SearchPersons(ctx context.Context, caseID, prefix string) ([]api.Pe
 	return persons, nil
 }
 
+// CreateFile saves the file in the case, the file gets a new ID
+// if it doesn't have one, so a retry can create the same file
 func (s svc) CreateFile(ctx context.Context, caseID string, file *api.File) error {
-	caze, err := s.GetCase(ctx, caseID)
-	if err != nil {
-		return err
+	if file.ID == "" {
+		file.ID = internal.NewID()
 	}
-
-	file.ID = internal.NewID()
 	file.CreatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexFile+"-"+caseID, file.ID, "", file)
+	if err != nil {
+		return fmt.Errorf("failed to save File : %w", err)
+	}
+	file.Version = version
+	return nil
+}
 
-	caze.Files = append(caze.Files, *file)
-	if err := s.UpdateCase(ctx, caze); err != nil {
-		return err
+func (s svc) UpdateFile(ctx context.Context, caseID string, file *api.File) error {
+	version, err := s.saveVersion(ctx, indexFile+"-"+caseID, file.ID, file.Version, file)
+	if err != nil {
+		return fmt.Errorf("failed to save File : %w", err)
 	}
+	file.Version = version
+	return nil
+}
 
+func (s svc) DeleteFile(ctx context.Context, caseID, fileID string) error {
+	if err := s.delete(ctx, indexFile+"-"+caseID, fileID); err != nil {
+		return fmt.Errorf("failed to delete File : %v", err)
+	}
 	return nil
 }
 
-func (s svc) UpdateFile(ctx context.Context, caseID string, file *api.File) error {
-	caze, err := s.GetCase(ctx, caseID)
+func (s svc) GetFileByID(ctx context.Context, caseID, fileID string) (*api.File, error) {
+	resp, err := s.searchByID(ctx, indexFile+"-"+caseID, fileID)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Cannot find File: %v", err)
 	}
 
-	for i, f := range caze.Files {
-		if f.ID == file.ID {
-			caze.Files[i] = *file
-			if err := s.UpdateCase(ctx, caze); err != nil {
-				return fmt.Errorf("cannot update file in case: %v", err)
-			}
-			return nil
-		}
+	var file api.File
+	if err := json.Unmarshal(resp, &file); err != nil {
+		return nil, fmt.Errorf("File json.Unmarshal: %v", err)
+	}
+	return &file, nil
+}
+
+func (s svc) GetFilesByIDs(ctx context.Context, caseID string, ids []string) ([]api.File, error) {
+	if len(ids) == 0 {
+		return []api.File{}, nil
 	}
 
-	return errors.New("file not found")
+	var query internal.QueryRequest
+	query.Query.IDs = map[string][]string{"values": ids}
+	search, err := s.searchPage(ctx, indexFile+"-"+caseID, query, 0, len(ids))
+	if err != nil {
+		return nil, fmt.Errorf("cannot search in files-document: %v", err)
+	}
+	return filesFromHits(search.Hits.Hits)
 }
 
-func (s svc) DeleteFile(ctx context.Context, caseID, fileID string) error {
-	caze, err := s.GetCase(ctx, caseID)
+// GetFiles returns all the files in the case
+func (s svc) GetFiles(ctx context.Context, caseID string) ([]api.File, error) {
+	search, err := s.search(ctx, indexFile+"-"+caseID)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("cannot search in files-document: %v", err)
 	}
+	return filesFromHits(search.Hits.Hits)
+}
 
-	for i, file := range caze.Files {
-		if file.ID == fileID {
-			caze.Files = append(caze.Files[:i], caze.Files[i+1:]...)
-			if err := s.UpdateCase(ctx, caze); err != nil {
-				return fmt.Errorf("cannot delete file in case: %v", err)
-			}
-			return nil
+// fileFields are the fields the files can be sorted by,
+// they are filtered by the FileFilter instead
+var fileFields = listFields{
+	sort: map[string]string{
+		"name": "name.keyword",
+		"size": "size",
+		"date": "createdAt",
+	},
+}
+
+// ListFiles returns a page of the files in the case that matches
+// the filter, the total number of them and the cursor for the next page
+func (s svc) ListFiles(ctx context.Context, caseID string, filter FileFilter, page Page) ([]api.File, int, string, error) {
+	field, err := fileFields.sortField(page.Sort)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	search, next, err := s.searchAfter(ctx, indexFile+"-"+caseID, fileQuery(filter), page, field)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	files, err := filesFromHits(search.Hits.Hits)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return files, search.Hits.Total.Value, next, nil
+}
+
+// fileQuery creates the query for the filter
+func fileQuery(filter FileFilter) internal.QueryRequest {
+	var filters, mustNot []internal.Must
+	switch {
+	case strings.HasSuffix(filter.Mime, "/*"):
+		filters = append(filters, internal.Must{Prefix: map[string]string{"mime.keyword": strings.TrimSuffix(filter.Mime, "*")}})
+	case filter.Mime != "":
+		filters = append(filters, internal.Must{Term: map[string]string{"mime.keyword": filter.Mime}})
+	}
+
+	// The files that haven't been processed has 0 as processedAt
+	if filter.Processed != nil {
+		processed := internal.Must{Range: map[string]internal.Range{"processedAt": {Gte: 1}}}
+		if *filter.Processed {
+			filters = append(filters, processed)
+		} else {
+			mustNot = append(mustNot, processed)
+		}
+	}
+
+	if filter.Keyword != "" {
+		filters = append(filters, internal.Must{Term: map[string]string{"keywords.keyword": filter.Keyword}})
+	}
+	if filter.UploadedFrom != 0 || filter.UploadedTo != 0 {
+		uploaded := internal.Range{Format: "epoch_second"}
+		if filter.UploadedFrom != 0 {
+			uploaded.Gte = filter.UploadedFrom
+		}
+		if filter.UploadedTo != 0 {
+			uploaded.Lte = filter.UploadedTo
+		}
+		filters = append(filters, internal.Must{Range: map[string]internal.Range{"createdAt": uploaded}})
+	}
+	if filter.Uploader != "" {
+		filters = append(filters, internal.Must{Bool: &internal.Bool{
+			Should: []internal.Must{
+				{Term: map[string]string{"uploaderID.keyword": filter.Uploader}},
+				{Term: map[string]string{"uploaderEmail.keyword": filter.Uploader}},
+			},
+			MinimumShouldMatch: 1,
+		}})
+	}
+	if filter.MinSize != 0 || filter.MaxSize != 0 {
+		var size internal.Range
+		if filter.MinSize != 0 {
+			size.Gte = filter.MinSize
 		}
+		if filter.MaxSize != 0 {
+			size.Lte = filter.MaxSize
+		}
+		filters = append(filters, internal.Must{Range: map[string]internal.Range{"size": size}})
 	}
 
-	return errors.New("file not found")
+	if len(filters) == 0 && len(mustNot) == 0 {
+		return internal.QueryRequest{Query: internal.Query{MatchAll: map[string]interface{}{}}}
+	}
+	return internal.QueryRequest{Query: internal.Query{Bool: &internal.Bool{Filter: filters, MustNot: mustNot}}}
 }
 
-func (s svc) GetFileByID(ctx context.Context, caseID, fileID string) (*api.File, error) {
-	caze, err := s.GetCase(ctx, caseID)
+// GetFilesByPath returns the files in the case that
+// are stored at the path in the filestore
+func (s svc) GetFilesByPath(ctx context.Context, caseID, path string) ([]api.File, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Term: map[string]string{"path.keyword": path},
+		},
+	}
+
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, indexFile+"-"+caseID, query, 0, 10000)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get case for file: %v", err)
+		return nil, fmt.Errorf("cannot search in files-document: %v", err)
 	}
+	return filesFromHits(search.Hits.Hits)
+}
 
-	for _, file := range caze.Files {
-		if file.ID == fileID {
-			return &file, nil
-		}
+// GetFilesProcessedWithin returns the files in the
+// case that were processed within the timespan
+func (s svc) GetFilesProcessedWithin(ctx context.Context, caseID string, fromDate, toDate int64) ([]api.File, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Bool: &internal.Bool{
+				Filter: []internal.Must{
+					{Range: map[string]internal.Range{"processedAt": {Gte: fromDate, Lte: toDate, Format: "epoch_second"}}},
+				},
+			},
+		},
 	}
 
-	return nil, errors.New("file not found")
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, indexFile+"-"+caseID, query, 0, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("cannot search in files-document: %v", err)
+	}
+	return filesFromHits(search.Hits.Hits)
 }
 
-func (s svc) GetFilesByIDs(ctx context.Context, caseID string, ids []string) ([]api.File, error) {
-	if len(ids) == 0 {
-		return []api.File{}, nil
+// filesFromHits converts the hits in the files-index to files
+func filesFromHits(hits []internal.Hit) ([]api.File, error) {
+	files := []api.File{}
+	for _, hit := range hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var file api.File
+		if err := json.Unmarshal(source, &file); err != nil {
+			return nil, fmt.Errorf("File json.Unmarshal: %v", err)
+		}
+		files = append(files, file)
 	}
+	return files, nil
+}
 
-	caze, err := s.GetCase(ctx, caseID)
+// CreateCustodyEvent adds an event to the chain of custody,
+// the events are never updated or deleted
+func (s svc) CreateCustodyEvent(ctx context.Context, caseID string, event *api.CustodyEvent) error {
+	event.ID = internal.NewID()
+	event.CreatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexCustody+"-"+caseID, event.ID, "", event)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get case for file: %v", err)
+		return fmt.Errorf("failed to save CustodyEvent : %w", err)
 	}
+	event.Version = version
+	return nil
+}
 
-	// Create a hash-map for the IDs
-	var idMap = make(map[string]bool)
-	for _, id := range ids {
-		idMap[id] = true
+// GetCustodyEvents returns the chain of custody
+// for the file, with the oldest event first
+func (s svc) GetCustodyEvents(ctx context.Context, caseID, fileID string) ([]api.CustodyEvent, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Term: map[string]string{"fileID.keyword": fileID},
+		},
 	}
 
-	var files []api.File
-	for _, f := range caze.Files {
-		if idMap[f.ID] {
-			files = append(files, f)
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, indexCustody+"-"+caseID, query, 0, 10000, "createdAt:asc")
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find CustodyEvents: %v", err)
+	}
+
+	var events []api.CustodyEvent
+	for _, hit := range search.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
 		}
+
+		var event api.CustodyEvent
+		if err := json.Unmarshal(source, &event); err != nil {
+			return nil, fmt.Errorf("CustodyEvent json.Unmarshal: %v", err)
+		}
+		events = append(events, event)
 	}
 
-	return files, nil
+	return events, nil
 }
 
 func (s svc) SearchFiles(ctx context.Context, caseID, prefix string) ([]api.File, error) {
 	// search with the prefix for name
-	search, err := s.searchWithPrefix(ctx, indexProcess+"-"+caseID, "name", prefix)
+	search, err := s.searchWithPrefix(ctx, indexFile+"-"+caseID, "name", prefix)
 	if err != nil {
 		return nil, err
 	}
 
 	// search with the wildcard for description
-	search2, err := s.searchWithWildcard(ctx, indexProcess+"-"+caseID, "description", prefix)
+	search2, err := s.searchWithWildcard(ctx, indexFile+"-"+caseID, "description", prefix)
 	if err != nil {
 		return nil, err
 	}
@@ -682,104 +1226,158 @@ func (s svc) SearchFiles(ctx context.Context, caseID, prefix string) ([]api.File
 
 	// exclude the duplicate files
 	var exists = make(map[string]bool)
-	for i := range files {
-		if exists[files[i].ID] {
-			files = append(files[:i], files[i+1:]...)
+	unique := files[:0]
+	for _, file := range files {
+		if exists[file.ID] {
+			continue
 		}
-		exists[files[i].ID] = true
+		exists[file.ID] = true
+		unique = append(unique, file)
 	}
 
-	return files, nil
+	return unique, nil
 }
 
-func (s svc) GetProcessedFile(ctx context.Context, caseID, id string) (interface{}, error) {
+func (s svc) GetProcessedFile(ctx context.Context, caseID, id string) (*api.ProcessedDocument, error) {
 	resp, err := s.searchByID(ctx, s.ProcessIndex(caseID), id)
 	if err != nil {
 		return nil, fmt.Errorf("Cannot find Processed File in Case: %v", err)
 	}
 
-	var processed interface{}
+	var processed indexer.Processed
 	if err := json.Unmarshal(resp, &processed); err != nil {
-		return nil, fmt.Errorf("- json.Unmarshal: %v", err)
+		return nil, fmt.Errorf("Processed json.Unmarshal: %v", err)
 	}
 
-	return &processed, nil
+	document := NewProcessedDocument(id, processed)
+	return &document, nil
 }
 
-func (s svc) GetProcessedFiles(ctx context.Context, caseID string) (interface{}, error) {
+func (s svc) GetProcessedFiles(ctx context.Context, caseID string) ([]api.ProcessedDocument, error) {
 	search, err := s.search(ctx, s.ProcessIndex(caseID))
 	if err != nil {
 		return nil, err
 	}
-
-	source, err := json.Marshal(search.Hits.Hits)
-	if err != nil {
-		return nil, fmt.Errorf("json.Marshal: %v", err)
-	}
-
-	var processes interface{}
-	if err := json.Unmarshal(source, &processes); err != nil {
-		return nil, fmt.Errorf("Processes json.Unmarshal: %v", err)
-	}
-
-	return &processes, nil
+	return processedDocuments(search.Hits.Hits)
 }
 
-func (s svc) GetProcessedFilesByIDs(ctx context.Context, caseID string, ids []string) (interface{}, error) {
+func (s svc) GetProcessedFilesByIDs(ctx context.Context, caseID string, ids []string) ([]api.ProcessedDocument, error) {
 	if len(ids) == 0 {
-		return nil, nil
+		return []api.ProcessedDocument{}, nil
 	}
 
-	search, err := s.searchByIDs(ctx, s.ProcessIndex(caseID), ids)
+	var query internal.QueryRequest
+	query.Query.IDs = map[string][]string{"values": ids}
+	search, err := s.searchPage(ctx, s.ProcessIndex(caseID), query, 0, len(ids))
 	if err != nil {
 		return nil, err
 	}
-
-	var processes interface{}
-	if err := json.Unmarshal(search, &processes); err != nil {
-		return nil, fmt.Errorf("Processes json.Unmarshal: %v", err)
-	}
-
-	return &processes, nil
+	return processedDocuments(search.Hits.Hits)
 }
 
-func (s svc) CreateProcess(ctx context.Context, process *api.Process) error {
-	process.ID = internal.NewID()
-	process.CreatedAt = time.Now().Unix()
-	if err := s.save(ctx, "processes", process.ID, process); err != nil {
-		return fmt.Errorf("failed to save Process : %v", err)
+// GetProcessedFileIDsByTimespan returns the IDs of the processed files
+// in the case that has an extracted document-date within the timespan,
+// documents from files in containers returns the ID of the uploaded file
+func (s svc) GetProcessedFileIDsByTimespan(ctx context.Context, caseID string, fromDate, toDate int64) ([]string, error) {
+	dateRange := internal.Range{Gte: fromDate, Lte: toDate, Format: "epoch_second"}
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Bool: &internal.Bool{
+				Should: []internal.Must{
+					{Range: map[string]internal.Range{"meta.date": dateRange}},
+					{Range: map[string]internal.Range{"meta.created": dateRange}},
+				},
+				MinimumShouldMatch: 1,
+			},
+		},
 	}
-	return nil
-}
 
-func (s svc) UpdateProcess(ctx context.Context, process *api.Process) error {
-	process.UpdatedAt = time.Now().Unix()
-	if err := s.save(ctx, "processes", process.ID, process); err != nil {
-		return fmt.Errorf("failed to save Process : %v", err)
+	// The documents are scrolled, a case can have
+	// more than the 10000 hits in a result-window
+	var ids []string
+	seen := make(map[string]bool)
+	err := s.scroll(ctx, s.ProcessIndex(caseID), query, func(hits []internal.Hit) error {
+		for _, hit := range hits {
+			source, err := json.Marshal(hit.Source)
+			if err != nil {
+				return fmt.Errorf("json.Marshal: %v", err)
+			}
+
+			var document struct {
+				Parent *struct {
+					FileID string `json:"file_id"`
+				} `json:"parent"`
+			}
+			if err := json.Unmarshal(source, &document); err != nil {
+				return fmt.Errorf("Processed json.Unmarshal: %v", err)
+			}
+
+			id := hit.ID
+			if document.Parent != nil && document.Parent.FileID != "" {
+				id = document.Parent.FileID
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot search in processes-document: %v", err)
 	}
+	return ids, nil
+}
+
+func (s svc) CreateProcess(ctx context.Context, process *api.Process) error {
+	process.ID = internal.NewID()
+	process.CreatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexJob, process.ID, "", process)
+	if err != nil {
+		return fmt.Errorf("failed to save Process : %w", err)
+	}
+	process.Version = version
 	return nil
 }
 
-func (s svc) SearchProcessedFiles(ctx context.Context, caseID, wildcard string) (interface{}, error) {
-	// search with the wildcard for content
-	search, err := s.searchWithWildcard(ctx, indexProcess+"-"+caseID, "content", wildcard)
+func (s svc) UpdateProcess(ctx context.Context, process *api.Process) error {
+	process.UpdatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexJob, process.ID, "", process)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to save Process : %w", err)
 	}
+	process.Version = version
+	return nil
+}
 
-	// unmarshal the data to an interface
-	var processes interface{}
-	if err := json.Unmarshal(search, &processes); err != nil {
-		return nil, fmt.Errorf("Processes json.Unmarshal: %v", err)
+func (s svc) SearchProcessedFiles(ctx context.Context, caseID, wildcard string) ([]api.ProcessedDocument, error) {
+	// search with the wildcard for content
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Bool: &internal.Bool{
+				Must: []internal.Must{{
+					Wildcard: map[string]interface{}{
+						"content": map[string]string{
+							"value": wildcard,
+						},
+					},
+				}},
+			},
+		},
 	}
 
-	return processes, nil
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, s.ProcessIndex(caseID), query, 0, 10000)
+	if err != nil {
+		return nil, err
+	}
+	return processedDocuments(search.Hits.Hits)
 }
 
 func (s svc) GetProcess(ctx context.Context, id string) (*api.Process, error) {
-	resp, err := s.searchByID(ctx, "processes", id)
+	resp, err := s.searchByID(ctx, indexJob, id)
 	if err != nil {
-		return nil, fmt.Errorf("Cannot find Event in Case: %v", err)
+		return nil, fmt.Errorf("Cannot find Process: %v", err)
 	}
 
 	var process api.Process
@@ -790,20 +1388,235 @@ func (s svc) GetProcess(ctx context.Context, id string) (*api.Process, error) {
 	return &process, nil
 }
 
+// GetProcesses gets the processing-jobs
+// for the case, the latest job first
+func (s svc) GetProcesses(ctx context.Context, caseID string) ([]api.Process, error) {
+	return s.getProcesses(ctx, internal.Query{Term: map[string]string{"caseID.keyword": caseID}})
+}
+
+// GetProcessesByStatus gets the processing-jobs
+// in every case with one of the statuses
+func (s svc) GetProcessesByStatus(ctx context.Context, statuses ...string) ([]api.Process, error) {
+	return s.getProcesses(ctx, internal.Query{Terms: map[string][]string{"status.keyword": statuses}})
+}
+
+func (s svc) getProcesses(ctx context.Context, query internal.Query) ([]api.Process, error) {
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, indexJob, internal.QueryRequest{Query: query}, 0, 10000, "createdAt:desc")
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Processes: %v", err)
+	}
+
+	var processes []api.Process
+	for _, hit := range search.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var process api.Process
+		if err := json.Unmarshal(source, &process); err != nil {
+			return nil, fmt.Errorf("Process json.Unmarshal: %v", err)
+		}
+		processes = append(processes, process)
+	}
+
+	return processes, nil
+}
+
+// GetProcessedDocumentsByFile returns the processed document for
+// the file, and the documents for the files in it if it's a container
+func (s svc) GetProcessedDocumentsByFile(ctx context.Context, caseID, fileID string) ([]api.ProcessedDocument, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Bool: &internal.Bool{
+				Should: []internal.Must{
+					{IDs: map[string][]string{"values": {fileID}}},
+					{Term: map[string]string{"parent.file_id.keyword": fileID}},
+				},
+				MinimumShouldMatch: 1,
+			},
+		},
+	}
+
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, s.ProcessIndex(caseID), query, 0, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("cannot search in processes-document: %v", err)
+	}
+	return processedDocuments(search.Hits.Hits)
+}
+
+// processedDocuments converts the hits
+// in the processes-index to documents
+func processedDocuments(hits []internal.Hit) ([]api.ProcessedDocument, error) {
+	documents := []api.ProcessedDocument{}
+	for _, hit := range hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var processed indexer.Processed
+		if err := json.Unmarshal(source, &processed); err != nil {
+			return nil, fmt.Errorf("Processed json.Unmarshal: %v", err)
+		}
+		documents = append(documents, NewProcessedDocument(hit.ID, processed))
+	}
+	return documents, nil
+}
+
+// pageCountKeys are the keys for the number of pages
+// in the raw metadata, PDF and Office from fscrawler
+var pageCountKeys = []string{"xmpTPg:NPages", "meta:page-count", "Page-Count"}
+
+// NewProcessedDocument converts a document from the indexers,
+// the dates are converted to unix-timestamps
+func NewProcessedDocument(id string, processed indexer.Processed) api.ProcessedDocument {
+	document := api.ProcessedDocument{
+		ID:          id,
+		FileID:      id,
+		Path:        processed.Path.Virtual,
+		Name:        processed.File.Filename,
+		Extension:   processed.File.Extension,
+		ContentType: processed.File.ContentType,
+		Size:        processed.File.Filesize,
+		Content:     processed.Content,
+		Title:       processed.Meta.Title,
+		Author:      processed.Meta.Author,
+		Modifier:    processed.Meta.Modifier,
+		Keywords:    processed.Meta.Keywords,
+		Language:    processed.Meta.Language,
+		CreatorTool: processed.Meta.CreatorTool,
+		CreatedAt:   unixDate(processed.Meta.Created),
+		ModifiedAt:  unixDate(processed.Meta.Date),
+		IndexedAt:   unixDate(processed.File.IndexingDate),
+		Metadata:    processed.Meta.Raw,
+		Error:       processed.Error,
+	}
+	if document.Keywords == nil {
+		document.Keywords = []string{}
+	}
+	if document.Metadata == nil {
+		document.Metadata = map[string]string{}
+	}
+
+	for _, key := range pageCountKeys {
+		if pages, err := strconv.Atoi(processed.Meta.Raw[key]); err == nil {
+			document.Pages = pages
+			break
+		}
+	}
+
+	// The uploaded files only have the time they were
+	// stored, the time the files in containers were
+	// modified is recorded by the container
+	if parent := processed.Parent; parent != nil {
+		document.FileID = parent.FileID
+		document.ParentID = parent.ID
+		document.ParentPath = parent.Path
+		document.Depth = parent.Depth
+		document.FileModifiedAt = unixDate(processed.File.LastModified)
+	}
+
+	if container := processed.Container; container != nil {
+		document.Container = true
+		document.Children = container.Children
+		document.ContainerError = container.Error
+	}
+	return document
+}
+
+// unixDate converts a date in a document to a
+// unix-timestamp, 0 if the date isn't known
+func unixDate(value string) int64 {
+	if date, ok := indexer.ParseDate(value); ok {
+		return date.Unix()
+	}
+	return 0
+}
+
+// CreateSuggestion saves a new suggestion, the ID is kept if it's
+// set so the same suggestion isn't created again for a file
+func (s svc) CreateSuggestion(ctx context.Context, caseID string, suggestion *api.Suggestion) error {
+	if suggestion.ID == "" {
+		suggestion.ID = internal.NewID()
+	}
+	suggestion.CreatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexSuggestion+"-"+caseID, suggestion.ID, "", suggestion)
+	if err != nil {
+		return fmt.Errorf("failed to save Suggestion : %w", err)
+	}
+	suggestion.Version = version
+	return nil
+}
+
+func (s svc) UpdateSuggestion(ctx context.Context, caseID string, suggestion *api.Suggestion) error {
+	suggestion.UpdatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexSuggestion+"-"+caseID, suggestion.ID, suggestion.Version, suggestion)
+	if err != nil {
+		return fmt.Errorf("failed to save Suggestion : %w", err)
+	}
+	suggestion.Version = version
+	return nil
+}
+
+func (s svc) GetSuggestionsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Suggestion, error) {
+	if len(ids) == 0 {
+		return []api.Suggestion{}, nil
+	}
+
+	query := internal.QueryRequest{Query: internal.Query{IDs: map[string][]string{"values": ids}}}
+	return s.getSuggestions(ctx, caseID, query, len(ids))
+}
+
+// GetSuggestions returns the suggestions in the case,
+// the latest suggestions are returned first
+func (s svc) GetSuggestions(ctx context.Context, caseID string) ([]api.Suggestion, error) {
+	// 10000 is the max result-window in elastic
+	return s.getSuggestions(ctx, caseID, internal.QueryRequest{Query: internal.Query{MatchAll: struct{}{}}}, 10000)
+}
+
+func (s svc) getSuggestions(ctx context.Context, caseID string, query internal.QueryRequest, size int) ([]api.Suggestion, error) {
+	search, err := s.searchPage(ctx, indexSuggestion+"-"+caseID, query, 0, size, "createdAt:desc")
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Suggestions in Case: %v", err)
+	}
+
+	suggestions := []api.Suggestion{}
+	for _, hit := range search.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var suggestion api.Suggestion
+		if err := json.Unmarshal(source, &suggestion); err != nil {
+			return nil, fmt.Errorf("Suggestion json.Unmarshal: %v", err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
 func (s svc) CreateLink(ctx context.Context, caseID string, link *api.Link) error {
 	link.ID = internal.NewID()
 	link.CreatedAt = time.Now().Unix()
-	if err := s.save(ctx, indexLink+"-"+caseID, link.ID, link); err != nil {
-		return fmt.Errorf("failed to save Link : %v", err)
+	version, err := s.saveVersion(ctx, indexLink+"-"+caseID, link.ID, "", link)
+	if err != nil {
+		return fmt.Errorf("failed to save Link : %w", err)
 	}
+	link.Version = version
 	return nil
 }
 
 func (s svc) UpdateLink(ctx context.Context, caseID string, link *api.Link) error {
 	link.UpdatedAt = time.Now().Unix()
-	if err := s.save(ctx, indexLink+"-"+caseID, link.ID, link); err != nil {
-		return fmt.Errorf("failed to save Link : %v", err)
+	version, err := s.saveVersion(ctx, indexLink+"-"+caseID, link.ID, link.Version, link)
+	if err != nil {
+		return fmt.Errorf("failed to save Link : %w", err)
 	}
+	link.Version = version
 	return nil
 }
 
@@ -889,85 +1702,788 @@ func (s svc) DeleteKeyword(ctx context.Context, caseID, id string) error {
 	return nil
 }
 
+// The fields in the keywords with the IDs of the objects
+const (
+	KeywordEvents   = "eventIDs"
+	KeywordPersons  = "personIDs"
+	KeywordEntities = "entityIDs"
+	KeywordFiles    = "fileIDs"
+)
+
+// keywordRetries is how many times the scripted updates of
+// the keywords are retried, if they conflict with another update
+const keywordRetries = 3
+
+// keywordFields are the fields with IDs, a
+// keyword is deleted when they are all empty
+var keywordFields = []string{KeywordEvents, KeywordPersons, KeywordEntities, KeywordFiles}
+
+// The scripts update the keywords in elasticsearch,
+// so the IDs added and removed by concurrent
+// requests aren't overwritten by each other
+const (
+	addKeywordScript = `
+if (ctx._source[params.field] == null) { ctx._source[params.field] = []; }
+if (ctx._source[params.field].contains(params.id)) { ctx.op = 'noop'; } else { ctx._source[params.field].add(params.id); }`
+
+	removeKeywordScript = `
+if (ctx._source[params.field] != null) { ctx._source[params.field].removeIf(id -> id == params.id); }
+boolean empty = true;
+for (def field : params.fields) { if (ctx._source[field] != null && !ctx._source[field].isEmpty()) { empty = false; } }
+if (empty) { ctx.op = 'delete'; }`
+)
+
+// AddKeywordIDs adds the ID of an object to the field in the keywords,
+// the keywords that don't exist are created. The IDs are added by a
+// script in elasticsearch instead of saving the read keywords
+func (s svc) AddKeywordIDs(ctx context.Context, caseID, field, id string, names []string) error {
+	if !validKeywordField(field) {
+		return fmt.Errorf("invalid keyword-field %q", field)
+	}
+
+	var operations []BulkOperation
+	for _, name := range names {
+		upsert := map[string]interface{}{"name": name}
+		for _, f := range keywordFields {
+			upsert[f] = []string{}
+		}
+		upsert[field] = []string{id}
+
+		operations = append(operations, BulkOperation{
+			Action: BulkUpdate,
+			Index:  s.KeywordIndex(caseID),
+			ID:     name,
+			Document: map[string]interface{}{
+				"script": map[string]interface{}{
+					"source": addKeywordScript,
+					"params": map[string]interface{}{"field": field, "id": id},
+				},
+				"upsert": upsert,
+			},
+		})
+	}
+
+	if err := s.updateKeywords(ctx, operations); err != nil {
+		return fmt.Errorf("cannot add IDs to the keywords : %v", err)
+	}
+	return nil
+}
+
+// RemoveKeywordIDs removes the ID of an object from the field in the
+// keywords, the keywords without any IDs left are deleted by the script
+func (s svc) RemoveKeywordIDs(ctx context.Context, caseID, field, id string, names []string) error {
+	if !validKeywordField(field) {
+		return fmt.Errorf("invalid keyword-field %q", field)
+	}
+
+	var operations []BulkOperation
+	for _, name := range names {
+		operations = append(operations, BulkOperation{
+			Action: BulkUpdate,
+			Index:  s.KeywordIndex(caseID),
+			ID:     name,
+			Document: map[string]interface{}{
+				"script": map[string]interface{}{
+					"source": removeKeywordScript,
+					"params": map[string]interface{}{"field": field, "id": id, "fields": keywordFields},
+				},
+			},
+		})
+	}
+
+	if err := s.updateKeywords(ctx, operations); err != nil {
+		return fmt.Errorf("cannot remove IDs from the keywords : %v", err)
+	}
+	return nil
+}
+
+// updateKeywords makes the scripted updates of the keywords, and retries
+// the ones that conflicted with an update by another request. The scripts
+// can be made again, an ID is only added or removed once
+func (s svc) updateKeywords(ctx context.Context, operations []BulkOperation) error {
+	var err error
+	for attempt := 0; attempt <= keywordRetries; attempt++ {
+		err = s.Bulk(ctx, operations)
+
+		var bulkErr *BulkError
+		if !errors.As(err, &bulkErr) || len(bulkErr.Errs) > 0 {
+			return err
+		}
+
+		operations = nil
+		for _, failure := range bulkErr.Failures {
+			if !errors.Is(failure.Err, ErrConflict) {
+				return err
+			}
+			operations = append(operations, failure.Operation)
+		}
+	}
+	return err
+}
+
+func validKeywordField(field string) bool {
+	for _, f := range keywordFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
 func (s svc) GetKeywordByID(ctx context.Context, caseID, id string) (*api.Keyword, error) {
 	resp, err := s.searchByID(ctx, indexKeyword+"-"+caseID, id)
 	if err != nil {
 		return nil, fmt.Errorf("Cannot find Keyword in Case: %v", err)
 	}
 
-	var keyword api.Keyword
-	if err := json.Unmarshal(resp, &keyword); err != nil {
-		return nil, fmt.Errorf("Keyword json.Unmarshal: %v", err)
+	var keyword api.Keyword
+	if err := json.Unmarshal(resp, &keyword); err != nil {
+		return nil, fmt.Errorf("Keyword json.Unmarshal: %v", err)
+	}
+
+	return &keyword, nil
+}
+
+func (s svc) GetKeywordsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Keyword, error) {
+	if len(ids) == 0 {
+		return []api.Keyword{}, nil
+	}
+
+	resp, err := s.searchByIDs(ctx, indexKeyword+"-"+caseID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Keywords in Case: %v", err)
+	}
+
+	var keywords []api.Keyword
+	if err := json.Unmarshal(resp, &keywords); err != nil {
+		return nil, fmt.Errorf("Keyword json.Unmarshal: %v", err)
+	}
+
+	return keywords, nil
+}
+
+func (s svc) GetKeywords(ctx context.Context, caseID string) ([]string, error) {
+	search, err := s.search(ctx, indexKeyword+"-"+caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var keywords []string
+	for _, hit := range search.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var keyword api.Keyword
+		if err := json.Unmarshal(source, &keyword); err != nil {
+			return nil, fmt.Errorf("Keyword json.Unmarshal: %v", err)
+		}
+
+		keywords = append(keywords, keyword.Name)
+	}
+
+	return keywords, nil
+}
+
+func (s svc) SearchKeywords(ctx context.Context, caseID, prefix string) ([]api.Keyword, error) {
+	search, err := s.searchWithPrefix(ctx, indexKeyword+"-"+caseID, "name", prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keywords []api.Keyword
+	if err := json.Unmarshal(search, &keywords); err != nil {
+		return nil, fmt.Errorf("Keywords json.Unmarshal: %v", err)
+	}
+	return keywords, nil
+}
+
+func (s svc) CreateUser(ctx context.Context, user *User) error {
+	if _, err := s.GetUserByEmail(ctx, user.Email); err == nil {
+		return fmt.Errorf("user with email %s already exists", user.Email)
+	}
+
+	user.CreatedAt = time.Now().Unix()
+	if err := s.save(ctx, indexUser, user.UID, user); err != nil {
+		return fmt.Errorf("failed to save User : %v", err)
+	}
+	return nil
+}
+
+func (s svc) GetUserByID(ctx context.Context, uid string) (*User, error) {
+	resp, err := s.searchByID(ctx, indexUser, uid)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find User: %v", err)
+	}
+
+	var user User
+	if err := json.Unmarshal(resp, &user); err != nil {
+		return nil, fmt.Errorf("User json.Unmarshal: %v", err)
+	}
+
+	return &user, nil
+}
+
+func (s svc) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Term: map[string]string{"email.keyword": email},
+		},
+	}
+
+	search, err := s.searchPage(ctx, indexUser, query, 0, 1)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find User: %v", err)
+	}
+	if len(search.Hits.Hits) == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	source, err := json.Marshal(search.Hits.Hits[0].Source)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %v", err)
+	}
+
+	var user User
+	if err := json.Unmarshal(source, &user); err != nil {
+		return nil, fmt.Errorf("User json.Unmarshal: %v", err)
+	}
+
+	return &user, nil
+}
+
+func (s svc) UpdateUser(ctx context.Context, user *User) error {
+	if err := s.save(ctx, indexUser, user.UID, user); err != nil {
+		return fmt.Errorf("failed to save User : %v", err)
+	}
+	return nil
+}
+
+func (s svc) DeleteUser(ctx context.Context, uid string) error {
+	if err := s.delete(ctx, indexUser, uid); err != nil {
+		return fmt.Errorf("cannot delete User : %v", err)
+	}
+	return nil
+}
+
+func (s svc) CreateToken(ctx context.Context, token *Token) error {
+	token.ID = internal.NewID()
+	token.CreatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexToken, token.ID, "", token)
+	if err != nil {
+		return fmt.Errorf("failed to save Token : %w", err)
+	}
+	token.Version = version
+	return nil
+}
+
+// UpdateToken saves the token if it still has the version it was
+// read with, so that the recorded usage isn't overwritten
+func (s svc) UpdateToken(ctx context.Context, token *Token) error {
+	token.UpdatedAt = time.Now().Unix()
+	version, err := s.saveVersion(ctx, indexToken, token.ID, token.Version, token)
+	if err != nil {
+		return fmt.Errorf("failed to save Token : %w", err)
+	}
+	token.Version = version
+	return nil
+}
+
+func (s svc) GetToken(ctx context.Context, id string) (*Token, error) {
+	resp, err := s.searchByID(ctx, indexToken, id)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Token: %v", err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(resp, &token); err != nil {
+		return nil, fmt.Errorf("Token json.Unmarshal: %v", err)
+	}
+
+	return &token, nil
+}
+
+// GetTokenByHash returns the token with the hash, the hash is a keyword
+// in the tokens-index - the tokens-index from before the template has it
+// as text, where the hash (lowercase hex) is a single term that matches too
+func (s svc) GetTokenByHash(ctx context.Context, hash string) (*Token, error) {
+	tokens, err := s.getTokensByTerm(ctx, "hash", hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("token not found")
+	}
+	return &tokens[0], nil
+}
+
+func (s svc) GetTokensByCreator(ctx context.Context, creatorID string) ([]Token, error) {
+	return s.getTokensByTerm(ctx, "creatorID.keyword", creatorID)
+}
+
+func (s svc) GetTokensByCase(ctx context.Context, caseID string) ([]Token, error) {
+	return s.getTokensByTerm(ctx, "caseIDs.keyword", caseID)
+}
+
+// RecordTokenUsage adds the number of requests to the use-count and
+// sets the last usage of the token, with a script to not lose
+// concurrent updates
+func (s svc) RecordTokenUsage(ctx context.Context, id string, count int) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"script": map[string]interface{}{
+			"source": "ctx._source.useCount += params.count; ctx._source.lastUsedAt = params.now",
+			"lang":   "painless",
+			"params": map[string]interface{}{"count": count, "now": time.Now().Unix()},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	retries := 3
+	req := esapi.UpdateRequest{
+		Index:           indexToken,
+		DocumentID:      id,
+		Body:            bytes.NewReader(body),
+		RetryOnConflict: &retries,
+	}
+
+	res, err := req.Do(ctx, s.es)
+	if err != nil {
+		return fmt.Errorf("Cannot get response: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return decodeError(res)
+	}
+	return nil
+}
+
+func (s svc) CreateUpload(ctx context.Context, upload *Upload) error {
+	upload.ID = internal.NewID()
+	upload.CreatedAt = time.Now().Unix()
+	if err := s.save(ctx, indexUpload, upload.ID, upload); err != nil {
+		return fmt.Errorf("failed to save Upload : %v", err)
+	}
+	return nil
+}
+
+func (s svc) UpdateUpload(ctx context.Context, upload *Upload) error {
+	upload.UpdatedAt = time.Now().Unix()
+	if err := s.save(ctx, indexUpload, upload.ID, upload); err != nil {
+		return fmt.Errorf("failed to save Upload : %v", err)
+	}
+	return nil
+}
+
+func (s svc) GetUpload(ctx context.Context, id string) (*Upload, error) {
+	resp, err := s.searchByID(ctx, indexUpload, id)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Upload: %v", err)
+	}
+
+	var upload Upload
+	if err := json.Unmarshal(resp, &upload); err != nil {
+		return nil, fmt.Errorf("Upload json.Unmarshal: %v", err)
+	}
+
+	return &upload, nil
+}
+
+// GetCompletedUploads returns the uploads that have a file,
+// and haven't been updated since the time, to be removed
+func (s svc) GetCompletedUploads(ctx context.Context, before int64) ([]Upload, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Bool: &internal.Bool{
+				Filter: []internal.Must{
+					{Exists: map[string]string{"field": "fileID"}},
+					{Range: map[string]internal.Range{"updatedAt": {Lte: before}}},
+				},
+			},
+		},
+	}
+
+	var uploads []Upload
+	err := s.scroll(ctx, indexUpload, query, func(hits []internal.Hit) error {
+		for _, hit := range hits {
+			source, err := json.Marshal(hit.Source)
+			if err != nil {
+				return fmt.Errorf("json.Marshal: %v", err)
+			}
+
+			var upload Upload
+			if err := json.Unmarshal(source, &upload); err != nil {
+				return fmt.Errorf("Upload json.Unmarshal: %v", err)
+			}
+			uploads = append(uploads, upload)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Uploads: %v", err)
+	}
+	return uploads, nil
+}
+
+func (s svc) DeleteUpload(ctx context.Context, id string) error {
+	if err := s.delete(ctx, indexUpload, id); err != nil {
+		return fmt.Errorf("cannot delete Upload : %v", err)
+	}
+	return nil
+}
+
+func (s svc) GetDataKeys(ctx context.Context, identifier string) ([]filestore.DataKey, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Term: map[string]string{"identifier.keyword": identifier},
+		},
+	}
+
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, indexDataKey, query, 0, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find DataKeys: %v", err)
+	}
+
+	var keys []filestore.DataKey
+	for _, hit := range search.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var key filestore.DataKey
+		if err := json.Unmarshal(source, &key); err != nil {
+			return nil, fmt.Errorf("DataKey json.Unmarshal: %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// CreateDataKey creates the data-key, it fails if the version
+// already exists so a key is never overwritten by another key
+func (s svc) CreateDataKey(ctx context.Context, key *filestore.DataKey) error {
+	if err := s.create(ctx, indexDataKey, dataKeyID(key), key); err != nil {
+		return fmt.Errorf("failed to create DataKey : %v", err)
+	}
+	return nil
+}
+
+func (s svc) UpdateDataKey(ctx context.Context, key *filestore.DataKey) error {
+	if err := s.save(ctx, indexDataKey, dataKeyID(key), key); err != nil {
+		return fmt.Errorf("failed to save DataKey : %v", err)
+	}
+	return nil
+}
+
+func dataKeyID(key *filestore.DataKey) string {
+	return fmt.Sprintf("%s-%d", key.Identifier, key.Version)
+}
+
+func (s svc) getTokensByTerm(ctx context.Context, field, value string) ([]Token, error) {
+	query := internal.QueryRequest{
+		Query: internal.Query{
+			Term: map[string]string{field: value},
+		},
+	}
+
+	// 10000 is the max result-window in elastic
+	search, err := s.searchPage(ctx, indexToken, query, 0, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot find Tokens: %v", err)
+	}
+
+	var tokens []Token
+	for _, hit := range search.Hits.Hits {
+		source, err := json.Marshal(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		var token Token
+		if err := json.Unmarshal(source, &token); err != nil {
+			return nil, fmt.Errorf("Token json.Unmarshal: %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// ProcessIndex returns the elastic-index for the processes in the specified case
+func (svc) ProcessIndex(caseID string) string { return fmt.Sprintf("%s-%s", indexProcess, caseID) }
+
+// IndexDocument indexes a document in the index, it's used
+// by the native indexer to save the processed files
+func (s svc) IndexDocument(ctx context.Context, index, id string, document interface{}) error {
+	if err := s.save(ctx, index, id, document); err != nil {
+		return fmt.Errorf("failed to index document : %v", err)
+	}
+	return nil
+}
+
+// PutIndexTemplates creates the index-templates, or updates the
+// templates that has an older version, it's done at startup
+// before any index for a case is created
+func (s svc) PutIndexTemplates(ctx context.Context) error {
+	for _, template := range indexTemplates {
+		name := templatePrefix + template.name
+		version, err := s.indexTemplateVersion(ctx, name)
+		if err != nil {
+			return fmt.Errorf("cannot get index-template %s: %v", name, err)
+		}
+		if version >= templateVersion {
+			continue
+		}
+
+		body, err := json.Marshal(template.body())
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %v", err)
+		}
+
+		res, err := s.es.Indices.PutIndexTemplate(name, bytes.NewReader(body), s.es.Indices.PutIndexTemplate.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("Cannot get response: %v", err)
+		}
+		if res.IsError() {
+			err := decodeError(res)
+			res.Body.Close()
+			return fmt.Errorf("cannot put index-template %s: %v", name, err)
+		}
+		res.Body.Close()
+	}
+	return nil
+}
+
+// MigrateIndices reindexes the indices that were created with an older
+// version of their template, or before the templates, so they get the
+// current mappings. An index is reindexed to {name}-v{templateVersion},
+// and replaced by an alias with its name so the API still uses the same
+// names. The documents that are saved during the migration may be lost,
+// so it's made while the API is stopped (with the migrate-flag)
+func (s svc) MigrateIndices(ctx context.Context) error {
+	for _, template := range indexTemplates {
+		indices, err := s.outdatedIndices(ctx, template.patterns)
+		if err != nil {
+			return fmt.Errorf("cannot get the indices for %s: %v", template.name, err)
+		}
+
+		for _, index := range indices {
+			if err := s.migrateIndex(ctx, template, index); err != nil {
+				return fmt.Errorf("cannot migrate index %s: %v", index.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// outdatedIndex is an index with an older
+// template-version than templateVersion
+type outdatedIndex struct {
+	// index is the name of the index in elasticsearch
+	index string
+
+	// name is the name the API uses for the index, it's
+	// the alias for an index that has been migrated before
+	name string
+}
+
+// outdatedIndices returns the indices with the patterns that
+// were created with an older version of the template
+func (s svc) outdatedIndices(ctx context.Context, patterns []string) ([]outdatedIndex, error) {
+	res, err := s.es.Indices.Get(patterns,
+		s.es.Indices.Get.WithContext(ctx),
+		s.es.Indices.Get.WithAllowNoIndices(true),
+		s.es.Indices.Get.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot get response: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if res.IsError() {
+		return nil, decodeError(res)
+	}
+
+	var indices map[string]struct {
+		Aliases  map[string]interface{} `json:"aliases"`
+		Mappings struct {
+			Meta struct {
+				TemplateVersion int `json:"template_version"`
+			} `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
+	}
+
+	var outdated []outdatedIndex
+	for index, state := range indices {
+		if state.Mappings.Meta.TemplateVersion >= templateVersion {
+			continue
+		}
+		name := index
+		for alias := range state.Aliases {
+			name = alias
+		}
+		outdated = append(outdated, outdatedIndex{index: index, name: name})
+	}
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].index < outdated[j].index })
+	return outdated, nil
+}
+
+// migrateIndex reindexes the index to a new index with the mappings
+// in the template, and replaces it with an alias to the new index.
+// The new index is recreated if a migration was interrupted before
+func (s svc) migrateIndex(ctx context.Context, template indexTemplate, index outdatedIndex) error {
+	target := fmt.Sprintf("%s-v%d", index.name, templateVersion)
+	if err := s.deleteIndex(ctx, target); err != nil {
+		return err
+	}
+	if err := s.newIndex(ctx, target, template.index()); err != nil {
+		return fmt.Errorf("cannot create index %s: %v", target, err)
+	}
+
+	if err := s.reindex(ctx, index.index, target); err != nil {
+		return fmt.Errorf("cannot reindex to %s: %v", target, err)
+	}
+
+	// The old index is removed in the same request as the
+	// alias is added, so the name is never missing
+	aliases, err := json.Marshal(map[string]interface{}{
+		"actions": []map[string]interface{}{
+			{"add": map[string]string{"index": target, "alias": index.name}},
+			{"remove_index": map[string]string{"index": index.index}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	res, err := s.es.Indices.UpdateAliases(bytes.NewReader(aliases), s.es.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("Cannot get response: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("cannot replace the index with an alias: %v", decodeError(res))
 	}
-
-	return &keyword, nil
+	return nil
 }
 
-func (s svc) GetKeywordsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Keyword, error) {
-	if len(ids) == 0 {
-		return []api.Keyword{}, nil
+// reindex copies the documents in the source-index to the
+// dest-index, and waits until every document is copied
+func (s svc) reindex(ctx context.Context, source, dest string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"source": map[string]string{"index": source},
+		"dest":   map[string]string{"index": dest},
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
 	}
 
-	resp, err := s.searchByIDs(ctx, indexKeyword+"-"+caseID, ids)
+	res, err := s.es.Reindex(bytes.NewReader(body),
+		s.es.Reindex.WithContext(ctx),
+		s.es.Reindex.WithRefresh(true),
+		s.es.Reindex.WithWaitForCompletion(true),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("Cannot find Keywords in Case: %v", err)
+		return fmt.Errorf("Cannot get response: %v", err)
 	}
+	defer res.Body.Close()
 
-	var keywords []api.Keyword
-	if err := json.Unmarshal(resp, &keywords); err != nil {
-		return nil, fmt.Errorf("Keyword json.Unmarshal: %v", err)
+	if res.IsError() {
+		return decodeError(res)
 	}
 
-	return keywords, nil
+	var result struct {
+		Failures []interface{} `json:"failures"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("Cannot parse the response body: %v", err)
+	}
+	if len(result.Failures) > 0 {
+		failuresJSON, _ := json.Marshal(result.Failures)
+		return fmt.Errorf("%d documents failed: %s", len(result.Failures), failuresJSON)
+	}
+	return nil
 }
 
-func (s svc) GetKeywords(ctx context.Context, caseID string) ([]string, error) {
-	search, err := s.search(ctx, indexKeyword+"-"+caseID)
+// deleteIndex deletes the index if it exists
+func (s svc) deleteIndex(ctx context.Context, index string) error {
+	res, err := s.es.Indices.Delete([]string{index}, s.es.Indices.Delete.WithContext(ctx))
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("Cannot get response: %v", err)
 	}
+	defer res.Body.Close()
 
-	var keywords []string
-	for _, hit := range search.Hits.Hits {
-		source, err := json.Marshal(hit.Source)
-		if err != nil {
-			return nil, fmt.Errorf("json.Marshal: %v", err)
-		}
-
-		var keyword api.Keyword
-		if err := json.Unmarshal(source, &keyword); err != nil {
-			return nil, fmt.Errorf("Keyword json.Unmarshal: %v", err)
-		}
-
-		keywords = append(keywords, keyword.Name)
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("cannot delete index %s: %v", index, decodeError(res))
 	}
-
-	return keywords, nil
+	return nil
 }
 
-func (s svc) SearchKeywords(ctx context.Context, caseID, prefix string) ([]api.Keyword, error) {
-	search, err := s.searchWithPrefix(ctx, indexKeyword+"-"+caseID, "name", prefix)
+// indexTemplateVersion returns the version of the
+// index-template, or 0 if it doesn't exist
+func (s svc) indexTemplateVersion(ctx context.Context, name string) (int, error) {
+	res, err := s.es.Indices.GetIndexTemplate(
+		s.es.Indices.GetIndexTemplate.WithContext(ctx),
+		s.es.Indices.GetIndexTemplate.WithName(name),
+	)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("Cannot get response: %v", err)
 	}
+	defer res.Body.Close()
 
-	var keywords []api.Keyword
-	if err := json.Unmarshal(search, &keywords); err != nil {
-		return nil, fmt.Errorf("Keywords json.Unmarshal: %v", err)
+	if res.StatusCode == http.StatusNotFound {
+		return 0, nil
+	} else if res.IsError() {
+		return 0, decodeError(res)
 	}
-	return keywords, nil
+
+	var templates struct {
+		IndexTemplates []struct {
+			IndexTemplate struct {
+				Version int `json:"version"`
+			} `json:"index_template"`
+		} `json:"index_templates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&templates); err != nil {
+		return 0, fmt.Errorf("Cannot parse the response body: %v", err)
+	}
+	if len(templates.IndexTemplates) == 0 {
+		return 0, nil
+	}
+	return templates.IndexTemplates[0].IndexTemplate.Version, nil
 }
 
-// ProcessIndex returns the elastic-index for the processes in the specified case
-func (svc) ProcessIndex(caseID string) string { return fmt.Sprintf("%s-%s", indexProcess, caseID) }
+// newIndex creates a new index,
+// with the mapping if specified
+func (s svc) newIndex(ctx context.Context, index string, mapping interface{}) error {
+	var body io.Reader
+	if mapping != nil {
+		mappingJSON, err := json.Marshal(mapping)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(mappingJSON)
+	}
 
-// newIndex creates a new index
-func (s svc) newIndex(ctx context.Context, index string) error {
 	// Create a request
-	req, err := http.NewRequest(http.MethodPut, s.urls[0]+"/"+index, nil)
+	req, err := http.NewRequest(http.MethodPut, s.urls[0]+"/"+index, body)
 	if err != nil {
 		return fmt.Errorf("failed to create http-request: %v", err)
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.WithContext(ctx)
 
 	// Perform the request
@@ -991,9 +2507,17 @@ func (s svc) newIndex(ctx context.Context, index string) error {
 }
 
 func (s svc) save(ctx context.Context, index, id string, data interface{}) error {
-	dataJSON, err := json.Marshal(data)
+	_, err := s.saveVersion(ctx, index, id, "", data)
+	return err
+}
+
+// saveVersion saves the document and returns the new version of it. If
+// the version is set the document is only saved if it still has the
+// version, otherwise ErrConflict is returned
+func (s svc) saveVersion(ctx context.Context, index, id, version string, data interface{}) (string, error) {
+	dataJSON, err := documentJSON(index, data)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req := esapi.IndexRequest{
@@ -1002,27 +2526,164 @@ func (s svc) save(ctx context.Context, index, id string, data interface{}) error
 		Body:       bytes.NewReader(dataJSON),
 		Refresh:    "true",
 	}
+	if version != "" {
+		seqNo, primaryTerm, err := parseVersion(version)
+		if err != nil {
+			return "", err
+		}
+		req.IfSeqNo = &seqNo
+		req.IfPrimaryTerm = &primaryTerm
+	}
 
 	// Perform the request with the client.
 	res, err := req.Do(ctx, s.es)
 	if err != nil {
-		return fmt.Errorf("Cannot get response: %v", err)
+		return "", fmt.Errorf("Cannot get response: %v", err)
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusConflict && version != "" {
+		return "", ErrConflict
+	}
 	if res.IsError() {
-		return decodeError(res)
+		return "", decodeError(res)
 	}
 
-	// Deserialize the response into a map.
-	var r internal.Response
+	// Deserialize the response to get the new version
+	var r internal.Hit
 	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
-		return fmt.Errorf("Cannot parse the response body: %v", err)
+		return "", fmt.Errorf("Cannot parse the response body: %v", err)
+	}
+	if r.SeqNo == nil || r.PrimaryTerm == nil {
+		return "", nil
+	}
+	return formatVersion(*r.SeqNo, *r.PrimaryTerm), nil
+}
+
+// create saves the document if
+// the ID doesn't already exist
+func (s svc) create(ctx context.Context, index, id string, data interface{}) error {
+	dataJSON, err := documentJSON(index, data)
+	if err != nil {
+		return err
+	}
+
+	req := esapi.CreateRequest{
+		Index:      index,
+		DocumentID: id,
+		Body:       bytes.NewReader(dataJSON),
+		Refresh:    "true",
+	}
+
+	res, err := req.Do(ctx, s.es)
+	if err != nil {
+		return fmt.Errorf("Cannot get response: %v", err)
 	}
+	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusConflict {
+		return errExists
+	}
+	if res.IsError() {
+		return decodeError(res)
+	}
 	return nil
 }
 
+// ErrConflict is returned when a document is saved with a
+// version, and the document has been changed since that version
+var ErrConflict = errors.New("the document has been changed since it was read")
+
+// versionedIndices are the indices with the API-models, the
+// version of the documents in them is set in api.Base.Version
+var versionedIndices = []string{
+	indexCase, indexEvent, indexEntity, indexPerson, indexFile,
+	indexLink, indexCustody, indexSuggestion, indexJob, indexToken,
+}
+
+// versioned returns true if the documents in the index has a version
+func versioned(index string) bool {
+	for _, prefix := range versionedIndices {
+		if index == prefix || strings.HasPrefix(index, prefix+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// formatVersion formats the sequence-number and primary-term in
+// elasticsearch as the version of a document, like "12-1"
+func formatVersion(seqNo, primaryTerm int) string {
+	return strconv.Itoa(seqNo) + "-" + strconv.Itoa(primaryTerm)
+}
+
+// parseVersion parses the sequence-number and primary-term in the version
+func parseVersion(version string) (int, int, error) {
+	parts := strings.Split(version, "-")
+	if len(parts) == 2 {
+		seqNo, err := strconv.Atoi(parts[0])
+		if err == nil {
+			primaryTerm, err := strconv.Atoi(parts[1])
+			if err == nil {
+				return seqNo, primaryTerm, nil
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid version %q", version)
+}
+
+// setVersions sets the version in the sources of the hits,
+// for the documents in the versioned indices
+func setVersions(hits []internal.Hit) {
+	for _, hit := range hits {
+		source, ok := hit.Source.(map[string]interface{})
+		if !ok || hit.SeqNo == nil || hit.PrimaryTerm == nil || !versioned(hit.Index) {
+			continue
+		}
+		source["version"] = formatVersion(*hit.SeqNo, *hit.PrimaryTerm)
+	}
+}
+
+// documentJSON encodes the document to save in the index, the version
+// isn't saved since it's the sequence-number of the document
+func documentJSON(index string, data interface{}) ([]byte, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil || !versioned(index) {
+		return dataJSON, err
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(dataJSON, &document); err != nil {
+		return dataJSON, nil
+	}
+	if _, ok := document["version"]; !ok {
+		return dataJSON, nil
+	}
+	delete(document, "version")
+	return json.Marshal(document)
+}
+
+// errExists is returned by create if the document already exists
+var errExists = errors.New("document already exists")
+
+// ensureIndex creates the index if it doesn't exist
+func (s svc) ensureIndex(ctx context.Context, index string) error {
+	res, err := s.es.Indices.Exists([]string{index}, s.es.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("Cannot get response: %v", err)
+	}
+	res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return s.newIndex(ctx, index, nil)
+	default:
+		return fmt.Errorf("[%s] cannot check if index %s exists", res.Status(), index)
+	}
+}
+
 func (s svc) delete(ctx context.Context, index, id string) error {
 	req := esapi.DeleteRequest{
 		Index:      index,
@@ -1050,17 +2711,32 @@ func (s svc) delete(ctx context.Context, index, id string) error {
 	return nil
 }
 
+// searchWithPrefix searches for the words that starts with the prefix in
+// the autocomplete-subfield for the field, the indices that were created
+// before the index-templates doesn't have it, and are searched with
+// a phrase-prefix in the field instead
 func (s svc) searchWithPrefix(ctx context.Context, index, field, prefix string) ([]byte, error) {
 	query := internal.QueryRequest{
 		Query: internal.Query{
 			Bool: &internal.Bool{
-				Must: []internal.Must{{
-					MatchPhrasePrefix: map[string]interface{}{
-						field: map[string]string{
-							"query": prefix,
+				Should: []internal.Must{
+					{
+						Match: map[string]interface{}{
+							field + ".autocomplete": map[string]string{
+								"query":    prefix,
+								"operator": "and",
+							},
 						},
 					},
-				}},
+					{
+						MatchPhrasePrefix: map[string]interface{}{
+							field: map[string]string{
+								"query": prefix,
+							},
+						},
+					},
+				},
+				MinimumShouldMatch: 1,
 			},
 		},
 	}
@@ -1075,6 +2751,7 @@ func (s svc) searchWithPrefix(ctx context.Context, index, field, prefix string)
 	res, err := s.es.Search(
 		s.es.Search.WithContext(ctx),
 		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
 		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
 		s.es.Search.WithSort("_doc"),
 		s.es.Search.WithSize(10),
@@ -1093,6 +2770,7 @@ func (s svc) searchWithPrefix(ctx context.Context, index, field, prefix string)
 	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 	}
+	setVersions(search.Hits.Hits)
 
 	var hits []interface{}
 	for _, hit := range search.Hits.Hits {
@@ -1115,6 +2793,7 @@ func (s svc) searchWithPrefix(ctx context.Context, index, field, prefix string)
 		if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 			return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 		}
+		setVersions(search.Hits.Hits)
 
 		for _, hit := range search.Hits.Hits {
 			hits = append(hits, hit.Source)
@@ -1153,6 +2832,7 @@ func (s svc) searchWithWildcard(ctx context.Context, index, field, wildcard stri
 	res, err := s.es.Search(
 		s.es.Search.WithContext(ctx),
 		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
 		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
 		s.es.Search.WithSort("_doc"),
 		s.es.Search.WithSize(10),
@@ -1171,6 +2851,7 @@ func (s svc) searchWithWildcard(ctx context.Context, index, field, wildcard stri
 	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 	}
+	setVersions(search.Hits.Hits)
 
 	var hits []interface{}
 	for _, hit := range search.Hits.Hits {
@@ -1193,6 +2874,7 @@ func (s svc) searchWithWildcard(ctx context.Context, index, field, wildcard stri
 		if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 			return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 		}
+		setVersions(search.Hits.Hits)
 
 		for _, hit := range search.Hits.Hits {
 			hits = append(hits, hit.Source)
@@ -1224,6 +2906,7 @@ func (s svc) searchByIDs(ctx context.Context, index string, ids []string) ([]byt
 	res, err := s.es.Search(
 		s.es.Search.WithContext(ctx),
 		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
 		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
 		s.es.Search.WithSort("_doc"),
 		s.es.Search.WithSize(10),
@@ -1242,6 +2925,7 @@ func (s svc) searchByIDs(ctx context.Context, index string, ids []string) ([]byt
 	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 	}
+	setVersions(search.Hits.Hits)
 
 	// make a hash-map of the ids
 	var idMap = make(map[string]bool)
@@ -1272,6 +2956,7 @@ func (s svc) searchByIDs(ctx context.Context, index string, ids []string) ([]byt
 		if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 			return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 		}
+		setVersions(search.Hits.Hits)
 
 		for _, hit := range search.Hits.Hits {
 			if idMap[hit.ID] {
@@ -1301,6 +2986,7 @@ func (s svc) searchByID(ctx context.Context, index, id string) ([]byte, error) {
 	res, err := s.es.Search(
 		s.es.Search.WithContext(ctx),
 		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
 		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
 		//es.Search.WithTrackTotalHits(true),
 		//es.Search.WithPretty(),
@@ -1318,6 +3004,7 @@ func (s svc) searchByID(ctx context.Context, index, id string) ([]byte, error) {
 	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 	}
+	setVersions(search.Hits.Hits)
 
 	for _, hit := range search.Hits.Hits {
 		if hit.ID == id {
@@ -1337,6 +3024,7 @@ func (s svc) search(ctx context.Context, index string) (*internal.Response, erro
 
 	res, err := s.es.Search(
 		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
 		s.es.Search.WithSort("_doc"),
 		s.es.Search.WithSize(10),
 		s.es.Search.WithScroll(scrollDuration),
@@ -1354,6 +3042,7 @@ func (s svc) search(ctx context.Context, index string) (*internal.Response, erro
 	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 	}
+	setVersions(search.Hits.Hits)
 
 	// init a variable to store all hits
 	hits := search.Hits.Hits
@@ -1374,6 +3063,7 @@ func (s svc) search(ctx context.Context, index string) (*internal.Response, erro
 		if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 			return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 		}
+		setVersions(search.Hits.Hits)
 
 		// append the hits from the latest scroll
 		hits = append(hits, search.Hits.Hits...)
@@ -1392,6 +3082,237 @@ func (s svc) search(ctx context.Context, index string) (*internal.Response, erro
 	return &search, nil
 }
 
+// scrollSize is the number of hits per scroll-request
+const scrollSize = 1000
+
+// scroll performs the query and calls fn with the hits from every
+// scroll-request, so all the hits are handled without keeping them
+// in memory. The scroll is cleared when it's done or fn fails
+func (s svc) scroll(ctx context.Context, index string, query internal.QueryRequest, fn func(hits []internal.Hit) error) error {
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+
+	scrollDuration := time.Minute
+	res, err := s.es.Search(
+		s.es.Search.WithContext(ctx),
+		s.es.Search.WithIndex(index),
+		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
+		s.es.Search.WithSort("_doc"),
+		s.es.Search.WithSize(scrollSize),
+		s.es.Search.WithScroll(scrollDuration),
+		s.es.Search.WithIgnoreUnavailable(true),
+	)
+
+	var scrollID string
+	defer func() {
+		if scrollID != "" {
+			s.clearScroll(scrollID)
+		}
+	}()
+
+	for {
+		if err != nil {
+			return fmt.Errorf("Cannot get response: %v", err)
+		}
+
+		var search internal.Response
+		if res.IsError() {
+			err = decodeError(res)
+		} else if err = json.NewDecoder(res.Body).Decode(&search); err != nil {
+			err = fmt.Errorf("Cannot parse the response body: %v", err)
+		}
+		res.Body.Close()
+		if search.ScrollID != "" {
+			scrollID = search.ScrollID
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(search.Hits.Hits) == 0 {
+			return nil
+		}
+		if err := fn(search.Hits.Hits); err != nil {
+			return err
+		}
+		if scrollID == "" {
+			return nil
+		}
+
+		res, err = s.es.Scroll(
+			s.es.Scroll.WithContext(ctx),
+			s.es.Scroll.WithScrollID(scrollID),
+			s.es.Scroll.WithScroll(scrollDuration),
+		)
+	}
+}
+
+// clearScroll clears the scroll, so elasticsearch doesn't keep
+// its context until it expires - it expires if it can't be cleared
+func (s svc) clearScroll(scrollID string) {
+	res, err := s.es.ClearScroll(s.es.ClearScroll.WithScrollID(scrollID))
+	if err == nil {
+		res.Body.Close()
+	}
+}
+
+// searchPage performs the query and returns a single page of
+// hits from the index, together with the total number of hits.
+// A missing index is treated as an empty result.
+func (s svc) searchPage(ctx context.Context, index string, query internal.QueryRequest, from, size int, sort ...string) (*internal.Response, error) {
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perform the search request.
+	res, err := s.es.Search(
+		s.es.Search.WithContext(ctx),
+		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
+		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
+		s.es.Search.WithSort(sort...),
+		s.es.Search.WithFrom(from),
+		s.es.Search.WithSize(size),
+		s.es.Search.WithTrackTotalHits(true),
+		s.es.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot get response: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, decodeError(res)
+	}
+
+	var search internal.Response
+	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
+		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
+	}
+	setVersions(search.Hits.Hits)
+
+	return &search, nil
+}
+
+// listFields are the fields that an index can be sorted and
+// filtered by in a list, by their names in the API
+type listFields struct {
+	sort    map[string]string
+	filters map[string]string
+}
+
+// sortField returns the field in the index for the sort
+func (f listFields) sortField(sort string) (string, error) {
+	field, ok := f.sort[sort]
+	if !ok {
+		return "", fmt.Errorf("cannot sort by %q", sort)
+	}
+	return field, nil
+}
+
+// list returns a page of the hits in the index that matches
+// the filters and the must-queries, sorted by the list-fields
+func (s svc) list(ctx context.Context, index string, fields listFields, filters map[string]string, page Page, must ...internal.Must) (*internal.Response, string, error) {
+	field, err := fields.sortField(page.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for name, value := range filters {
+		filterField, ok := fields.filters[name]
+		if !ok {
+			return nil, "", fmt.Errorf("cannot filter by %q", name)
+		}
+		must = append(must, internal.Must{Term: map[string]string{filterField: value}})
+	}
+
+	query := internal.QueryRequest{Query: internal.Query{MatchAll: map[string]interface{}{}}}
+	if len(must) > 0 {
+		query = internal.QueryRequest{Query: internal.Query{Bool: &internal.Bool{Filter: must}}}
+	}
+	return s.searchAfter(ctx, index, query, page, field)
+}
+
+// decodeHits decodes the sources of the hits to the slice v points to
+func decodeHits(hits []internal.Hit, v interface{}) error {
+	sources := make([]interface{}, len(hits))
+	for i, hit := range hits {
+		sources[i] = hit.Source
+	}
+
+	sourcesJSON, err := json.Marshal(sources)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	if err := json.Unmarshal(sourcesJSON, v); err != nil {
+		return fmt.Errorf("json.Unmarshal: %v", err)
+	}
+	return nil
+}
+
+// searchAfter returns a page of the hits for the query sorted by the
+// field, and by the ID for the hits with the same value. The cursor
+// is the sort-values of the last hit, the hits after it are returned
+// with search_after, and the cursor for the next page is empty when
+// there aren't any more hits
+func (s svc) searchAfter(ctx context.Context, index string, query internal.QueryRequest, page Page, field string) (*internal.Response, string, error) {
+	if page.Order != "asc" && page.Order != "desc" {
+		return nil, "", fmt.Errorf("invalid sort-order %q", page.Order)
+	}
+	if page.Cursor != "" {
+		after, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query.SearchAfter = after
+	}
+
+	search, err := s.searchPage(ctx, index, query, 0, page.Size, field+":"+page.Order, "id.keyword:"+page.Order)
+	if err != nil {
+		return nil, "", err
+	}
+
+	hits := search.Hits.Hits
+	if len(hits) == 0 || len(hits) < page.Size {
+		return search, "", nil
+	}
+	next, err := encodeCursor(hits[len(hits)-1].Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	return search, next, nil
+}
+
+// encodeCursor encodes the sort-values as an opaque cursor
+func encodeCursor(values []interface{}) (string, error) {
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(valuesJSON), nil
+}
+
+// decodeCursor decodes the sort-values in the cursor
+func decodeCursor(cursor string) ([]interface{}, error) {
+	valuesJSON, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	// The numbers are kept as they are, so large
+	// values aren't rounded by converting them
+	var values []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(valuesJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil || len(values) == 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	return values, nil
+}
+
 func (s svc) searchByName(ctx context.Context, index, name string) (*internal.Response, error) {
 	var query internal.QueryRequest
 	query.Query.Match = map[string]string{"name": name}
@@ -1405,6 +3326,7 @@ func (s svc) searchByName(ctx context.Context, index, name string) (*internal.Re
 	res, err := s.es.Search(
 		s.es.Search.WithContext(ctx),
 		s.es.Search.WithIndex(index),
+		s.es.Search.WithSeqNoPrimaryTerm(true),
 		s.es.Search.WithBody(bytes.NewReader(queryJSON)),
 		//es.Search.WithTrackTotalHits(true),
 		//es.Search.WithPretty(),
@@ -1422,6 +3344,7 @@ func (s svc) searchByName(ctx context.Context, index, name string) (*internal.Re
 	if err := json.NewDecoder(res.Body).Decode(&search); err != nil {
 		return nil, fmt.Errorf("Cannot parse the response body: %v", err)
 	}
+	setVersions(search.Hits.Hits)
 
 	return &search, nil
 }
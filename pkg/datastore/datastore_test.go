@@ -2,10 +2,21 @@ package datastore_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
 	"github.com/matryer/is"
 )
 
@@ -22,3 +33,670 @@ func TestSearch(t *testing.T) {
 	is.NoErr(err)
 	log.Println(keywords)
 }
+
+func TestNewProcessedDocument(t *testing.T) {
+	is := is.New(t)
+
+	document := datastore.NewProcessedDocument("file-1-abc", indexer.Processed{
+		Content: "Dear John",
+		Meta: indexer.Meta{
+			Title:   "Letter",
+			Created: "2021-01-15T10:00:00.000Z",
+			Date:    "2021-01-16T10:00:00+01:00",
+			Raw:     map[string]string{"meta:page-count": "2"},
+		},
+		File: indexer.File{
+			Filename:     "letter.docx",
+			ContentType:  "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			Filesize:     1024,
+			LastModified: "2021-01-14T09:30:00.000Z",
+		},
+		Path:   indexer.Path{Virtual: "/evidence.zip/letter.docx"},
+		Parent: &indexer.Parent{FileID: "file-1", ID: "file-1", Path: "/evidence.zip", Depth: 1},
+	})
+	is.Equal(document.ID, "file-1-abc")
+	is.Equal(document.FileID, "file-1")
+	is.Equal(document.ParentID, "file-1")
+	is.Equal(document.Depth, 1)
+	is.Equal(document.Path, "/evidence.zip/letter.docx")
+	is.Equal(document.Name, "letter.docx")
+	is.Equal(document.Size, int64(1024))
+	is.Equal(document.Content, "Dear John")
+	is.Equal(document.Pages, 2)
+	is.Equal(document.CreatedAt, time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC).Unix())
+	is.Equal(document.ModifiedAt, time.Date(2021, 1, 16, 9, 0, 0, 0, time.UTC).Unix())
+	is.Equal(document.FileModifiedAt, time.Date(2021, 1, 14, 9, 30, 0, 0, time.UTC).Unix())
+	is.Equal(document.Container, false)
+
+	// The uploaded files only have the time they were stored
+	document = datastore.NewProcessedDocument("file-2", indexer.Processed{
+		File:      indexer.File{Filename: "evidence.zip", LastModified: "2021-01-14T09:30:00.000Z"},
+		Container: &indexer.Container{Children: 3},
+	})
+	is.Equal(document.FileID, "file-2")
+	is.Equal(document.FileModifiedAt, int64(0))
+	is.Equal(document.Container, true)
+	is.Equal(document.Children, 3)
+	is.Equal(document.Keywords, []string{})
+}
+
+func TestPutIndexTemplates(t *testing.T) {
+	is := is.New(t)
+
+	// The events-template is up to date, the cases-template
+	// is outdated and the other templates doesn't exist
+	var mu sync.Mutex
+	put := make(map[string]map[string]interface{})
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/_index_template/")
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, `{}`)
+		case r.Method == http.MethodGet && name == "timeline-investigator-events":
+			fmt.Fprint(w, `{"index_templates":[{"name":"timeline-investigator-events","index_template":{"version":1}}]}`)
+		case r.Method == http.MethodGet && name == "timeline-investigator-cases":
+			fmt.Fprint(w, `{"index_templates":[{"name":"timeline-investigator-cases","index_template":{}}]}`)
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"resource_not_found_exception","reason":"not found"},"status":404}`)
+		case r.Method == http.MethodPut:
+			var body map[string]interface{}
+			is.NoErr(json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			put[name] = body
+			mu.Unlock()
+			fmt.Fprint(w, `{"acknowledged":true}`)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	is.NoErr(db.PutIndexTemplates(context.Background()))
+
+	is.Equal(len(put), 8)
+	is.True(put["timeline-investigator-events"] == nil)
+	tokens := put["timeline-investigator-tokens"]["template"].(map[string]interface{})["mappings"].(map[string]interface{})
+	is.Equal(tokens["properties"].(map[string]interface{})["hash"], map[string]interface{}{"type": "keyword"})
+	is.Equal(put["timeline-investigator-files"]["index_patterns"], []interface{}{"files-*"})
+	cases := put["timeline-investigator-cases"]
+	is.Equal(cases["version"], float64(1))
+	is.Equal(cases["index_patterns"], []interface{}{"cases"})
+
+	persons := put["timeline-investigator-persons"]
+	is.Equal(persons["index_patterns"], []interface{}{"persons-*"})
+	template := persons["template"].(map[string]interface{})
+	analyzers := template["settings"].(map[string]interface{})["analysis"].(map[string]interface{})["analyzer"].(map[string]interface{})
+	is.True(analyzers["autocomplete"] != nil)
+	properties := template["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+	is.Equal(properties["custom"], map[string]interface{}{"type": "flattened"})
+	is.Equal(properties["createdAt"], map[string]interface{}{"type": "date", "format": "epoch_second"})
+	firstName := properties["firstName"].(map[string]interface{})["fields"].(map[string]interface{})
+	is.Equal(firstName["autocomplete"].(map[string]interface{})["analyzer"], "autocomplete")
+}
+
+func TestMigrateIndices(t *testing.T) {
+	is := is.New(t)
+
+	// events-case-1 is from before the templates, and events-case-2
+	// has been migrated to the current version of the template
+	var mu sync.Mutex
+	var requests []string
+	var created, reindex, aliases map[string]interface{}
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/" && r.Method != http.MethodGet {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+		}
+
+		decode := func() map[string]interface{} {
+			var body map[string]interface{}
+			is.NoErr(json.NewDecoder(r.Body).Decode(&body))
+			return body
+		}
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, `{}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/events-*":
+			fmt.Fprint(w, `{
+				"events-case-1":{"aliases":{},"mappings":{"properties":{"fromDate":{"type":"long"}}}},
+				"events-case-2-v1":{"aliases":{"events-case-2":{}},"mappings":{"_meta":{"template_version":1}}}
+			}`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{}`)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"index_not_found_exception","reason":"no such index"},"status":404}`)
+		case r.Method == http.MethodPut:
+			created = decode()
+			fmt.Fprint(w, `{"acknowledged":true}`)
+		case r.URL.Path == "/_reindex":
+			is.Equal(r.URL.Query().Get("wait_for_completion"), "true")
+			reindex = decode()
+			fmt.Fprint(w, `{"total":2,"created":2,"failures":[]}`)
+		case r.URL.Path == "/_aliases":
+			aliases = decode()
+			fmt.Fprint(w, `{"acknowledged":true}`)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	is.NoErr(db.MigrateIndices(context.Background()))
+
+	// Only the outdated index is reindexed, and
+	// replaced by an alias with the same name
+	is.Equal(requests, []string{
+		"DELETE /events-case-1-v1",
+		"PUT /events-case-1-v1",
+		"POST /_reindex",
+		"POST /_aliases",
+	})
+	mappings := created["mappings"].(map[string]interface{})
+	is.Equal(mappings["_meta"], map[string]interface{}{"template_version": float64(1)})
+	fromDate := mappings["properties"].(map[string]interface{})["fromDate"]
+	is.Equal(fromDate, map[string]interface{}{"type": "date", "format": "epoch_second"})
+	is.Equal(reindex["source"], map[string]interface{}{"index": "events-case-1"})
+	is.Equal(reindex["dest"], map[string]interface{}{"index": "events-case-1-v1"})
+	is.Equal(aliases["actions"], []interface{}{
+		map[string]interface{}{"add": map[string]interface{}{"index": "events-case-1-v1", "alias": "events-case-1"}},
+		map[string]interface{}{"remove_index": map[string]interface{}{"index": "events-case-1"}},
+	})
+}
+
+func TestMigrateCaseFiles(t *testing.T) {
+	is := is.New(t)
+
+	// case-1 has two files in the case-document, and one of
+	// them was moved before the migration was interrupted
+	var mu sync.Mutex
+	var requests []string
+	created := make(map[string]api.File)
+	var saved api.Case
+	var migration map[string]int64
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, `{}`)
+		case r.Method == http.MethodHead && r.URL.Path == "/migrations/_doc/case-files":
+			if migration == nil {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.URL.Path == "/migrations/_doc/case-files":
+			is.NoErr(json.NewDecoder(r.Body).Decode(&migration))
+			fmt.Fprint(w, `{"result":"created"}`)
+		case r.Method == http.MethodHead && r.URL.Path == "/files-case-2":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/cases/_search":
+			fmt.Fprint(w, `{"_scroll_id":"scroll","hits":{"hits":[
+				{"_id":"case-1","_source":{"id":"case-1","name":"Fraud","files":[{"id":"file-1","name":"a.txt"},{"id":"file-2","name":"b.txt"}]}},
+				{"_id":"case-2","_source":{"id":"case-2","name":"Theft"}}
+			]}}`)
+		case r.URL.Path == "/_search/scroll":
+			fmt.Fprint(w, `{"_scroll_id":"scroll","hits":{"hits":[]}}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/files-case-2":
+			fmt.Fprint(w, `{"acknowledged":true}`)
+		case r.URL.Path == "/files-case-1/_doc/file-1/_create":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error":{"type":"version_conflict_engine_exception","reason":"document already exists"},"status":409}`)
+		case strings.HasPrefix(r.URL.Path, "/files-case-1/_doc/"):
+			var file api.File
+			is.NoErr(json.NewDecoder(r.Body).Decode(&file))
+			created[file.ID] = file
+			fmt.Fprint(w, `{"result":"created"}`)
+		case r.URL.Path == "/cases/_doc/case-1":
+			is.NoErr(json.NewDecoder(r.Body).Decode(&saved))
+			fmt.Fprint(w, `{"result":"updated"}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	is.NoErr(db.MigrateCaseFiles(context.Background()))
+
+	is.Equal(len(created), 1)
+	is.Equal(created["file-2"].Name, "b.txt")
+	is.Equal(saved.ID, "case-1")
+	is.Equal(saved.Name, "Fraud")
+	is.Equal(len(saved.Files), 0)
+	is.True(contains(requests, "PUT /files-case-2"))
+	is.True(!contains(requests, "PUT /cases/_doc/case-2")) // cases without files are kept as is
+	is.True(migration["migratedAt"] > 0)
+
+	// The cases aren't read again when the migration is finished
+	requests = nil
+	is.NoErr(db.MigrateCaseFiles(context.Background()))
+	is.Equal(requests, []string{"HEAD /migrations/_doc/case-files"})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestListFiles(t *testing.T) {
+	is := is.New(t)
+
+	// The fake returns two files with their sort-values,
+	// and keeps the last query and sort it got
+	var query map[string]interface{}
+	var sort string
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		is.Equal(r.URL.Path, "/files-case-1/_search")
+		sort = r.URL.Query().Get("sort")
+		query = nil
+		is.NoErr(json.NewDecoder(r.Body).Decode(&query))
+		fmt.Fprint(w, `{"hits":{"total":{"value":5},"hits":[
+			{"_id":"f1","_source":{"id":"f1","name":"a.txt"},"sort":[1610616400,"f1"]},
+			{"_id":"f2","_source":{"id":"f2","name":"b.txt"},"sort":[1610616500,"f2"]}
+		]}}`)
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	processed := false
+	filter := datastore.FileFilter{Mime: "image/*", Processed: &processed, MinSize: 1024}
+	files, total, next, err := db.ListFiles(ctx, "case-1", filter, datastore.Page{Size: 2, Sort: "date", Order: "desc"})
+	is.NoErr(err)
+	is.Equal(total, 5)
+	is.Equal(len(files), 2)
+	is.Equal(files[1].Name, "b.txt")
+	is.True(next != "")
+	is.Equal(sort, "createdAt:desc,id.keyword:desc")
+
+	boolQuery := query["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	is.Equal(boolQuery["filter"], []interface{}{
+		map[string]interface{}{"prefix": map[string]interface{}{"mime.keyword": "image/"}},
+		map[string]interface{}{"range": map[string]interface{}{"size": map[string]interface{}{"gte": float64(1024)}}},
+	})
+	is.Equal(boolQuery["must_not"], []interface{}{
+		map[string]interface{}{"range": map[string]interface{}{"processedAt": map[string]interface{}{"gte": float64(1)}}},
+	})
+	is.True(query["search_after"] == nil)
+
+	// The next page starts after the last file
+	_, _, next, err = db.ListFiles(ctx, "case-1", datastore.FileFilter{}, datastore.Page{Size: 3, Sort: "date", Order: "desc", Cursor: next})
+	is.NoErr(err)
+	is.Equal(query["search_after"], []interface{}{float64(1610616500), "f2"})
+	is.Equal(query["query"], map[string]interface{}{"match_all": map[string]interface{}{}})
+	is.Equal(next, "") // fewer files than the page-size
+
+	_, _, _, err = db.ListFiles(ctx, "case-1", datastore.FileFilter{}, datastore.Page{Size: 2, Sort: "mime", Order: "asc"})
+	is.True(err != nil)
+	_, _, _, err = db.ListFiles(ctx, "case-1", datastore.FileFilter{}, datastore.Page{Size: 2, Sort: "date", Order: "asc", Cursor: "not a cursor"})
+	is.True(err != nil)
+}
+
+func TestListEvents(t *testing.T) {
+	is := is.New(t)
+
+	var query map[string]interface{}
+	var sort string
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		is.Equal(r.URL.Path, "/events-case-1/_search")
+		sort = r.URL.Query().Get("sort")
+		query = nil
+		is.NoErr(json.NewDecoder(r.Body).Decode(&query))
+		fmt.Fprint(w, `{"hits":{"total":{"value":1},"hits":[
+			{"_id":"e1","_source":{"id":"e1","importance":3,"description":"Meeting"},"sort":[3,"e1"]}
+		]}}`)
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	page := datastore.Page{Size: 10, Sort: "importance", Order: "desc"}
+	events, total, next, err := db.ListEvents(ctx, "case-1", map[string]string{"keyword": "meeting"}, page)
+	is.NoErr(err)
+	is.Equal(total, 1)
+	is.Equal(events, []api.Event{{Base: api.Base{ID: "e1"}, Importance: 3, Description: "Meeting"}})
+	is.Equal(next, "")
+	is.Equal(sort, "importance:desc,id.keyword:desc")
+	is.Equal(query["query"], map[string]interface{}{"bool": map[string]interface{}{"filter": []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"keywords.keyword": "meeting"}},
+	}}})
+
+	_, _, _, err = db.ListEvents(ctx, "case-1", map[string]string{"description": "Meeting"}, page)
+	is.True(err != nil) // only the fields for the events can be filtered
+	_, _, _, err = db.ListEvents(ctx, "case-1", nil, datastore.Page{Size: 10, Sort: "description", Order: "asc"})
+	is.True(err != nil)
+}
+
+func TestSearchEventsByTimespan(t *testing.T) {
+	is := is.New(t)
+
+	var query map[string]interface{}
+	var sort string
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		is.Equal(r.URL.Path, "/events-case-1/_search")
+		sort = r.URL.Query().Get("sort")
+		is.Equal(r.URL.Query().Get("from"), "0")
+		query = nil
+		is.NoErr(json.NewDecoder(r.Body).Decode(&query))
+		fmt.Fprint(w, `{"hits":{"total":{"value":12000},"hits":[
+			{"_id":"e1","_source":{"id":"e1","fromDate":1100127600,"toDate":1100214000},"sort":[1100127600000,"e1"]}
+		]}}`)
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	// The pages after the first are searched after the cursor,
+	// so there isn't a limit for the result-window
+	page := datastore.Page{Size: 1, Sort: "date", Order: "asc"}
+	events, total, next, err := db.SearchEventsByTimespan(ctx, "case-1", 1100000000, 1100200000, page)
+	is.NoErr(err)
+	is.Equal(total, 12000)
+	is.Equal(len(events), 1)
+	is.Equal(events[0].FromDate, int64(1100127600))
+	is.True(next != "")
+	is.Equal(sort, "fromDate:asc,id.keyword:asc")
+	is.Equal(query["search_after"], nil)
+
+	// The dates are compared without a format, so the
+	// old indices with the dates as numbers match too
+	is.Equal(query["query"], map[string]interface{}{"bool": map[string]interface{}{"filter": []interface{}{
+		map[string]interface{}{"range": map[string]interface{}{"fromDate": map[string]interface{}{"lte": float64(1100200000)}}},
+		map[string]interface{}{"range": map[string]interface{}{"toDate": map[string]interface{}{"gte": float64(1100000000)}}},
+	}}})
+
+	page.Cursor = next
+	_, _, _, err = db.SearchEventsByTimespan(ctx, "case-1", 1100000000, 1100200000, page)
+	is.NoErr(err)
+	is.Equal(query["search_after"], []interface{}{float64(1100127600000), "e1"})
+}
+
+func TestGetProcessedFileIDsByTimespan(t *testing.T) {
+	is := is.New(t)
+
+	// The documents are returned by two scroll-requests, the
+	// second has more documents from the files in a container
+	var scrolls int
+	var cleared bool
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, `{}`)
+		case r.URL.Path == "/processes-case-1/_search":
+			is.Equal(r.URL.Query().Get("scroll"), "60000ms")
+			fmt.Fprint(w, `{"_scroll_id":"scroll-1","hits":{"total":{"value":4},"hits":[
+				{"_id":"f1","_source":{}},
+				{"_id":"f2-a","_source":{"parent":{"file_id":"f2"}}}
+			]}}`)
+		case r.URL.Path == "/_search/scroll/scroll-1" && r.Method == http.MethodDelete:
+			cleared = true
+			fmt.Fprint(w, `{"succeeded":true}`)
+		case r.URL.Path == "/_search/scroll":
+			scrolls++
+			if scrolls == 1 {
+				fmt.Fprint(w, `{"_scroll_id":"scroll-1","hits":{"hits":[
+					{"_id":"f2-b","_source":{"parent":{"file_id":"f2"}}},
+					{"_id":"f3","_source":{}}
+				]}}`)
+				return
+			}
+			fmt.Fprint(w, `{"_scroll_id":"scroll-1","hits":{"hits":[]}}`)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+
+	ids, err := db.GetProcessedFileIDsByTimespan(context.Background(), "case-1", 1100000000, 1100200000)
+	is.NoErr(err)
+	is.Equal(ids, []string{"f1", "f2", "f3"})
+	is.Equal(scrolls, 2)
+	is.True(cleared)
+}
+
+func TestUpdateEventVersion(t *testing.T) {
+	is := is.New(t)
+
+	var stored map[string]interface{}
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, `{}`)
+		case r.URL.Path == "/events-case-1/_search":
+			is.Equal(r.URL.Query().Get("seq_no_primary_term"), "true")
+			fmt.Fprint(w, `{"hits":{"total":{"value":1},"hits":[
+				{"_index":"events-case-1","_id":"e1","_seq_no":12,"_primary_term":1,"_source":{"id":"e1","importance":3}}
+			]}}`)
+		case r.URL.Path == "/events-case-1/_doc/e1":
+			stored = nil
+			is.NoErr(json.NewDecoder(r.Body).Decode(&stored))
+			if r.URL.Query().Get("if_seq_no") != "12" || r.URL.Query().Get("if_primary_term") != "1" {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, `{"error":{"type":"version_conflict_engine_exception"},"status":409}`)
+				return
+			}
+			fmt.Fprint(w, `{"_id":"e1","_seq_no":13,"_primary_term":1,"result":"updated"}`)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	event, err := db.GetEventByID(ctx, "case-1", "e1")
+	is.NoErr(err)
+	is.Equal(event.Version, "12-1")
+
+	event.Importance = 4
+	is.NoErr(db.UpdateEvent(ctx, "case-1", event))
+	is.Equal(event.Version, "13-1")
+	_, ok := stored["version"]
+	is.True(!ok) // the version isn't stored in the document
+
+	// the event has been changed since version 13-1
+	err = db.UpdateEvent(ctx, "case-1", event)
+	is.True(errors.Is(err, datastore.ErrConflict))
+}
+
+func TestBulk(t *testing.T) {
+	is := is.New(t)
+
+	var lines []string
+	var refresh string
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		is.Equal(r.URL.Path, "/_bulk")
+		refresh = r.URL.Query().Get("refresh")
+		body, err := io.ReadAll(r.Body)
+		is.NoErr(err)
+		lines = strings.Split(strings.TrimSpace(string(body)), "\n")
+		fmt.Fprint(w, `{"errors":true,"items":[
+			{"index":{"_index":"keywords-case-1","_id":"healthy","status":200,"result":"updated"}},
+			{"delete":{"_index":"keywords-case-1","_id":"deleted","status":404,"result":"not_found"}},
+			{"delete":{"_index":"events-case-1","_id":"e1","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected"}}}
+		]}`)
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	err = db.Bulk(ctx, []datastore.BulkOperation{
+		{Action: datastore.BulkIndex, Index: db.KeywordIndex("case-1"), ID: "healthy", Document: api.Keyword{Name: "healthy", EventIDs: []string{"e2"}}},
+		{Action: datastore.BulkDelete, Index: db.KeywordIndex("case-1"), ID: "deleted"},
+		{Action: datastore.BulkDelete, Index: "events-case-1", ID: "e1"},
+	})
+	is.Equal(refresh, "true")
+	is.Equal(len(lines), 4) // the deletes don't have a body
+	is.Equal(lines[0], `{"index":{"_id":"healthy","_index":"keywords-case-1"}}`)
+
+	// the documents that are already deleted aren't failures
+	var bulkErr *datastore.BulkError
+	is.True(errors.As(err, &bulkErr))
+	is.Equal(len(bulkErr.Failures), 1)
+	is.Equal(bulkErr.Failures[0].Operation.ID, "e1")
+	is.Equal(bulkErr.Failures[0].Err.Error(), "[429] es_rejected_execution_exception: rejected")
+
+	is.NoErr(db.Bulk(ctx, nil))
+}
+
+func TestDeleteCase(t *testing.T) {
+	is := is.New(t)
+
+	// The events are found, the other indices don't exist
+	// unless the persons-index is failing
+	var failing = true
+	var deleted []string
+	var caseDeleted bool
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/":
+			fmt.Fprint(w, `{}`)
+		case r.URL.Path == "/cases/_search":
+			fmt.Fprint(w, `{"hits":{"hits":[{"_id":"case-1","_source":{"id":"case-1"}}]}}`)
+		case r.URL.Path == "/events-case-1/_search":
+			is.Equal(r.URL.Query().Get("ignore_unavailable"), "true")
+			fmt.Fprint(w, `{"hits":{"hits":[{"_id":"e1","_source":{}},{"_id":"e2","_source":{}}]}}`)
+		case r.URL.Path == "/persons-case-1/_search" && failing:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"type":"search_phase_execution_exception","reason":"all shards failed"},"status":503}`)
+		case strings.HasSuffix(r.URL.Path, "/_search"):
+			fmt.Fprint(w, `{"hits":{"hits":[]}}`)
+		case r.URL.Path == "/_bulk":
+			body, err := io.ReadAll(r.Body)
+			is.NoErr(err)
+			deleted = strings.Split(strings.TrimSpace(string(body)), "\n")
+			fmt.Fprint(w, `{"errors":false,"items":[]}`)
+		case r.URL.Path == "/cases/_doc/case-1" && r.Method == http.MethodDelete:
+			caseDeleted = true
+			fmt.Fprint(w, `{"result":"deleted"}`)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	// The case is kept if the objects couldn't be found
+	err = db.DeleteCase(ctx, "case-1")
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "persons-case-1"))
+	is.Equal(deleted, nil)
+	is.True(!caseDeleted)
+
+	failing = false
+	is.NoErr(db.DeleteCase(ctx, "case-1"))
+	is.Equal(deleted, []string{
+		`{"delete":{"_id":"e1","_index":"events-case-1"}}`,
+		`{"delete":{"_id":"e2","_index":"events-case-1"}}`,
+	})
+	is.True(caseDeleted)
+}
+
+func TestKeywordIDs(t *testing.T) {
+	is := is.New(t)
+
+	var requests [][]string
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		is.Equal(r.URL.Path, "/_bulk")
+		body, err := io.ReadAll(r.Body)
+		is.NoErr(err)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		requests = append(requests, lines)
+
+		// The first update of "b" conflicts with another request
+		switch len(requests) {
+		case 1:
+			fmt.Fprint(w, `{"errors":true,"items":[
+				{"update":{"_index":"keywords-case-1","_id":"a","status":200,"result":"updated"}},
+				{"update":{"_index":"keywords-case-1","_id":"b","status":409,"error":{"type":"version_conflict_engine_exception","reason":"conflict"}}}
+			]}`)
+		case 2:
+			fmt.Fprint(w, `{"errors":false,"items":[
+				{"update":{"_index":"keywords-case-1","_id":"b","status":201,"result":"created"}}
+			]}`)
+		default:
+			fmt.Fprint(w, `{"errors":true,"items":[
+				{"update":{"_index":"keywords-case-1","_id":"a","status":404,"error":{"type":"document_missing_exception","reason":"missing"}}}
+			]}`)
+		}
+	}))
+	defer es.Close()
+
+	db, err := datastore.NewService(es.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+
+	// The IDs are added with a script, and the keywords are created if they don't exist
+	is.NoErr(db.AddKeywordIDs(ctx, "case-1", datastore.KeywordEvents, "e1", []string{"a", "b"}))
+	is.Equal(len(requests), 2)
+	is.Equal(len(requests[0]), 4)
+	is.Equal(requests[0][0], `{"update":{"_id":"a","_index":"keywords-case-1"}}`)
+	var update struct {
+		Script struct {
+			Params map[string]interface{} `json:"params"`
+		} `json:"script"`
+		Upsert api.Keyword `json:"upsert"`
+	}
+	is.NoErr(json.Unmarshal([]byte(requests[0][1]), &update))
+	is.Equal(update.Script.Params["field"], "eventIDs")
+	is.Equal(update.Script.Params["id"], "e1")
+	is.Equal(update.Upsert, api.Keyword{Name: "a", EventIDs: []string{"e1"}, PersonIDs: []string{}, EntityIDs: []string{}, FileIDs: []string{}})
+
+	// Only the conflicting update is retried
+	is.Equal(len(requests[1]), 2)
+	is.Equal(requests[1][0], `{"update":{"_id":"b","_index":"keywords-case-1"}}`)
+
+	// Keywords that are deleted already aren't failures
+	is.NoErr(db.RemoveKeywordIDs(ctx, "case-1", datastore.KeywordEvents, "e1", []string{"a"}))
+	is.Equal(len(requests), 3)
+	is.True(strings.Contains(requests[2][1], `"fields":["eventIDs","personIDs","entityIDs","fileIDs"]`))
+
+	is.True(db.AddKeywordIDs(ctx, "case-1", "names", "e1", []string{"a"}) != nil)
+}
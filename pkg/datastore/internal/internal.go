@@ -24,35 +24,66 @@ type Shards struct {
 type Hits struct {
 	Hits     []Hit   `json:"hits,omitempty"`
 	MaxScore float64 `json:"max_score,omitempty"`
-	Total    float64 `json:"took,omitempty"`
+	Total    Total   `json:"total,omitempty"`
+}
+
+type Total struct {
+	Value    int    `json:"value,omitempty"`
+	Relation string `json:"relation,omitempty"`
 }
 
 type Hit struct {
-	ID     string      `json:"_id,omitempty"`
-	Index  string      `json:"_index,omitempty"`
-	Type   string      `json:"_type,omitempty"`
-	Score  float64     `json:"_score,omitempty"`
-	Source interface{} `json:"_source,omitempty"`
+	ID     string        `json:"_id,omitempty"`
+	Index  string        `json:"_index,omitempty"`
+	Type   string        `json:"_type,omitempty"`
+	Score  float64       `json:"_score,omitempty"`
+	Source interface{}   `json:"_source,omitempty"`
+	Sort   []interface{} `json:"sort,omitempty"`
+
+	SeqNo       *int `json:"_seq_no,omitempty"`
+	PrimaryTerm *int `json:"_primary_term,omitempty"`
 }
 
 type QueryRequest struct {
-	Query Query `json:"query"`
+	Query       Query         `json:"query"`
+	SearchAfter []interface{} `json:"search_after,omitempty"`
 }
 
 type Query struct {
+	MatchAll interface{} `json:"match_all,omitempty"`
 	Match    interface{} `json:"match,omitempty"`
 	Wildcard interface{} `json:"wildcard,omitempty"`
 	IDs      interface{} `json:"ids,omitempty"`
+	Term     interface{} `json:"term,omitempty"`
+	Terms    interface{} `json:"terms,omitempty"`
 	Bool     *Bool       `json:"bool,omitempty"`
 }
 
 type Bool struct {
-	Must []Must `json:"must,omitempty"`
+	Must               []Must `json:"must,omitempty"`
+	Filter             []Must `json:"filter,omitempty"`
+	Should             []Must `json:"should,omitempty"`
+	MustNot            []Must `json:"must_not,omitempty"`
+	MinimumShouldMatch int    `json:"minimum_should_match,omitempty"`
 }
 
 type Must struct {
+	Match             interface{} `json:"match,omitempty"`
 	MatchPhrasePrefix interface{} `json:"match_phrase_prefix,omitempty"`
 	Wildcard          interface{} `json:"wildcard,omitempty"`
+	Range             interface{} `json:"range,omitempty"`
+	Term              interface{} `json:"term,omitempty"`
+	IDs               interface{} `json:"ids,omitempty"`
+	Prefix            interface{} `json:"prefix,omitempty"`
+	Exists            interface{} `json:"exists,omitempty"`
+	Bool              *Bool       `json:"bool,omitempty"`
+}
+
+// Range is a range-query for a field
+type Range struct {
+	Gte    interface{} `json:"gte,omitempty"`
+	Lte    interface{} `json:"lte,omitempty"`
+	Format string      `json:"format,omitempty"`
 }
 
 // NewID generates a new ID
@@ -0,0 +1,248 @@
+package datastore
+
+// templateVersion is the version of the index-templates, increase
+// it when a template is changed so it's updated at startup. The
+// templates are only applied to the indices that are created
+// after they are updated, the existing indices are reindexed
+// with the new mappings by MigrateIndices
+const templateVersion = 1
+
+// templatePriority is the priority for the index-templates,
+// so they are used before the templates in elasticsearch
+const templatePriority = 100
+
+// indexTemplate is an index-template for the indices with the patterns
+type indexTemplate struct {
+	name       string
+	patterns   []string
+	properties map[string]interface{}
+}
+
+// indexTemplates are the templates for the indices, the case-
+// indices are named {index}-{caseID}. Strings that aren't in the
+// properties are mapped as text with a keyword-subfield, like
+// the dynamic mapping in elasticsearch
+var indexTemplates = []indexTemplate{
+	{
+		name:     indexCase,
+		patterns: []string{indexCase},
+		properties: withBase(map[string]interface{}{
+			"name":        autocompleteText,
+			"description": autocompleteText,
+			"fromDate":    timestamp,
+			"toDate":      timestamp,
+		}),
+	},
+	{
+		name:     indexEvent,
+		patterns: []string{indexEvent + "-*"},
+		properties: withBase(map[string]interface{}{
+			"description": autocompleteText,
+			"importance":  map[string]string{"type": "integer"},
+			"fromDate":    timestamp,
+			"toDate":      timestamp,
+		}),
+	},
+	{
+		name:     indexEntity,
+		patterns: []string{indexEntity + "-*"},
+		properties: withBase(map[string]interface{}{
+			"title":  autocompleteText,
+			"custom": flattened,
+		}),
+	},
+	{
+		name:     indexPerson,
+		patterns: []string{indexPerson + "-*"},
+		properties: withBase(map[string]interface{}{
+			"firstName":    autocompleteText,
+			"lastName":     autocompleteText,
+			"emailAddress": autocompleteText,
+			"custom":       flattened,
+		}),
+	},
+	{
+		name:     indexFile,
+		patterns: []string{indexFile + "-*"},
+		properties: withBase(map[string]interface{}{
+			"name":        autocompleteText,
+			"description": autocompleteText,
+			"path":        map[string]interface{}{"type": "text", "fields": map[string]interface{}{"keyword": keywordSubfield}},
+			"size":        map[string]string{"type": "long"},
+			"processedAt": timestamp,
+		}),
+	},
+	{
+		name:       indexLink,
+		patterns:   []string{indexLink + "-*"},
+		properties: withBase(map[string]interface{}{}),
+	},
+	{
+		name:     indexKeyword,
+		patterns: []string{indexKeyword + "-*"},
+		properties: map[string]interface{}{
+			"name": autocompleteText,
+		},
+	},
+	{
+		// The tokens are found by the hash of their secret, it's
+		// a keyword so it's matched exactly and not analyzed
+		name:     indexToken,
+		patterns: []string{indexToken},
+		properties: withBase(map[string]interface{}{
+			"hash":       map[string]string{"type": "keyword"},
+			"expiresAt":  timestamp,
+			"revokedAt":  timestamp,
+			"lastUsedAt": timestamp,
+		}),
+	},
+	{
+		// The processed documents from fscrawler and the native
+		// indexer, the dates that can't be parsed are ignored
+		// so the documents are still indexed
+		name:     indexProcess,
+		patterns: []string{indexProcess + "-*"},
+		properties: map[string]interface{}{
+			"content": map[string]string{"type": "text"},
+			"meta": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"title":    autocompleteText,
+					"author":   autocompleteText,
+					"date":     documentDate,
+					"created":  documentDate,
+					"language": map[string]string{"type": "keyword"},
+					"raw":      flattened,
+				},
+			},
+			"file": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"filename":      autocompleteText,
+					"filesize":      map[string]string{"type": "long"},
+					"indexing_date": documentDate,
+					"last_modified": documentDate,
+				},
+			},
+		},
+	},
+}
+
+// The mappings for the fields
+var (
+	// timestamp is a unix-timestamp in the API-models
+	timestamp = map[string]string{"type": "date", "format": "epoch_second"}
+
+	// documentDate is a date in a processed document
+	documentDate = map[string]interface{}{"type": "date", "ignore_malformed": true}
+
+	// flattened is an object with arbitrary keys, like the custom
+	// fields, that would add a field to the mapping for every key
+	flattened = map[string]string{"type": "flattened"}
+
+	// autocompleteText is text with a keyword-subfield, and a
+	// subfield with the prefixes of the words for the searches
+	autocompleteText = map[string]interface{}{
+		"type": "text",
+		"fields": map[string]interface{}{
+			"keyword": keywordSubfield,
+			"autocomplete": map[string]string{
+				"type":            "text",
+				"analyzer":        "autocomplete",
+				"search_analyzer": "autocomplete_search",
+			},
+		},
+	}
+
+	keywordSubfield = map[string]interface{}{"type": "keyword", "ignore_above": 256}
+)
+
+// withBase adds the timestamps in api.Base to the properties
+func withBase(properties map[string]interface{}) map[string]interface{} {
+	for _, field := range []string{"createdAt", "updatedAt", "deletedAt"} {
+		properties[field] = timestamp
+	}
+	return properties
+}
+
+// indexSettings are the settings for the indices, with the analyzers
+// for the autocomplete-subfields. The words are indexed with their
+// prefixes (2 to 20 characters), and the searches are truncated to
+// the longest prefix so longer words still match
+var indexSettings = map[string]interface{}{
+	"analysis": map[string]interface{}{
+		"tokenizer": map[string]interface{}{
+			"autocomplete": map[string]interface{}{
+				"type":        "edge_ngram",
+				"min_gram":    2,
+				"max_gram":    20,
+				"token_chars": []string{"letter", "digit"},
+			},
+		},
+		"filter": map[string]interface{}{
+			"autocomplete_truncate": map[string]interface{}{
+				"type":   "truncate",
+				"length": 20,
+			},
+		},
+		"analyzer": map[string]interface{}{
+			"autocomplete": map[string]interface{}{
+				"type":      "custom",
+				"tokenizer": "autocomplete",
+				"filter":    []string{"lowercase"},
+			},
+			"autocomplete_search": map[string]interface{}{
+				"type":      "custom",
+				"tokenizer": "standard",
+				"filter":    []string{"lowercase", "autocomplete_truncate"},
+			},
+		},
+	},
+}
+
+// dynamicTemplates maps the fields that aren't in the properties, like
+// the objects in links and the legacy files in cases, by their name and type
+var dynamicTemplates = []map[string]interface{}{
+	{"custom": map[string]interface{}{
+		"match":              "custom",
+		"match_mapping_type": "object",
+		"mapping":            flattened,
+	}},
+	{"timestamps": map[string]interface{}{
+		"match_pattern":      "regex",
+		"match":              "^(createdAt|updatedAt|deletedAt|fromDate|toDate|processedAt)$",
+		"match_mapping_type": "long",
+		"mapping":            timestamp,
+	}},
+	{"strings": map[string]interface{}{
+		"match_mapping_type": "string",
+		"mapping": map[string]interface{}{
+			"type":   "text",
+			"fields": map[string]interface{}{"keyword": keywordSubfield},
+		},
+	}},
+}
+
+// body returns the body for the template in elasticsearch,
+// the version is kept in the mapping too so every index
+// has the version of the template it was created with
+func (t indexTemplate) body() map[string]interface{} {
+	return map[string]interface{}{
+		"index_patterns": t.patterns,
+		"priority":       templatePriority,
+		"version":        templateVersion,
+		"template":       t.index(),
+	}
+}
+
+// index returns the settings and mappings for the indices, an
+// index is created with them when it's migrated, since the name
+// of the new index doesn't have to match the patterns
+func (t indexTemplate) index() map[string]interface{} {
+	return map[string]interface{}{
+		"settings": indexSettings,
+		"mappings": map[string]interface{}{
+			"_meta":             map[string]int{"template_version": templateVersion},
+			"dynamic_templates": dynamicTemplates,
+			"properties":        t.properties,
+		},
+	}
+}
@@ -0,0 +1,666 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// chunkSize is the size of the plaintext in every
+	// encrypted chunk, the chunks are encrypted separately
+	// so the files can be read from any offset
+	chunkSize = 64 << 10
+
+	// prefixSize is the size of the random prefix for the
+	// nonces, the rest of the nonce is the chunk-counter
+	// and a flag for the last chunk
+	prefixSize = 7
+
+	// keyCacheTTL is how long the latest version of the data-key
+	// for an identifier is cached, a key that is rotated by another
+	// instance of the API is used for new files after it
+	keyCacheTTL = 5 * time.Minute
+)
+
+// magic is the start of every encrypted file
+var magic = []byte("\x89TIE\r\n\x1a\n")
+
+// ErrNoDataKey is returned if the data-key
+// for an encrypted file cannot be found
+var ErrNoDataKey = errors.New("data-key not found")
+
+// ErrNotEncrypted is returned when a file that isn't encrypted
+// is opened, unless the unencrypted files are allowed
+var ErrNotEncrypted = errors.New("the file isn't encrypted")
+
+// MasterKey is a key that wraps the data-keys
+type MasterKey struct {
+	ID  string
+	Key []byte
+}
+
+// DataKey is a wrapped data-key for an identifier, a new
+// version of the key is created when the key is rotated
+type DataKey struct {
+	Identifier  string `json:"identifier"`
+	Version     int    `json:"version"`
+	MasterKeyID string `json:"masterKeyID"`
+	Wrapped     []byte `json:"wrapped"`
+	CreatedAt   int64  `json:"createdAt"`
+}
+
+// KeyStore stores the wrapped data-keys
+type KeyStore interface {
+	GetDataKeys(ctx context.Context, identifier string) ([]DataKey, error)
+
+	// CreateDataKey must fail if
+	// the version already exists
+	CreateDataKey(ctx context.Context, key *DataKey) error
+	UpdateDataKey(ctx context.Context, key *DataKey) error
+}
+
+// Rotator is implemented by the filestores
+// that can rotate the keys for an identifier
+type Rotator interface {
+	RotateKey(identifier string) (int, error)
+}
+
+// EncryptedOptions holds the options for the encrypted filestore
+type EncryptedOptions struct {
+	// MasterKeys wrap the data-keys, the first master-key
+	// is used to wrap new data-keys, the other keys are only
+	// used to unwrap (and re-wrap) older keys, so the
+	// master-key is rotated by adding a new key first
+	MasterKeys []MasterKey
+
+	// AllowUnencrypted returns the files that were stored before
+	// the encryption was enabled as they are, it's only for the
+	// migration to encryption - otherwise files without the
+	// header are rejected, since they could have been replaced
+	AllowUnencrypted bool
+}
+
+type encrypted struct {
+	store            Service
+	keys             KeyStore
+	masterKeys       map[string]cipher.AEAD
+	current          string
+	allowUnencrypted bool
+
+	// cache has the unwrapped data-keys by
+	// identifier and version, and latest has
+	// the latest version for the identifiers
+	mu     sync.Mutex
+	cache  map[string]cipher.AEAD
+	latest map[string]latestKey
+}
+
+// latestKey is the latest version of the data-key
+// for an identifier, and when it was loaded
+type latestKey struct {
+	version  int
+	loadedAt time.Time
+}
+
+// NewEncrypted creates a new Service that encrypts the files with
+// AES-GCM before they are stored in the underlying store, every
+// identifier has its own data-key that is wrapped by the master-key.
+func NewEncrypted(store Service, keys KeyStore, options EncryptedOptions) (Service, error) {
+	masterKeys := options.MasterKeys
+	if len(masterKeys) == 0 {
+		return nil, errors.New("encryption: specify a master-key")
+	}
+
+	s := &encrypted{
+		store:            store,
+		keys:             keys,
+		masterKeys:       make(map[string]cipher.AEAD),
+		current:          masterKeys[0].ID,
+		allowUnencrypted: options.AllowUnencrypted,
+		cache:            make(map[string]cipher.AEAD),
+		latest:           make(map[string]latestKey),
+	}
+	for _, masterKey := range masterKeys {
+		if masterKey.ID == "" {
+			return nil, errors.New("encryption: specify an ID for the master-key")
+		}
+		if len(masterKey.Key) != 32 {
+			return nil, fmt.Errorf("encryption: master-key %q must be 32 bytes", masterKey.ID)
+		}
+		aead, err := newAEAD(masterKey.Key)
+		if err != nil {
+			return nil, err
+		}
+		s.masterKeys[masterKey.ID] = aead
+	}
+
+	return s, nil
+}
+
+// Upload encrypts the content while it is streamed to the
+// underlying store, the size and the hashes are for the plaintext
+func (s *encrypted) Upload(identifier, name string, content io.Reader) (*File, error) {
+	if err := ValidName(identifier); err != nil {
+		return nil, fmt.Errorf("invalid identifier %q", identifier)
+	}
+
+	version, aead, err := s.currentKey(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	h := newHasher()
+	pr, pw := io.Pipe()
+	done := make(chan int64)
+	go func() {
+		size, err := encrypt(pw, io.TeeReader(content, h), aead, identifier, version)
+		pw.CloseWithError(err)
+		done <- size
+	}()
+
+	file, err := s.store.Upload(identifier, name, pr)
+	pr.Close()
+	size := <-done
+	if err != nil {
+		return nil, err
+	}
+
+	file.Size = int(size)
+	file.Hashes = h.sums()
+	return file, nil
+}
+
+func (s *encrypted) Delete(filename string) error { return s.store.Delete(filename) }
+
+func (s *encrypted) GetContent(filename string) ([]byte, error) {
+	object, err := s.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(object)
+}
+
+// Open opens the file and decrypts it while it is read, files
+// that were stored before the encryption was enabled are
+// returned as they are if the unencrypted files are allowed
+func (s *encrypted) Open(filename string) (Object, error) {
+	object, err := s.store.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	header, identifier, version, err := readHeader(object)
+	if err == ErrNotEncrypted && !s.allowUnencrypted {
+		object.Close()
+		return nil, fmt.Errorf("encryption: %s: %w", filename, ErrNotEncrypted)
+	}
+	if err == ErrNotEncrypted {
+		if _, err := object.Seek(0, io.SeekStart); err != nil {
+			object.Close()
+			return nil, err
+		}
+		return object, nil
+	}
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+
+	aead, err := s.key(identifier, version)
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+
+	decrypted, err := newDecrypter(object, aead, header)
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// RotateKey creates a new version of the data-key for the identifier,
+// new files are encrypted with the new key and the older versions are
+// re-wrapped with the current master-key
+func (s *encrypted) RotateKey(identifier string) (int, error) {
+	keys, err := s.dataKeys(identifier)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	for _, key := range keys {
+		if key.Version > version {
+			version = key.Version
+		}
+	}
+
+	key, err := s.newDataKey(identifier, version+1)
+	if err != nil {
+		return 0, err
+	}
+	return key.Version, nil
+}
+
+// currentKey returns the latest version of the data-key
+// for the identifier, the key is created if it doesn't exist
+func (s *encrypted) currentKey(identifier string) (int, cipher.AEAD, error) {
+	s.mu.Lock()
+	latest, ok := s.latest[identifier]
+	aead := s.cache[cacheKey(identifier, latest.version)]
+	s.mu.Unlock()
+	if ok && aead != nil && time.Since(latest.loadedAt) < keyCacheTTL {
+		return latest.version, aead, nil
+	}
+
+	version, err := s.load(identifier)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if version == 0 {
+		key, err := s.newDataKey(identifier, 1)
+		if err != nil {
+			// The key can have been created
+			// by a concurrent upload
+			if version, err = s.load(identifier); err != nil || version == 0 {
+				return 0, nil, fmt.Errorf("encryption: cannot create data-key: %v", err)
+			}
+		} else {
+			version = key.Version
+		}
+	}
+
+	aead, err = s.key(identifier, version)
+	if err != nil {
+		return 0, nil, err
+	}
+	return version, aead, nil
+}
+
+// key returns the unwrapped data-key for the version, the
+// keys for the identifier are loaded if it isn't cached
+func (s *encrypted) key(identifier string, version int) (cipher.AEAD, error) {
+	s.mu.Lock()
+	aead, ok := s.cache[cacheKey(identifier, version)]
+	s.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	if _, err := s.load(identifier); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	aead, ok = s.cache[cacheKey(identifier, version)]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrNoDataKey
+	}
+	return aead, nil
+}
+
+// load unwraps the data-keys for the identifier and caches
+// them, and returns the latest version (0 if there isn't any)
+func (s *encrypted) load(identifier string) (int, error) {
+	keys, err := s.dataKeys(identifier)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	for _, key := range keys {
+		if _, err := s.unwrap(key); err != nil {
+			return 0, err
+		}
+		if key.Version > version {
+			version = key.Version
+		}
+	}
+
+	if version > 0 {
+		s.mu.Lock()
+		s.latest[identifier] = latestKey{version: version, loadedAt: time.Now()}
+		s.mu.Unlock()
+	}
+	return version, nil
+}
+
+func cacheKey(identifier string, version int) string {
+	return identifier + "/" + strconv.Itoa(version)
+}
+
+// dataKeys gets the data-keys for the identifier, the keys that
+// are wrapped with an old master-key are re-wrapped with the current
+func (s *encrypted) dataKeys(identifier string) ([]DataKey, error) {
+	keys, err := s.keys.GetDataKeys(context.Background(), identifier)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: cannot get data-keys: %v", err)
+	}
+
+	for i := range keys {
+		if keys[i].MasterKeyID == s.current {
+			continue
+		}
+
+		plaintext, err := s.unwrapKey(keys[i])
+		if err != nil {
+			return nil, err
+		}
+		keys[i].Wrapped, err = s.wrapKey(plaintext, keys[i].Identifier, keys[i].Version)
+		if err != nil {
+			return nil, err
+		}
+		keys[i].MasterKeyID = s.current
+
+		// The old master-key can still unwrap the key,
+		// so it is only logged if it cannot be saved
+		if err := s.keys.UpdateDataKey(context.Background(), &keys[i]); err != nil {
+			log.Printf("encryption : failed to re-wrap data-key %s/%d : %v", identifier, keys[i].Version, err)
+		}
+	}
+
+	return keys, nil
+}
+
+// newDataKey generates and saves a new data-key
+func (s *encrypted) newDataKey(identifier string, version int) (*DataKey, error) {
+	plaintext := make([]byte, 32)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, fmt.Errorf("encryption: failed to generate data-key: %v", err)
+	}
+
+	wrapped, err := s.wrapKey(plaintext, identifier, version)
+	if err != nil {
+		return nil, err
+	}
+
+	key := DataKey{
+		Identifier:  identifier,
+		Version:     version,
+		MasterKeyID: s.current,
+		Wrapped:     wrapped,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if err := s.keys.CreateDataKey(context.Background(), &key); err != nil {
+		return nil, err
+	}
+
+	// The new key is the latest version, so it's
+	// cached without being read and unwrapped again
+	aead, err := newAEAD(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[cacheKey(identifier, version)] = aead
+	s.latest[identifier] = latestKey{version: version, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return &key, nil
+}
+
+// unwrap unwraps the data-key and caches it
+func (s *encrypted) unwrap(key DataKey) (cipher.AEAD, error) {
+	plaintext, err := s.unwrapKey(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[cacheKey(key.Identifier, key.Version)] = aead
+	s.mu.Unlock()
+	return aead, nil
+}
+
+// wrapKey encrypts the data-key with the current master-key,
+// the identifier and version is authenticated with the key
+func (s *encrypted) wrapKey(plaintext []byte, identifier string, version int) ([]byte, error) {
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("encryption: failed to generate nonce: %v", err)
+	}
+	return s.masterKeys[s.current].Seal(nonce, nonce, plaintext, keyAAD(identifier, version)), nil
+}
+
+func (s *encrypted) unwrapKey(key DataKey) ([]byte, error) {
+	masterKey, ok := s.masterKeys[key.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption: master-key %q for data-key %s/%d is missing", key.MasterKeyID, key.Identifier, key.Version)
+	}
+	if len(key.Wrapped) < 12 {
+		return nil, fmt.Errorf("encryption: invalid data-key %s/%d", key.Identifier, key.Version)
+	}
+
+	plaintext, err := masterKey.Open(nil, key.Wrapped[:12], key.Wrapped[12:], keyAAD(key.Identifier, key.Version))
+	if err != nil {
+		return nil, fmt.Errorf("encryption: cannot unwrap data-key %s/%d: %v", key.Identifier, key.Version, err)
+	}
+	return plaintext, nil
+}
+
+func keyAAD(identifier string, version int) []byte {
+	return []byte(identifier + "/" + strconv.Itoa(version))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// The encrypted file starts with a header, that is authenticated with
+// every chunk, followed by the chunks that are each encrypted with a
+// nonce from the prefix, the number of the chunk and a flag for the last
+// chunk (so a truncated file cannot be mistaken for a complete file):
+//
+//	magic | version (4) | prefix (7) | len(identifier) (1) | identifier
+//	chunk 0 | chunk 1 | ... | last chunk
+func encrypt(w io.Writer, r io.Reader, aead cipher.AEAD, identifier string, version int) (int64, error) {
+	if len(identifier) > 255 {
+		return 0, errors.New("encryption: identifier is too long")
+	}
+
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return 0, fmt.Errorf("encryption: failed to generate nonce: %v", err)
+	}
+
+	header := append([]byte{}, magic...)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[len(magic):], uint32(version))
+	header = append(header, prefix...)
+	header = append(header, byte(len(identifier)))
+	header = append(header, identifier...)
+	if _, err := w.Write(header); err != nil {
+		return 0, err
+	}
+
+	var size int64
+	chunk, next := make([]byte, chunkSize), make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+aead.Overhead())
+
+	n, err := io.ReadFull(r, chunk)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, err
+	}
+	last := err != nil
+
+	for counter := uint32(0); ; counter++ {
+		// The next chunk is read ahead to
+		// know if the chunk is the last
+		var m int
+		var nextLast bool
+		if !last {
+			m, err = io.ReadFull(r, next)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return size, err
+			}
+			last = err == io.EOF
+			nextLast = err == io.ErrUnexpectedEOF
+		}
+
+		sealed = aead.Seal(sealed[:0], chunkNonce(prefix, counter, last), chunk[:n], header)
+		if _, err := w.Write(sealed); err != nil {
+			return size, err
+		}
+		size += int64(n)
+
+		if last {
+			return size, nil
+		}
+		if counter == ^uint32(0) {
+			return size, errors.New("encryption: the file is too large")
+		}
+		chunk, next, n, last = next, chunk, m, nextLast
+	}
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixSize:], counter)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// readHeader reads the header from
+// the start of an encrypted file
+func readHeader(r io.Reader) (header []byte, identifier string, version int, err error) {
+	header = make([]byte, len(magic)+4+prefixSize+1)
+	if _, err := io.ReadFull(r, header); err != nil || !bytes.Equal(header[:len(magic)], magic) {
+		return nil, "", 0, ErrNotEncrypted
+	}
+
+	id := make([]byte, header[len(header)-1])
+	if _, err := io.ReadFull(r, id); err != nil {
+		return nil, "", 0, fmt.Errorf("encryption: invalid header: %v", err)
+	}
+
+	version = int(binary.BigEndian.Uint32(header[len(magic):]))
+	return append(header, id...), string(id), version, nil
+}
+
+// decrypter is an opened encrypted file,
+// the chunks are decrypted when they are read
+type decrypter struct {
+	object Object
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+
+	size   int64
+	chunks int64
+	offset int64
+
+	chunk int64
+	plain []byte
+	buf   []byte
+}
+
+func newDecrypter(object Object, aead cipher.AEAD, header []byte) (*decrypter, error) {
+	end, err := object.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	sealedSize := int64(chunkSize + aead.Overhead())
+	body := end - int64(len(header))
+	chunks := (body + sealedSize - 1) / sealedSize
+	lastSize := body - (chunks-1)*sealedSize - int64(aead.Overhead())
+	if chunks == 0 || lastSize < 0 {
+		return nil, errors.New("encryption: the file is truncated")
+	}
+
+	return &decrypter{
+		object: object,
+		aead:   aead,
+		header: header,
+		prefix: header[len(magic)+4 : len(magic)+4+prefixSize],
+		size:   (chunks-1)*chunkSize + lastSize,
+		chunks: chunks,
+		chunk:  -1,
+		buf:    make([]byte, sealedSize),
+	}, nil
+}
+
+func (d *decrypter) Read(p []byte) (int, error) {
+	if d.offset >= d.size {
+		return 0, io.EOF
+	}
+
+	index := d.offset / chunkSize
+	if index != d.chunk {
+		if err := d.load(index); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain[d.offset-index*chunkSize:])
+	d.offset += int64(n)
+	return n, nil
+}
+
+// load reads and decrypts the chunk
+func (d *decrypter) load(index int64) error {
+	sealedSize := int64(len(d.buf))
+	if _, err := d.object.Seek(int64(len(d.header))+index*sealedSize, io.SeekStart); err != nil {
+		return err
+	}
+
+	sealed := d.buf
+	if index == d.chunks-1 {
+		sealed = d.buf[:d.size-index*chunkSize+int64(d.aead.Overhead())]
+	}
+	if _, err := io.ReadFull(d.object, sealed); err != nil {
+		return fmt.Errorf("encryption: cannot read chunk %d: %v", index, err)
+	}
+
+	plain, err := d.aead.Open(d.plain[:0], chunkNonce(d.prefix, uint32(index), index == d.chunks-1), sealed, d.header)
+	if err != nil {
+		return fmt.Errorf("encryption: cannot decrypt chunk %d: %v", index, err)
+	}
+	d.plain = plain
+	d.chunk = index
+	return nil
+}
+
+func (d *decrypter) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += d.offset
+	case io.SeekEnd:
+		offset += d.size
+	default:
+		return 0, errors.New("encryption: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("encryption: negative position")
+	}
+	d.offset = offset
+	return offset, nil
+}
+
+func (d *decrypter) Close() error { return d.object.Close() }
@@ -0,0 +1,180 @@
+package filestore_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+
+	"github.com/matryer/is"
+)
+
+// testKeyStore keeps the data-keys in memory,
+// and counts the times the keys are read
+type testKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]filestore.DataKey
+	gets int
+}
+
+func (ks *testKeyStore) GetDataKeys(ctx context.Context, identifier string) ([]filestore.DataKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.gets++
+	var keys []filestore.DataKey
+	for _, key := range ks.keys {
+		if key.Identifier == identifier {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (ks *testKeyStore) CreateDataKey(ctx context.Context, key *filestore.DataKey) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	id := key.Identifier + string(rune('0'+key.Version))
+	if _, ok := ks.keys[id]; ok {
+		return errors.New("conflict")
+	}
+	ks.keys[id] = *key
+	return nil
+}
+
+func (ks *testKeyStore) UpdateDataKey(ctx context.Context, key *filestore.DataKey) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[key.Identifier+string(rune('0'+key.Version))] = *key
+	return nil
+}
+
+func TestEncrypted(t *testing.T) {
+	is := is.New(t)
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	plain, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	keys := &testKeyStore{keys: make(map[string]filestore.DataKey)}
+	first := filestore.MasterKey{ID: "first", Key: newKey(t)}
+	store, err := filestore.NewEncrypted(plain, keys, filestore.EncryptedOptions{MasterKeys: []filestore.MasterKey{first}})
+	is.NoErr(err)
+
+	// The content is larger than a chunk
+	data := make([]byte, 200<<10)
+	_, err = rand.Read(data)
+	is.NoErr(err)
+
+	file, err := store.Upload("case-1", "evidence.bin", bytes.NewReader(data))
+	is.NoErr(err)
+	is.Equal(file.Size, len(data))
+	hashes, _, err := filestore.Hash(bytes.NewReader(data))
+	is.NoErr(err)
+	is.Equal(file.Hashes, hashes)
+
+	// The stored file is encrypted
+	stored, err := plain.GetContent(file.Path)
+	is.NoErr(err)
+	is.True(!bytes.Contains(stored, data[:1024]))
+
+	content, err := store.GetContent(file.Path)
+	is.NoErr(err)
+	is.True(bytes.Equal(content, data))
+
+	// The file can be read from any offset
+	object, err := store.Open(file.Path)
+	is.NoErr(err)
+	size, err := object.Seek(0, io.SeekEnd)
+	is.NoErr(err)
+	is.Equal(size, int64(len(data)))
+	_, err = object.Seek(64<<10-2, io.SeekStart)
+	is.NoErr(err)
+	chunk := make([]byte, 4)
+	_, err = io.ReadFull(object, chunk)
+	is.NoErr(err)
+	is.Equal(chunk, data[64<<10-2:64<<10+2])
+	is.NoErr(object.Close())
+
+	// Empty files and files with a size of whole chunks, the
+	// data-keys for the case are cached when they are unwrapped
+	gets := keys.gets
+	for _, size := range []int{0, 64 << 10, 128 << 10} {
+		f, err := store.Upload("case-1", "evidence.bin", bytes.NewReader(data[:size]))
+		is.NoErr(err)
+		content, err := store.GetContent(f.Path)
+		is.NoErr(err)
+		is.True(bytes.Equal(content, data[:size]))
+	}
+	is.Equal(keys.gets, gets)
+
+	// Modified or truncated files cannot be read
+	tampered := append([]byte{}, stored...)
+	tampered[len(tampered)-100] ^= 1
+	is.NoErr(os.WriteFile(file.Path, tampered, 0644))
+	_, err = store.GetContent(file.Path)
+	is.True(err != nil)
+	is.NoErr(os.WriteFile(file.Path, stored[:len(stored)-(16+len(data)%(64<<10))], 0644))
+	_, err = store.GetContent(file.Path)
+	is.True(err != nil)
+	is.NoErr(os.WriteFile(file.Path, stored, 0644))
+
+	// Files are still readable after the data-key is rotated
+	version, err := store.(filestore.Rotator).RotateKey("case-1")
+	is.NoErr(err)
+	is.Equal(version, 2)
+	rotated, err := store.Upload("case-1", "evidence.txt", strings.NewReader("rotated"))
+	is.NoErr(err)
+	content, err = store.GetContent(file.Path)
+	is.NoErr(err)
+	is.True(bytes.Equal(content, data))
+
+	// and after the master-key is rotated
+	second := filestore.MasterKey{ID: "second", Key: newKey(t)}
+	store, err = filestore.NewEncrypted(plain, keys, filestore.EncryptedOptions{MasterKeys: []filestore.MasterKey{second, first}})
+	is.NoErr(err)
+	content, err = store.GetContent(rotated.Path)
+	is.NoErr(err)
+	is.Equal(string(content), "rotated")
+	for _, key := range keys.keys {
+		is.Equal(key.MasterKeyID, "second")
+	}
+
+	// so the old master-key isn't needed anymore
+	store, err = filestore.NewEncrypted(plain, keys, filestore.EncryptedOptions{MasterKeys: []filestore.MasterKey{second}})
+	is.NoErr(err)
+	content, err = store.GetContent(file.Path)
+	is.NoErr(err)
+	is.True(bytes.Equal(content, data))
+
+	// Files that aren't encrypted are rejected, unless the files
+	// stored before the encryption are allowed while they are migrated
+	legacy, err := plain.Upload("case-1", "legacy.txt", strings.NewReader("legacy"))
+	is.NoErr(err)
+	_, err = store.GetContent(legacy.Path)
+	is.True(errors.Is(err, filestore.ErrNotEncrypted))
+	store, err = filestore.NewEncrypted(plain, keys, filestore.EncryptedOptions{
+		MasterKeys:       []filestore.MasterKey{second},
+		AllowUnencrypted: true,
+	})
+	is.NoErr(err)
+	content, err = store.GetContent(legacy.Path)
+	is.NoErr(err)
+	is.Equal(string(content), "legacy")
+}
+
+func newKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -1,19 +1,41 @@
 package filestore
 
 import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"hash"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
+// ErrOutsideStore is returned for paths
+// that aren't inside the filestore
+var ErrOutsideStore = errors.New("path is outside the filestore")
+
 // Service handles the methods
-// for the filestore
+// for the filestore, the files are stored
+// under generated IDs in a directory for
+// the identifier, the name of the file
+// is only kept as metadata
 type Service interface {
-	Upload(identifier, name string, content []byte) (*File, error)
-	Delete(identifier, name string) error
+	Upload(identifier, name string, content io.Reader) (*File, error)
+	Delete(filename string) error
 	GetContent(filename string) ([]byte, error)
+	Open(filename string) (Object, error)
+}
+
+// Object is an opened file in the filestore,
+// that can be read from any offset
+type Object interface {
+	io.ReadSeeker
+	io.Closer
 }
 
 type svc struct {
@@ -40,49 +62,148 @@ func New(path string) (Service, error) {
 // File is the object
 // with file-information
 type File struct {
+	ID   string
 	Name string
 	Path string
 	Size int
+	Hashes
 }
 
-func (s svc) Upload(identifier, name string, content []byte) (*File, error) {
-	if len(name) == 0 {
-		return nil, errors.New("specify name for file to upload")
+// ValidName returns an error if the name cannot be used
+// for a file, names with paths are rejected so a file
+// can never be mistaken for another location
+func ValidName(name string) error {
+	switch {
+	case len(name) == 0:
+		return errors.New("specify name for file to upload")
+	case name == "." || name == "..":
+		return fmt.Errorf("invalid file name %q", name)
+	case strings.ContainsAny(name, "/\\\x00"):
+		return fmt.Errorf("invalid file name %q: must not contain a path", name)
 	}
+	return nil
+}
+
+// Hashes are the hex-encoded
+// hashes for the content of a file
+type Hashes struct {
+	MD5    string
+	SHA1   string
+	SHA256 string
+}
 
-	// Create the file and handle the errors
-	path := fmt.Sprintf("%s/%s/%s", s.path, identifier, name)
-	file, err := os.Create(path)
+// Hash reads the content and
+// returns the hashes for it
+func Hash(content io.Reader) (Hashes, int64, error) {
+	h := newHasher()
+	size, err := io.Copy(h, content)
 	if err != nil {
-		// Return the error if it isn't a path error
-		if _, ok := err.(*os.PathError); !ok {
-			return nil, err
-		}
-
-		// Create a directory for the identifier
-		if err := os.Mkdir(fmt.Sprintf("%s/%s", s.path, identifier), os.ModePerm); err != nil {
-			return nil, fmt.Errorf("Failed to create new dir: %s", err.Error())
-		}
-
-		// Try to create the file again
-		file, err = os.Create(path)
-		if err != nil {
-			return nil, err
-		}
+		return Hashes{}, size, err
+	}
+	return h.sums(), size, nil
+}
+
+// hasher computes every hash
+// in a single pass of the content
+type hasher struct {
+	io.Writer
+	md5, sha1, sha256 hash.Hash
+}
+
+func newHasher() *hasher {
+	h := &hasher{md5: md5.New(), sha1: sha1.New(), sha256: sha256.New()}
+	h.Writer = io.MultiWriter(h.md5, h.sha1, h.sha256)
+	return h
+}
+
+func (h *hasher) sums() Hashes {
+	return Hashes{
+		MD5:    hex.EncodeToString(h.md5.Sum(nil)),
+		SHA1:   hex.EncodeToString(h.sha1.Sum(nil)),
+		SHA256: hex.EncodeToString(h.sha256.Sum(nil)),
+	}
+}
+
+// Upload streams the content to a file, the
+// content is never buffered in memory and
+// it is hashed while it is written
+func (s svc) Upload(identifier, name string, content io.Reader) (*File, error) {
+	if err := ValidName(name); err != nil {
+		return nil, err
+	}
+	if err := ValidName(identifier); err != nil {
+		return nil, fmt.Errorf("invalid identifier %q", identifier)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a directory for the identifier
+	if err := os.MkdirAll(fmt.Sprintf("%s/%s", s.path, identifier), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("Failed to create new dir: %s", err.Error())
+	}
+
+	// The file is created exclusively, so
+	// another file can never be overwritten
+	path := fmt.Sprintf("%s/%s/%s", s.path, identifier, id)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
 	}
 	defer file.Close()
 
-	size, err := file.Write(content)
+	h := newHasher()
+	size, err := io.Copy(io.MultiWriter(file, h), content)
 	if err != nil {
+		file.Close()
 		os.Remove(path)
 		return nil, fmt.Errorf("Failed to write data to file: %s", err.Error())
 	}
 
-	return &File{Name: name, Path: path, Size: size}, nil
+	return &File{ID: id, Name: name, Path: path, Size: int(size), Hashes: h.sums()}, nil
+}
+
+// Delete deletes the file with the path
+// that was returned from the upload
+func (s svc) Delete(filename string) error {
+	if !s.contains(filename) {
+		return ErrOutsideStore
+	}
+	return os.Remove(filename)
+}
+
+func (s svc) GetContent(filename string) ([]byte, error) {
+	if !s.contains(filename) {
+		return nil, ErrOutsideStore
+	}
+	return os.ReadFile(filename)
+}
+
+// Open opens the file for reading
+func (s svc) Open(filename string) (Object, error) {
+	if !s.contains(filename) {
+		return nil, ErrOutsideStore
+	}
+	return os.Open(filename)
 }
 
-func (s svc) Delete(identifier, name string) error {
-	return os.Remove(fmt.Sprintf("%s/%s/%s", s.path, identifier, name))
+// contains returns true if the path
+// is a file inside of the filestore
+func (s svc) contains(filename string) bool {
+	rel, err := filepath.Rel(s.path, filepath.Clean(filename))
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
-func (svc) GetContent(filename string) ([]byte, error) { return ioutil.ReadFile(filename) }
+// newID generates a random ID for a file
+func newID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate ID for file: %v", err)
+	}
+	return hex.EncodeToString(id), nil
+}
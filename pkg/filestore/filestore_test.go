@@ -0,0 +1,53 @@
+package filestore_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+
+	"github.com/matryer/is"
+)
+
+func TestFilestore(t *testing.T) {
+	is := is.New(t)
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	// Files with the same name doesn't overwrite each other
+	first, err := store.Upload("case-1", "evidence.txt", strings.NewReader("first"))
+	is.NoErr(err)
+	second, err := store.Upload("case-1", "evidence.txt", strings.NewReader("second"))
+	is.NoErr(err)
+	is.Equal(first.Name, "evidence.txt")
+	is.True(first.Path != second.Path)
+	is.Equal(filepath.Base(first.Path), first.ID)
+
+	// Deleting one of the files keeps the other
+	is.NoErr(store.Delete(first.Path))
+	content, err := store.GetContent(second.Path)
+	is.NoErr(err)
+	is.Equal(string(content), "second")
+
+	// Paths in the names or identifiers are rejected
+	for _, name := range []string{"../evidence.txt", "..", "dir/evidence.txt", `dir\evidence.txt`, ""} {
+		_, err := store.Upload("case-1", name, strings.NewReader("x"))
+		is.True(err != nil)
+	}
+	_, err = store.Upload("..", "evidence.txt", strings.NewReader("x"))
+	is.True(err != nil)
+
+	// Files outside of the filestore cannot be touched
+	outside := filepath.Join(basePath, "..", "outside.txt")
+	is.Equal(store.Delete(outside), filestore.ErrOutsideStore)
+	_, err = store.Open(outside)
+	is.Equal(err, filestore.ErrOutsideStore)
+	_, err = store.GetContent(basePath)
+	is.Equal(err, filestore.ErrOutsideStore)
+}
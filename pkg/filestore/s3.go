@@ -0,0 +1,603 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minPartSize is the smallest part that S3 accepts
+	// in a multipart upload (except for the last part)
+	minPartSize = 5 << 20
+
+	// defaultPartSize is the size of the parts that are
+	// buffered in memory while a file is uploaded
+	defaultPartSize = 16 << 20
+
+	// maxParts is the max number of parts in an upload
+	maxParts = 10000
+
+	// defaultTimeout is the default Timeout for the requests
+	defaultTimeout = 5 * time.Minute
+)
+
+// S3Options holds the options for
+// an S3-compatible object storage
+type S3Options struct {
+	// Endpoint is the URL for the storage,
+	// like https://s3.eu-north-1.amazonaws.com
+	// or http://minio:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// PathStyle addresses the bucket in the path
+	// instead of the host, MinIO requires it
+	PathStyle bool
+
+	// PartSize is the size of the parts in multipart
+	// uploads, defaults to 16 MiB and must be at least 5 MiB
+	PartSize int
+
+	// Timeout is how long a request can take, defaults to 5 minutes.
+	// The objects are streamed without a total timeout, but a read
+	// of an object fails if the storage doesn't respond in time
+	Timeout time.Duration
+}
+
+type s3svc struct {
+	opts     S3Options
+	endpoint *url.URL
+	client   *http.Client
+
+	// stream is the client for reading the objects,
+	// the reads are timed out by the objects instead
+	stream *http.Client
+}
+
+// NewS3 creates a new Service that stores
+// the files in an S3-compatible bucket
+func NewS3(opts S3Options) (Service, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("s3: specify bucket")
+	}
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+		return nil, errors.New("s3: specify access-key and secret-key")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.PartSize == 0 {
+		opts.PartSize = defaultPartSize
+	}
+	if opts.PartSize < minPartSize {
+		return nil, fmt.Errorf("s3: part-size must be at least %d bytes", minPartSize)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %v", err)
+	}
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3: invalid endpoint %q", opts.Endpoint)
+	}
+
+	s := &s3svc{
+		opts:     opts,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: opts.Timeout},
+		stream:   &http.Client{},
+	}
+
+	// Check that the bucket exists and that
+	// the credentials can be used for it
+	res, err := s.do(http.MethodHead, "", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3: cannot access bucket %q: %s", opts.Bucket, res.Status)
+	}
+
+	return s, nil
+}
+
+// Upload streams the content to the bucket, files larger
+// than a part are uploaded with a multipart upload so
+// only a single part is buffered in memory at a time
+func (s *s3svc) Upload(identifier, name string, content io.Reader) (*File, error) {
+	if err := ValidName(name); err != nil {
+		return nil, err
+	}
+	if err := ValidName(identifier); err != nil {
+		return nil, fmt.Errorf("invalid identifier %q", identifier)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	key := identifier + "/" + id
+
+	h := newHasher()
+	content = io.TeeReader(content, h)
+
+	// Files smaller than a part are
+	// uploaded with a single request
+	part := make([]byte, s.opts.PartSize)
+	n, err := io.ReadFull(content, part)
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF:
+		if err := s.put(key, part[:n]); err != nil {
+			return nil, err
+		}
+		return &File{ID: id, Name: name, Path: key, Size: n, Hashes: h.sums()}, nil
+	case nil:
+	default:
+		return nil, fmt.Errorf("Failed to read data: %v", err)
+	}
+
+	size, err := s.multipart(key, part, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{ID: id, Name: name, Path: key, Size: int(size), Hashes: h.sums()}, nil
+}
+
+// Delete deletes the object with the
+// key that was returned from the upload
+func (s *s3svc) Delete(filename string) error {
+	if !validKey(filename) {
+		return ErrOutsideStore
+	}
+
+	res, err := s.do(http.MethodDelete, filename, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return decodeS3Error(res)
+	}
+	return nil
+}
+
+func (s *s3svc) GetContent(filename string) ([]byte, error) {
+	object, err := s.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(object)
+}
+
+// Open opens the object for reading, the object
+// is read with ranged requests from the offset
+func (s *s3svc) Open(filename string) (Object, error) {
+	if !validKey(filename) {
+		return nil, ErrOutsideStore
+	}
+
+	res, err := s.do(http.MethodHead, filename, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3: cannot open %q: %s", filename, res.Status)
+	}
+
+	return &s3Object{s: s, key: filename, size: res.ContentLength}, nil
+}
+
+func (s *s3svc) put(key string, data []byte) error {
+	res, err := s.do(http.MethodPut, key, nil, nil, data)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return decodeS3Error(res)
+	}
+	return nil
+}
+
+// multipart uploads the first part and the rest of the content
+// as a multipart upload, the upload is aborted if it fails
+func (s *s3svc) multipart(key string, first []byte, content io.Reader) (int64, error) {
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := s.doXML(http.MethodPost, key, url.Values{"uploads": {""}}, nil, &initiated); err != nil {
+		return 0, err
+	}
+
+	abort := func(err error) (int64, error) {
+		res, abortErr := s.do(http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadID}}, nil, nil)
+		if abortErr == nil {
+			res.Body.Close()
+		}
+		return 0, err
+	}
+
+	type completedPart struct {
+		PartNumber int
+		ETag       string
+	}
+	var completed struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}
+
+	var size int64
+	part := first
+	for number := 1; ; number++ {
+		if number > maxParts {
+			return abort(fmt.Errorf("s3: the file is larger than %d parts", maxParts))
+		}
+
+		query := url.Values{
+			"partNumber": {strconv.Itoa(number)},
+			"uploadId":   {initiated.UploadID},
+		}
+		res, err := s.do(http.MethodPut, key, query, nil, part)
+		if err != nil {
+			return abort(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return abort(fmt.Errorf("s3: failed to upload part %d: %s", number, res.Status))
+		}
+
+		completed.Parts = append(completed.Parts, completedPart{PartNumber: number, ETag: res.Header.Get("ETag")})
+		size += int64(len(part))
+
+		// The buffer is reused for the next part
+		n, err := io.ReadFull(content, first[:cap(first)])
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return abort(fmt.Errorf("Failed to read data: %v", err))
+		}
+		part = first[:n]
+	}
+
+	body, err := xml.Marshal(completed)
+	if err != nil {
+		return abort(err)
+	}
+	if err := s.doXML(http.MethodPost, key, url.Values{"uploadId": {initiated.UploadID}}, body, nil); err != nil {
+		return abort(err)
+	}
+
+	return size, nil
+}
+
+// doXML performs the request and decodes the XML-response,
+// S3 can return errors in the body of an OK-response
+func (s *s3svc) doXML(method, key string, query url.Values, payload []byte, to interface{}) error {
+	res, err := s.do(method, key, query, nil, payload)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return decodeS3Error(res)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("s3: cannot read response: %v", err)
+	}
+
+	var e s3Error
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("s3: %s: %s", e.Code, e.Message)
+	}
+	if to == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(body, to); err != nil {
+		return fmt.Errorf("s3: cannot parse response: %v", err)
+	}
+	return nil
+}
+
+// do signs and performs a request for the key,
+// or for the bucket if the key is empty
+func (s *s3svc) do(method, key string, query url.Values, header http.Header, payload []byte) (*http.Response, error) {
+	req, err := s.newRequest(method, key, query, header, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: cannot get response: %v", err)
+	}
+	return res, nil
+}
+
+// newRequest creates a signed request for the key,
+// or for the bucket if the key is empty
+func (s *s3svc) newRequest(method, key string, query url.Values, header http.Header, payload []byte) (*http.Request, error) {
+	u := *s.endpoint
+	if s.opts.PathStyle {
+		u.Path += "/" + s.opts.Bucket
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+	}
+	u.Path += "/" + key
+	if key == "" && s.opts.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+	}
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = canonicalQuery(query)
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("s3: failed to create http-request: %v", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	hash := sha256.Sum256(payload)
+	signV4(req, hex.EncodeToString(hash[:]), s.opts.AccessKeyID, s.opts.SecretAccessKey, s.opts.Region, time.Now())
+	return req, nil
+}
+
+// signV4 signs the request with AWS Signature Version 4,
+// every header in the request and the host is signed
+func signV4(req *http.Request, payloadHash, accessKeyID, secretAccessKey, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath URI-encodes every segment of the path,
+// the way S3 expects it in the canonical request
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = escape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery encodes the query sorted by the keys
+func canonicalQuery(query url.Values) string {
+	var params []string
+	for key, values := range query {
+		for _, value := range values {
+			params = append(params, escape(key)+"="+escape(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// escape encodes everything but the unreserved characters
+func escape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// validKey returns true if the key
+// is an object created by an upload
+func validKey(key string) bool {
+	parts := strings.Split(key, "/")
+	return len(parts) == 2 && ValidName(parts[0]) == nil && ValidName(parts[1]) == nil
+}
+
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func decodeS3Error(res *http.Response) error {
+	var e s3Error
+	if err := xml.NewDecoder(res.Body).Decode(&e); err != nil {
+		return fmt.Errorf("s3: [%s]", res.Status)
+	}
+	return fmt.Errorf("s3: [%s] %s: %s", res.Status, e.Code, e.Message)
+}
+
+// s3Object is an opened object, that lazily
+// starts a ranged request from the offset
+type s3Object struct {
+	s      *s3svc
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+
+	// timeout cancels the request if the storage
+	// doesn't respond to a read within the Timeout
+	timeout *time.Timer
+	cancel  context.CancelFunc
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+
+	if o.body == nil {
+		if err := o.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	o.timeout.Reset(o.s.opts.Timeout)
+	n, err := o.body.Read(p)
+	o.timeout.Stop()
+	o.offset += int64(n)
+	return n, err
+}
+
+// open starts a ranged request from the offset,
+// the storage must return the object from the offset
+func (o *s3Object) open() error {
+	header := http.Header{"Range": {fmt.Sprintf("bytes=%d-", o.offset)}}
+	req, err := o.s.newRequest(http.MethodGet, o.key, nil, header, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	timeout := time.AfterFunc(o.s.opts.Timeout, cancel)
+	res, err := o.s.stream.Do(req.WithContext(ctx))
+	timeout.Stop()
+	if err != nil {
+		cancel()
+		return fmt.Errorf("s3: cannot get response: %v", err)
+	}
+
+	switch {
+	case res.StatusCode == http.StatusPartialContent:
+	case res.StatusCode == http.StatusOK && o.offset == 0:
+		// The whole object is returned
+		// if the range is ignored
+	case res.StatusCode == http.StatusOK:
+		res.Body.Close()
+		cancel()
+		return fmt.Errorf("s3: the range from %d was ignored for %q", o.offset, o.key)
+	default:
+		defer cancel()
+		defer res.Body.Close()
+		return decodeS3Error(res)
+	}
+
+	o.body = res.Body
+	o.timeout = timeout
+	o.cancel = cancel
+	return nil
+}
+
+// closeBody closes the request from the offset
+func (o *s3Object) closeBody() error {
+	if o.body == nil {
+		return nil
+	}
+	o.timeout.Stop()
+	err := o.body.Close()
+	o.cancel()
+	o.body = nil
+	return err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, errors.New("s3: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("s3: negative position")
+	}
+
+	// A new request is made from the
+	// offset on the next read
+	if offset != o.offset {
+		o.closeBody()
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *s3Object) Close() error {
+	return o.closeBody()
+}
@@ -1,12 +1,10 @@
 package fscrawler
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -27,13 +25,19 @@ type Client struct {
 // with the specified URL
 //
 // fs := fscrawler.New("http://localhost:8080/fscrawler")
+//
+// The documents can take long to upload and process,
+// so the requests are only limited by their context
 func New(url string) *Client {
 	return &Client{
-		httpClient: &http.Client{Timeout: 5 * time.Second},
+		httpClient: &http.Client{},
 		url:        url,
 	}
 }
 
+// pingTimeout is the timeout for pinging fscrawler
+const pingTimeout = 5 * time.Second
+
 // Ping to check if fscrawler is healthy
 //
 // if ok, err := fs.Ping(); !ok {
@@ -43,7 +47,14 @@ func New(url string) *Client {
 //     log.Fatal("fscrawler not ok")
 // }
 func (c *Client) Ping(ctx context.Context) (bool, error) {
-	resp, err := c.httpClient.Get(c.url)
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "fscrawler.Status: create request")
+	}
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return false, errors.Wrap(err, "fscrawler.Status: get url")
 	}
@@ -67,11 +78,20 @@ type Process struct {
 	id      string
 	index   string
 	fileURI string
+	name    string
+	content io.Reader
 }
 
 // NewProcess creates a new process to be used for a new processing-job
 func (c *Client) NewProcess(fileURI string) *Process {
-	return &Process{client: c, fileURI: fileURI}
+	return &Process{client: c, fileURI: fileURI, name: filepath.Base(fileURI)}
+}
+
+// NewProcessFromReader creates a new process for content that isn't
+// on the local disk (like a file in an object storage), the name
+// is used by fscrawler to detect the type of the content
+func (c *Client) NewProcessFromReader(name string, content io.Reader) *Process {
+	return &Process{client: c, name: name, content: content}
 }
 
 // WithID sets an ID for the processor
@@ -88,52 +108,38 @@ func (p *Process) WithIndex(index string) *Process {
 
 // Start the process-job
 func (p *Process) Start(ctx context.Context) error {
-	file, err := os.Open(p.fileURI)
-	if err != nil {
-		return errors.Wrap(err, "cannot open file to process: ")
-	}
-	defer file.Close()
-
-	// Create a new writer
-	var body bytes.Buffer
-	writer := multipart.NewWriter(&body)
-	defer writer.Close()
-
-	// Pass custom-ID if it has been added as an argument
-	if len(p.id) > 0 {
-		if err := writer.WriteField("id", p.id); err != nil {
-			return errors.Wrap(err, "cannot set custom-id: ")
+	content := p.content
+	if content == nil {
+		file, err := os.Open(p.fileURI)
+		if err != nil {
+			return errors.Wrap(err, "cannot open file to process: ")
 		}
-	}
-
-	// Pass custom-Index if it has been added as an argument
-	if len(p.index) > 0 {
-		if err := writer.WriteField("index", p.index); err != nil {
-			return errors.Wrap(err, "cannot set custom-index: ")
-		}
-	}
-
-	// Get part for file
-	part, err := writer.CreateFormFile("file", filepath.Base(p.fileURI))
-	if err != nil {
-		return errors.Wrap(err, "cannot create form-file: ")
-	}
-
-	// Write file to part
-	if _, err := io.Copy(part, file); err != nil {
-		return errors.Wrap(err, "cannot write file to part")
-	}
-
-	if err := writer.Close(); err != nil {
-		return errors.Wrap(err, "writer.Close(): ")
-	}
+		defer file.Close()
+		content = file
+	}
+
+	// The multipart-body is streamed
+	// so the file isn't kept in memory
+	body, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	done := make(chan struct{})
+	go func() {
+		pw.CloseWithError(p.writeBody(writer, content))
+		close(done)
+	}()
+
+	// The content cannot be closed before it's no longer read
+	defer func() {
+		body.Close()
+		<-done
+	}()
 
 	// Make the request
-	req, err := http.NewRequest("POST", p.client.url+"/_upload", &body)
+	req, err := http.NewRequest("POST", p.client.url+"/_upload", body)
 	if err != nil {
 		return errors.Wrap(err, "cannot create new request: ")
 	}
-	req.WithContext(ctx)
+	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	resp, err := p.client.httpClient.Do(req)
 	if err != nil {
@@ -157,8 +163,38 @@ func (p *Process) Start(ctx context.Context) error {
 	return nil
 }
 
+// writeBody writes the fields and the file to the multipart-body
+func (p *Process) writeBody(writer *multipart.Writer, content io.Reader) error {
+	// Pass custom-ID if it has been added as an argument
+	if len(p.id) > 0 {
+		if err := writer.WriteField("id", p.id); err != nil {
+			return errors.Wrap(err, "cannot set custom-id: ")
+		}
+	}
+
+	// Pass custom-Index if it has been added as an argument
+	if len(p.index) > 0 {
+		if err := writer.WriteField("index", p.index); err != nil {
+			return errors.Wrap(err, "cannot set custom-index: ")
+		}
+	}
+
+	// Get part for file
+	part, err := writer.CreateFormFile("file", p.name)
+	if err != nil {
+		return errors.Wrap(err, "cannot create form-file: ")
+	}
+
+	// Write file to part
+	if _, err := io.Copy(part, content); err != nil {
+		return errors.Wrap(err, "cannot write file to part")
+	}
+
+	return errors.Wrap(writer.Close(), "writer.Close(): ")
+}
+
 func decodeResponse(r *http.Response, to interface{}) error {
-	respBodyBytes, err := ioutil.ReadAll(r.Body)
+	respBodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		return errors.Wrap(err, "fscrawler.decodeResponse: read response body")
 	}
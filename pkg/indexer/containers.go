@@ -0,0 +1,312 @@
+package indexer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The content-types for the containers
+const (
+	typeZIP  = "application/zip"
+	typeTar  = "application/x-tar"
+	typeGzip = "application/gzip"
+	typeMbox = "application/mbox"
+	type7z   = "application/x-7z-compressed"
+	typeRAR  = "application/vnd.rar"
+	typePST  = "application/vnd.ms-outlook"
+)
+
+// unsupported are the containers that can't be expanded,
+// they are indexed with the reason as the container-error
+var unsupported = map[string]string{
+	type7z:  "7z-archives are not supported, extract the archive and upload the files",
+	typeRAR: "RAR-archives are not supported, extract the archive and upload the files",
+}
+
+// addFunc indexes a file in a container, with the time it
+// was last modified if the container records it
+type addFunc func(name string, modified time.Time, content io.Reader) error
+
+// expander adds the files in a container
+type expander func(name string, r io.Reader, add addFunc) error
+
+// expanders maps the content-types for the
+// containers that are streamed to their expander
+var expanders = map[string]expander{
+	typeTar:  expandTar,
+	typeGzip: expandGzip,
+	typeMbox: expandMbox,
+}
+
+// attachments maps the content-types for the documents
+// that has files in them to their expander
+var attachments = map[string]expander{
+	typeEML: expandEML,
+}
+
+// expandZIP adds the files in a ZIP-archive, Office-documents
+// are ZIP-archives too, and are extracted as documents
+func (n *Native) expandZIP(processed *Processed, content io.Reader, add addFunc) error {
+	r, size, cleanup, err := n.readerAt(content)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %v", processed.File.Filename, err)
+	}
+	defer cleanup()
+
+	archive, err := zip.NewReader(r, size)
+	if err == nil && ooxmlType(archive) != "" {
+		return n.extract(processed, io.NewSectionReader(r, 0, size), add)
+	}
+
+	processed.File.Filesize = size
+	processed.Container = &Container{}
+	if err != nil {
+		return err
+	}
+
+	for _, f := range archive.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		// Files that can't be opened are indexed with
+		// the error, like encrypted files
+		var content io.ReadCloser = io.NopCloser(errReader{fmt.Errorf("cannot open %s: unsupported compression or encryption", f.Name)})
+		if rc, err := f.Open(); err == nil {
+			content = rc
+		}
+		err := add(f.Name, zipModified(f), content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zipModified returns when the file in the ZIP-archive was
+// last modified, or the zero time if it isn't recorded
+func zipModified(f *zip.File) time.Time {
+	if f.ModifiedDate == 0 && f.ModifiedTime == 0 {
+		return time.Time{}
+	}
+	return f.Modified
+}
+
+// knownTime returns the zero time for the times
+// before 1970, archives have them if it isn't known
+func knownTime(t time.Time) time.Time {
+	if t.Unix() <= 0 {
+		return time.Time{}
+	}
+	return t
+}
+
+// readerAt returns the content as a ReaderAt, for ZIP-archives and
+// PST-files, the content is written to a temporary file if it can't seek
+func (n *Native) readerAt(content io.Reader) (io.ReaderAt, int64, func(), error) {
+	if rs, ok := content.(io.ReadSeeker); ok {
+		size, err := rs.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if ra, ok := content.(io.ReaderAt); ok {
+			return ra, size, func() {}, nil
+		}
+		return &seekReaderAt{rs: rs}, size, func() {}, nil
+	}
+
+	f, err := os.CreateTemp(n.TempDir, "indexer-*")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+
+	size, err := io.Copy(f, content)
+	if err != nil {
+		cleanup()
+		return nil, 0, nil, err
+	}
+	return f, size, cleanup, nil
+}
+
+// expandTar adds the regular files in a TAR-archive
+func expandTar(name string, r io.Reader, add addFunc) error {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !header.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		if err := add(header.Name, knownTime(header.ModTime), archive); err != nil {
+			return err
+		}
+	}
+}
+
+// expandGzip adds the decompressed file, with the name from
+// the gzip-header or the name without the extension
+func expandGzip(name string, r io.Reader, add addFunc) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	child := path.Base(strings.Replace(gz.Name, "\\", "/", -1))
+	if gz.Name == "" || child == "." || child == "/" {
+		child = gunzipName(path.Base(name))
+	}
+	return add(child, knownTime(gz.ModTime), gz)
+}
+
+// gunzipName returns the name for the
+// decompressed file from the gzip-file
+func gunzipName(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tgz"):
+		return name[:len(name)-len(".tgz")] + ".tar"
+	case strings.HasSuffix(lower, ".gz"):
+		return name[:len(name)-len(".gz")]
+	}
+	return name + ".out"
+}
+
+// mboxSeparator starts a new message in an mbox-file
+var mboxSeparator = []byte("From ")
+
+// expandMbox adds the messages in an mbox-file as EML-files,
+// the escaped lines in the messages (>From) are unescaped
+func expandMbox(name string, r io.Reader, add addFunc) error {
+	reader := bufio.NewReader(r)
+	var msg bytes.Buffer
+	var count int
+	var started bool
+	flush := func() error {
+		if msg.Len() == 0 {
+			return nil
+		}
+		count++
+		err := add(fmt.Sprintf("%d.eml", count), time.Time{}, bytes.NewReader(msg.Bytes()))
+		msg.Reset()
+		return err
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		switch {
+		case len(line) == 0:
+		case bytes.HasPrefix(line, mboxSeparator):
+			if err := flush(); err != nil {
+				return err
+			}
+			started = true
+		case !started:
+			// The lines before the first message aren't a message
+		case line[0] == '>' && bytes.HasPrefix(bytes.TrimLeft(line, ">"), mboxSeparator):
+			msg.Write(line[1:])
+		default:
+			msg.Write(line)
+		}
+
+		if err == io.EOF {
+			return flush()
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// expansion counts the files and the bytes that are expanded
+// from the containers in an uploaded file, for the limits
+type expansion struct {
+	maxBytes   int64
+	maxEntries int
+
+	bytes   int64
+	entries int
+}
+
+// add counts a file in a container, and returns an
+// error if the expansion has reached a limit
+func (e *expansion) add() error {
+	if e.maxBytes > 0 && e.bytes >= e.maxBytes {
+		return fmt.Errorf("the limit of %d bytes expanded from the upload is reached", e.maxBytes)
+	}
+	if e.maxEntries > 0 && e.entries >= e.maxEntries {
+		return fmt.Errorf("the limit of %d files expanded from the upload is reached", e.maxEntries)
+	}
+	e.entries++
+	return nil
+}
+
+// reader counts the bytes read from a file in a container,
+// the read fails when the limit for the bytes is reached
+func (e *expansion) reader(r io.Reader) io.Reader {
+	return &expansionReader{r: r, e: e}
+}
+
+type expansionReader struct {
+	r io.Reader
+	e *expansion
+}
+
+func (r *expansionReader) Read(p []byte) (int, error) {
+	if r.e.maxBytes > 0 {
+		if r.e.bytes >= r.e.maxBytes {
+			return 0, fmt.Errorf("the limit of %d bytes expanded from the upload is reached", r.e.maxBytes)
+		}
+		if remaining := r.e.maxBytes - r.e.bytes; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := r.r.Read(p)
+	r.e.bytes += int64(n)
+	return n, err
+}
+
+// seekReaderAt reads at an offset by seeking
+type seekReaderAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (s *seekReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(s.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// errReader returns the error when it's read
+type errReader struct {
+	err error
+}
+
+func (e errReader) Read(p []byte) (int, error) { return 0, e.err }
@@ -0,0 +1,184 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// The tags in the EXIF-data that are extracted
+const (
+	tagMake                = 0x010f
+	tagModel               = 0x0110
+	tagDateTime            = 0x0132
+	tagExifIFD             = 0x8769
+	tagDateTimeOriginal    = 0x9003
+	tagDateTimeDigitized   = 0x9004
+	tagOffsetTime          = 0x9010
+	tagOffsetTimeOriginal  = 0x9011
+	tagOffsetTimeDigitized = 0x9012
+)
+
+// exifDateFormat is the format for the dates in EXIF
+const exifDateFormat = "2006:01:02 15:04:05"
+
+// extractJPEG extracts the metadata in the EXIF-data of a
+// JPEG-image, the time it was taken is the created-date and
+// the time it was changed is the date. Images without EXIF,
+// or with EXIF that can't be read, are indexed without it
+func extractJPEG(data []byte) (string, Meta, error) {
+	tags, err := readEXIF(data)
+	if err != nil || len(tags) == 0 {
+		return "", Meta{}, nil
+	}
+
+	var meta Meta
+	raw := make(map[string]string)
+	for _, tag := range []struct {
+		key    string
+		value  uint16
+		offset uint16
+		date   *string
+	}{
+		{key: "exif:DateTimeOriginal", value: tagDateTimeOriginal, offset: tagOffsetTimeOriginal, date: &meta.Created},
+		{key: "exif:DateTimeDigitized", value: tagDateTimeDigitized, offset: tagOffsetTimeDigitized},
+		{key: "tiff:DateTime", value: tagDateTime, offset: tagOffsetTime, date: &meta.Date},
+	} {
+		date, ok := exifDate(tags[tag.value], tags[tag.offset])
+		if !ok {
+			continue
+		}
+		raw[tag.key] = date
+		if tag.date != nil {
+			*tag.date = date
+		}
+	}
+
+	if camera := tags[tagMake]; camera != "" {
+		raw["tiff:Make"] = camera
+	}
+	if model := tags[tagModel]; model != "" {
+		raw["tiff:Model"] = model
+		meta.CreatorTool = strings.TrimSpace(tags[tagMake] + " " + model)
+	}
+	if len(raw) > 0 {
+		meta.Raw = raw
+	}
+	return "", meta, nil
+}
+
+// exifDate formats a date in EXIF, the dates are in the local
+// time of the camera, they are in UTC if the offset isn't known
+func exifDate(value, offset string) (string, bool) {
+	t, err := time.Parse(exifDateFormat, value)
+	if err != nil {
+		return "", false
+	}
+	if zone, err := time.Parse("-07:00", offset); err == nil {
+		_, seconds := zone.Zone()
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.FixedZone("", seconds))
+	}
+	return t.Format(dateFormat), true
+}
+
+// errNoEXIF is returned for images without EXIF-data
+var errNoEXIF = errors.New("no EXIF-data")
+
+// readEXIF reads the text-tags in the first IFD and
+// the EXIF-IFD, from the APP1-segment of a JPEG-image
+func readEXIF(data []byte) (map[uint16]string, error) {
+	if !bytes.HasPrefix(data, []byte{0xff, 0xd8}) {
+		return nil, errors.New("not a JPEG-image")
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			return nil, errors.New("invalid JPEG-segment")
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+
+		// The image-data starts after SOS, the
+		// EXIF-data is in the segments before it
+		if marker == 0xda || length < 2 || i+2+length > len(data) {
+			break
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return readTIFF(segment[6:])
+		}
+		i += 2 + length
+	}
+	return nil, errNoEXIF
+}
+
+// readTIFF reads the text-tags in the TIFF-structure of the EXIF-data
+func readTIFF(data []byte) (map[uint16]string, error) {
+	if len(data) < 8 {
+		return nil, errNoEXIF
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid TIFF-header")
+	}
+
+	tags := make(map[uint16]string)
+	exif, err := readIFD(data, order, order.Uint32(data[4:]), tags)
+	if err != nil {
+		return nil, err
+	}
+	if exif > 0 {
+		if _, err := readIFD(data, order, exif, tags); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// readIFD reads the ASCII-values in an IFD to the tags,
+// and returns the offset for the EXIF-IFD if it's in it
+func readIFD(data []byte, order binary.ByteOrder, offset uint32, tags map[uint16]string) (uint32, error) {
+	if int64(offset)+2 > int64(len(data)) {
+		return 0, errors.New("invalid IFD-offset")
+	}
+	count := int(order.Uint16(data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(data) {
+		return 0, errors.New("invalid IFD-length")
+	}
+
+	var exif uint32
+	for i := 0; i < count; i++ {
+		entry := data[start+i*12 : start+(i+1)*12]
+		tag, kind := order.Uint16(entry), order.Uint16(entry[2:])
+		length := order.Uint32(entry[4:])
+		switch {
+		case tag == tagExifIFD:
+			exif = order.Uint32(entry[8:])
+		case kind == 2:
+			// ASCII-values that fits in four bytes are in the entry
+			value := entry[8:12]
+			if length > 4 {
+				at := order.Uint32(entry[8:])
+				if int64(at)+int64(length) > int64(len(data)) {
+					continue
+				}
+				value = data[at : at+length]
+			}
+			if int(length) < len(value) {
+				value = value[:length]
+			}
+			tags[tag] = strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+		}
+	}
+	return exif, nil
+}
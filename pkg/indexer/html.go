@@ -0,0 +1,123 @@
+package indexer
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// attributeRegex matches the attributes in an HTML-tag
+var attributeRegex = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+
+// blockTags are the HTML-tags that starts a new line
+var blockTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "tr": true, "table": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"pre": true, "blockquote": true, "section": true, "article": true,
+	"header": true, "footer": true, "hr": true, "ul": true, "ol": true,
+}
+
+// extractHTML extracts the text from an HTML-document,
+// and the title and metadata from the head
+func extractHTML(data []byte) (string, Meta, error) {
+	var text strings.Builder
+	var meta Meta
+	s := toUTF8(data)
+	for len(s) > 0 {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			text.WriteString(html.UnescapeString(s))
+			break
+		}
+		text.WriteString(html.UnescapeString(s[:start]))
+		s = s[start:]
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			break
+		}
+		tag := s[1:end]
+		s = s[end+1:]
+
+		var name string
+		if fields := strings.Fields(tag); len(fields) > 0 {
+			name = strings.ToLower(strings.TrimRight(fields[0], "/"))
+		}
+		switch name {
+		case "script", "style", "title":
+			// The content of these tags isn't
+			// text, except for the title
+			end := indexFold(s, "</"+name)
+			if end < 0 {
+				end = len(s)
+			}
+			if name == "title" {
+				meta.Title = strings.TrimSpace(html.UnescapeString(s[:end]))
+			}
+			s = s[end:]
+		case "meta":
+			attributes := htmlAttributes(tag)
+			switch strings.ToLower(attributes["name"]) {
+			case "author":
+				meta.Author = attributes["content"]
+			case "keywords":
+				meta.Keywords = splitKeywords(attributes["content"])
+			case "description":
+				if meta.Raw == nil {
+					meta.Raw = make(map[string]string)
+				}
+				meta.Raw["description"] = attributes["content"]
+			}
+		case "td", "th", "/td", "/th":
+			text.WriteString(" ")
+		default:
+			if blockTags[strings.TrimPrefix(name, "/")] {
+				text.WriteString("\n")
+			}
+		}
+	}
+
+	return cleanText(text.String()), meta, nil
+}
+
+// htmlAttributes returns the attributes in the tag,
+// the names are lower-case and the values unescaped
+func htmlAttributes(tag string) map[string]string {
+	attributes := make(map[string]string)
+	for _, match := range attributeRegex.FindAllStringSubmatch(tag, -1) {
+		value := strings.Trim(match[2], `"'`)
+		attributes[strings.ToLower(match[1])] = html.UnescapeString(value)
+	}
+	return attributes
+}
+
+// splitKeywords splits the keywords on commas and semicolons
+func splitKeywords(keywords string) []string {
+	var split []string
+	for _, keyword := range strings.FieldsFunc(keywords, func(r rune) bool { return r == ',' || r == ';' }) {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			split = append(split, keyword)
+		}
+	}
+	return split
+}
+
+// indexFold returns the index of the first
+// case-insensitive match of substr in s
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,79 @@
+package indexer
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/fscrawler"
+)
+
+// Indexer extracts the text and metadata
+// from documents and indexes them
+type Indexer interface {
+	Index(ctx context.Context, doc Document) error
+}
+
+// Document is a document to index
+type Document struct {
+	// ID of the indexed document,
+	// it's the ID of the file
+	ID string
+
+	// Index to save the document in
+	Index string
+
+	// Name of the file, it's used
+	// to detect the type of the content
+	Name string
+
+	// Modified is when the file was last modified,
+	// if it's known, like for the files in archives
+	Modified time.Time
+
+	Content io.Reader
+}
+
+// fsCrawler indexes the documents with fscrawler
+type fsCrawler struct {
+	client *fscrawler.Client
+}
+
+// NewFSCrawler creates an indexer that uploads
+// the documents to fscrawler over its REST-interface
+func NewFSCrawler(client *fscrawler.Client) Indexer {
+	return fsCrawler{client: client}
+}
+
+// Index uploads the document to fscrawler
+func (i fsCrawler) Index(ctx context.Context, doc Document) error {
+	return i.client.NewProcessFromReader(doc.Name, doc.Content).WithID(doc.ID).WithIndex(doc.Index).Start(ctx)
+}
+
+// dateLayouts are the layouts for the dates in the
+// documents from both indexers, and the EXIF-dates
+// in the raw metadata from fscrawler
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	exifDateFormat,
+}
+
+// ParseDate parses a date in a processed document,
+// dates without a time-zone are in UTC
+func ParseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
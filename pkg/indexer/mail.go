@@ -0,0 +1,242 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+)
+
+// extractEML extracts the text from the body of an email,
+// the subject is the title and the sender is the author
+func extractEML(data []byte) (string, Meta, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return "", Meta{}, err
+	}
+
+	decoder := new(mime.WordDecoder)
+	header := func(key string) string {
+		value := msg.Header.Get(key)
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			return decoded
+		}
+		return value
+	}
+
+	meta := Meta{
+		Title:  header("Subject"),
+		Author: header("From"),
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		meta.Date = formatDate(date)
+		meta.Created = meta.Date
+	}
+
+	raw := make(map[string]string)
+	for _, key := range []string{"To", "Cc", "Bcc", "Message-ID"} {
+		if value := header(key); value != "" {
+			raw[strings.ToLower(key)] = value
+		}
+	}
+	if len(raw) > 0 {
+		meta.Raw = raw
+	}
+
+	text, _, err := mailBody(textproto.MIMEHeader(msg.Header), msg.Body)
+	if err != nil {
+		return "", Meta{}, err
+	}
+	return strings.TrimSpace(text), meta, nil
+}
+
+// mailBody returns the text in the body and its media-type,
+// the plain text is used for alternative parts if it exists
+func mailBody(header textproto.MIMEHeader, body io.Reader) (string, string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	body = decodeTransfer(header, body)
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		reader := multipart.NewReader(body, params["boundary"])
+		var texts []string
+		var plain, html string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return "", "", err
+			}
+
+			if isAttachment(part) {
+				continue
+			}
+			text, partType, err := mailBody(part.Header, part)
+			if err != nil {
+				return "", "", err
+			}
+			switch {
+			case text == "":
+			case partType == "text/html" && html == "":
+				html = text
+			case partType != "text/html" && plain == "":
+				plain = text
+			}
+			if text != "" {
+				texts = append(texts, text)
+			}
+		}
+
+		if mediaType == "multipart/alternative" {
+			if plain != "" {
+				return plain, "text/plain", nil
+			}
+			return html, "text/html", nil
+		}
+		return strings.Join(texts, "\n\n"), mediaType, nil
+	case mediaType == "text/plain", mediaType == "text/html":
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return "", "", err
+		}
+		text := decodeCharset(data, params["charset"])
+		if mediaType == "text/html" {
+			text, _, _ = extractHTML([]byte(text))
+		}
+		return text, mediaType, nil
+	}
+	return "", mediaType, nil
+}
+
+// expandEML adds the attachments in an email,
+// forwarded emails are added as EML-files
+func expandEML(name string, r io.Reader, add addFunc) error {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return err
+	}
+	var count int
+	return mailAttachments(textproto.MIMEHeader(msg.Header), msg.Body, &count, add)
+}
+
+// mailAttachments adds the attachments in the
+// multipart-body, and in the nested multiparts
+func mailAttachments(header textproto.MIMEHeader, body io.Reader, count *int, add addFunc) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return nil
+	}
+
+	reader := multipart.NewReader(decodeTransfer(header, body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch {
+		case isAttachment(part), partType == typeEML:
+			*count++
+			if err := add(attachmentName(part, *count), attachmentModified(part), decodeTransfer(part.Header, part)); err != nil {
+				return err
+			}
+		case strings.HasPrefix(partType, "multipart/"):
+			if err := mailAttachments(part.Header, part, count, add); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// isAttachment returns true if the part is an attachment
+func isAttachment(part *multipart.Part) bool {
+	disposition, _, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+	return disposition == "attachment"
+}
+
+// attachmentName returns the file-name for the attachment, from
+// the disposition or the content-type, or a name from the number
+func attachmentName(part *multipart.Part, number int) string {
+	name := part.FileName()
+	if name == "" {
+		_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		name = params["name"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+
+	// The name is used as a path in the email
+	name = path.Base(strings.Replace(name, "\\", "/", -1))
+	if name == "" || name == "." || name == "/" {
+		name = fmt.Sprintf("attachment-%d", number)
+		if mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type")); mediaType == typeEML {
+			name += ".eml"
+		}
+	}
+	return name
+}
+
+// attachmentModified returns the modification-date
+// in the disposition, if the sender included it
+func attachmentModified(part *multipart.Part) time.Time {
+	_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+	modified, err := mail.ParseDate(params["modification-date"])
+	if err != nil {
+		return time.Time{}
+	}
+	return modified
+}
+
+// decodeTransfer decodes the body from its transfer-encoding
+func decodeTransfer(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineRemover{body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// decodeCharset decodes the text from the charset,
+// unknown charsets are decoded as UTF-8 or Latin-1
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		return latin1(data)
+	}
+	return toUTF8(data)
+}
+
+// newlineRemover removes the line-breaks in base64-encoded parts
+type newlineRemover struct {
+	r io.Reader
+}
+
+func (n newlineRemover) Read(p []byte) (int, error) {
+	read, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:read] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
@@ -0,0 +1,582 @@
+package indexer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The content-types that are extracted natively
+const (
+	typeText = "text/plain"
+	typeHTML = "text/html"
+	typePDF  = "application/pdf"
+	typeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	typeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	typePPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	typeEML  = "message/rfc822"
+	typeJPEG = "image/jpeg"
+)
+
+// dateFormat is the format for the dates in the documents
+const dateFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// extensions maps the file-extensions to their content-type
+var extensions = map[string]string{
+	".txt":  typeText,
+	".text": typeText,
+	".log":  typeText,
+	".csv":  typeText,
+	".md":   typeText,
+	".htm":  typeHTML,
+	".html": typeHTML,
+	".pdf":  typePDF,
+	".docx": typeDOCX,
+	".xlsx": typeXLSX,
+	".pptx": typePPTX,
+	".eml":  typeEML,
+	".jpg":  typeJPEG,
+	".jpeg": typeJPEG,
+	".zip":  typeZIP,
+	".tar":  typeTar,
+	".gz":   typeGzip,
+	".mbox": typeMbox,
+	".mbx":  typeMbox,
+	".7z":   type7z,
+	".rar":  typeRAR,
+	".pst":  typePST,
+	".ost":  typePST,
+}
+
+// extractor extracts the text and metadata from the content
+type extractor func(data []byte) (string, Meta, error)
+
+// extractors for the content-types
+var extractors = map[string]extractor{
+	typeText: extractText,
+	typeHTML: extractHTML,
+	typePDF:  extractPDF,
+	typeDOCX: extractDOCX,
+	typeXLSX: extractXLSX,
+	typePPTX: extractPPTX,
+	typeEML:  extractEML,
+	typeJPEG: extractJPEG,
+}
+
+// Processed is a processed document, in the
+// same shape as the documents from fscrawler
+type Processed struct {
+	Content string `json:"content,omitempty"`
+	Meta    Meta   `json:"meta"`
+	File    File   `json:"file"`
+	Path    Path   `json:"path"`
+
+	// Parent is set for the files that
+	// are extracted from a container
+	Parent *Parent `json:"parent,omitempty"`
+
+	// Container is set for containers
+	Container *Container `json:"container,omitempty"`
+
+	// Error is the reason the content couldn't be
+	// extracted, for the files in containers
+	Error string `json:"error,omitempty"`
+}
+
+// Meta is the metadata that is
+// extracted from the document
+type Meta struct {
+	Author      string            `json:"author,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Date        string            `json:"date,omitempty"`
+	Keywords    []string          `json:"keywords,omitempty"`
+	Modifier    string            `json:"modifier,omitempty"`
+	Created     string            `json:"created,omitempty"`
+	CreatorTool string            `json:"creator_tool,omitempty"`
+	Language    string            `json:"language,omitempty"`
+	Raw         map[string]string `json:"raw,omitempty"`
+}
+
+// File is the information for the file
+type File struct {
+	Extension    string `json:"extension,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	IndexingDate string `json:"indexing_date"`
+	LastModified string `json:"last_modified,omitempty"`
+	Filesize     int64  `json:"filesize"`
+	Filename     string `json:"filename"`
+}
+
+// Path is the path for the file, the virtual path for
+// files in containers is the path from the uploaded
+// file, like /mail.zip/inbox.mbox/12.eml
+type Path struct {
+	Virtual string `json:"virtual"`
+	Real    string `json:"real"`
+}
+
+// Parent links a file in a container to the container,
+// and to the uploaded file it was extracted from
+type Parent struct {
+	// FileID is the ID of the uploaded file
+	FileID string `json:"file_id"`
+
+	// ID of the document for the container
+	ID string `json:"id"`
+
+	// Path is the virtual path of the container
+	Path string `json:"path"`
+
+	// Depth of the file, files directly
+	// in the uploaded file have depth 1
+	Depth int `json:"depth"`
+}
+
+// Container is the information for a container
+type Container struct {
+	// Children is the number of files in the container
+	Children int `json:"children"`
+
+	// Error is the reason the
+	// container couldn't be expanded
+	Error string `json:"error,omitempty"`
+}
+
+// Store saves the processed documents
+type Store interface {
+	IndexDocument(ctx context.Context, index, id string, document interface{}) error
+}
+
+// Native extracts the text and metadata in the API,
+// without fscrawler, for plain text, HTML, PDF, DOCX,
+// XLSX, PPTX, EML-files and the EXIF in JPEG-images.
+// Containers (ZIP, TAR, GZIP and mbox) and the attachments
+// in emails are expanded, and every file in them is
+// indexed as its own document
+type Native struct {
+	store Store
+
+	// MaxSize is the max size of a file to extract the
+	// content from, larger files and files of other types
+	// are only indexed with the information for the file
+	MaxSize int64
+
+	// MaxDepth is the max depth of containers
+	// in containers that are expanded
+	MaxDepth int
+
+	// MaxExpandedSize is the max number of bytes that are read
+	// from the files in the containers in an uploaded file, and
+	// MaxEntries is the max number of files in them. The expansion
+	// stops when a limit is reached, so an archive-bomb can't fill
+	// the index, the files that were expanded before are kept
+	MaxExpandedSize int64
+	MaxEntries      int
+
+	// TempDir is where ZIP-files in containers are written,
+	// since they can't be read without seeking
+	TempDir string
+}
+
+// NewNative creates a native indexer
+// that saves the documents in the store
+func NewNative(store Store) *Native {
+	return &Native{
+		store:           store,
+		MaxSize:         100 << 20,
+		MaxDepth:        10,
+		MaxExpandedSize: 20 << 30,
+		MaxEntries:      100000,
+	}
+}
+
+// Index extracts the document and saves it in the store,
+// the files in containers are saved before the container
+func (n *Native) Index(ctx context.Context, doc Document) error {
+	limits := &expansion{maxBytes: n.MaxExpandedSize, maxEntries: n.MaxEntries}
+	return n.index(ctx, doc, nil, limits)
+}
+
+// Extract extracts the text and metadata from
+// the content, containers aren't expanded
+func (n *Native) Extract(name string, content io.Reader) (*Processed, error) {
+	content, head, err := peek(content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", name, err)
+	}
+
+	processed := newProcessed(name, detect(name, head), nil)
+	if err := n.extract(processed, content, nil); err != nil {
+		return nil, err
+	}
+	return processed, nil
+}
+
+// index processes the document and saves it, files in
+// containers that can't be extracted are saved with
+// the error, so they don't fail the whole container
+func (n *Native) index(ctx context.Context, doc Document, parent *Parent, limits *expansion) error {
+	if err := ctx.Err(); err != nil {
+		return &stopError{err}
+	}
+
+	processed, err := n.process(ctx, doc, parent, limits)
+	if err != nil {
+		var stop *stopError
+		if parent == nil || errors.As(err, &stop) {
+			return err
+		}
+		processed.Content = ""
+		processed.Error = err.Error()
+	}
+
+	if err := n.store.IndexDocument(ctx, doc.Index, doc.ID, processed); err != nil {
+		return &stopError{err}
+	}
+	return nil
+}
+
+// process extracts the document, or expands it if it's a container
+func (n *Native) process(ctx context.Context, doc Document, parent *Parent, limits *expansion) (*Processed, error) {
+	content, head, err := peek(doc.Content)
+	processed := newProcessed(doc.Name, detect(doc.Name, head), parent)
+	processed.File.LastModified = formatDate(doc.Modified)
+	if err != nil {
+		return processed, fmt.Errorf("cannot read %s: %v", doc.Name, err)
+	}
+
+	add := n.adder(ctx, doc, processed, parent, limits)
+	contentType := processed.File.ContentType
+	switch {
+	case unsupported[contentType] != "":
+		processed.Container = &Container{Error: unsupported[contentType]}
+		processed.File.Filesize, err = io.Copy(io.Discard, content)
+	case contentType == typeZIP && add != nil:
+		err = n.expandZIP(processed, content, add)
+	case contentType == typePST && add != nil:
+		err = n.expandPST(processed, content, add)
+	case expanders[contentType] != nil && add != nil:
+		processed.Container = &Container{}
+		counter := &countingReader{r: content}
+		err = expanders[contentType](doc.Name, counter, add)
+		if err == nil {
+			_, err = io.Copy(io.Discard, counter)
+		}
+		processed.File.Filesize = counter.n
+	default:
+		err = n.extract(processed, content, add)
+	}
+
+	// The files that were expanded before a container
+	// failed are kept, with the error for the container
+	var stop *stopError
+	if err != nil && processed.Container != nil && !errors.As(err, &stop) {
+		processed.Container.Error = err.Error()
+		err = nil
+	}
+	return processed, err
+}
+
+// extract reads the content and extracts the text and
+// metadata, and the attachments if add is specified
+func (n *Native) extract(processed *Processed, content io.Reader, add addFunc) error {
+	name := processed.File.Filename
+	data, err := io.ReadAll(io.LimitReader(content, n.MaxSize+1))
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %v", name, err)
+	}
+
+	// The rest of a large file is only read for the size
+	size := int64(len(data))
+	if size > n.MaxSize {
+		rest, err := io.Copy(io.Discard, content)
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %v", name, err)
+		}
+		processed.File.Filesize = size + rest
+		return nil
+	}
+	processed.File.Filesize = size
+
+	contentType := processed.File.ContentType
+	if contentType == typeZIP {
+		contentType = detectOOXML(data)
+		processed.File.ContentType = contentType
+	}
+
+	extract, ok := extractors[contentType]
+	if !ok {
+		return nil
+	}
+
+	text, meta, err := extract(data)
+	if err != nil {
+		return fmt.Errorf("cannot extract %s from %s: %v", contentType, name, err)
+	}
+	processed.Content = text
+	processed.Meta = meta
+
+	if expand, ok := attachments[contentType]; ok && add != nil {
+		return expand(name, bytes.NewReader(data), add)
+	}
+	return nil
+}
+
+// adder returns the function that indexes the files in the
+// container, or nil if the container is nested too deep
+func (n *Native) adder(ctx context.Context, doc Document, processed *Processed, parent *Parent, limits *expansion) addFunc {
+	child := Parent{FileID: doc.ID, ID: doc.ID, Path: processed.Path.Virtual, Depth: 1}
+	if parent != nil {
+		child.FileID = parent.FileID
+		child.Depth = parent.Depth + 1
+	}
+	if child.Depth > n.MaxDepth {
+		return nil
+	}
+
+	seen := make(map[string]int)
+	return func(name string, modified time.Time, content io.Reader) error {
+		if processed.Container == nil {
+			processed.Container = &Container{}
+		}
+
+		// The expansion of the upload stops at the limits,
+		// the error is set for the containers it stopped in
+		if err := limits.add(); err != nil {
+			return err
+		}
+		processed.Container.Children++
+
+		// The files in a container can have the same path
+		name = strings.TrimLeft(name, "/")
+		path := child.Path + "/" + name
+		key := path
+		if seen[path] > 0 {
+			key = fmt.Sprintf("%s#%d", path, seen[path])
+		}
+		seen[path]++
+
+		parent := child
+		return n.index(ctx, Document{
+			ID:       childID(child.FileID, key),
+			Index:    doc.Index,
+			Name:     name,
+			Modified: modified,
+			Content:  limits.reader(content),
+		}, &parent, limits)
+	}
+}
+
+// newProcessed creates the document with the information for the file
+func newProcessed(name, contentType string, parent *Parent) *Processed {
+	virtual := "/" + strings.TrimLeft(name, "/")
+	if parent != nil {
+		virtual = parent.Path + virtual
+	}
+	filename := path.Base(name)
+	return &Processed{
+		File: File{
+			Extension:    strings.TrimPrefix(strings.ToLower(path.Ext(filename)), "."),
+			ContentType:  contentType,
+			IndexingDate: time.Now().UTC().Format(dateFormat),
+			Filename:     filename,
+		},
+		Path:   Path{Virtual: virtual, Real: virtual},
+		Parent: parent,
+	}
+}
+
+// childID returns the ID of the document for a file in a
+// container, from the uploaded file and the path in it
+func childID(fileID, path string) string {
+	sum := sha1.Sum([]byte(path))
+	return fileID + "-" + hex.EncodeToString(sum[:16])
+}
+
+// stopError is an error that stops the indexing,
+// like an error from the store or a cancelled context
+type stopError struct {
+	err error
+}
+
+func (e *stopError) Error() string { return e.err.Error() }
+func (e *stopError) Unwrap() error { return e.err }
+
+// sniffLen is the length of the content
+// that is used to detect the content-type
+const sniffLen = 512
+
+// peek returns the start of the content to detect its type,
+// and a reader for the content from the start
+func peek(content io.Reader) (io.Reader, []byte, error) {
+	if rs, ok := content.(io.ReadSeeker); ok {
+		head := make([]byte, sniffLen)
+		n, err := io.ReadFull(rs, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, nil, err
+		}
+		if _, err := rs.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+		return rs, head[:n], nil
+	}
+
+	br := bufio.NewReaderSize(content, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	return br, head, nil
+}
+
+// countingReader counts the bytes that are read
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// detect returns the content-type from the
+// file-extension, or from the content
+func detect(name string, data []byte) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return typeGzip
+	}
+	if contentType, ok := extensions[path.Ext(lower)]; ok {
+		return contentType
+	}
+
+	switch {
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		return typeTar
+	case bytes.HasPrefix(data, []byte("From ")):
+		return typeMbox
+	case bytes.HasPrefix(data, []byte("7z\xbc\xaf\x27\x1c")):
+		return type7z
+	case bytes.HasPrefix(data, []byte("!BDN")):
+		return typePST
+	}
+
+	contentType := http.DetectContentType(data)
+	switch {
+	case strings.HasPrefix(contentType, typeText), strings.HasPrefix(contentType, typeHTML):
+		return strings.SplitN(contentType, ";", 2)[0]
+	case contentType == "application/x-gzip":
+		return typeGzip
+	}
+	return contentType
+}
+
+// detectOOXML returns the content-type for
+// office-documents, that are zip-files
+func detectOOXML(data []byte) string {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return typeZIP
+	}
+	if contentType := ooxmlType(archive); contentType != "" {
+		return contentType
+	}
+	return typeZIP
+}
+
+// ooxmlType returns the content-type for an Office-document,
+// or an empty string if the archive isn't a document
+func ooxmlType(archive *zip.Reader) string {
+	for _, f := range archive.File {
+		switch f.Name {
+		case "word/document.xml":
+			return typeDOCX
+		case "xl/workbook.xml":
+			return typeXLSX
+		case "ppt/presentation.xml":
+			return typePPTX
+		}
+	}
+	return ""
+}
+
+// extractText extracts plain text
+func extractText(data []byte) (string, Meta, error) {
+	return toUTF8(data), Meta{}, nil
+}
+
+// toUTF8 converts the text to UTF-8, the text is decoded
+// as UTF-16 if it starts with a byte-order mark, and as
+// Latin-1 if it isn't valid UTF-8
+func toUTF8(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xef, 0xbb, 0xbf}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}), bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		return decodeUTF16(data[2:], data[0] == 0xfe)
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return latin1(data)
+}
+
+// decodeUTF16 decodes UTF-16 text
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// latin1 decodes Latin-1 text
+func latin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// cleanText collapses the whitespace on every
+// line, and removes repeated empty lines
+func cleanText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// formatDate formats the time for the documents,
+// or returns an empty string if the time is zero
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(dateFormat)
+}
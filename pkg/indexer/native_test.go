@@ -0,0 +1,794 @@
+package indexer_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
+
+	"github.com/matryer/is"
+)
+
+// testStore keeps the indexed documents
+type testStore map[string]interface{}
+
+func (s testStore) IndexDocument(ctx context.Context, index, id string, document interface{}) error {
+	s[index+"/"+id] = document
+	return nil
+}
+
+func TestNative(t *testing.T) {
+	is := is.New(t)
+	native := indexer.NewNative(nil)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		content     string
+		meta        indexer.Meta
+	}{
+		{
+			name:        "notes.txt",
+			data:        []byte("caf\xe9 receipt"),
+			contentType: "text/plain",
+			content:     "café receipt",
+		},
+		{
+			name: "page.html",
+			data: []byte(`<html><head><title>Meeting &amp; notes</title>
+				<meta name="author" content="Jane Doe"><meta name="keywords" content="fraud, invoice">
+				<script>var secret = 1;</script><style>p { color: red }</style></head>
+				<body><p>The meeting is at   <b>noon</b>.</p><!-- hidden --><div>Bring the&nbsp;invoice</div></body></html>`),
+			contentType: "text/html",
+			content:     "The meeting is at noon.\n\nBring the invoice",
+			meta:        indexer.Meta{Title: "Meeting & notes", Author: "Jane Doe", Keywords: []string{"fraud", "invoice"}},
+		},
+		{
+			name:        "report.pdf",
+			data:        testPDF(),
+			contentType: "application/pdf",
+			content:     "Hello World\nSecond line (part)",
+			meta: indexer.Meta{
+				Title:   "Report",
+				Author:  "Anna",
+				Created: "2021-01-15T11:00:00.000Z",
+				Raw:     map[string]string{"xmpTPg:NPages": "1"},
+			},
+		},
+		{
+			name: "letter.docx",
+			data: testZip(map[string]string{
+				"word/document.xml": `<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>Dear</w:t></w:r><w:r><w:t xml:space="preserve"> John,</w:t></w:r></w:p>` +
+					`<w:p><w:r><w:t>Transfer</w:t><w:tab/><w:t>100 EUR</w:t></w:r></w:p></w:body></w:document>`,
+				"docProps/core.xml": testCore,
+			}),
+			contentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			content:     "Dear John,\nTransfer\t100 EUR",
+			meta:        testCoreMeta,
+		},
+		{
+			name: "ledger.xlsx",
+			data: testZip(map[string]string{
+				"xl/workbook.xml":      `<workbook/>`,
+				"xl/sharedStrings.xml": `<sst><si><t>Name</t></si><si><r><t>Am</t></r><r><t>ount</t></r></si><si><t>Acme</t></si></sst>`,
+				"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row>` +
+					`<row><c t="s"><v>2</v></c><c><v>42.5</v></c><c t="inlineStr"><is><t>paid</t></is></c></row></sheetData></worksheet>`,
+			}),
+			contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			content:     "Name\tAmount\nAcme\t42.5\tpaid",
+		},
+		{
+			name: "deck.pptx",
+			data: testZip(map[string]string{
+				"ppt/presentation.xml":   `<presentation/>`,
+				"ppt/slides/slide10.xml": `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Last</a:t></a:r></a:p></p:sld>`,
+				"ppt/slides/slide2.xml":  `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>Second</a:t></a:r></a:p></p:sld>`,
+				"ppt/slides/slide1.xml":  `<p:sld xmlns:p="p" xmlns:a="a"><a:p><a:r><a:t>First</a:t></a:r></a:p></p:sld>`,
+			}),
+			contentType: "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+			content:     "First\n\nSecond\n\nLast",
+		},
+		{
+			name: "mail.eml",
+			data: []byte(strings.Replace(`From: Jane Doe <jane@example.com>
+To: john@example.com
+Subject: =?utf-8?q?Caf=C3=A9_invoice?=
+Date: Fri, 15 Jan 2021 12:00:00 +0100
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="mixed"
+
+--mixed
+Content-Type: multipart/alternative; boundary="alt"
+
+--alt
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+The invoice is attached, pay it by =
+Monday.
+--alt
+Content-Type: text/html; charset=utf-8
+
+<p>The invoice is attached</p>
+--alt--
+--mixed
+Content-Type: application/pdf
+Content-Disposition: attachment; filename="invoice.pdf"
+Content-Transfer-Encoding: base64
+
+JVBERi0xLjQK
+--mixed--
+`, "\n", "\r\n", -1)),
+			contentType: "message/rfc822",
+			content:     "The invoice is attached, pay it by Monday.",
+			meta: indexer.Meta{
+				Title:   "Café invoice",
+				Author:  "Jane Doe <jane@example.com>",
+				Date:    "2021-01-15T11:00:00.000Z",
+				Created: "2021-01-15T11:00:00.000Z",
+				Raw:     map[string]string{"to": "john@example.com"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		processed, err := native.Extract(test.name, bytes.NewReader(test.data))
+		is.NoErr(err)
+		is.Equal(processed.File.ContentType, test.contentType)
+		is.Equal(processed.File.Filename, test.name)
+		is.Equal(processed.File.Filesize, int64(len(test.data)))
+		is.Equal(processed.Content, test.content)
+		is.Equal(fmt.Sprint(processed.Meta), fmt.Sprint(test.meta))
+	}
+
+	// The type is detected from the content without an extension
+	processed, err := native.Extract("letter", bytes.NewReader(tests[3].data))
+	is.NoErr(err)
+	is.Equal(processed.Content, tests[3].content)
+
+	// Other types and large files are only indexed
+	// with the information for the file
+	processed, err = native.Extract("image.png", bytes.NewReader([]byte("\x89PNG\r\n\x1a\n")))
+	is.NoErr(err)
+	is.Equal(processed.File.ContentType, "image/png")
+	is.Equal(processed.Content, "")
+
+	native.MaxSize = 4
+	processed, err = native.Extract("notes.txt", strings.NewReader("0123456789"))
+	is.NoErr(err)
+	is.Equal(processed.File.Filesize, int64(10))
+	is.Equal(processed.Content, "")
+
+	_, err = indexer.NewNative(nil).Extract("broken.pdf", strings.NewReader("not a pdf"))
+	is.True(err != nil)
+}
+
+func TestNativeIndex(t *testing.T) {
+	is := is.New(t)
+
+	store := testStore{}
+	native := indexer.NewNative(store)
+	err := native.Index(context.Background(), indexer.Document{
+		ID:      "file-1",
+		Index:   "processes-case-1",
+		Name:    "notes.txt",
+		Content: strings.NewReader("notes"),
+	})
+	is.NoErr(err)
+
+	processed := store["processes-case-1/file-1"].(*indexer.Processed)
+	is.Equal(processed.Content, "notes")
+	is.Equal(processed.File.Extension, "txt")
+	is.Equal(processed.Path.Virtual, "/notes.txt")
+}
+
+func TestNativeContainers(t *testing.T) {
+	is := is.New(t)
+
+	mbox := strings.Replace(`From jane@example.com Fri Jan 15 12:00:00 2021
+From: jane@example.com
+Subject: First
+Content-Type: multipart/mixed; boundary="mixed"
+
+--mixed
+Content-Type: text/plain
+
+See the attached
+>From the bank
+--mixed
+Content-Type: text/plain
+Content-Disposition: attachment; filename="transfer.txt"
+
+100 EUR to Acme
+--mixed--
+
+From john@example.com Sat Jan 16 12:00:00 2021
+From: john@example.com
+Subject: Second
+
+Thanks
+`, "\n", "\r\n", -1)
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	gz.Name = "backup.tar"
+	gz.Write(testTar(map[string]string{"docs/plan.txt": "the plan"}))
+	gz.Close()
+
+	data := testZip(map[string]string{
+		"docs/notes.txt":    "notes",
+		"mail/inbox.mbox":   mbox,
+		"backup.tar.gz":     archive.String(),
+		"broken.pdf":        "not a pdf",
+		"outlook/inbox.pst": "!BDN",
+		"letter.docx":       string(testZip(map[string]string{"word/document.xml": `<w:document><w:p><w:t>Dear John</w:t></w:p></w:document>`})),
+	})
+
+	store := testStore{}
+	native := indexer.NewNative(store)
+	err := native.Index(context.Background(), indexer.Document{
+		ID:      "file-1",
+		Index:   "processes-case-1",
+		Name:    "evidence.zip",
+		Content: bytes.NewReader(data),
+	})
+	is.NoErr(err)
+
+	// The documents are found by their path
+	documents := make(map[string]*indexer.Processed)
+	ids := make(map[string]string)
+	for key, document := range store {
+		processed := document.(*indexer.Processed)
+		documents[processed.Path.Virtual] = processed
+		ids[processed.Path.Virtual] = strings.TrimPrefix(key, "processes-case-1/")
+	}
+	is.Equal(len(documents), 12)
+
+	root := documents["/evidence.zip"]
+	is.Equal(ids["/evidence.zip"], "file-1")
+	is.Equal(root.Parent, nil)
+	is.Equal(root.Container.Children, 6)
+	is.Equal(root.File.Filesize, int64(len(data)))
+
+	notes := documents["/evidence.zip/docs/notes.txt"]
+	is.Equal(notes.Content, "notes")
+	is.Equal(notes.File.Filename, "notes.txt")
+	is.Equal(*notes.Parent, indexer.Parent{FileID: "file-1", ID: "file-1", Path: "/evidence.zip", Depth: 1})
+	is.True(strings.HasPrefix(ids["/evidence.zip/docs/notes.txt"], "file-1-"))
+
+	// Office-documents in containers aren't expanded
+	letter := documents["/evidence.zip/letter.docx"]
+	is.Equal(letter.Content, "Dear John")
+	is.Equal(letter.Container, nil)
+
+	// Emails in the mbox, and their attachments
+	inbox := documents["/evidence.zip/mail/inbox.mbox"]
+	is.Equal(inbox.Container.Children, 2)
+	first := documents["/evidence.zip/mail/inbox.mbox/1.eml"]
+	is.Equal(first.Meta.Title, "First")
+	is.Equal(first.Content, "See the attached\r\nFrom the bank")
+	is.Equal(first.Container.Children, 1)
+	is.Equal(first.Parent.ID, ids["/evidence.zip/mail/inbox.mbox"])
+	transfer := documents["/evidence.zip/mail/inbox.mbox/1.eml/transfer.txt"]
+	is.Equal(transfer.Content, "100 EUR to Acme")
+	is.Equal(*transfer.Parent, indexer.Parent{
+		FileID: "file-1",
+		ID:     ids["/evidence.zip/mail/inbox.mbox/1.eml"],
+		Path:   "/evidence.zip/mail/inbox.mbox/1.eml",
+		Depth:  3,
+	})
+	is.Equal(documents["/evidence.zip/mail/inbox.mbox/2.eml"].Meta.Title, "Second")
+
+	// Compressed archives
+	plan := documents["/evidence.zip/backup.tar.gz/backup.tar/docs/plan.txt"]
+	is.Equal(plan.Content, "the plan")
+	is.Equal(plan.Parent.Depth, 3)
+
+	// The times that the archives records are kept
+	is.Equal(plan.File.LastModified, "2021-01-14T09:30:00.000Z")
+	is.Equal(notes.File.LastModified, "")
+
+	// Files that can't be extracted don't fail the container
+	is.True(documents["/evidence.zip/broken.pdf"].Error != "")
+	is.True(documents["/evidence.zip/outlook/inbox.pst"].Container.Error != "")
+
+	// Containers deeper than the max depth are only indexed with
+	// the information for the file, the archive can't seek
+	store = testStore{}
+	native = indexer.NewNative(store)
+	native.MaxDepth = 1
+	err = native.Index(context.Background(), indexer.Document{
+		ID:      "file-1",
+		Index:   "processes-case-1",
+		Name:    "evidence.zip",
+		Content: struct{ io.Reader }{bytes.NewReader(data)},
+	})
+	is.NoErr(err)
+	is.Equal(len(store), 7)
+}
+
+func TestNativeLimits(t *testing.T) {
+	is := is.New(t)
+
+	files := make(map[string]string)
+	for i := 0; i < 10; i++ {
+		files[fmt.Sprintf("%d.txt", i)] = strings.Repeat("x", 100)
+	}
+	archive := testZip(files)
+	data := testZip(map[string]string{"nested.zip": string(archive)})
+
+	index := func(native *indexer.Native, store testStore) map[string]*indexer.Processed {
+		err := native.Index(context.Background(), indexer.Document{
+			ID:      "file-1",
+			Index:   "processes-case-1",
+			Name:    "evidence.zip",
+			Content: bytes.NewReader(data),
+		})
+		is.NoErr(err)
+		documents := make(map[string]*indexer.Processed)
+		for _, document := range store {
+			processed := document.(*indexer.Processed)
+			documents[processed.Path.Virtual] = processed
+		}
+		return documents
+	}
+
+	// The expansion stops at the max number of files in the upload,
+	// the nested archive and the first four files in it are expanded
+	store := testStore{}
+	native := indexer.NewNative(store)
+	native.MaxEntries = 5
+	documents := index(native, store)
+	is.Equal(len(documents), 6)
+	nested := documents["/evidence.zip/nested.zip"]
+	is.Equal(nested.Container.Children, 4)
+	is.True(strings.Contains(nested.Container.Error, "limit of 5 files"))
+
+	// The expansion stops at the max number of bytes that are read
+	// from the files in the containers, the nested archive and
+	// two and a half of the files in it are read
+	store = testStore{}
+	native = indexer.NewNative(store)
+	native.MaxExpandedSize = int64(len(archive)) + 250
+	documents = index(native, store)
+	nested = documents["/evidence.zip/nested.zip"]
+	is.True(strings.Contains(nested.Container.Error, fmt.Sprintf("limit of %d bytes", native.MaxExpandedSize)))
+	var read int
+	for path, document := range documents {
+		if strings.HasPrefix(path, "/evidence.zip/nested.zip/") && document.Error == "" {
+			read++
+		}
+	}
+	is.Equal(read, 2)
+}
+
+func TestNativeJPEG(t *testing.T) {
+	is := is.New(t)
+	native := indexer.NewNative(nil)
+
+	data := testJPEG([]testTag{
+		{tag: 0x010f, value: "Canon"},
+		{tag: 0x0110, value: "EOS"},
+		{tag: 0x0132, value: "2021:01:16 08:00:00"},
+	}, []testTag{
+		{tag: 0x9003, value: "2021:01:15 12:00:00"},
+		{tag: 0x9011, value: "+01:00"},
+	})
+	processed, err := native.Extract("IMG_0001.JPG", bytes.NewReader(data))
+	is.NoErr(err)
+	is.Equal(processed.File.ContentType, "image/jpeg")
+	is.Equal(processed.Meta.Created, "2021-01-15T12:00:00.000+01:00")
+	is.Equal(processed.Meta.Date, "2021-01-16T08:00:00.000Z")
+	is.Equal(processed.Meta.CreatorTool, "Canon EOS")
+	is.Equal(processed.Meta.Raw["exif:DateTimeOriginal"], processed.Meta.Created)
+	is.Equal(processed.Meta.Raw["tiff:Model"], "EOS")
+
+	// Images without EXIF are indexed without the metadata
+	processed, err = native.Extract("photo.jpg", bytes.NewReader([]byte{0xff, 0xd8, 0xff, 0xda, 0x00, 0x02}))
+	is.NoErr(err)
+	is.Equal(processed.Meta.Raw, nil)
+}
+
+func TestNativePST(t *testing.T) {
+	is := is.New(t)
+
+	sent := time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC)
+	pst := &testPST{data: make([]byte, 1024)}
+	pst.node(0x122, 0x122, pst.block(testPC(testProp{id: 0x3001, kind: 0x1f, value: testUTF16("")})), 0)
+	pst.node(0x8022, 0x122, pst.block(testPC(testProp{id: 0x3001, kind: 0x1f, value: testUTF16("Inbox")})), 0)
+	pst.node(0x8042, 0x8022, pst.block(testPC(testProp{id: 0x3001, kind: 0x1f, value: testUTF16("Bank")})), 0)
+
+	// A message with an attachment, and a forwarded message
+	forwarded := pst.block(testPC(
+		testProp{id: 0x0037, kind: 0x1f, value: testUTF16("Original")},
+		testProp{id: 0x1000, kind: 0x1f, value: testUTF16("The original message")},
+	))
+	transfer := pst.block(testPC(
+		testProp{id: 0x3008, kind: 0x40, value: testFiletime(sent)},
+		testProp{id: 0x3701, kind: 0x102, value: []byte("100 EUR to Acme")},
+		testProp{id: 0x3705, kind: 0x03, value: []byte{1}},
+		testProp{id: 0x3707, kind: 0x1f, value: testUTF16("transfer.txt")},
+		testProp{id: 0x370e, kind: 0x1f, value: testUTF16("text/plain")},
+	))
+	original := pst.block(testPC(
+		testProp{id: 0x3001, kind: 0x1f, value: testUTF16("Original")},
+		testProp{id: 0x3701, kind: 0x0d, value: []byte{0x44, 0x80, 0, 0, 0, 0, 0, 0}},
+		testProp{id: 0x3705, kind: 0x03, value: []byte{5}},
+	))
+	pst.node(0x200024, 0x8022, pst.block(testPC(
+		testProp{id: 0x0037, kind: 0x1f, value: testUTF16("\x01\x04RE: Transfer")},
+		testProp{id: 0x0039, kind: 0x40, value: testFiletime(sent)},
+		testProp{id: 0x0c1a, kind: 0x1f, value: testUTF16("Jane Doe")},
+		testProp{id: 0x0e04, kind: 0x1f, value: testUTF16("John Doe")},
+		testProp{id: 0x0e07, kind: 0x03, value: []byte{1}},
+		testProp{id: 0x1000, kind: 0x1f, value: testUTF16("See the attached")},
+		testProp{id: 0x5d01, kind: 0x1f, value: testUTF16("jane@example.com")},
+	)), pst.subnodes(
+		testSubnode{nid: 0x8005, data: transfer},
+		testSubnode{nid: 0x8025, data: original, sub: pst.subnodes(testSubnode{nid: 0x8044, data: forwarded})},
+	))
+
+	// A received message, with the body in a subnode in two blocks
+	body := pst.xblock(pst.block(testUTF16("Your statement ")), pst.block(testUTF16("is ready")))
+	pst.node(0x200044, 0x8042, pst.block(testPC(
+		testProp{id: 0x007d, kind: 0x1f, value: testUTF16("From: bank@example.com\r\nSubject: Statement\r\nContent-Type: text/html\r\n\r\n")},
+		testProp{id: 0x1000, kind: 0x1f, subnode: 0x8101},
+	)), pst.subnodes(testSubnode{nid: 0x8101, data: body}))
+
+	// The hidden messages in the folders are skipped
+	pst.node(0x200064, 0x8022, pst.block(testPC(testProp{id: 0x0e07, kind: 0x03, value: []byte{0x40}})), 0)
+
+	store := testStore{}
+	native := indexer.NewNative(store)
+	err := native.Index(context.Background(), indexer.Document{
+		ID:      "file-1",
+		Index:   "processes-case-1",
+		Name:    "mailbox.pst",
+		Content: struct{ io.Reader }{bytes.NewReader(pst.bytes())},
+	})
+	is.NoErr(err)
+
+	documents := make(map[string]*indexer.Processed)
+	for _, document := range store {
+		processed := document.(*indexer.Processed)
+		documents[processed.Path.Virtual] = processed
+	}
+	is.Equal(len(documents), 5)
+	is.Equal(documents["/mailbox.pst"].Container, &indexer.Container{Children: 2})
+
+	message := documents["/mailbox.pst/Inbox/1.eml"]
+	is.Equal(message.Meta.Title, "RE: Transfer")
+	is.Equal(message.Meta.Author, `"Jane Doe" <jane@example.com>`)
+	is.Equal(message.Meta.Raw["to"], "John Doe")
+	is.Equal(message.Content, "See the attached")
+	is.Equal(message.File.LastModified, "2021-01-15T10:00:00.000Z")
+	is.Equal(message.Container.Children, 2)
+
+	attachment := documents["/mailbox.pst/Inbox/1.eml/transfer.txt"]
+	is.Equal(attachment.Content, "100 EUR to Acme")
+	is.Equal(attachment.File.LastModified, "2021-01-15T10:00:00.000Z")
+	is.Equal(documents["/mailbox.pst/Inbox/1.eml/Original.eml"].Meta.Title, "Original")
+	is.Equal(documents["/mailbox.pst/Inbox/1.eml/Original.eml"].Content, "The original message")
+
+	statement := documents["/mailbox.pst/Inbox/Bank/1.eml"]
+	is.Equal(statement.Meta.Title, "Statement")
+	is.Equal(statement.Meta.Author, "bank@example.com")
+	is.Equal(statement.Content, "Your statement is ready")
+
+	// ANSI-files can't be read
+	ansi := pst.bytes()
+	ansi[10] = 14
+	store = testStore{}
+	native = indexer.NewNative(store)
+	err = native.Index(context.Background(), indexer.Document{ID: "file-1", Index: "processes-case-1", Name: "old.pst", Content: bytes.NewReader(ansi)})
+	is.NoErr(err)
+	is.True(strings.HasPrefix(store["processes-case-1/file-1"].(*indexer.Processed).Container.Error, "ANSI PST-files"))
+}
+
+const testCore = `<cp:coreProperties xmlns:cp="cp" xmlns:dc="dc" xmlns:dcterms="dcterms">
+<dc:title>Letter</dc:title><dc:creator>Jane Doe</dc:creator><cp:keywords>bank; transfer</cp:keywords>
+<cp:lastModifiedBy>John</cp:lastModifiedBy><dcterms:created>2021-01-15T10:00:00Z</dcterms:created>
+<dcterms:modified>2021-01-16T10:00:00Z</dcterms:modified></cp:coreProperties>`
+
+var testCoreMeta = indexer.Meta{
+	Title:    "Letter",
+	Author:   "Jane Doe",
+	Keywords: []string{"bank", "transfer"},
+	Modifier: "John",
+	Created:  "2021-01-15T10:00:00.000Z",
+	Date:     "2021-01-16T10:00:00.000Z",
+}
+
+// testZip creates a zip-archive with the files
+func testZip(files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, _ := archive.Create(name)
+		w.Write([]byte(content))
+	}
+	archive.Close()
+	return buf.Bytes()
+}
+
+// testModified is when the files in the tar-archives were modified
+var testModified = time.Date(2021, 1, 14, 9, 30, 0, 0, time.UTC)
+
+// testTar creates a tar-archive with the files
+func testTar(files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	for name, content := range files {
+		archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), ModTime: testModified, Typeflag: tar.TypeReg})
+		archive.Write([]byte(content))
+	}
+	archive.Close()
+	return buf.Bytes()
+}
+
+// testTag is an ASCII-tag in the EXIF-data
+type testTag struct {
+	tag   uint16
+	value string
+}
+
+// testJPEG creates a JPEG-image with the tags in the
+// first IFD, and the EXIF-tags in the EXIF-IFD
+func testJPEG(tags, exif []testTag) []byte {
+	order := binary.BigEndian
+	exifOffset := 8 + 2 + 12*(len(tags)+1) + 4
+	dataOffset := exifOffset + 2 + 12*len(exif) + 4
+
+	var ifds, values bytes.Buffer
+	entry := func(tag, kind uint16, count, value uint32) {
+		binary.Write(&ifds, order, struct {
+			Tag, Kind    uint16
+			Count, Value uint32
+		}{tag, kind, count, value})
+	}
+	ifd := func(tags []testTag, extra int) {
+		binary.Write(&ifds, order, uint16(len(tags)+extra))
+		for _, tag := range tags {
+			value := tag.value + "\x00"
+			if len(value) <= 4 {
+				// Short values are in the entry
+				inline := make([]byte, 4)
+				copy(inline, value)
+				entry(tag.tag, 2, uint32(len(value)), order.Uint32(inline))
+				continue
+			}
+			entry(tag.tag, 2, uint32(len(value)), uint32(dataOffset+values.Len()))
+			values.WriteString(value)
+		}
+	}
+	ifd(tags, 1)
+	entry(0x8769, 4, 1, uint32(exifOffset))
+	binary.Write(&ifds, order, uint32(0))
+	ifd(exif, 0)
+	binary.Write(&ifds, order, uint32(0))
+
+	var segment bytes.Buffer
+	segment.WriteString("Exif\x00\x00MM\x00*")
+	binary.Write(&segment, order, uint32(8))
+	segment.Write(ifds.Bytes())
+	segment.Write(values.Bytes())
+
+	var jpeg bytes.Buffer
+	jpeg.Write([]byte{0xff, 0xd8, 0xff, 0xe1})
+	binary.Write(&jpeg, order, uint16(segment.Len()+2))
+	jpeg.Write(segment.Bytes())
+	jpeg.Write([]byte{0xff, 0xda, 0x00, 0x02, 0xff, 0xd9})
+	return jpeg.Bytes()
+}
+
+// testPDF creates a PDF with a compressed content-stream
+func testPDF() []byte {
+	var content bytes.Buffer
+	w := zlib.NewWriter(&content)
+	w.Write([]byte("BT /F1 12 Tf 72 712 Td (Hello) Tj 40 0 Td (World) Tj 0 -14 Td [(Sec) 20 (ond) -300 (line \\(part\\))] TJ ET"))
+	w.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n")
+	pdf.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", content.Len())
+	pdf.Write(content.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("5 0 obj\n<< /Title (Report) /Author <FEFF0041006E006E0061> /CreationDate (D:20210115120000+01'00') >>\nendobj\n")
+	pdf.WriteString("trailer\n<< /Root 1 0 R /Info 5 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+// testPST creates a Unicode PST-file, the data-blocks are
+// encoded with the permute-encoding, the B-trees are leaves
+type testPST struct {
+	data []byte
+	bbt  []byte
+	nbt  []byte
+	bid  uint64
+}
+
+// testSubnode is a subnode in an SLBLOCK
+type testSubnode struct {
+	nid       uint32
+	data, sub uint64
+}
+
+// block adds a data-block, and returns its BID
+func (p *testPST) block(data []byte) uint64 {
+	encoded := make([]byte, len(data))
+	for i, b := range data {
+		encoded[i] = testPermute[b]
+	}
+	return p.add(encoded, 0)
+}
+
+// xblock adds an XBLOCK with the data-blocks
+func (p *testPST) xblock(bids ...uint64) uint64 {
+	block := make([]byte, 8+8*len(bids))
+	block[0], block[1] = 0x01, 0x01
+	binary.LittleEndian.PutUint16(block[2:], uint16(len(bids)))
+	for i, bid := range bids {
+		binary.LittleEndian.PutUint64(block[8+8*i:], bid)
+	}
+	return p.add(block, 2)
+}
+
+// subnodes adds an SLBLOCK with the subnodes
+func (p *testPST) subnodes(subnodes ...testSubnode) uint64 {
+	block := make([]byte, 8+24*len(subnodes))
+	block[0] = 0x02
+	binary.LittleEndian.PutUint16(block[2:], uint16(len(subnodes)))
+	for i, subnode := range subnodes {
+		binary.LittleEndian.PutUint64(block[8+24*i:], uint64(subnode.nid))
+		binary.LittleEndian.PutUint64(block[16+24*i:], subnode.data)
+		binary.LittleEndian.PutUint64(block[24+24*i:], subnode.sub)
+	}
+	return p.add(block, 2)
+}
+
+// add adds the block to the BBT, internal blocks has the second bit
+func (p *testPST) add(block []byte, internal uint64) uint64 {
+	p.bid += 4
+	bid := p.bid | internal
+	entry := make([]byte, 24)
+	binary.LittleEndian.PutUint64(entry, bid)
+	binary.LittleEndian.PutUint64(entry[8:], uint64(len(p.data)))
+	binary.LittleEndian.PutUint16(entry[16:], uint16(len(block)))
+	p.bbt = append(p.bbt, entry...)
+
+	// The blocks are aligned to 64 bytes, with the trailer
+	p.data = append(p.data, block...)
+	p.data = append(p.data, make([]byte, (len(block)+16+63)/64*64-len(block))...)
+	return bid
+}
+
+// node adds a node to the NBT
+func (p *testPST) node(nid, parent uint32, data, sub uint64) {
+	entry := make([]byte, 32)
+	binary.LittleEndian.PutUint32(entry, nid)
+	binary.LittleEndian.PutUint64(entry[8:], data)
+	binary.LittleEndian.PutUint64(entry[16:], sub)
+	binary.LittleEndian.PutUint32(entry[24:], parent)
+	p.nbt = append(p.nbt, entry...)
+}
+
+// bytes returns the file, with the pages for the B-trees after the blocks
+func (p *testPST) bytes() []byte {
+	data := append([]byte{}, p.data...)
+	data = append(data, make([]byte, 512-len(data)%512)...)
+	nbt := len(data)
+	data = append(data, testPage(p.nbt, 32, 0x81)...)
+	bbt := len(data)
+	data = append(data, testPage(p.bbt, 24, 0x80)...)
+
+	copy(data, "!BDN")
+	copy(data[8:], "SM")
+	binary.LittleEndian.PutUint16(data[10:], 23)
+	binary.LittleEndian.PutUint64(data[224:], uint64(nbt))
+	binary.LittleEndian.PutUint64(data[240:], uint64(bbt))
+	data[513] = 0x01
+	return data
+}
+
+// testPage creates a leaf-page in a B-tree
+func testPage(entries []byte, size int, pageType byte) []byte {
+	page := make([]byte, 512)
+	copy(page, entries)
+	page[488], page[489], page[490] = byte(len(entries)/size), byte(488/size), byte(size)
+	page[496], page[497] = pageType, pageType
+	return page
+}
+
+// testProp is a property in a property-context, the value is in
+// the record for the integers, in a subnode if it's set, or in
+// the heap for the other types
+type testProp struct {
+	id, kind uint16
+	value    []byte
+	subnode  uint32
+}
+
+// testPC creates the heap with a property-context
+func testPC(props ...testProp) []byte {
+	items := [][]byte{{0xb5, 2, 6, 0, 0x40, 0, 0, 0}, nil}
+	for _, prop := range props {
+		record := make([]byte, 8)
+		binary.LittleEndian.PutUint16(record, prop.id)
+		binary.LittleEndian.PutUint16(record[2:], prop.kind)
+		switch {
+		case prop.subnode != 0:
+			binary.LittleEndian.PutUint32(record[4:], prop.subnode)
+		case prop.kind == 0x03:
+			copy(record[4:], prop.value)
+		default:
+			items = append(items, prop.value)
+			binary.LittleEndian.PutUint32(record[4:], uint32(len(items))<<5)
+		}
+		items[1] = append(items[1], record...)
+	}
+
+	heap := []byte{0, 0, 0xec, 0xbc, 0x20, 0, 0, 0, 0, 0, 0, 0}
+	offsets := []uint16{uint16(len(heap))}
+	for _, item := range items {
+		heap = append(heap, item...)
+		offsets = append(offsets, uint16(len(heap)))
+	}
+	binary.LittleEndian.PutUint16(heap, uint16(len(heap)))
+	heap = binary.LittleEndian.AppendUint16(heap, uint16(len(items)))
+	heap = binary.LittleEndian.AppendUint16(heap, 0)
+	for _, offset := range offsets {
+		heap = binary.LittleEndian.AppendUint16(heap, offset)
+	}
+	return heap
+}
+
+// testUTF16 encodes the text as UTF-16, like the strings in a PST-file
+func testUTF16(text string) []byte {
+	var data []byte
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return data
+}
+
+// testFiletime returns the time as a FILETIME
+func testFiletime(t time.Time) []byte {
+	return binary.LittleEndian.AppendUint64(nil, uint64(t.UnixNano()/100+116444736000000000))
+}
+
+// testPermute is the table for the permute-encoding, the
+// first part of mpbbCrypt in [MS-PST], it's decoded
+// with the inverse table in the indexer
+var testPermute = [256]byte{
+	65, 54, 19, 98, 168, 33, 110, 187, 244, 22, 204, 4, 127, 100, 232, 93,
+	30, 242, 203, 42, 116, 197, 94, 53, 210, 149, 71, 158, 150, 45, 154, 136,
+	76, 125, 132, 63, 219, 172, 49, 182, 72, 95, 246, 196, 216, 57, 139, 231,
+	35, 59, 56, 142, 200, 193, 223, 37, 177, 32, 165, 70, 96, 78, 156, 251,
+	170, 211, 86, 81, 69, 124, 85, 0, 7, 201, 43, 157, 133, 155, 9, 160,
+	143, 173, 179, 15, 99, 171, 137, 75, 215, 167, 21, 90, 113, 102, 66, 191,
+	38, 74, 107, 152, 250, 234, 119, 83, 178, 112, 5, 44, 253, 89, 58, 134,
+	126, 206, 6, 235, 130, 120, 87, 199, 141, 67, 175, 180, 28, 212, 91, 205,
+	226, 233, 39, 79, 195, 8, 114, 128, 207, 176, 239, 245, 40, 109, 190, 48,
+	77, 52, 146, 213, 14, 60, 34, 50, 229, 228, 249, 159, 194, 209, 10, 129,
+	18, 225, 238, 145, 131, 118, 227, 151, 230, 97, 138, 23, 121, 164, 183, 220,
+	144, 122, 92, 140, 2, 166, 202, 105, 222, 80, 26, 17, 147, 185, 82, 135,
+	88, 252, 237, 29, 55, 73, 27, 106, 224, 41, 51, 153, 189, 108, 217, 148,
+	243, 64, 84, 111, 240, 198, 115, 184, 214, 62, 101, 24, 68, 31, 221, 103,
+	16, 241, 12, 25, 236, 174, 3, 161, 20, 123, 169, 11, 255, 248, 163, 192,
+	162, 1, 247, 46, 188, 36, 104, 117, 13, 254, 186, 47, 181, 208, 218, 61,
+}
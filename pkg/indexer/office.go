@@ -0,0 +1,342 @@
+package indexer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// coreProperties are the metadata in docProps/core.xml
+// in DOCX-, XLSX- and PPTX-files
+type coreProperties struct {
+	Title          string `xml:"title"`
+	Subject        string `xml:"subject"`
+	Creator        string `xml:"creator"`
+	Keywords       string `xml:"keywords"`
+	LastModifiedBy string `xml:"lastModifiedBy"`
+	Created        string `xml:"created"`
+	Modified       string `xml:"modified"`
+}
+
+// appProperties are the metadata in docProps/app.xml
+type appProperties struct {
+	Application string `xml:"Application"`
+}
+
+// extractDOCX extracts the text from a Word-document
+func extractDOCX(data []byte) (string, Meta, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", Meta{}, err
+	}
+
+	var text strings.Builder
+	for _, name := range []string{"word/document.xml", "word/footnotes.xml", "word/endnotes.xml"} {
+		r, err := openZipFile(archive, name)
+		if err == errNotInArchive && name != "word/document.xml" {
+			continue
+		} else if err != nil {
+			return "", Meta{}, err
+		}
+		err = ooxmlText(r, &text)
+		r.Close()
+		if err != nil {
+			return "", Meta{}, err
+		}
+	}
+
+	return cleanOOXML(text.String()), ooxmlMeta(archive), nil
+}
+
+// extractPPTX extracts the text from the slides in a presentation
+func extractPPTX(data []byte) (string, Meta, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", Meta{}, err
+	}
+
+	var slides []string
+	for _, f := range archive.File {
+		if path.Dir(f.Name) == "ppt/slides" && strings.HasSuffix(f.Name, ".xml") {
+			slides = append(slides, f.Name)
+		}
+	}
+
+	var text strings.Builder
+	for _, name := range sortedNames(slides) {
+		r, err := openZipFile(archive, name)
+		if err != nil {
+			return "", Meta{}, err
+		}
+		err = ooxmlText(r, &text)
+		r.Close()
+		if err != nil {
+			return "", Meta{}, err
+		}
+		text.WriteString("\n")
+	}
+
+	return cleanOOXML(text.String()), ooxmlMeta(archive), nil
+}
+
+// extractXLSX extracts the cells from the sheets in a workbook,
+// every row is a line with the cells separated by tabs
+func extractXLSX(data []byte) (string, Meta, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", Meta{}, err
+	}
+
+	// The strings are shared between the sheets
+	var shared []string
+	if r, err := openZipFile(archive, "xl/sharedStrings.xml"); err == nil {
+		shared, err = xlsxSharedStrings(r)
+		r.Close()
+		if err != nil {
+			return "", Meta{}, err
+		}
+	}
+
+	var sheets []string
+	for _, f := range archive.File {
+		if path.Dir(f.Name) == "xl/worksheets" && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+
+	var text strings.Builder
+	for _, name := range sortedNames(sheets) {
+		r, err := openZipFile(archive, name)
+		if err != nil {
+			return "", Meta{}, err
+		}
+		err = xlsxSheet(r, shared, &text)
+		r.Close()
+		if err != nil {
+			return "", Meta{}, err
+		}
+		text.WriteString("\n")
+	}
+
+	return cleanOOXML(text.String()), ooxmlMeta(archive), nil
+}
+
+// ooxmlText writes the text in the text-elements (w:t and a:t),
+// with new lines for the paragraphs and breaks
+func ooxmlText(r io.Reader, text *strings.Builder) error {
+	decoder := xml.NewDecoder(r)
+	var inText bool
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+// xlsxSharedStrings reads the shared strings in a workbook
+func xlsxSharedStrings(r io.Reader) ([]string, error) {
+	decoder := xml.NewDecoder(r)
+	var shared []string
+	var current strings.Builder
+	var inText, inPhonetic bool
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return shared, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				inPhonetic = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				shared = append(shared, current.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhonetic = false
+			}
+		case xml.CharData:
+			if inText && !inPhonetic {
+				current.Write(t)
+			}
+		}
+	}
+}
+
+// xlsxSheet writes the values for the cells in a sheet
+func xlsxSheet(r io.Reader, shared []string, text *strings.Builder) error {
+	decoder := xml.NewDecoder(r)
+	var cellType string
+	var value strings.Builder
+	var inValue bool
+	var cells []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				cells = nil
+			case "c":
+				cellType = ""
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "t" {
+						cellType = attr.Value
+					}
+				}
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				cell := value.String()
+				if cellType == "s" {
+					if i, err := strconv.Atoi(cell); err == nil && i >= 0 && i < len(shared) {
+						cell = shared[i]
+					}
+				}
+				cells = append(cells, cell)
+			case "row":
+				text.WriteString(strings.Join(cells, "\t"))
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+}
+
+// ooxmlMeta reads the metadata from the document-properties
+func ooxmlMeta(archive *zip.Reader) Meta {
+	var meta Meta
+	if r, err := openZipFile(archive, "docProps/core.xml"); err == nil {
+		var core coreProperties
+		if err := xml.NewDecoder(r).Decode(&core); err == nil {
+			meta.Title = strings.TrimSpace(core.Title)
+			meta.Author = strings.TrimSpace(core.Creator)
+			meta.Keywords = splitKeywords(core.Keywords)
+			meta.Modifier = strings.TrimSpace(core.LastModifiedBy)
+			meta.Created = formatDate(parseW3CDate(core.Created))
+			meta.Date = formatDate(parseW3CDate(core.Modified))
+			if subject := strings.TrimSpace(core.Subject); subject != "" {
+				meta.Raw = map[string]string{"subject": subject}
+			}
+		}
+		r.Close()
+	}
+	if r, err := openZipFile(archive, "docProps/app.xml"); err == nil {
+		var app appProperties
+		if err := xml.NewDecoder(r).Decode(&app); err == nil {
+			meta.CreatorTool = strings.TrimSpace(app.Application)
+		}
+		r.Close()
+	}
+	return meta
+}
+
+// errNotInArchive is returned when a file isn't in the archive
+var errNotInArchive = errors.New("file is not in the archive")
+
+// openZipFile opens the file in the archive
+func openZipFile(archive *zip.Reader, name string) (io.ReadCloser, error) {
+	for _, f := range archive.File {
+		if f.Name == name {
+			return f.Open()
+		}
+	}
+	return nil, errNotInArchive
+}
+
+// parseW3CDate parses the dates in the document-properties
+func parseW3CDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// cleanOOXML removes the white-space at the end of the lines, and
+// repeated empty lines, the tabs between the cells are kept
+func cleanOOXML(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// sortedNames sorts the names of the files in the archive
+// by the number in the name, so slide10 is after slide9
+func sortedNames(names []string) []string {
+	number := func(name string) int {
+		digits := strings.TrimFunc(strings.TrimSuffix(path.Base(name), ".xml"), func(r rune) bool { return !unicode.IsDigit(r) })
+		n, _ := strconv.Atoi(digits)
+		return n
+	}
+	sort.Slice(names, func(i, j int) bool { return number(names[i]) < number(names[j]) })
+	return names
+}
@@ -0,0 +1,527 @@
+package indexer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	pdfObjectRegex = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfLengthRegex = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfFilterRegex = regexp.MustCompile(`/Filter\s*(\[[^\]]*\]|/[A-Za-z0-9]+)`)
+	pdfNameRegex   = regexp.MustCompile(`/([A-Za-z0-9]+)`)
+	pdfInfoRegex   = regexp.MustCompile(`/Info\s+(\d+)\s+\d+\s+R`)
+	pdfRefRegex    = regexp.MustCompile(`^(\d+)\s+\d+\s+R`)
+	pdfIntRegex    = regexp.MustCompile(`/(N|First)\s+(\d+)`)
+	pdfPageRegex   = regexp.MustCompile(`/Type\s*/Page\b`)
+)
+
+// pdfObject is an object in a PDF-file
+type pdfObject struct {
+	number int
+	dict   []byte
+	stream []byte
+}
+
+// extractPDF extracts the text layer from a PDF-file, the text is
+// extracted from the content-streams in the order of the objects
+//
+// NOTE : Text in fonts without a standard encoding (like
+// fonts with CID-encodings) can't be extracted, and scanned
+// documents without a text layer have to be OCRed
+func extractPDF(data []byte) (string, Meta, error) {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if !bytes.Contains(head, []byte("%PDF-")) {
+		return "", Meta{}, errors.New("not a PDF-file")
+	}
+
+	objects := pdfObjects(data)
+	byNumber := make(map[int]pdfObject)
+	var text strings.Builder
+	var pages int
+	for _, object := range objects {
+		byNumber[object.number] = object
+		if pdfPageRegex.Match(object.dict) {
+			pages++
+		}
+		if object.stream == nil || !pdfContentStream(object.dict) {
+			continue
+		}
+		if decoded, ok := pdfDecode(object.dict, object.stream); ok {
+			text.WriteString(pdfText(decoded))
+			text.WriteString("\n")
+		}
+	}
+
+	var meta Meta
+	if match := pdfInfoRegex.FindAllSubmatch(data, -1); len(match) > 0 {
+		number, _ := strconv.Atoi(string(match[len(match)-1][1]))
+		if info, ok := byNumber[number]; ok {
+			meta = pdfInfo(info.dict, byNumber)
+		}
+	}
+	if pages > 0 {
+		if meta.Raw == nil {
+			meta.Raw = make(map[string]string)
+		}
+		meta.Raw["xmpTPg:NPages"] = strconv.Itoa(pages)
+	}
+
+	return cleanText(text.String()), meta, nil
+}
+
+// pdfObjects returns the objects in the PDF-file,
+// including the objects in object-streams
+func pdfObjects(data []byte) []pdfObject {
+	var objects []pdfObject
+	for pos := 0; pos < len(data); {
+		match := pdfObjectRegex.FindSubmatchIndex(data[pos:])
+		if match == nil {
+			break
+		}
+		number, _ := strconv.Atoi(string(data[pos+match[2] : pos+match[3]]))
+		start := pos + match[1]
+		end := bytes.Index(data[start:], []byte("endobj"))
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += start
+		}
+		object := pdfObject{number: number, dict: data[start:end]}
+		pos = end
+
+		// The stream is read from the length if it's
+		// a direct value, since it can contain "endobj"
+		if streamStart := bytes.Index(object.dict, []byte("stream")); streamStart >= 0 {
+			dict := object.dict[:streamStart]
+			content := start + streamStart + len("stream")
+			if content < len(data) && data[content] == '\r' {
+				content++
+			}
+			if content < len(data) && data[content] == '\n' {
+				content++
+			}
+
+			streamEnd := -1
+			if length := pdfLengthRegex.FindSubmatch(dict); length != nil && len(length[2]) == 0 {
+				if n, err := strconv.Atoi(string(length[1])); err == nil && content+n <= len(data) {
+					streamEnd = content + n
+				}
+			}
+			if streamEnd < 0 {
+				if i := bytes.Index(data[content:], []byte("endstream")); i >= 0 {
+					streamEnd = content + i
+				} else {
+					streamEnd = len(data)
+				}
+			}
+
+			object.dict = dict
+			object.stream = data[content:streamEnd]
+			if i := bytes.Index(data[streamEnd:], []byte("endobj")); i >= 0 {
+				pos = streamEnd + i
+			} else {
+				pos = len(data)
+			}
+		}
+
+		objects = append(objects, object)
+		if pdfHasName(object.dict, "ObjStm") {
+			objects = append(objects, pdfObjectStream(object)...)
+		}
+	}
+	return objects
+}
+
+// pdfObjectStream returns the objects in an object-stream
+func pdfObjectStream(object pdfObject) []pdfObject {
+	decoded, ok := pdfDecode(object.dict, object.stream)
+	if !ok {
+		return nil
+	}
+
+	var n, first int
+	for _, match := range pdfIntRegex.FindAllSubmatch(object.dict, -1) {
+		value, _ := strconv.Atoi(string(match[2]))
+		if string(match[1]) == "N" {
+			n = value
+		} else {
+			first = value
+		}
+	}
+	if first > len(decoded) {
+		return nil
+	}
+
+	header := strings.Fields(string(decoded[:first]))
+	var objects []pdfObject
+	for i := 0; i < n && 2*i+1 < len(header); i++ {
+		number, _ := strconv.Atoi(header[2*i])
+		start, _ := strconv.Atoi(header[2*i+1])
+		end := len(decoded) - first
+		if 2*i+3 < len(header) {
+			end, _ = strconv.Atoi(header[2*i+3])
+		}
+		if start < 0 || start > end || first+end > len(decoded) {
+			break
+		}
+		objects = append(objects, pdfObject{number: number, dict: decoded[first+start : first+end]})
+	}
+	return objects
+}
+
+// pdfContentStream returns false for the streams
+// that are known not to be content-streams
+func pdfContentStream(dict []byte) bool {
+	for _, name := range []string{"Image", "XRef", "ObjStm", "Metadata", "FontFile", "FontFile2", "FontFile3", "Length1", "EmbeddedFile", "ICCBased"} {
+		if pdfHasName(dict, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// pdfHasName returns true if the dictionary has the name
+func pdfHasName(dict []byte, name string) bool {
+	for _, match := range pdfNameRegex.FindAllSubmatch(dict, -1) {
+		if string(match[1]) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pdfDecode decodes the stream with the filters in the
+// dictionary, ok is false if a filter isn't supported
+func pdfDecode(dict, stream []byte) ([]byte, bool) {
+	match := pdfFilterRegex.FindSubmatch(dict)
+	if match == nil {
+		return stream, true
+	}
+
+	var err error
+	for _, filter := range pdfNameRegex.FindAllSubmatch(match[1], -1) {
+		switch string(filter[1]) {
+		case "FlateDecode", "Fl":
+			var r io.ReadCloser
+			if r, err = zlib.NewReader(bytes.NewReader(stream)); err != nil {
+				return nil, false
+			}
+			// Truncated streams are still decoded
+			stream, _ = io.ReadAll(r)
+			r.Close()
+		case "ASCIIHexDecode", "AHx":
+			hexData := bytes.Map(func(r rune) rune {
+				if unicode.IsSpace(r) || r == '>' {
+					return -1
+				}
+				return r
+			}, stream)
+			if len(hexData)%2 == 1 {
+				hexData = append(hexData, '0')
+			}
+			if stream, err = hex.DecodeString(string(hexData)); err != nil {
+				return nil, false
+			}
+		case "ASCII85Decode", "A85":
+			encoded := bytes.TrimSuffix(bytes.TrimSpace(stream), []byte("~>"))
+			decoded := make([]byte, 4*len(encoded))
+			n, _, err := ascii85.Decode(decoded, encoded, true)
+			if err != nil {
+				return nil, false
+			}
+			stream = decoded[:n]
+		default:
+			return nil, false
+		}
+	}
+	return stream, true
+}
+
+// pdfText extracts the text from a content-stream
+func pdfText(stream []byte) string {
+	var text strings.Builder
+	var strs []string
+	var nums []float64
+	var inArray bool
+	var lastY float64
+
+	for i := 0; i < len(stream); {
+		c := stream[i]
+		switch {
+		case c == '(':
+			s, n := pdfLiteral(stream[i:])
+			strs = append(strs, pdfDecodeText(s))
+			i += n
+		case c == '<' && i+1 < len(stream) && stream[i+1] == '<', c == '>' && i+1 < len(stream) && stream[i+1] == '>':
+			i += 2
+		case c == '<':
+			end := bytes.IndexByte(stream[i:], '>')
+			if end < 0 {
+				end = len(stream) - i
+			}
+			s, _ := pdfHex(stream[i+1 : i+end])
+			strs = append(strs, pdfDecodeText(s))
+			i += end + 1
+		case c == '[':
+			inArray = true
+			i++
+		case c == ']':
+			inArray = false
+			i++
+		case c == '%':
+			for i < len(stream) && stream[i] != '\n' && stream[i] != '\r' {
+				i++
+			}
+		case pdfWhitespace(c) || c == '{' || c == '}' || c == ')' || c == '>':
+			i++
+		default:
+			start := i
+			for i++; i < len(stream) && !pdfWhitespace(stream[i]) && !pdfDelimiter(stream[i]); i++ {
+			}
+			token := string(stream[start:i])
+			if num, err := strconv.ParseFloat(token, 64); err == nil {
+				// Large adjustments in a TJ-array are spaces
+				if inArray && num < -200 {
+					strs = append(strs, " ")
+				}
+				nums = append(nums, num)
+				continue
+			}
+
+			switch token {
+			case "Tj", "TJ":
+				text.WriteString(strings.Join(strs, ""))
+			case "'", `"`:
+				text.WriteString("\n")
+				text.WriteString(strings.Join(strs, ""))
+			case "T*":
+				text.WriteString("\n")
+			case "Td", "TD":
+				if len(nums) >= 2 && nums[len(nums)-1] != 0 {
+					text.WriteString("\n")
+				} else {
+					text.WriteString(" ")
+				}
+			case "Tm":
+				if len(nums) >= 6 {
+					if y := nums[len(nums)-1]; y != lastY {
+						text.WriteString("\n")
+						lastY = y
+					} else {
+						text.WriteString(" ")
+					}
+				}
+			case "ID":
+				// Skip the data for inline images
+				end := bytes.Index(stream[i:], []byte("EI"))
+				if end < 0 {
+					end = len(stream) - i
+				}
+				i += end + 2
+			}
+			if !inArray {
+				strs, nums = nil, nil
+			}
+		}
+	}
+	return text.String()
+}
+
+// pdfLiteral reads a literal string, it
+// returns the string and the bytes read
+func pdfLiteral(data []byte) ([]byte, int) {
+	var s []byte
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				s = append(s, c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s, i + 1
+			}
+			s = append(s, c)
+		case '\\':
+			i++
+			if i >= len(data) {
+				return s, i
+			}
+			switch e := data[i]; e {
+			case 'n':
+				s = append(s, '\n')
+			case 'r':
+				s = append(s, '\r')
+			case 't':
+				s = append(s, '\t')
+			case 'b':
+				s = append(s, '\b')
+			case 'f':
+				s = append(s, '\f')
+			case '\r':
+				if i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					octal := 0
+					for j := 0; j < 3 && i < len(data) && data[i] >= '0' && data[i] <= '7'; j++ {
+						octal = octal*8 + int(data[i]-'0')
+						i++
+					}
+					i--
+					s = append(s, byte(octal))
+				} else {
+					s = append(s, e)
+				}
+			}
+		default:
+			s = append(s, c)
+		}
+	}
+	return s, len(data)
+}
+
+// pdfHex decodes a hex-string
+func pdfHex(data []byte) ([]byte, error) {
+	digits := bytes.Map(func(r rune) rune {
+		if pdfWhitespace(byte(r)) {
+			return -1
+		}
+		return r
+	}, data)
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	return hex.DecodeString(string(digits))
+}
+
+// pdfDecodeText decodes a string as UTF-16 if it has a byte-order mark,
+// or as PDFDocEncoding (which is close enough to Latin-1), control-
+// characters are removed since they are glyph-IDs in other encodings
+func pdfDecodeText(s []byte) string {
+	var text string
+	if bytes.HasPrefix(s, []byte{0xfe, 0xff}) {
+		text = decodeUTF16(s[2:], true)
+	} else {
+		text = latin1(s)
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// pdfInfo reads the metadata from the info-dictionary
+func pdfInfo(dict []byte, objects map[int]pdfObject) Meta {
+	value := func(key string) string {
+		i := bytes.Index(dict, []byte("/"+key))
+		if i < 0 {
+			return ""
+		}
+		rest := bytes.TrimLeft(dict[i+len(key)+1:], " \t\r\n")
+
+		// The value can be a reference to another object
+		if ref := pdfRefRegex.FindSubmatch(rest); ref != nil {
+			number, _ := strconv.Atoi(string(ref[1]))
+			rest = bytes.TrimSpace(objects[number].dict)
+		}
+		switch {
+		case bytes.HasPrefix(rest, []byte("<<")):
+			return ""
+		case bytes.HasPrefix(rest, []byte("(")):
+			s, _ := pdfLiteral(rest)
+			return strings.TrimSpace(pdfDecodeText(s))
+		case bytes.HasPrefix(rest, []byte("<")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return ""
+			}
+			s, _ := pdfHex(rest[1:end])
+			return strings.TrimSpace(pdfDecodeText(s))
+		}
+		return ""
+	}
+
+	meta := Meta{
+		Title:       value("Title"),
+		Author:      value("Author"),
+		Keywords:    splitKeywords(value("Keywords")),
+		CreatorTool: value("Creator"),
+		Created:     formatDate(pdfDate(value("CreationDate"))),
+		Date:        formatDate(pdfDate(value("ModDate"))),
+	}
+
+	raw := make(map[string]string)
+	for _, key := range []string{"Subject", "Producer"} {
+		if v := value(key); v != "" {
+			raw[strings.ToLower(key)] = v
+		}
+	}
+	if len(raw) > 0 {
+		meta.Raw = raw
+	}
+	return meta
+}
+
+// pdfDate parses a date in a PDF, "D:YYYYMMDDHHmmSSOHH'mm'",
+// every part after the year is optional
+func pdfDate(s string) time.Time {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+	var digits int
+	for digits < len(s) && digits < 14 && s[digits] >= '0' && s[digits] <= '9' {
+		digits++
+	}
+	if digits < 4 {
+		return time.Time{}
+	}
+
+	// Pad the missing parts with the first month and day
+	date := s[:digits] + "0101000000"[digits-4:]
+	t, err := time.Parse("20060102150405", date)
+	if err != nil {
+		return time.Time{}
+	}
+
+	zone := strings.Replace(s[digits:], "'", "", -1)
+	if len(zone) >= 5 && (zone[0] == '+' || zone[0] == '-') {
+		hours, _ := strconv.Atoi(zone[1:3])
+		minutes, _ := strconv.Atoi(zone[3:5])
+		offset := hours*3600 + minutes*60
+		if zone[0] == '-' {
+			offset = -offset
+		}
+		t = t.Add(-time.Duration(offset) * time.Second)
+	}
+	return t
+}
+
+// pdfWhitespace returns true for white-space characters
+func pdfWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+// pdfDelimiter returns true for delimiter characters
+func pdfDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -0,0 +1,1007 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The PST-files are read as described in [MS-PST], the NDB-layer
+// (the B-trees for the nodes and the blocks) and the LTP-layer
+// (the heaps with the properties), only Unicode-files are read
+
+// The offsets in the header of a Unicode PST-file
+const (
+	pstHeaderSize  = 514
+	pstVersion     = 10
+	pstNBTRoot     = 224
+	pstBBTRoot     = 240
+	pstCryptMethod = 513
+)
+
+// The versions of the PST-files
+const (
+	pstVersionANSI    = 14
+	pstVersionUnicode = 23
+	pstVersion4K      = 36
+)
+
+// The encodings for the data-blocks
+const (
+	pstCryptNone    = 0x00
+	pstCryptPermute = 0x01
+	pstCryptCyclic  = 0x02
+)
+
+// The pages in the B-trees, the entries are
+// before cEnt, and the type is in the trailer
+const (
+	pstPageSize  = 512
+	pstPageCount = 488
+	pstPageEntry = 490
+	pstPageLevel = 491
+	pstPageType  = 496
+	pstTypeBBT   = 0x80
+	pstTypeNBT   = 0x81
+)
+
+// The node-types, in the lowest bits of the NIDs
+const (
+	pstNodeMessage    = 0x04
+	pstNodeAttachment = 0x05
+	pstRootFolder     = 0x122
+)
+
+// The properties that are read for the
+// folders, messages and attachments
+const (
+	propSubject        = 0x0037
+	propSubmitTime     = 0x0039
+	propSentName       = 0x0042
+	propHeaders        = 0x007d
+	propDisplayBcc     = 0x0e02
+	propDisplayCc      = 0x0e03
+	propDisplayTo      = 0x0e04
+	propDeliveryTime   = 0x0e06
+	propMessageFlags   = 0x0e07
+	propSenderName     = 0x0c1a
+	propSenderEmail    = 0x0c1f
+	propBody           = 0x1000
+	propHTML           = 0x1013
+	propMessageID      = 0x1035
+	propDisplayName    = 0x3001
+	propModified       = 0x3008
+	propAttachData     = 0x3701
+	propAttachFilename = 0x3704
+	propAttachMethod   = 0x3705
+	propAttachLongName = 0x3707
+	propAttachMime     = 0x370e
+	propSenderSMTP     = 0x5d01
+)
+
+// The types of the property-values
+const (
+	propTypeInt16   = 0x0002
+	propTypeInt32   = 0x0003
+	propTypeBoolean = 0x000b
+	propTypeObject  = 0x000d
+	propTypeString8 = 0x001e
+	propTypeString  = 0x001f
+	propTypeTime    = 0x0040
+	propTypeBinary  = 0x0102
+)
+
+// The attachments that are messages, and the
+// flag for the hidden messages in the folders
+const (
+	attachEmbedded = 5
+	flagAssociated = 0x40
+)
+
+// pstMaxDepth limits the folders in a path, and the
+// messages in messages, for files that loops
+const pstMaxDepth = 64
+
+// openPST reads the header of a PST-file, ANSI-files
+// and OST-files with 4K-pages aren't supported
+func openPST(r io.ReaderAt) (*pstFile, error) {
+	header := make([]byte, pstHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("invalid PST-header: %v", err)
+	}
+	if string(header[:4]) != "!BDN" {
+		return nil, errors.New("invalid PST-header")
+	}
+
+	switch version := binary.LittleEndian.Uint16(header[pstVersion:]); {
+	case version == pstVersionANSI || version == pstVersionANSI+1:
+		return nil, errors.New("ANSI PST-files (from Outlook 2002 and earlier) are not supported, export the mailbox as mbox and upload it")
+	case version >= pstVersion4K:
+		return nil, errors.New("OST-files with 4K-pages are not supported, export the mailbox as mbox and upload it")
+	case version < pstVersionUnicode:
+		return nil, fmt.Errorf("unknown PST-version %d", version)
+	}
+
+	crypt := header[pstCryptMethod]
+	if crypt > pstCryptCyclic {
+		return nil, fmt.Errorf("unknown PST-encryption %d", crypt)
+	}
+	return &pstFile{
+		r:     r,
+		nbt:   binary.LittleEndian.Uint64(header[pstNBTRoot:]),
+		bbt:   binary.LittleEndian.Uint64(header[pstBBTRoot:]),
+		crypt: crypt,
+	}, nil
+}
+
+// pstFile reads the nodes in a PST-file
+type pstFile struct {
+	r     io.ReaderAt
+	nbt   uint64
+	bbt   uint64
+	crypt byte
+}
+
+// pstNode has the blocks for the data and
+// the subnodes of a node, or a subnode
+type pstNode struct {
+	data uint64
+	sub  uint64
+}
+
+// pstEntry is a node in the NBT, with its parent-folder
+type pstEntry struct {
+	node   pstNode
+	parent uint32
+}
+
+// expandPST adds the messages in a PST- or OST-file as EML-files,
+// in the paths of their folders, with the attachments in them
+func (n *Native) expandPST(processed *Processed, content io.Reader, add addFunc) error {
+	r, size, cleanup, err := n.readerAt(content)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %v", processed.File.Filename, err)
+	}
+	defer cleanup()
+
+	processed.File.Filesize = size
+	processed.Container = &Container{}
+	file, err := openPST(r)
+	if err != nil {
+		return err
+	}
+	return file.expand(add)
+}
+
+// expand adds the messages in the order of their NIDs, the hidden
+// messages (like the views and rules) in the folders are skipped.
+// Messages that can't be read are added with the error
+func (p *pstFile) expand(add addFunc) error {
+	entries := make(map[uint32]pstEntry)
+	var messages []uint32
+	err := p.nodes(p.nbt, -1, make(map[uint64]bool), func(nid uint32, entry pstEntry) {
+		entries[nid] = entry
+		if nid&0x1f == pstNodeMessage {
+			messages = append(messages, nid)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	paths := make(map[uint32]string)
+	counts := make(map[uint32]int)
+	for _, nid := range messages {
+		entry := entries[nid]
+		msg, err := p.properties(entry.node)
+		if err == nil && msg.uint32(propMessageFlags)&flagAssociated != 0 {
+			continue
+		}
+
+		counts[entry.parent]++
+		name := path.Join(p.folder(entry.parent, entries, paths), fmt.Sprintf("%d.eml", counts[entry.parent]))
+		if err != nil {
+			err = add(name, time.Time{}, errReader{fmt.Errorf("cannot read the message: %v", err)})
+		} else {
+			err = addMessage(name, msg, add)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addMessage adds the message as an EML-file,
+// the EML is written while the file is indexed
+func addMessage(name string, msg *pstProperties, add addFunc) error {
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.CloseWithError(writeMessage(w, msg, 0))
+	}()
+
+	err := add(name, messageDate(msg), r)
+	r.Close()
+	<-done
+	return err
+}
+
+// folder returns the path of a folder from the folders under
+// the root, the paths are kept for the next messages in them
+func (p *pstFile) folder(nid uint32, entries map[uint32]pstEntry, paths map[uint32]string) string {
+	var prefix string
+	var chain []uint32
+	for nid != pstRootFolder && len(chain) < pstMaxDepth {
+		if known, ok := paths[nid]; ok {
+			prefix = known
+			break
+		}
+		entry, ok := entries[nid]
+		if !ok {
+			break
+		}
+		chain = append(chain, nid)
+		if entry.parent == nid {
+			break
+		}
+		nid = entry.parent
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		prefix = path.Join(prefix, p.folderName(chain[i], entries[chain[i]].node))
+		paths[chain[i]] = prefix
+	}
+	return prefix
+}
+
+// folderName returns the display-name of the folder,
+// or a name from the NID if it can't be used in a path
+func (p *pstFile) folderName(nid uint32, node pstNode) string {
+	var name string
+	if props, err := p.properties(node); err == nil {
+		name = strings.TrimSpace(strings.Replace(props.text(propDisplayName), "/", "_", -1))
+	}
+	if name == "" || name == "." || name == ".." {
+		name = fmt.Sprintf("folder-%x", nid)
+	}
+	return name
+}
+
+// nodes calls fn for the entries in the leaf-pages of the NBT,
+// the pages that are already read are invalid, so a file that
+// loops can't be read forever
+func (p *pstFile) nodes(ib uint64, level int, read map[uint64]bool, fn func(nid uint32, entry pstEntry)) error {
+	if read[ib] {
+		return fmt.Errorf("invalid NBT-page at %d", ib)
+	}
+	read[ib] = true
+
+	entries, size, pageLevel, err := p.page(ib, pstTypeNBT)
+	if err != nil {
+		return err
+	}
+	if level >= 0 && pageLevel != level-1 {
+		return fmt.Errorf("invalid NBT-level at %d", ib)
+	}
+
+	for i := 0; i+size <= len(entries); i += size {
+		entry := entries[i:]
+		if pageLevel > 0 {
+			if err := p.nodes(binary.LittleEndian.Uint64(entry[16:]), pageLevel, read, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if size < 32 {
+			return fmt.Errorf("invalid NBT-entries at %d", ib)
+		}
+		fn(binary.LittleEndian.Uint32(entry), pstEntry{
+			node: pstNode{
+				data: binary.LittleEndian.Uint64(entry[8:]),
+				sub:  binary.LittleEndian.Uint64(entry[16:]),
+			},
+			parent: binary.LittleEndian.Uint32(entry[24:]),
+		})
+	}
+	return nil
+}
+
+// lookup finds a block in the BBT, and returns its offset and size
+func (p *pstFile) lookup(bid uint64) (uint64, int, error) {
+	// The lowest bit of the BIDs is reserved
+	key := bid &^ 1
+	ib, level := p.bbt, -1
+	for {
+		entries, size, pageLevel, err := p.page(ib, pstTypeBBT)
+		if err != nil {
+			return 0, 0, err
+		}
+		if level >= 0 && pageLevel != level-1 {
+			return 0, 0, fmt.Errorf("invalid BBT-level at %d", ib)
+		}
+		level = pageLevel
+
+		if level == 0 {
+			for i := 0; i+size <= len(entries); i += size {
+				entry := entries[i:]
+				if binary.LittleEndian.Uint64(entry)&^1 == key {
+					return binary.LittleEndian.Uint64(entry[8:]), int(binary.LittleEndian.Uint16(entry[16:])), nil
+				}
+			}
+			return 0, 0, fmt.Errorf("block %d not found", bid)
+		}
+
+		// The child-page is the last with a key before the BID
+		var next uint64
+		for i := 0; i+size <= len(entries); i += size {
+			entry := entries[i:]
+			if binary.LittleEndian.Uint64(entry)&^1 > key {
+				break
+			}
+			next = binary.LittleEndian.Uint64(entry[16:])
+		}
+		if next == 0 {
+			return 0, 0, fmt.Errorf("block %d not found", bid)
+		}
+		ib = next
+	}
+}
+
+// page reads a page in a B-tree, and returns the entries,
+// the size of the entries and the level of the page
+func (p *pstFile) page(ib uint64, pageType byte) ([]byte, int, int, error) {
+	page := make([]byte, pstPageSize)
+	if ib > 1<<62 {
+		return nil, 0, 0, fmt.Errorf("invalid page-offset %d", ib)
+	}
+	if _, err := p.r.ReadAt(page, int64(ib)); err != nil {
+		return nil, 0, 0, fmt.Errorf("cannot read the page at %d: %v", ib, err)
+	}
+
+	count, size, level := int(page[pstPageCount]), int(page[pstPageEntry]), int(page[pstPageLevel])
+	if page[pstPageType] != pageType || page[pstPageType+1] != pageType || size < 24 || count*size > pstPageCount {
+		return nil, 0, 0, fmt.Errorf("invalid page at %d", ib)
+	}
+	return page[:count*size], size, level, nil
+}
+
+// block reads a block, the data-blocks are decoded,
+// the internal blocks (for the trees) aren't encoded
+func (p *pstFile) block(bid uint64) ([]byte, error) {
+	ib, size, err := p.lookup(bid)
+	if err != nil {
+		return nil, err
+	}
+	if ib > 1<<62 {
+		return nil, fmt.Errorf("invalid offset for block %d", bid)
+	}
+
+	data := make([]byte, size)
+	if _, err := p.r.ReadAt(data, int64(ib)); err != nil {
+		return nil, fmt.Errorf("cannot read block %d: %v", bid, err)
+	}
+	if bid&2 == 0 {
+		p.decode(data, uint32(bid))
+	}
+	return data, nil
+}
+
+// decode decodes the data in a data-block with the
+// encoding for the file, the key is the BID for
+// the block for the cyclic encoding
+func (p *pstFile) decode(data []byte, key uint32) {
+	switch p.crypt {
+	case pstCryptPermute:
+		for i, b := range data {
+			data[i] = pstCryptI[b]
+		}
+	case pstCryptCyclic:
+		w := uint16(key ^ key>>16)
+		for i, b := range data {
+			b += byte(w)
+			b = pstCryptR[b]
+			b += byte(w >> 8)
+			b = pstCryptS[b]
+			b -= byte(w >> 8)
+			b = pstCryptI[b]
+			b -= byte(w)
+			data[i] = b
+			w++
+		}
+	}
+}
+
+// blocks calls fn for the data-blocks of a node, the data for large
+// nodes is in the blocks that an XBLOCK (or an XXBLOCK) references
+func (p *pstFile) blocks(bid uint64, level byte, fn func(block []byte) error) error {
+	block, err := p.block(bid)
+	if err != nil {
+		return err
+	}
+	if bid&2 == 0 {
+		return fn(block)
+	}
+
+	if len(block) < 8 || block[0] != 0x01 || block[1] == 0 || block[1] > level {
+		return fmt.Errorf("invalid data-tree in block %d", bid)
+	}
+	count := int(binary.LittleEndian.Uint16(block[2:]))
+	if 8+count*8 > len(block) {
+		return fmt.Errorf("invalid data-tree in block %d", bid)
+	}
+	for i := 0; i < count; i++ {
+		if err := p.blocks(binary.LittleEndian.Uint64(block[8+i*8:]), block[1]-1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// data returns the data-blocks of a node
+func (p *pstFile) data(bid uint64) ([][]byte, error) {
+	var blocks [][]byte
+	err := p.blocks(bid, 2, func(block []byte) error {
+		blocks = append(blocks, block)
+		return nil
+	})
+	return blocks, err
+}
+
+// subnodes reads the subnodes of a node, from an SLBLOCK,
+// or from the SLBLOCKs that an SIBLOCK references
+func (p *pstFile) subnodes(bid uint64, level byte, subnodes map[uint32]pstNode) error {
+	if bid == 0 {
+		return nil
+	}
+	block, err := p.block(bid)
+	if err != nil {
+		return err
+	}
+
+	if len(block) < 8 || block[0] != 0x02 || block[1] > level {
+		return fmt.Errorf("invalid subnodes in block %d", bid)
+	}
+	size := 24
+	if block[1] > 0 {
+		size = 16
+	}
+	count := int(binary.LittleEndian.Uint16(block[2:]))
+	if 8+count*size > len(block) {
+		return fmt.Errorf("invalid subnodes in block %d", bid)
+	}
+
+	for i := 0; i < count; i++ {
+		entry := block[8+i*size:]
+		if block[1] > 0 {
+			if err := p.subnodes(binary.LittleEndian.Uint64(entry[8:]), 0, subnodes); err != nil {
+				return err
+			}
+			continue
+		}
+		subnodes[binary.LittleEndian.Uint32(entry)] = pstNode{
+			data: binary.LittleEndian.Uint64(entry[8:]),
+			sub:  binary.LittleEndian.Uint64(entry[16:]),
+		}
+	}
+	return nil
+}
+
+// properties reads the property-context of a node, the
+// values are read from the heap or from the subnodes
+func (p *pstFile) properties(node pstNode) (*pstProperties, error) {
+	blocks, err := p.data(node.data)
+	if err != nil {
+		return nil, err
+	}
+	heap, err := newHeap(blocks)
+	if err != nil {
+		return nil, err
+	}
+	if heap.client != 0xbc {
+		return nil, errors.New("invalid property-context")
+	}
+
+	props := &pstProperties{
+		file:     p,
+		heap:     heap,
+		subnodes: make(map[uint32]pstNode),
+		values:   make(map[uint16]pstValue),
+	}
+	if err := p.subnodes(node.sub, 1, props.subnodes); err != nil {
+		return nil, err
+	}
+
+	// The properties are in a BTH with
+	// 2-byte keys and 6-byte records
+	header, err := heap.item(heap.root)
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 8 || header[0] != 0xb5 || header[1] != 2 || header[2] != 6 || header[3] > 8 {
+		return nil, errors.New("invalid property-context")
+	}
+	if err := props.read(binary.LittleEndian.Uint32(header[4:]), int(header[3])); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// pstHeap is a heap-on-node, the items are in the blocks of the node
+type pstHeap struct {
+	blocks [][]byte
+	client byte
+	root   uint32
+}
+
+// newHeap reads the header of the heap, in the first block
+func newHeap(blocks [][]byte) (*pstHeap, error) {
+	if len(blocks) == 0 || len(blocks[0]) < 12 || blocks[0][2] != 0xec {
+		return nil, errors.New("invalid heap")
+	}
+	return &pstHeap{
+		blocks: blocks,
+		client: blocks[0][3],
+		root:   binary.LittleEndian.Uint32(blocks[0][4:]),
+	}, nil
+}
+
+// item returns the item for the HID, from the
+// page-map at the end of the block it is in
+func (h *pstHeap) item(hid uint32) ([]byte, error) {
+	if hid == 0 {
+		return nil, nil
+	}
+	index, block := int(hid>>5&0x7ff), int(hid>>16)
+	if hid&0x1f != 0 || index == 0 || block >= len(h.blocks) || len(h.blocks[block]) < 2 {
+		return nil, fmt.Errorf("invalid HID %x", hid)
+	}
+
+	data := h.blocks[block]
+	pageMap := int(binary.LittleEndian.Uint16(data))
+	if pageMap+4 > len(data) {
+		return nil, fmt.Errorf("invalid HID %x", hid)
+	}
+	count := int(binary.LittleEndian.Uint16(data[pageMap:]))
+	if index > count || pageMap+4+2*(count+1) > len(data) {
+		return nil, fmt.Errorf("invalid HID %x", hid)
+	}
+
+	start := int(binary.LittleEndian.Uint16(data[pageMap+4+2*(index-1):]))
+	end := int(binary.LittleEndian.Uint16(data[pageMap+4+2*index:]))
+	if start > end || end > len(data) {
+		return nil, fmt.Errorf("invalid HID %x", hid)
+	}
+	return data[start:end], nil
+}
+
+// pstValue is the type and the value of a property, the value
+// is in the record if it fits in four bytes, otherwise it's
+// the HID of the value, or the NID of the subnode it is in
+type pstValue struct {
+	kind  uint16
+	value uint32
+}
+
+// pstProperties are the properties of a folder,
+// a message or an attachment
+type pstProperties struct {
+	file     *pstFile
+	heap     *pstHeap
+	subnodes map[uint32]pstNode
+	values   map[uint16]pstValue
+}
+
+// read reads the records in the BTH, from the
+// records on the level down to the leaf-records
+func (props *pstProperties) read(hid uint32, level int) error {
+	records, err := props.heap.item(hid)
+	if err != nil {
+		return err
+	}
+
+	size := 8
+	if level > 0 {
+		size = 6
+	}
+	for i := 0; i+size <= len(records); i += size {
+		record := records[i:]
+		if level > 0 {
+			if err := props.read(binary.LittleEndian.Uint32(record[2:]), level-1); err != nil {
+				return err
+			}
+			continue
+		}
+		props.values[binary.LittleEndian.Uint16(record)] = pstValue{
+			kind:  binary.LittleEndian.Uint16(record[2:]),
+			value: binary.LittleEndian.Uint32(record[4:]),
+		}
+	}
+	return nil
+}
+
+// write writes the value of a property that isn't in the
+// record, from the heap or from the subnode it is in
+func (props *pstProperties) write(w io.Writer, id uint16) error {
+	v, ok := props.values[id]
+	switch {
+	case !ok:
+		return nil
+	case v.value&0x1f == 0:
+		item, err := props.heap.item(v.value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(item)
+		return err
+	}
+
+	node, ok := props.subnodes[v.value]
+	if !ok {
+		return fmt.Errorf("subnode %x not found", v.value)
+	}
+	return props.file.blocks(node.data, 2, func(block []byte) error {
+		_, err := w.Write(block)
+		return err
+	})
+}
+
+// bytes returns the value of a property that isn't in the record
+func (props *pstProperties) bytes(id uint16) ([]byte, error) {
+	var buf bytes.Buffer
+	err := props.write(&buf, id)
+	return buf.Bytes(), err
+}
+
+// text returns the value of a string-property, binary
+// values are decoded like text, as UTF-8 or Latin-1
+func (props *pstProperties) text(id uint16) string {
+	v, ok := props.values[id]
+	if !ok {
+		return ""
+	}
+	data, err := props.bytes(id)
+	if err != nil {
+		return ""
+	}
+
+	switch v.kind {
+	case propTypeString:
+		return strings.TrimRight(decodeUTF16(data, false), "\x00")
+	case propTypeString8, propTypeBinary:
+		return strings.TrimRight(toUTF8(data), "\x00")
+	}
+	return ""
+}
+
+// uint32 returns the value of an integer-property
+func (props *pstProperties) uint32(id uint16) uint32 {
+	switch v := props.values[id]; v.kind {
+	case propTypeInt16, propTypeInt32, propTypeBoolean:
+		return v.value
+	}
+	return 0
+}
+
+// time returns the value of a time-property, the
+// times are FILETIMEs, from the heap since they
+// don't fit in the record
+func (props *pstProperties) time(id uint16) time.Time {
+	if props.values[id].kind != propTypeTime {
+		return time.Time{}
+	}
+	data, err := props.bytes(id)
+	if err != nil || len(data) != 8 {
+		return time.Time{}
+	}
+	return filetime(binary.LittleEndian.Uint64(data))
+}
+
+// filetime converts a FILETIME (the 100-nanoseconds
+// since 1601) to the time, zero is the zero time
+func filetime(ft uint64) time.Time {
+	if ft == 0 {
+		return time.Time{}
+	}
+	const epoch = 116444736000000000
+	ticks := int64(ft - epoch)
+	return time.Unix(ticks/1e7, ticks%1e7*100).UTC()
+}
+
+// object returns the subnode with an embedded message,
+// the value is the NID of the subnode and its size
+func (props *pstProperties) object(id uint16) (pstNode, bool) {
+	v := props.values[id]
+	if v.kind != propTypeObject {
+		return pstNode{}, false
+	}
+	data, err := props.bytes(id)
+	if err != nil || len(data) < 4 {
+		return pstNode{}, false
+	}
+	node, ok := props.subnodes[binary.LittleEndian.Uint32(data)]
+	return node, ok
+}
+
+// messageDate returns when the message was sent, or
+// when it was received if it doesn't have the time
+func messageDate(msg *pstProperties) time.Time {
+	if date := msg.time(propSubmitTime); !date.IsZero() {
+		return date
+	}
+	return msg.time(propDeliveryTime)
+}
+
+// writeMessage writes the message as an EML, with the body and the
+// attachments in a multipart. The headers are the headers the message
+// was received with, or the headers from the properties if it was sent
+func writeMessage(w io.Writer, msg *pstProperties, depth int) error {
+	header := transportHeaders(msg.text(propHeaders))
+	if header == "" {
+		header = messageHeaders(msg)
+	}
+
+	writer := multipart.NewWriter(w)
+	contentType := mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": writer.Boundary()})
+	if _, err := fmt.Fprintf(w, "%sMIME-Version: 1.0\r\nContent-Type: %s\r\n\r\n", header, contentType); err != nil {
+		return err
+	}
+
+	// The HTML-body is used if the message doesn't have the plain text
+	body, mediaType := msg.text(propBody), "text/plain"
+	if body == "" {
+		body, mediaType = msg.text(propHTML), "text/html"
+	}
+	if body != "" {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mediaType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+		encoder := quotedprintable.NewWriter(part)
+		if _, err := io.WriteString(encoder, body); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+
+	for _, nid := range sortedNIDs(msg.subnodes, pstNodeAttachment) {
+		attachment, err := msg.file.properties(msg.subnodes[nid])
+		if err != nil {
+			return fmt.Errorf("cannot read attachment %x: %v", nid, err)
+		}
+		if err := writeAttachment(writer, attachment, depth); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// writeAttachment writes the attachment as a part, embedded messages
+// are written as EMLs, and the attachments that are references
+// to files (that aren't in the PST-file) are skipped
+func writeAttachment(writer *multipart.Writer, attachment *pstProperties, depth int) error {
+	name := attachment.text(propAttachLongName)
+	for _, id := range []uint16{propAttachFilename, propDisplayName} {
+		if name == "" {
+			name = attachment.text(id)
+		}
+	}
+
+	params := map[string]string{}
+	if name != "" {
+		params["filename"] = name
+	}
+	if modified := attachment.time(propModified); !modified.IsZero() {
+		params["modification-date"] = modified.Format(time.RFC1123Z)
+	}
+	header := textproto.MIMEHeader{}
+
+	if attachment.uint32(propAttachMethod) == attachEmbedded {
+		node, ok := attachment.object(propAttachData)
+		if !ok || depth >= pstMaxDepth {
+			return nil
+		}
+		msg, err := attachment.file.properties(node)
+		if err != nil {
+			return fmt.Errorf("cannot read the embedded message %s: %v", name, err)
+		}
+		if name != "" && !strings.HasSuffix(strings.ToLower(name), ".eml") {
+			params["filename"] = name + ".eml"
+		}
+		header.Set("Content-Type", typeEML)
+		header.Set("Content-Disposition", disposition(params))
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		return writeMessage(part, msg, depth+1)
+	}
+
+	if kind := attachment.values[propAttachData].kind; kind != propTypeBinary {
+		return nil
+	}
+	contentType := attachment.text(propAttachMime)
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", disposition(params))
+	header.Set("Content-Transfer-Encoding", "base64")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	encoder := base64.NewEncoder(base64.StdEncoding, part)
+	if err := attachment.write(encoder, propAttachData); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// disposition returns the disposition for an attachment,
+// the file-name is encoded if it isn't ASCII
+func disposition(params map[string]string) string {
+	if value := mime.FormatMediaType("attachment", params); value != "" {
+		return value
+	}
+	return "attachment"
+}
+
+// sortedNIDs returns the NIDs of the subnodes with the type, in order
+func sortedNIDs(subnodes map[uint32]pstNode, nodeType uint32) []uint32 {
+	var nids []uint32
+	for nid := range subnodes {
+		if nid&0x1f == nodeType {
+			nids = append(nids, nid)
+		}
+	}
+	sort.Slice(nids, func(i, j int) bool { return nids[i] < nids[j] })
+	return nids
+}
+
+// transportHeaders returns the headers the message was received
+// with, without the headers for the body, since it's rewritten
+func transportHeaders(headers string) string {
+	var b strings.Builder
+	var skip bool
+	for _, line := range strings.Split(strings.Replace(headers, "\r\n", "\n", -1), "\n") {
+		if line == "" {
+			break
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			key := strings.ToLower(strings.TrimSpace(strings.SplitN(line, ":", 2)[0]))
+			skip = strings.HasPrefix(key, "content-") || key == "mime-version"
+		}
+		if !skip {
+			b.WriteString(line + "\r\n")
+		}
+	}
+	return b.String()
+}
+
+// messageHeaders returns the headers for a message from its
+// properties, the recipients only have their display-names
+func messageHeaders(msg *pstProperties) string {
+	var b strings.Builder
+	write := func(key, value string) {
+		if value = strings.Join(strings.Fields(value), " "); value != "" {
+			fmt.Fprintf(&b, "%s: %s\r\n", key, value)
+		}
+	}
+
+	name := msg.text(propSenderName)
+	if name == "" {
+		name = msg.text(propSentName)
+	}
+	address := msg.text(propSenderSMTP)
+	if address == "" {
+		address = msg.text(propSenderEmail)
+	}
+	if strings.Contains(address, "@") {
+		write("From", (&mail.Address{Name: strings.Join(strings.Fields(name), " "), Address: strings.TrimSpace(address)}).String())
+	} else {
+		write("From", encodeHeader(name))
+	}
+
+	write("To", encodeHeader(msg.text(propDisplayTo)))
+	write("Cc", encodeHeader(msg.text(propDisplayCc)))
+	write("Bcc", encodeHeader(msg.text(propDisplayBcc)))
+	write("Subject", encodeHeader(subject(msg.text(propSubject))))
+	if date := messageDate(msg); !date.IsZero() {
+		write("Date", date.Format(time.RFC1123Z))
+	}
+	write("Message-ID", msg.text(propMessageID))
+	return b.String()
+}
+
+// encodeHeader encodes the value for a header, if it isn't ASCII
+func encodeHeader(value string) string {
+	return mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(value), " "))
+}
+
+// subject removes the marker for the length of the
+// prefix (like "RE: ") that some subjects start with
+func subject(value string) string {
+	if runes := []rune(value); len(runes) >= 2 && runes[0] == 0x01 {
+		return string(runes[2:])
+	}
+	return value
+}
+
+// The tables for the encodings of the data-blocks, mpbbCrypt in
+// [MS-PST], pstCryptI is the inverse of pstCryptR, and pstCryptS
+// is its own inverse, so the cyclic encoding decodes itself
+var (
+	pstCryptR = [256]byte{
+		65, 54, 19, 98, 168, 33, 110, 187, 244, 22, 204, 4, 127, 100, 232, 93,
+		30, 242, 203, 42, 116, 197, 94, 53, 210, 149, 71, 158, 150, 45, 154, 136,
+		76, 125, 132, 63, 219, 172, 49, 182, 72, 95, 246, 196, 216, 57, 139, 231,
+		35, 59, 56, 142, 200, 193, 223, 37, 177, 32, 165, 70, 96, 78, 156, 251,
+		170, 211, 86, 81, 69, 124, 85, 0, 7, 201, 43, 157, 133, 155, 9, 160,
+		143, 173, 179, 15, 99, 171, 137, 75, 215, 167, 21, 90, 113, 102, 66, 191,
+		38, 74, 107, 152, 250, 234, 119, 83, 178, 112, 5, 44, 253, 89, 58, 134,
+		126, 206, 6, 235, 130, 120, 87, 199, 141, 67, 175, 180, 28, 212, 91, 205,
+		226, 233, 39, 79, 195, 8, 114, 128, 207, 176, 239, 245, 40, 109, 190, 48,
+		77, 52, 146, 213, 14, 60, 34, 50, 229, 228, 249, 159, 194, 209, 10, 129,
+		18, 225, 238, 145, 131, 118, 227, 151, 230, 97, 138, 23, 121, 164, 183, 220,
+		144, 122, 92, 140, 2, 166, 202, 105, 222, 80, 26, 17, 147, 185, 82, 135,
+		88, 252, 237, 29, 55, 73, 27, 106, 224, 41, 51, 153, 189, 108, 217, 148,
+		243, 64, 84, 111, 240, 198, 115, 184, 214, 62, 101, 24, 68, 31, 221, 103,
+		16, 241, 12, 25, 236, 174, 3, 161, 20, 123, 169, 11, 255, 248, 163, 192,
+		162, 1, 247, 46, 188, 36, 104, 117, 13, 254, 186, 47, 181, 208, 218, 61,
+	}
+	pstCryptS = [256]byte{
+		20, 83, 15, 86, 179, 200, 122, 156, 235, 101, 72, 23, 22, 21, 159, 2,
+		204, 84, 124, 131, 0, 13, 12, 11, 162, 98, 168, 118, 219, 217, 237, 199,
+		197, 164, 220, 172, 133, 116, 214, 208, 167, 155, 174, 154, 150, 113, 102, 195,
+		99, 153, 184, 221, 115, 146, 142, 132, 125, 165, 94, 209, 93, 147, 177, 87,
+		81, 80, 128, 137, 82, 148, 79, 78, 10, 107, 188, 141, 127, 110, 71, 70,
+		65, 64, 68, 1, 17, 203, 3, 63, 247, 244, 225, 169, 143, 60, 58, 249,
+		251, 240, 25, 48, 130, 9, 46, 201, 157, 160, 134, 73, 238, 111, 77, 109,
+		196, 45, 129, 52, 37, 135, 27, 136, 170, 252, 6, 161, 18, 56, 253, 76,
+		66, 114, 100, 19, 55, 36, 106, 117, 119, 67, 255, 230, 180, 75, 54, 92,
+		228, 216, 53, 61, 69, 185, 44, 236, 183, 49, 43, 41, 7, 104, 163, 14,
+		105, 123, 24, 158, 33, 57, 190, 40, 26, 91, 120, 245, 35, 202, 42, 176,
+		175, 62, 254, 4, 140, 231, 229, 152, 50, 149, 211, 246, 74, 232, 166, 234,
+		233, 243, 213, 47, 112, 32, 242, 31, 5, 103, 173, 85, 16, 206, 205, 227,
+		39, 59, 218, 186, 215, 194, 38, 212, 145, 29, 210, 28, 34, 51, 248, 250,
+		241, 90, 239, 207, 144, 182, 139, 181, 189, 192, 191, 8, 151, 30, 108, 226,
+		97, 224, 198, 193, 89, 171, 187, 88, 222, 95, 223, 96, 121, 126, 178, 138,
+	}
+	pstCryptI = [256]byte{
+		71, 241, 180, 230, 11, 106, 114, 72, 133, 78, 158, 235, 226, 248, 148, 83,
+		224, 187, 160, 2, 232, 90, 9, 171, 219, 227, 186, 198, 124, 195, 16, 221,
+		57, 5, 150, 48, 245, 55, 96, 130, 140, 201, 19, 74, 107, 29, 243, 251,
+		143, 38, 151, 202, 145, 23, 1, 196, 50, 45, 110, 49, 149, 255, 217, 35,
+		209, 0, 94, 121, 220, 68, 59, 26, 40, 197, 97, 87, 32, 144, 61, 131,
+		185, 67, 190, 103, 210, 70, 66, 118, 192, 109, 91, 126, 178, 15, 22, 41,
+		60, 169, 3, 84, 13, 218, 93, 223, 246, 183, 199, 98, 205, 141, 6, 211,
+		105, 92, 134, 214, 20, 247, 165, 102, 117, 172, 177, 233, 69, 33, 112, 12,
+		135, 159, 116, 164, 34, 76, 111, 191, 31, 86, 170, 46, 179, 120, 51, 80,
+		176, 163, 146, 188, 207, 25, 28, 167, 99, 203, 30, 77, 62, 75, 27, 155,
+		79, 231, 240, 238, 173, 58, 181, 89, 4, 234, 64, 85, 37, 81, 229, 122,
+		137, 56, 104, 82, 123, 252, 39, 174, 215, 189, 250, 7, 244, 204, 142, 95,
+		239, 53, 156, 132, 43, 21, 213, 119, 52, 73, 182, 18, 10, 127, 113, 136,
+		253, 157, 24, 65, 125, 147, 216, 88, 44, 206, 254, 36, 175, 222, 184, 54,
+		200, 161, 128, 166, 153, 152, 168, 47, 14, 129, 101, 115, 228, 194, 162, 138,
+		212, 225, 17, 208, 8, 139, 42, 242, 237, 154, 100, 63, 193, 108, 249, 236,
+	}
+)
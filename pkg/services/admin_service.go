@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"sort"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+)
+
+// AdminService holds the dependencies
+// for the admin-service
+type AdminService struct {
+	db          datastore.Service
+	auth        authentication.Service
+	store       filestore.Service
+	caseService *CaseService
+}
+
+// NewAdminService creates a new admin-service
+func NewAdminService(db datastore.Service, auth authentication.Service, store filestore.Service, caseService *CaseService) *AdminService {
+	return &AdminService{db: db, auth: auth, store: store, caseService: caseService}
+}
+
+// Cases lists every case in the system
+func (s *AdminService) Cases(ctx context.Context, r api.AdminCasesRequest) (*api.AdminCasesResponse, error) {
+	cases, err := s.db.GetCases(ctx)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.AdminCasesResponse{Cases: cases}, nil
+}
+
+// Transfer transfers the ownership of a case to another user
+func (s *AdminService) Transfer(ctx context.Context, r api.AdminTransferRequest) (*api.AdminTransferResponse, error) {
+	if r.UserID == "" {
+		return nil, api.Error(errors.New("userID is required"), api.ErrCannotPerformOperation)
+	}
+
+	caze, err := s.db.GetCase(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
+	}
+
+	// Get the email from the authentication-provider
+	// if it isn't specified in the request
+	email := r.Email
+	if email == "" {
+		user, err := s.auth.GetUserByID(ctx, r.UserID)
+		if errors.Is(err, authentication.ErrNotSupported) {
+			return nil, api.Error(errors.New("the email of the new owner is required, the authentication-provider cannot look up users"), api.ErrCannotPerformOperation)
+		} else if err != nil {
+			return nil, api.Error(err, api.ErrNotFound)
+		}
+		email = user.Email
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return nil, api.Error(fmt.Errorf("invalid email %q: %v", email, err), api.ErrCannotPerformOperation)
+	}
+	email = address.Address
+
+	caze.CreatorID = r.UserID
+	if !isAllowed(caze, email) {
+		caze.Investigators = append(caze.Investigators, email)
+	}
+
+	var found bool
+	for i := range caze.Roles {
+		if caze.Roles[i].Email == email {
+			caze.Roles[i].Name = api.RoleOwner
+			found = true
+		}
+	}
+	if !found {
+		caze.Roles = append(caze.Roles, api.Role{Email: email, Name: api.RoleOwner})
+	}
+
+	if r.RemoveEmail != "" && r.RemoveEmail != email {
+		var investigators []string
+		for _, investigator := range caze.Investigators {
+			if investigator != r.RemoveEmail {
+				investigators = append(investigators, investigator)
+			}
+		}
+		var roles []api.Role
+		for _, role := range caze.Roles {
+			if role.Email != r.RemoveEmail {
+				roles = append(roles, role)
+			}
+		}
+		caze.Investigators = investigators
+		caze.Roles = roles
+	}
+
+	if err := s.db.UpdateCase(ctx, caze); err != nil {
+		return nil, updateError(err)
+	}
+
+	return &api.AdminTransferResponse{Transferred: *caze}, nil
+}
+
+// Users lists the users with their memberships in the cases,
+// the users are the investigators from every case
+func (s *AdminService) Users(ctx context.Context, r api.AdminUsersRequest) (*api.AdminUsersResponse, error) {
+	cases, err := s.db.GetCases(ctx)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	var memberships = make(map[string][]api.Membership)
+	for i := range cases {
+		for _, email := range cases[i].Investigators {
+			memberships[email] = append(memberships[email], api.Membership{
+				CaseID:   cases[i].ID,
+				CaseName: cases[i].Name,
+				Role:     roleOf(&cases[i], api.User{Email: email}),
+			})
+		}
+	}
+
+	users := make([]api.UserMemberships, 0, len(memberships))
+	for email, m := range memberships {
+		users = append(users, api.UserMemberships{Email: email, Memberships: m})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+
+	return &api.AdminUsersResponse{Users: users}, nil
+}
+
+// Delete deletes a case without being an owner of it
+func (s *AdminService) Delete(ctx context.Context, r api.AdminDeleteRequest) (*api.AdminDeleteResponse, error) {
+	if err := s.db.DeleteCase(ctx, r.ID); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+	return &api.AdminDeleteResponse{}, nil
+}
+
+// RotateKey rotates the data-key for the files in a case
+func (s *AdminService) RotateKey(ctx context.Context, r api.AdminRotateKeyRequest) (*api.AdminRotateKeyResponse, error) {
+	rotator, ok := s.store.(filestore.Rotator)
+	if !ok {
+		return nil, api.Error(errors.New("the filestore isn't encrypted"), api.ErrCannotPerformOperation)
+	}
+
+	if _, err := s.db.GetCase(ctx, r.ID); err != nil {
+		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
+	}
+
+	version, err := rotator.RotateKey(r.ID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.AdminRotateKeyResponse{Version: version}, nil
+}
+
+// CreateUser creates a user that signs in with a password
+func (s *AdminService) CreateUser(ctx context.Context, r api.AdminCreateUserRequest) (*api.AdminCreateUserResponse, error) {
+	manager, err := s.userManager()
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := mail.ParseAddress(r.Email)
+	if err != nil {
+		return nil, api.Error(fmt.Errorf("invalid email %q: %v", r.Email, err), api.ErrCannotPerformOperation)
+	}
+
+	user, err := manager.CreateUser(ctx, address.Address, r.Name, r.Password)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.AdminCreateUserResponse{ID: user.UID}, nil
+}
+
+// DeleteUser deletes a user
+func (s *AdminService) DeleteUser(ctx context.Context, r api.AdminDeleteUserRequest) (*api.AdminDeleteUserResponse, error) {
+	manager, err := s.userManager()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := manager.DeleteUser(ctx, r.ID); err != nil {
+		return nil, api.Error(err, api.ErrNotFound)
+	}
+
+	return &api.AdminDeleteUserResponse{}, nil
+}
+
+// ResetPassword sets a new password for a user
+func (s *AdminService) ResetPassword(ctx context.Context, r api.AdminResetPasswordRequest) (*api.AdminResetPasswordResponse, error) {
+	manager, err := s.userManager()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := manager.SetPassword(ctx, r.ID, r.Password); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.AdminResetPasswordResponse{}, nil
+}
+
+// userManager returns the authentication-provider
+// if the users are managed by the TI-API
+func (s *AdminService) userManager() (authentication.UserManager, error) {
+	manager, ok := s.auth.(authentication.UserManager)
+	if !ok {
+		return nil, api.Error(errors.New("the users are managed by the authentication-provider"), api.ErrCannotPerformOperation)
+	}
+	return manager, nil
+}
+
+// Authenticate is a middleware
+// in the http-handler
+//
+// NOTE : Only system-admins are allowed
+func (s *AdminService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	ctx, err := s.caseService.Authenticate(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.GetUser(ctx).Admin {
+		return nil, api.ErrNotAllowed
+	}
+	return ctx, nil
+}
@@ -0,0 +1,170 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+
+	"github.com/matryer/is"
+	"github.com/pacedotdev/oto/otohttp"
+)
+
+// GetUserByID isn't supported, like with the oidc-provider
+func (testAuth) GetUserByID(ctx context.Context, uid string) (*authentication.User, error) {
+	return nil, authentication.ErrNotSupported
+}
+
+func TestAdminService(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		Name:          "Case 1",
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "viewer@test.com"},
+		Roles: []api.Role{
+			{Email: "owner@test.com", Name: api.RoleOwner},
+			{Email: "viewer@test.com", Name: api.RoleViewer},
+		},
+	}
+	db := testDB{cases: map[string]*api.Case{caze.ID: caze}}
+
+	router := otohttp.NewServer()
+	router.Basepath = "/api/"
+	caseService := services.NewCaseService(db, testAuth{}, "Admin@test.com")
+	api.RegisterCaseService(router, caseService)
+	api.RegisterAdminService(router, services.NewAdminService(db, testAuth{}, nil, caseService))
+	authorizer := services.NewAuthorizer(router, caseService)
+
+	call := func(endpoint, token string, request, response interface{}) string {
+		body, err := json.Marshal(request)
+		is.NoErr(err)
+		r := httptest.NewRequest(http.MethodPost, "/api/"+endpoint, bytes.NewReader(body))
+		r.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		authorizer.ServeHTTP(w, r)
+
+		var errResponse struct{ Error string }
+		data := w.Body.Bytes()
+		is.NoErr(json.Unmarshal(data, &errResponse))
+		if errResponse.Error == "" && response != nil {
+			is.NoErr(json.Unmarshal(data, response))
+		}
+		return errResponse.Error
+	}
+
+	// Only admins can use the admin-service
+	is.Equal(call("AdminService.Cases", "owner", api.AdminCasesRequest{}, nil), api.ErrNotAllowed.Error())
+
+	var cases api.AdminCasesResponse
+	is.Equal(call("AdminService.Cases", "admin", api.AdminCasesRequest{}, &cases), "")
+	is.Equal(len(cases.Cases), 1)
+	is.Equal(call("AdminService.Cases", "claimed", api.AdminCasesRequest{}, &cases), "")
+
+	// The email must be verified by the provider to be an admin
+	is.Equal(call("AdminService.Cases", "unverified", api.AdminCasesRequest{}, nil), api.ErrNotAllowed.Error())
+
+	// Admins doesn't have access to the cases
+	is.Equal(call("CaseService.Get", "admin", api.CaseGetRequest{ID: caze.ID}, nil), api.ErrNotAllowed.Error())
+
+	var users api.AdminUsersResponse
+	is.Equal(call("AdminService.Users", "admin", api.AdminUsersRequest{}, &users), "")
+	is.Equal(len(users.Users), 2)
+	is.Equal(users.Users[0].Email, "owner@test.com")
+	is.Equal(users.Users[0].Memberships[0].Role, api.RoleOwner)
+	is.Equal(users.Users[1].Memberships[0].Role, api.RoleViewer)
+
+	// The users cannot be looked up by the provider
+	errMsg := call("AdminService.Transfer", "admin", api.AdminTransferRequest{ID: caze.ID, UserID: "viewer"}, nil)
+	is.True(strings.Contains(errMsg, "the email of the new owner is required"))
+	errMsg = call("CaseService.List", "admin", api.CaseListRequest{UserID: "viewer"}, nil)
+	is.True(strings.Contains(errMsg, "AdminService.Users"))
+
+	// The users are managed by the provider
+	errMsg = call("AdminService.CreateUser", "admin", api.AdminCreateUserRequest{
+		Email:    "new@test.com",
+		Password: "correct horse battery",
+	}, nil)
+	is.True(strings.Contains(errMsg, "the users are managed by the authentication-provider"))
+
+	// Transfer the case from the owner to the viewer
+	var transfer api.AdminTransferResponse
+	is.Equal(call("AdminService.Transfer", "admin", api.AdminTransferRequest{
+		ID:          caze.ID,
+		UserID:      "viewer",
+		Email:       "viewer@test.com",
+		RemoveEmail: "owner@test.com",
+	}, &transfer), "")
+	is.Equal(transfer.Transferred.CreatorID, "viewer")
+	is.Equal(transfer.Transferred.Investigators, []string{"viewer@test.com"})
+	is.Equal(transfer.Transferred.Roles, []api.Role{{Email: "viewer@test.com", Name: api.RoleOwner}})
+
+	// The previous owner has lost the access
+	is.Equal(call("CaseService.Get", "owner", api.CaseGetRequest{ID: caze.ID}, nil), api.ErrNotAllowed.Error())
+}
+
+// testUsers manages the users in memory,
+// like the local authentication-provider
+type testUsers struct {
+	testAuth
+	passwords map[string]string
+}
+
+func (a testUsers) CreateUser(ctx context.Context, email, name, password string) (*authentication.User, error) {
+	a.passwords[email] = password
+	return &authentication.User{UID: email, Email: email, DisplayName: name}, nil
+}
+
+func (a testUsers) SetPassword(ctx context.Context, uid, password string) error {
+	if _, ok := a.passwords[uid]; !ok {
+		return errors.New("user not found")
+	}
+	a.passwords[uid] = password
+	return nil
+}
+
+func (a testUsers) DeleteUser(ctx context.Context, uid string) error {
+	if _, ok := a.passwords[uid]; !ok {
+		return errors.New("user not found")
+	}
+	delete(a.passwords, uid)
+	return nil
+}
+
+func TestAdminServiceUsers(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	auth := testUsers{passwords: make(map[string]string)}
+	adminService := services.NewAdminService(testDB{}, auth, nil, nil)
+
+	_, err := adminService.CreateUser(ctx, api.AdminCreateUserRequest{Email: "not-an-email", Password: "correct horse battery"})
+	is.True(err != nil)
+
+	created, err := adminService.CreateUser(ctx, api.AdminCreateUserRequest{
+		Email:    "Simon <sja@avian.dk>",
+		Name:     "Simon",
+		Password: "correct horse battery",
+	})
+	is.NoErr(err)
+	is.Equal(created.ID, "sja@avian.dk")
+	is.Equal(auth.passwords["sja@avian.dk"], "correct horse battery")
+
+	_, err = adminService.ResetPassword(ctx, api.AdminResetPasswordRequest{ID: created.ID, Password: "staple battery horse"})
+	is.NoErr(err)
+	is.Equal(auth.passwords["sja@avian.dk"], "staple battery horse")
+
+	_, err = adminService.DeleteUser(ctx, api.AdminDeleteUserRequest{ID: created.ID})
+	is.NoErr(err)
+	_, err = adminService.DeleteUser(ctx, api.AdminDeleteUserRequest{ID: created.ID})
+	is.True(strings.HasPrefix(err.Error(), api.ErrNotFound.Error()))
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
+)
+
+// AuthService holds the dependencies
+// for the auth-service
+type AuthService struct {
+	auth authentication.Service
+}
+
+// NewAuthService creates a new auth-service
+func NewAuthService(auth authentication.Service) *AuthService {
+	return &AuthService{auth: auth}
+}
+
+// SignIn signs in the user with email and password
+func (s *AuthService) SignIn(ctx context.Context, r api.AuthSignInRequest) (*api.AuthSignInResponse, error) {
+	if r.Email == "" || r.Password == "" {
+		return nil, api.Error(errors.New("email and password is required"), api.ErrNotAllowed)
+	}
+
+	token, err := s.auth.SignIn(ctx, r.Email, r.Password)
+	if err != nil {
+		if errors.Is(err, authentication.ErrNotSupported) {
+			return nil, api.Error(err, api.ErrCannotPerformOperation)
+		}
+		return nil, api.Error(err, api.ErrNotAllowed)
+	}
+
+	return &api.AuthSignInResponse{Token: token}, nil
+}
+
+// Authenticate is a middleware
+// in the http-handler
+//
+// NOTE : Signing in doesn't require a token
+func (s *AuthService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	return ctx, nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/pacedotdev/oto/otohttp"
+)
+
+// permission holds the roles that are required in a case
+// to call an endpoint, and the field in the request that
+// holds the id of the case
+type permission struct {
+	field string
+	roles []string
+}
+
+var (
+	// ownerRoles are the roles that can manage a case
+	ownerRoles = []string{api.RoleOwner}
+
+	// editRoles are the roles that can change the evidence in a case
+	editRoles = []string{api.RoleOwner, api.RoleEditor}
+
+	// viewRoles are the roles that can browse the evidence in a case,
+	// auditors can only see the case, the files and their custody
+	viewRoles = []string{api.RoleOwner, api.RoleEditor, api.RoleViewer}
+)
+
+// permissions for the endpoints that belongs to a case,
+// no roles means that every investigator is allowed,
+// including the auditors
+//
+// NOTE : endpoints that are missing here, but still has a
+// caseID in the request, are only allowed for the investigators
+// that can browse the case, and API-tokens are only allowed to
+// call endpoints for a case
+var permissions = map[string]permission{
+	"CaseService.Get":                 {field: "id"},
+	"CaseService.Keywords":            {field: "id", roles: viewRoles},
+	"CaseService.Update":              {field: "id", roles: ownerRoles},
+	"CaseService.Delete":              {field: "id", roles: ownerRoles},
+	"CaseService.InvestigatorsAdd":    {field: "id", roles: ownerRoles},
+	"CaseService.InvestigatorsRemove": {field: "id", roles: ownerRoles},
+	"CaseService.RoleSet":             {field: "id", roles: ownerRoles},
+
+	"EntityService.Get":            {field: "caseID", roles: viewRoles},
+	"EntityService.List":           {field: "caseID", roles: viewRoles},
+	"EntityService.Create":         {field: "caseID", roles: editRoles},
+	"EntityService.Update":         {field: "caseID", roles: editRoles},
+	"EntityService.Delete":         {field: "caseID", roles: editRoles},
+	"EntityService.KeywordsAdd":    {field: "caseID", roles: editRoles},
+	"EntityService.KeywordsRemove": {field: "caseID", roles: editRoles},
+
+	"EventService.Get":            {field: "caseID", roles: viewRoles},
+	"EventService.List":           {field: "caseID", roles: viewRoles},
+	"EventService.Create":         {field: "caseID", roles: editRoles},
+	"EventService.Update":         {field: "caseID", roles: editRoles},
+	"EventService.Delete":         {field: "caseID", roles: editRoles},
+	"EventService.KeywordsAdd":    {field: "caseID", roles: editRoles},
+	"EventService.KeywordsRemove": {field: "caseID", roles: editRoles},
+
+	"FileService.List":           {field: "caseID"},
+	"FileService.Open":           {field: "caseID", roles: viewRoles},
+	"FileService.Processed":      {field: "caseID", roles: viewRoles},
+	"FileService.Processes":      {field: "caseID", roles: viewRoles},
+	"FileService.Custody":        {field: "caseID"},
+	"FileService.Verify":         {field: "caseID"},
+	"FileService.New":            {field: "caseID", roles: editRoles},
+	"FileService.Process":        {field: "caseID", roles: editRoles},
+	"FileService.Update":         {field: "caseID", roles: editRoles},
+	"FileService.Delete":         {field: "caseID", roles: editRoles},
+	"FileService.KeywordsAdd":    {field: "caseID", roles: editRoles},
+	"FileService.KeywordsRemove": {field: "caseID", roles: editRoles},
+
+	"LinkService.Get":    {field: "caseID", roles: viewRoles},
+	"LinkService.Create": {field: "caseID", roles: editRoles},
+	"LinkService.Add":    {field: "caseID", roles: editRoles},
+	"LinkService.Remove": {field: "caseID", roles: editRoles},
+	"LinkService.Delete": {field: "caseID", roles: editRoles},
+
+	"PersonService.Get":            {field: "caseID", roles: viewRoles},
+	"PersonService.List":           {field: "caseID", roles: viewRoles},
+	"PersonService.Create":         {field: "caseID", roles: editRoles},
+	"PersonService.Update":         {field: "caseID", roles: editRoles},
+	"PersonService.Delete":         {field: "caseID", roles: editRoles},
+	"PersonService.KeywordsAdd":    {field: "caseID", roles: editRoles},
+	"PersonService.KeywordsRemove": {field: "caseID", roles: editRoles},
+
+	"ProcessService.Jobs":   {field: "caseID", roles: viewRoles},
+	"ProcessService.Start":  {field: "caseID", roles: editRoles},
+	"ProcessService.Abort":  {field: "caseID", roles: editRoles},
+	"ProcessService.Pause":  {field: "caseID", roles: editRoles},
+	"ProcessService.Resume": {field: "caseID", roles: editRoles},
+
+	"SearchService.SearchWithTimespan": {field: "caseID", roles: viewRoles},
+	"SearchService.SearchWithText":     {field: "caseID", roles: viewRoles},
+
+	"SuggestionService.List":   {field: "caseID", roles: viewRoles},
+	"SuggestionService.Accept": {field: "caseID", roles: editRoles},
+	"SuggestionService.Reject": {field: "caseID", roles: editRoles},
+}
+
+// Authorizer is a middleware for the oto-router that
+// authorizes every request to an endpoint that belongs
+// to a case, before it reaches the service
+type Authorizer struct {
+	router      *otohttp.Server
+	caseService *CaseService
+}
+
+// NewAuthorizer creates a new authorizer for the router
+func NewAuthorizer(router *otohttp.Server, caseService *CaseService) *Authorizer {
+	return &Authorizer{router: router, caseService: caseService}
+}
+
+// ServeHTTP authorizes the request and passes
+// it on to the router if the user is allowed
+func (a *Authorizer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		a.router.ServeHTTP(w, r)
+		return
+	}
+
+	// Read the body and put it back for
+	// the router to decode the request
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.router.OnErr(w, r, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The case is read from the body as the router decodes it into
+	// the request, the keys are matched case-insensitively and the
+	// last one wins, so the IDs that are authorized are the IDs that
+	// the services get. Bodies with the ID more than once are rejected
+	var request caseRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		// let the router handle invalid requests
+		a.router.ServeHTTP(w, r)
+		return
+	}
+	if key, ok := duplicateKey(body, "id", "caseID"); ok {
+		a.router.OnErr(w, r, api.Error(fmt.Errorf("%s is in the request more than once", key), api.ErrNotAllowed))
+		return
+	}
+
+	endpoint := strings.TrimPrefix(r.URL.Path, a.router.Basepath)
+	perm, ok := permissions[endpoint]
+	if !ok {
+		if request.CaseID == "" {
+			// API-tokens only has access to endpoints within their cases
+			if isAPIToken(utils.GetToken(r)) {
+				a.router.OnErr(w, r, api.ErrNotAllowed)
+				return
+			}
+			a.router.ServeHTTP(w, r)
+			return
+		}
+		perm = permission{field: "caseID", roles: viewRoles}
+	}
+
+	caseID := request.CaseID
+	if perm.field == "id" {
+		caseID = request.ID
+	}
+	if caseID == "" {
+		a.router.OnErr(w, r, api.Error(fmt.Errorf("%s is required", perm.field), api.ErrNotAllowed))
+		return
+	}
+
+	ctx, err := authorizeCase(r, a.caseService, caseID, perm.roles...)
+	if err != nil {
+		a.router.OnErr(w, r, err)
+		return
+	}
+
+	a.router.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// caseRequest holds the fields for the ID of the case in the
+// requests, with the same names as in the request-structs
+type caseRequest struct {
+	ID     string `json:"id"`
+	CaseID string `json:"caseID"`
+}
+
+// duplicateKey checks if any of the keys is in the top-level of the
+// JSON-object more than once, in any letter case. It returns the
+// key as it was in the body if it was
+func duplicateKey(body []byte, keys ...string) (string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return "", false
+	}
+
+	var found = make(map[string]bool)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return "", false
+		}
+		key, _ := t.(string)
+		for _, k := range keys {
+			if strings.EqualFold(key, k) {
+				if found[k] {
+					return key, true
+				}
+				found[k] = true
+			}
+		}
+
+		// skip the value
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// authorizeCase authenticates the request and checks that
+// the user (or API-token) has one of the roles in the case
+func authorizeCase(r *http.Request, caseService *CaseService, caseID string, roles ...string) (context.Context, error) {
+	ctx, err := caseService.Authenticate(r.Context(), r)
+	if err != nil {
+		return nil, err
+	}
+	if err := caseService.authorize(ctx, caseID, roles...); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// authorize checks that the user (or API-token) in the context has
+// one of the roles in the case (or any role if none is specified).
+// The requests are authorized by the Authorizer before they reach
+// the services, the services check it again so they never act on
+// a case that wasn't authorized
+func (s *CaseService) authorize(ctx context.Context, caseID string, roles ...string) error {
+	// A case that cannot be found is reported as not allowed,
+	// to not reveal which cases that exists
+	caze, err := s.db.GetCase(ctx, caseID)
+	if err != nil {
+		return api.ErrNotAllowed
+	}
+
+	allowed := hasRole(caze, utils.GetUser(ctx), roles...)
+	if token, ok := utils.GetAPIToken(ctx); ok {
+		allowed = tokenHasRole(caze, token, roles...)
+	}
+	if !allowed {
+		return api.ErrNotAllowed
+	}
+	return nil
+}
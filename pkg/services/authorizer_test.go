@@ -0,0 +1,261 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/matryer/is"
+	"github.com/pacedotdev/oto/otohttp"
+)
+
+// testAuth uses the uid of the user as token
+type testAuth struct {
+	authentication.Service
+}
+
+func (testAuth) GetUserByToken(ctx context.Context, idToken string) (*authentication.User, error) {
+	user := &authentication.User{UID: idToken, Email: idToken + "@test.com", EmailVerified: true}
+	if idToken == "unverified" {
+		user.Email = "admin@test.com"
+		user.EmailVerified = false
+	}
+	if idToken == "claimed" {
+		user.CustomClaims = map[string]interface{}{"admin": true}
+	}
+	return user, nil
+}
+
+func TestAuthorizer(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "viewer@test.com", "auditor@test.com"},
+		Roles: []api.Role{
+			{Email: "owner@test.com", Name: api.RoleOwner},
+			{Email: "viewer@test.com", Name: api.RoleViewer},
+			{Email: "auditor@test.com", Name: api.RoleAuditor},
+		},
+	}
+	db := testDB{cases: map[string]*api.Case{caze.ID: caze}}
+
+	router := otohttp.NewServer()
+	router.Basepath = "/api/"
+	caseService := services.NewCaseService(db, testAuth{})
+	api.RegisterCaseService(router, caseService)
+	api.RegisterEntityService(router, services.NewEntityService(db, caseService))
+	api.RegisterEventService(router, services.NewEventService(db, caseService))
+	api.RegisterFileService(router, services.NewFileService(db, nil, caseService, nil))
+	api.RegisterLinkService(router, services.NewLinkService(db, caseService))
+	api.RegisterPersonService(router, services.NewPersonService(db, caseService))
+	api.RegisterSearchService(router, services.NewSearchService(db, caseService))
+	api.RegisterTokenService(router, services.NewTokenService(db, caseService))
+	authorizer := services.NewAuthorizer(router, caseService)
+
+	call := func(endpoint, token string) string {
+		body := strings.NewReader(`{"id":"case-1","caseID":"case-1"}`)
+		r := httptest.NewRequest(http.MethodPost, "/api/"+endpoint, body)
+		r.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		authorizer.ServeHTTP(w, r)
+
+		var response struct{ Error string }
+		is.NoErr(json.NewDecoder(w.Body).Decode(&response))
+		return response.Error
+	}
+
+	// Every endpoint that belongs to a case
+	// should reject users outside of the case
+	mutating := []string{"New", "Create", "Update", "Delete", "Process", "Add", "Remove", "KeywordsAdd", "KeywordsRemove", "InvestigatorsAdd", "InvestigatorsRemove", "RoleSet"}
+
+	// Auditors can only see the case, the files and their custody
+	audit := map[string]bool{"CaseService.Get": true, "FileService.List": true, "FileService.Custody": true, "FileService.Verify": true}
+	for _, service := range []interface{}{
+		(*api.CaseService)(nil),
+		(*api.EntityService)(nil),
+		(*api.EventService)(nil),
+		(*api.FileService)(nil),
+		(*api.LinkService)(nil),
+		(*api.PersonService)(nil),
+		(*api.SearchService)(nil),
+		(*api.TokenService)(nil),
+	} {
+		serviceType := reflect.TypeOf(service).Elem()
+		for i := 0; i < serviceType.NumMethod(); i++ {
+			method := serviceType.Method(i)
+			if method.Name == "Authenticate" {
+				continue
+			}
+
+			request := method.Type.In(1)
+			_, hasCaseID := request.FieldByName("CaseID")
+			_, hasID := request.FieldByName("ID")
+			if !hasCaseID && !(serviceType.Name() == "CaseService" && hasID) {
+				continue
+			}
+
+			endpoint := serviceType.Name() + "." + method.Name
+			t.Run(endpoint, func(t *testing.T) {
+				is := is.New(t)
+				is.Equal(call(endpoint, "outsider"), api.ErrNotAllowed.Error())
+
+				for _, name := range mutating {
+					if method.Name == name {
+						is.Equal(call(endpoint, "viewer"), api.ErrNotAllowed.Error())
+					}
+				}
+
+				if audit[endpoint] {
+					is.True(!strings.HasPrefix(call(endpoint, "auditor"), api.ErrNotAllowed.Error()))
+				} else {
+					is.Equal(call(endpoint, "auditor"), api.ErrNotAllowed.Error())
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizerAPIToken(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "editor@test.com"},
+		Roles: []api.Role{
+			{Email: "owner@test.com", Name: api.RoleOwner},
+			{Email: "editor@test.com", Name: api.RoleEditor},
+		},
+	}
+	other := &api.Case{
+		Base:          api.Base{ID: "case-2"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com"},
+	}
+
+	// the tokens are stored with the sha256-hash of the secret
+	hash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+	token := func(id, role, creator string, revokedAt int64) *datastore.Token {
+		return &datastore.Token{Token: api.Token{
+			Base:         api.Base{ID: id},
+			CreatorID:    creator,
+			CreatorEmail: creator + "@test.com",
+			CaseIDs:      []string{caze.ID},
+			Role:         role,
+			RevokedAt:    revokedAt,
+		}}
+	}
+	db := testDB{
+		cases: map[string]*api.Case{caze.ID: caze, other.ID: other},
+		tokens: map[string]*datastore.Token{
+			hash("ti_viewer"):  token("1", api.RoleViewer, "owner", 0),
+			hash("ti_editor"):  token("2", api.RoleEditor, "owner", 0),
+			hash("ti_revoked"): token("3", api.RoleEditor, "owner", 1257894000),
+			hash("ti_former"):  token("4", api.RoleEditor, "editor", 0),
+		},
+	}
+
+	router := otohttp.NewServer()
+	router.Basepath = "/api/"
+	caseService := services.NewCaseService(db, testAuth{})
+	api.RegisterCaseService(router, caseService)
+	api.RegisterEventService(router, services.NewEventService(db, caseService))
+	api.RegisterTokenService(router, services.NewTokenService(db, caseService))
+	authorizer := services.NewAuthorizer(router, caseService)
+
+	call := func(endpoint, caseID, token string) string {
+		body := strings.NewReader(`{"id":"` + caseID + `","caseID":"` + caseID + `"}`)
+		r := httptest.NewRequest(http.MethodPost, "/api/"+endpoint, body)
+		r.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		authorizer.ServeHTTP(w, r)
+
+		var response struct{ Error string }
+		is.NoErr(json.NewDecoder(w.Body).Decode(&response))
+		return response.Error
+	}
+	notAllowed := func(err string) bool { return strings.HasPrefix(err, api.ErrNotAllowed.Error()) }
+
+	// tokens reach the endpoints in their case with their role
+	is.True(!notAllowed(call("EventService.Get", "case-1", "ti_viewer")))
+	is.True(notAllowed(call("EventService.Delete", "case-1", "ti_viewer")))
+	is.True(notAllowed(call("CaseService.Update", "case-1", "ti_editor")))
+
+	// the usage is recorded with the first request, the
+	// next requests are counted until the interval has passed
+	is.Equal(db.tokens[hash("ti_viewer")].UseCount, 1)
+	caseService.TokenUsageInterval = 0
+	is.True(!notAllowed(call("EventService.Get", "case-1", "ti_viewer")))
+	is.Equal(db.tokens[hash("ti_viewer")].UseCount, 3)
+
+	// but not other cases
+	is.True(notAllowed(call("EventService.Get", "case-2", "ti_editor")))
+
+	// or endpoints that doesn't belong to a case
+	is.True(notAllowed(call("CaseService.New", "", "ti_editor")))
+	is.True(notAllowed(call("TokenService.Create", "", "ti_editor")))
+
+	// revoked or unknown tokens
+	is.True(notAllowed(call("EventService.Get", "case-1", "ti_revoked")))
+	is.True(notAllowed(call("EventService.Get", "case-1", "ti_unknown")))
+
+	// tokens from users that no longer own the case
+	is.True(notAllowed(call("EventService.Get", "case-1", "ti_former")))
+}
+
+func TestAuthorizerCaseIDInBody(t *testing.T) {
+	is := is.New(t)
+
+	mine := &api.Case{Base: api.Base{ID: "mine"}, CreatorID: "attacker", Investigators: []string{"attacker@test.com"}}
+	victim := &api.Case{Base: api.Base{ID: "victim"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	db := testDB{cases: map[string]*api.Case{mine.ID: mine, victim.ID: victim}}
+
+	router := otohttp.NewServer()
+	router.Basepath = "/api/"
+	caseService := services.NewCaseService(db, testAuth{})
+	eventService := services.NewEventService(db, caseService)
+	api.RegisterEventService(router, eventService)
+	authorizer := services.NewAuthorizer(router, caseService)
+
+	call := func(body string) string {
+		r := httptest.NewRequest(http.MethodPost, "/api/EventService.Delete", strings.NewReader(body))
+		r.Header.Set("Authorization", "attacker")
+		w := httptest.NewRecorder()
+		authorizer.ServeHTTP(w, r)
+
+		var response struct{ Error string }
+		is.NoErr(json.NewDecoder(w.Body).Decode(&response))
+		return response.Error
+	}
+	notAllowed := func(err string) bool { return strings.HasPrefix(err, api.ErrNotAllowed.Error()) }
+
+	// the router decodes the keys case-insensitively, so the
+	// case is authorized as the router decodes the request
+	is.True(notAllowed(call(`{"caseID":"mine","CaseID":"victim","id":"e1"}`)))
+	is.True(notAllowed(call(`{"CaseID":"victim","id":"e1"}`)))
+	is.True(notAllowed(call(`{"caseid":"victim","id":"e1"}`)))
+	is.True(notAllowed(call(`{"caseID":"mine","caseID":"mine","id":"e1"}`)))
+	is.True(!notAllowed(call(`{"caseID":"mine","id":"e1"}`)))
+
+	// the services check the case too
+	ctx := utils.SetUser(context.Background(), api.User{UID: "attacker", Email: "attacker@test.com"})
+	_, err := eventService.Delete(ctx, api.EventDeleteRequest{CaseID: victim.ID, ID: "e1"})
+	is.Equal(err, api.ErrNotAllowed)
+}
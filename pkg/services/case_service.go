@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/authentication"
@@ -14,13 +17,31 @@ import (
 
 // CaseService handles cases
 type CaseService struct {
-	db   datastore.Service
-	auth authentication.Service
+	// TokenUsageInterval is how often the usage of an
+	// API-token is recorded, the requests in between are
+	// counted and recorded with the next request after it
+	TokenUsageInterval time.Duration
+
+	db     datastore.Service
+	auth   authentication.Service
+	admins map[string]bool
+	usage  *tokenUsage
 }
 
-// NewCaseService creates a new case-service
-func NewCaseService(db datastore.Service, auth authentication.Service) *CaseService {
-	return &CaseService{db: db, auth: auth}
+// NewCaseService creates a new case-service,
+// admins are the emails of the system-administrators
+func NewCaseService(db datastore.Service, auth authentication.Service, admins ...string) *CaseService {
+	s := &CaseService{
+		TokenUsageInterval: time.Minute,
+		db:                 db,
+		auth:               auth,
+		admins:             make(map[string]bool),
+		usage:              &tokenUsage{tokens: make(map[string]*usage)},
+	}
+	for _, email := range admins {
+		s.admins[strings.ToLower(email)] = true
+	}
+	return s
 }
 
 // New creates a new case
@@ -38,6 +59,7 @@ func (s *CaseService) New(ctx context.Context, r api.CaseNewRequest) (*api.CaseN
 		FromDate:      r.FromDate,
 		ToDate:        r.ToDate,
 		Investigators: []string{currentUser.Email},
+		Roles:         []api.Role{{Email: currentUser.Email, Name: api.RoleOwner}},
 	}
 
 	if err := s.db.CreateCase(ctx, &caze); err != nil {
@@ -49,58 +71,107 @@ func (s *CaseService) New(ctx context.Context, r api.CaseNewRequest) (*api.CaseN
 
 // Get returns the requested case
 func (s *CaseService) Get(ctx context.Context, r api.CaseGetRequest) (*api.CaseGetResponse, error) {
+	if err := s.authorize(ctx, r.ID); err != nil {
+		return nil, err
+	}
+
 	caze, err := s.db.GetCase(ctx, r.ID)
 	if err != nil {
 		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
 	}
 
-	if !isAllowed(caze, utils.GetUser(ctx).Email) {
-		return nil, api.ErrNotAllowed
+	// The files are stored in their own index, only the first
+	// page is included in the case, and the rest of them are
+	// listed with the cursor in FileService.List - the page has
+	// the default size and sorting for the list of files
+	page := datastore.Page{Size: defaultPageSize, Sort: fileSortDate, Order: sortAsc}
+	files, total, next, err := s.db.ListFiles(ctx, caze.ID, datastore.FileFilter{}, page)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
+	caze.Files = files
 
-	return &api.CaseGetResponse{Case: *caze}, nil
+	return &api.CaseGetResponse{Case: *caze, FilesTotal: total, FilesNextCursor: next}, nil
 }
 
 // Update updates the specified case
 func (s *CaseService) Update(ctx context.Context, r api.CaseUpdateRequest) (*api.CaseUpdateResponse, error) {
-	return nil, errors.New("Not implemented yet")
+	if err := s.authorize(ctx, r.ID, ownerRoles...); err != nil {
+		return nil, err
+	}
+
+	if r.FromDate > r.ToDate {
+		return nil, api.ErrInvalidDates
+	}
+
+	caze, err := s.db.GetCase(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
+	}
+	if err := checkVersion(&caze.Base, r.Version); err != nil {
+		return nil, err
+	}
+
+	caze.Name = r.Name
+	caze.Description = r.Description
+	caze.FromDate = r.FromDate
+	caze.ToDate = r.ToDate
+
+	if err := s.db.UpdateCase(ctx, caze); err != nil {
+		return nil, updateError(err)
+	}
+
+	return &api.CaseUpdateResponse{Updated: *caze}, nil
 }
 
 // Delete deletes the specified case
 func (s *CaseService) Delete(ctx context.Context, r api.CaseDeleteRequest) (*api.CaseDeleteResponse, error) {
-	return nil, s.db.DeleteCase(ctx, r.ID)
+	if err := s.authorize(ctx, r.ID, ownerRoles...); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.DeleteCase(ctx, r.ID); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+	return &api.CaseDeleteResponse{}, nil
 }
 
 // List the cases for a specified user
 func (s *CaseService) List(ctx context.Context, r api.CaseListRequest) (*api.CaseListResponse, error) {
 	currentUser := utils.GetUser(ctx)
+	email := currentUser.Email
 	if currentUser.UID != r.UserID {
-		// TODO : Check if user is system-admin (?)
-		/*
-			user, err := s.auth.GetUserByID(ctx, r.UserID)
-			if err != nil {
-				return nil, err
-			}
-		*/
-		return nil, api.ErrNotAllowed
+		// Only system-admins can list the cases for other users
+		if !currentUser.Admin {
+			return nil, api.ErrNotAllowed
+		}
+
+		user, err := s.auth.GetUserByID(ctx, r.UserID)
+		if errors.Is(err, authentication.ErrNotSupported) {
+			return nil, api.Error(errors.New("the authentication-provider cannot look up users, list the memberships with AdminService.Users"), api.ErrCannotPerformOperation)
+		} else if err != nil {
+			return nil, api.Error(err, api.ErrNotFound)
+		}
+		email = user.Email
+	}
+
+	page, err := listPage(r.ListOptions, sortCreated, "updated", "name")
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	cases, err := s.db.GetCasesByEmail(ctx, currentUser.Email)
+	cases, total, next, err := s.db.ListCasesByEmail(ctx, email, r.ListOptions.Filters, page)
 	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	return &api.CaseListResponse{Cases: cases}, nil
+	return &api.CaseListResponse{Cases: cases, Total: total, NextCursor: next}, nil
 }
 
 // Keywords lists all the keywords for the case
 func (s *CaseService) Keywords(ctx context.Context, r api.CaseKeywordsRequest) (*api.CaseKeywordsResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.isAllowed(ctx, r.ID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.authorize(ctx, r.ID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	keywords, err := s.db.GetKeywords(ctx, r.ID)
@@ -111,12 +182,155 @@ func (s *CaseService) Keywords(ctx context.Context, r api.CaseKeywordsRequest) (
 	return &api.CaseKeywordsResponse{Keywords: keywords}, nil
 }
 
+// InvestigatorsAdd invites investigators to the case
+func (s *CaseService) InvestigatorsAdd(ctx context.Context, r api.CaseInvestigatorsAddRequest) (*api.CaseInvestigatorsAddResponse, error) {
+	if err := s.authorize(ctx, r.ID, ownerRoles...); err != nil {
+		return nil, err
+	}
+
+	caze, err := s.db.GetCase(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
+	}
+
+	// Investigators are editors by default
+	if r.Role == "" {
+		r.Role = api.RoleEditor
+	}
+	if !api.ValidRole(r.Role) {
+		return nil, api.ErrInvalidRole
+	}
+
+	for _, email := range r.Emails {
+		address, err := mail.ParseAddress(email)
+		if err != nil {
+			return nil, api.Error(fmt.Errorf("invalid email %q: %v", email, err), api.ErrCannotPerformOperation)
+		}
+		if !isAllowed(caze, address.Address) {
+			caze.Investigators = append(caze.Investigators, address.Address)
+			caze.Roles = append(caze.Roles, api.Role{Email: address.Address, Name: r.Role})
+		}
+	}
+
+	if err := s.db.UpdateCase(ctx, caze); err != nil {
+		return nil, updateError(err)
+	}
+
+	return &api.CaseInvestigatorsAddResponse{Updated: *caze}, nil
+}
+
+// InvestigatorsRemove removes investigators from the case
+func (s *CaseService) InvestigatorsRemove(ctx context.Context, r api.CaseInvestigatorsRemoveRequest) (*api.CaseInvestigatorsRemoveResponse, error) {
+	if err := s.authorize(ctx, r.ID, ownerRoles...); err != nil {
+		return nil, err
+	}
+
+	caze, err := s.db.GetCase(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
+	}
+
+	// Make a hash-map of the emails to remove
+	var remove = make(map[string]bool)
+	for _, email := range r.Emails {
+		remove[email] = true
+	}
+
+	var investigators []string
+	for _, investigator := range caze.Investigators {
+		if !remove[investigator] {
+			investigators = append(investigators, investigator)
+		}
+	}
+
+	// A case must always have an investigator
+	if len(investigators) == 0 {
+		return nil, api.ErrLastInvestigator
+	}
+
+	var roles []api.Role
+	for _, role := range caze.Roles {
+		if !remove[role.Email] {
+			roles = append(roles, role)
+		}
+	}
+
+	// A case with owners must keep one
+	if !keepsOwner(caze, utils.GetUser(ctx), investigators, roles) {
+		return nil, api.ErrLastOwner
+	}
+
+	caze.Investigators = investigators
+	caze.Roles = roles
+	if err := s.db.UpdateCase(ctx, caze); err != nil {
+		return nil, updateError(err)
+	}
+
+	return &api.CaseInvestigatorsRemoveResponse{Updated: *caze}, nil
+}
+
+// RoleSet sets the role for an investigator in the case
+func (s *CaseService) RoleSet(ctx context.Context, r api.CaseRoleSetRequest) (*api.CaseRoleSetResponse, error) {
+	if err := s.authorize(ctx, r.ID, ownerRoles...); err != nil {
+		return nil, err
+	}
+
+	caze, err := s.db.GetCase(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("case - %v", api.ErrNotFound)
+	}
+
+	if !api.ValidRole(r.Role) {
+		return nil, api.ErrInvalidRole
+	}
+
+	if !isAllowed(caze, r.Email) {
+		return nil, api.Error(fmt.Errorf("investigator %s", r.Email), api.ErrNotFound)
+	}
+
+	var found bool
+	roles := make([]api.Role, len(caze.Roles))
+	copy(roles, caze.Roles)
+	for i := range roles {
+		if roles[i].Email == r.Email {
+			roles[i].Name = r.Role
+			found = true
+		}
+	}
+	if !found {
+		roles = append(roles, api.Role{Email: r.Email, Name: r.Role})
+	}
+
+	// A case with owners must keep one
+	if !keepsOwner(caze, utils.GetUser(ctx), caze.Investigators, roles) {
+		return nil, api.ErrLastOwner
+	}
+	caze.Roles = roles
+
+	if err := s.db.UpdateCase(ctx, caze); err != nil {
+		return nil, updateError(err)
+	}
+
+	return &api.CaseRoleSetResponse{Updated: *caze}, nil
+}
+
 // Authenticate is a middleware
 // in the http-handler
 //
 // NOTE : Only for Go-servers
 func (s *CaseService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
-	usr, err := s.auth.GetUserByToken(ctx, utils.GetToken(r))
+	// The user has already been authenticated
+	// for the request by the authorizer
+	if utils.GetUser(ctx).UID != "" {
+		return ctx, nil
+	}
+
+	token := utils.GetToken(r)
+	if isAPIToken(token) {
+		return s.authenticateAPIToken(ctx, token)
+	}
+
+	usr, err := s.auth.GetUserByToken(ctx, token)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotAllowed)
 	}
@@ -128,9 +342,23 @@ func (s *CaseService) Authenticate(ctx context.Context, r *http.Request) (contex
 		PhotoURL:    usr.PhotoURL,
 		ProviderID:  usr.ProviderID,
 		UID:         usr.UID,
+		Admin:       s.isAdmin(usr),
 	}), nil
 }
 
+// isAdmin checks if the user is a system-admin,
+// from the config or from the claims of the user
+//
+// NOTE : admins don't have access to the cases
+// unless they are investigators in them
+func (s *CaseService) isAdmin(usr *authentication.User) bool {
+	if usr.EmailVerified && usr.Email != "" && s.admins[strings.ToLower(usr.Email)] {
+		return true
+	}
+	admin, _ := usr.CustomClaims["admin"].(bool)
+	return admin
+}
+
 func isAllowed(caze *api.Case, email string) bool {
 	for _, investigator := range caze.Investigators {
 		if investigator == email {
@@ -140,10 +368,125 @@ func isAllowed(caze *api.Case, email string) bool {
 	return false
 }
 
-func (s *CaseService) isAllowed(ctx context.Context, caseID, email string) (bool, error) {
-	caze, err := s.db.GetCase(ctx, caseID)
-	if err != nil {
-		return false, err
+// owners returns the number of investigators that are owners
+func owners(investigators []string, roles []api.Role) int {
+	var investigator = make(map[string]bool)
+	for _, email := range investigators {
+		investigator[email] = true
+	}
+	var count int
+	for _, role := range roles {
+		if role.Name == api.RoleOwner && investigator[role.Email] {
+			count++
+		}
+	}
+	return count
+}
+
+// roleOf returns the role of the user in the case,
+// or an empty string if the user isn't an investigator
+func roleOf(caze *api.Case, user api.User) string {
+	// the creator loses the access when
+	// removed from the investigators
+	if !isAllowed(caze, user.Email) {
+		return ""
+	}
+	for _, role := range caze.Roles {
+		if role.Email == user.Email {
+			return role.Name
+		}
+	}
+	// the creator is the owner of the cases from before
+	// roles existed, the other investigators are editors
+	if caze.CreatorID != "" && caze.CreatorID == user.UID {
+		return api.RoleOwner
+	}
+	return api.RoleEditor
+}
+
+// keepsOwner checks that a case with an owner still has one
+// after the investigators or roles are changed by the user. The
+// creator is the only owner of the cases from before roles
+// existed, and cannot remove or demote themselves from them
+func keepsOwner(caze *api.Case, user api.User, investigators []string, roles []api.Role) bool {
+	if owners(caze.Investigators, caze.Roles) > 0 {
+		return owners(investigators, roles) > 0
+	}
+	if caze.CreatorID == "" || caze.CreatorID != user.UID {
+		return true
+	}
+	changed := &api.Case{CreatorID: caze.CreatorID, Investigators: investigators, Roles: roles}
+	return roleOf(changed, user) == api.RoleOwner
+}
+
+// hasRole checks if the user is an investigator in the case
+// with one of the roles (or any role if none is specified)
+func hasRole(caze *api.Case, user api.User, roles ...string) bool {
+	role := roleOf(caze, user)
+	if role == "" {
+		return false
+	}
+	if len(roles) == 0 {
+		return true
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVersion checks that the version from a request is the
+// version of the object that was read, the version is optional
+// so the object is updated as it was read if it's empty
+func checkVersion(object *api.Base, version string) error {
+	if version == "" {
+		return nil
+	}
+	if version != object.Version {
+		return api.Error(fmt.Errorf("version %s is not the current version %s", version, object.Version), api.ErrConflict)
+	}
+	return nil
+}
+
+// updateError returns the error for an update that failed,
+// the conflicts are returned as such so the client can
+// get the object again and retry the update
+func updateError(err error) error {
+	if errors.Is(err, datastore.ErrConflict) {
+		return api.Error(err, api.ErrConflict)
+	}
+	return api.Error(err, api.ErrCannotPerformOperation)
+}
+
+// addKeywords returns the keywords with the added ones,
+// the keywords that are there already aren't repeated
+func addKeywords(keywords, add []string) []string {
+	var exists = make(map[string]bool)
+	for _, keyword := range keywords {
+		exists[keyword] = true
+	}
+	for _, keyword := range add {
+		if !exists[keyword] {
+			exists[keyword] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+// removeKeywords returns the keywords without the removed ones
+func removeKeywords(keywords, remove []string) []string {
+	var removed = make(map[string]bool)
+	for _, keyword := range remove {
+		removed[keyword] = true
+	}
+	var kept []string
+	for _, keyword := range keywords {
+		if !removed[keyword] {
+			kept = append(kept, keyword)
+		}
 	}
-	return isAllowed(caze, email), nil
+	return kept
 }
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/matryer/is"
+)
+
+func TestCaseServiceLastOwner(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "editor@test.com"},
+		Roles: []api.Role{
+			{Email: "owner@test.com", Name: api.RoleOwner},
+			{Email: "editor@test.com", Name: api.RoleEditor},
+		},
+	}
+	db := testDB{cases: map[string]*api.Case{caze.ID: caze}}
+
+	caseService := services.NewCaseService(db, testAuth{})
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// The last owner can't be demoted or removed
+	_, err := caseService.RoleSet(ctx, api.CaseRoleSetRequest{ID: caze.ID, Email: "owner@test.com", Role: api.RoleEditor})
+	is.Equal(err, api.ErrLastOwner)
+	_, err = caseService.InvestigatorsRemove(ctx, api.CaseInvestigatorsRemoveRequest{ID: caze.ID, Emails: []string{"owner@test.com"}})
+	is.Equal(err, api.ErrLastOwner)
+	is.Equal(caze.Roles[0].Name, api.RoleOwner)
+	is.Equal(len(caze.Investigators), 2)
+
+	// but when there is another owner
+	_, err = caseService.RoleSet(ctx, api.CaseRoleSetRequest{ID: caze.ID, Email: "editor@test.com", Role: api.RoleOwner})
+	is.NoErr(err)
+	_, err = caseService.RoleSet(ctx, api.CaseRoleSetRequest{ID: caze.ID, Email: "owner@test.com", Role: api.RoleViewer})
+	is.NoErr(err)
+
+	// The creator has the demoted role, and loses
+	// the access to the case when removed
+	_, err = caseService.InvestigatorsRemove(ctx, api.CaseInvestigatorsRemoveRequest{ID: caze.ID, Emails: []string{"editor@test.com"}})
+	is.Equal(err, api.ErrNotAllowed)
+	editorCtx := utils.SetUser(context.Background(), api.User{UID: "editor", Email: "editor@test.com"})
+	_, err = caseService.InvestigatorsRemove(editorCtx, api.CaseInvestigatorsRemoveRequest{ID: caze.ID, Emails: []string{"owner@test.com"}})
+	is.NoErr(err)
+	is.Equal(caze.Investigators, []string{"editor@test.com"})
+	_, err = caseService.Get(ctx, api.CaseGetRequest{ID: caze.ID})
+	is.Equal(err, api.ErrNotAllowed)
+}
+
+func TestCaseServiceLegacyCreator(t *testing.T) {
+	is := is.New(t)
+
+	// The creator is the only owner of
+	// cases from before roles existed
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "editor@test.com"},
+	}
+	db := testDB{cases: map[string]*api.Case{caze.ID: caze}}
+
+	caseService := services.NewCaseService(db, testAuth{})
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	_, err := caseService.RoleSet(ctx, api.CaseRoleSetRequest{ID: caze.ID, Email: "owner@test.com", Role: api.RoleEditor})
+	is.Equal(err, api.ErrLastOwner)
+	_, err = caseService.InvestigatorsRemove(ctx, api.CaseInvestigatorsRemoveRequest{ID: caze.ID, Emails: []string{"owner@test.com"}})
+	is.Equal(err, api.ErrLastOwner)
+
+	// but can make another investigator the owner
+	_, err = caseService.RoleSet(ctx, api.CaseRoleSetRequest{ID: caze.ID, Email: "editor@test.com", Role: api.RoleOwner})
+	is.NoErr(err)
+	_, err = caseService.InvestigatorsRemove(ctx, api.CaseInvestigatorsRemoveRequest{ID: caze.ID, Emails: []string{"owner@test.com"}})
+	is.NoErr(err)
+	is.Equal(caze.Investigators, []string{"editor@test.com"})
+}
+
+func TestCaseServiceGetFiles(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	for i := 0; i < 150; i++ {
+		caze.Files = append(caze.Files, api.File{Base: api.Base{ID: fmt.Sprintf("file-%d", i)}})
+	}
+	db := testDB{cases: map[string]*api.Case{caze.ID: caze}}
+
+	caseService := services.NewCaseService(db, testAuth{})
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// Only the first page of files is included in the case,
+	// with the total and the cursor for FileService.List
+	resp, err := caseService.Get(ctx, api.CaseGetRequest{ID: caze.ID})
+	is.NoErr(err)
+	is.Equal(len(resp.Case.Files), 100)
+	is.Equal(resp.FilesTotal, 150)
+	is.Equal(resp.FilesNextCursor, "100")
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+)
+
+// DownloadHandler streams files from the filestore, with
+// support for range-requests so large files (like videos
+// and PDFs) can be opened and seeked within
+//
+// GET  {basepath}{caseID}/{fileID}              opens the file inline
+// GET  {basepath}{caseID}/{fileID}?download=1   downloads the file as an attachment
+// HEAD {basepath}{caseID}/{fileID}              returns the headers for the file
+type DownloadHandler struct {
+	db          datastore.Service
+	store       filestore.Service
+	caseService *CaseService
+
+	// Basepath is the path the handler is mounted on
+	Basepath string
+
+	// WriteTimeout is how long a write to the client can take,
+	// the deadline is extended for every write, so a download
+	// can take longer than the write-timeout of the server as
+	// long as the client keeps reading
+	WriteTimeout time.Duration
+}
+
+// defaultWriteTimeout is the WriteTimeout for the downloads
+const defaultWriteTimeout = 15 * time.Second
+
+// NewDownloadHandler creates a new download-handler
+func NewDownloadHandler(db datastore.Service, store filestore.Service, caseService *CaseService) *DownloadHandler {
+	return &DownloadHandler{
+		db:           db,
+		store:        store,
+		caseService:  caseService,
+		Basepath:     "/download/",
+		WriteTimeout: defaultWriteTimeout,
+	}
+}
+
+// ServeHTTP streams the requested file
+func (h *DownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		handlerError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, h.Basepath), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		handlerError(w, http.StatusNotFound, api.ErrNotFound)
+		return
+	}
+	caseID, fileID := parts[0], parts[1]
+
+	ctx, err := authorizeCase(r, h.caseService, caseID, viewRoles...)
+	if err != nil {
+		handlerError(w, http.StatusForbidden, err)
+		return
+	}
+
+	file, err := h.db.GetFileByID(ctx, caseID, fileID)
+	if err != nil {
+		handlerError(w, http.StatusNotFound, api.Error(err, api.ErrNotFound))
+		return
+	}
+
+	object, err := h.store.Open(file.Path)
+	if err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	defer object.Close()
+
+	// Only record the first request when the file is
+	// read in ranges, or the chain of custody would
+	// get an event for every seek in a video
+	if rng := r.Header.Get("Range"); r.Method == http.MethodGet && (rng == "" || strings.HasPrefix(rng, "bytes=0-")) {
+		if err := recordCustody(ctx, h.db, caseID, file.ID, custodyOpened, "download"); err != nil {
+			handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+			return
+		}
+	}
+
+	disposition := "inline"
+	if r.URL.Query().Get("download") != "" {
+		disposition = "attachment"
+	}
+
+	// The evidence is untrusted, so it must never be
+	// able to run scripts in the origin of the API
+	w.Header().Set("Content-Type", contentType(file.Mime))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Name}))
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private")
+
+	modified := time.Unix(file.CreatedAt, 0)
+	if file.UpdatedAt != 0 {
+		modified = time.Unix(file.UpdatedAt, 0)
+	}
+
+	// ServeContent handles the range-requests and
+	// only reads the requested parts of the file
+	http.ServeContent(newDeadlineWriter(w, h.WriteTimeout), r, file.Name, modified, object)
+}
+
+// deadlineWriter extends the write-deadline of the connection before
+// every write, so large files aren't cut off by the write-timeout of
+// the server, the connection still times out if the client stalls
+type deadlineWriter struct {
+	http.ResponseWriter
+	controller *http.ResponseController
+	timeout    time.Duration
+}
+
+func newDeadlineWriter(w http.ResponseWriter, timeout time.Duration) *deadlineWriter {
+	return &deadlineWriter{ResponseWriter: w, controller: http.NewResponseController(w), timeout: timeout}
+}
+
+func (w *deadlineWriter) WriteHeader(status int) {
+	w.extend()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *deadlineWriter) Write(p []byte) (int, error) {
+	w.extend()
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the ResponseWriter for the ResponseController
+func (w *deadlineWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// extend extends the deadline, writers that don't support
+// deadlines (like the recorder in the tests) are skipped
+func (w *deadlineWriter) extend() {
+	if w.timeout > 0 {
+		w.controller.SetWriteDeadline(time.Now().Add(w.timeout))
+	}
+}
+
+// contentType returns the mime-type for the file,
+// or a binary type if the mime-type is invalid
+func contentType(mimeType string) string {
+	if _, _, err := mime.ParseMediaType(mimeType); err != nil {
+		return "application/octet-stream"
+	}
+	return mimeType
+}
@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+
+	"github.com/matryer/is"
+)
+
+func TestDownloadHandler(t *testing.T) {
+	is := is.New(t)
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	f, err := store.Upload("case-1", "evidence.html", strings.NewReader("<script>0123456789</script>"))
+	is.NoErr(err)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "viewer@test.com"},
+		Files: []api.File{{
+			Base: api.Base{ID: "file-1", CreatedAt: 1257894000},
+			Name: f.Name,
+			Path: f.Path,
+			Mime: "text/html",
+			Size: f.Size,
+		}},
+	}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+	}
+	handler := services.NewDownloadHandler(db, store, services.NewCaseService(db, testAuth{}))
+
+	get := func(path, token string, headers map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Authorization", token)
+		for key, value := range headers {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/download/case-1/file-1", "viewer", nil)
+	is.Equal(w.Code, http.StatusOK)
+	is.Equal(w.Body.String(), "<script>0123456789</script>")
+	is.Equal(w.Header().Get("Content-Type"), "text/html")
+	is.Equal(w.Header().Get("Content-Disposition"), `inline; filename=evidence.html`)
+	is.Equal(w.Header().Get("Content-Security-Policy"), "sandbox")
+	is.Equal(w.Header().Get("Accept-Ranges"), "bytes")
+
+	// Range-requests only returns the requested bytes
+	w = get("/download/case-1/file-1", "viewer", map[string]string{"Range": "bytes=8-17"})
+	is.Equal(w.Code, http.StatusPartialContent)
+	is.Equal(w.Body.String(), "0123456789")
+	is.Equal(w.Header().Get("Content-Range"), "bytes 8-17/27")
+
+	// Only the first request is recorded in the chain of custody
+	is.Equal(len(db.custody["file-1"]), 1)
+	is.Equal(db.custody["file-1"][0].Action, "opened")
+	is.Equal(db.custody["file-1"][0].UserEmail, "viewer@test.com")
+
+	w = get("/download/case-1/file-1?download=1", "viewer", nil)
+	is.Equal(w.Header().Get("Content-Disposition"), `attachment; filename=evidence.html`)
+
+	is.Equal(get("/download/case-1/file-1", "outsider", nil).Code, http.StatusForbidden)
+	is.Equal(get("/download/case-1/file-2", "viewer", nil).Code, http.StatusNotFound)
+	is.Equal(get("/download/case-1", "viewer", nil).Code, http.StatusNotFound)
+}
+
+func TestDownloadHandlerWriteTimeout(t *testing.T) {
+	is := is.New(t)
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1<<20)
+	f, err := store.Upload("case-1", "evidence.bin", bytes.NewReader(content))
+	is.NoErr(err)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com"},
+		Files:         []api.File{{Base: api.Base{ID: "file-1"}, Name: f.Name, Path: f.Path, Size: f.Size}},
+	}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+	}
+	handler := services.NewDownloadHandler(db, store, services.NewCaseService(db, testAuth{}))
+	handler.WriteTimeout = 300 * time.Millisecond
+
+	// The server has a shorter write-timeout than the download takes
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.WriteTimeout = 300 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	r, err := http.NewRequest(http.MethodGet, server.URL+"/download/case-1/file-1", nil)
+	is.NoErr(err)
+	r.Header.Set("Authorization", "owner")
+	res, err := http.DefaultClient.Do(r)
+	is.NoErr(err)
+	defer res.Body.Close()
+	is.Equal(res.StatusCode, http.StatusOK)
+
+	// Read the file slowly, so the download takes
+	// longer than the write-timeout of the server
+	started := time.Now()
+	var downloaded bytes.Buffer
+	chunk := make([]byte, 256<<10)
+	for {
+		n, err := res.Body.Read(chunk)
+		downloaded.Write(chunk[:n])
+		if err == io.EOF {
+			break
+		}
+		is.NoErr(err)
+		time.Sleep(20 * time.Millisecond)
+	}
+	is.True(time.Since(started) > time.Second)
+	is.Equal(downloaded.Len(), len(content))
+	is.True(bytes.Equal(downloaded.Bytes(), content))
+}
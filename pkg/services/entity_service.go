@@ -6,7 +6,6 @@ import (
 
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
-	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
 )
 
 // EntityService holds the dependencies
@@ -31,12 +30,8 @@ func NewEntityService(db datastore.Service, caseService *CaseService) *EntitySer
 
 // Create creates a new entity
 func (s *EntityService) Create(ctx context.Context, r api.EntityCreateRequest) (*api.EntityCreateResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	if !s.validType(r.Type) {
@@ -59,12 +54,8 @@ func (s *EntityService) Create(ctx context.Context, r api.EntityCreateRequest) (
 
 // Update updates an existing entity
 func (s *EntityService) Update(ctx context.Context, r api.EntityUpdateRequest) (*api.EntityUpdateResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	if !s.validType(r.Type) {
@@ -75,6 +66,9 @@ func (s *EntityService) Update(ctx context.Context, r api.EntityUpdateRequest) (
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
+	if err := checkVersion(&entity.Base, r.Version); err != nil {
+		return nil, err
+	}
 
 	entity.Title = r.Title
 	entity.PhotoURL = r.PhotoURL
@@ -82,7 +76,7 @@ func (s *EntityService) Update(ctx context.Context, r api.EntityUpdateRequest) (
 	entity.Custom = r.Custom
 
 	if err := s.db.UpdateEntity(ctx, r.CaseID, entity); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+		return nil, updateError(err)
 	}
 
 	return &api.EntityUpdateResponse{Updated: *entity}, nil
@@ -90,12 +84,8 @@ func (s *EntityService) Update(ctx context.Context, r api.EntityUpdateRequest) (
 
 // Delete deletes an existing entity
 func (s *EntityService) Delete(ctx context.Context, r api.EntityDeleteRequest) (*api.EntityDeleteResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the entity to delete
@@ -104,12 +94,12 @@ func (s *EntityService) Delete(ctx context.Context, r api.EntityDeleteRequest) (
 		return nil, api.Error(err, api.ErrNotFound)
 	}
 
-	// Delete the keywords for the entity
-	if err := s.removeKeywords(ctx, r.CaseID, entity, entity.Keywords); err != nil {
+	if err := s.db.DeleteEntity(ctx, r.CaseID, entity.ID); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	if err := s.db.DeleteEntity(ctx, r.CaseID, entity.ID); err != nil {
+	// Remove the entity from its keywords when it's deleted
+	if err := s.db.RemoveKeywordIDs(ctx, r.CaseID, datastore.KeywordEntities, entity.ID, entity.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -118,12 +108,8 @@ func (s *EntityService) Delete(ctx context.Context, r api.EntityDeleteRequest) (
 
 // Get the specified entity
 func (s *EntityService) Get(ctx context.Context, r api.EntityGetRequest) (*api.EntityGetResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	entity, err := s.db.GetEntityByID(ctx, r.CaseID, r.ID)
@@ -136,20 +122,21 @@ func (s *EntityService) Get(ctx context.Context, r api.EntityGetRequest) (*api.E
 
 // List all entities
 func (s *EntityService) List(ctx context.Context, r api.EntityListRequest) (*api.EntityListResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
+	}
+
+	page, err := listPage(r.ListOptions, sortCreated, "title", "type")
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	entities, err := s.db.GetEntities(ctx, r.CaseID)
+	entities, total, next, err := s.db.ListEntities(ctx, r.CaseID, r.ListOptions.Filters, page)
 	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	return &api.EntityListResponse{Entities: entities}, nil
+	return &api.EntityListResponse{Entities: entities, Total: total, NextCursor: next}, nil
 }
 
 // Types returns the existing entity-types
@@ -159,12 +146,8 @@ func (s *EntityService) Types(ctx context.Context, r api.EntityTypesRequest) (*a
 
 // KeywordsAdd adds keywords to an entity
 func (s *EntityService) KeywordsAdd(ctx context.Context, r api.KeywordsAddRequest) (*api.KeywordsAddResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the entity to add the keyword to
@@ -172,41 +155,20 @@ func (s *EntityService) KeywordsAdd(ctx context.Context, r api.KeywordsAddReques
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
-
-	// Get the keywords that should be added to the entity
-	keywords, err := s.db.GetKeywordsByIDs(ctx, r.CaseID, r.Keywords)
-	if err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
-	}
-
-	// Create a map of the keywords that were found from the db
-	// and add the Entity ID to each keyword
-	var keywordFound = make(map[string]bool)
-	for i := range keywords {
-		keywordFound[keywords[i].Name] = true
-		keywords[i].EntityIDs = append(keywords[i].EntityIDs, entity.ID)
-	}
-
-	// Add the keywords from the request to the entity
-	// and append the keywords that didn't already exist
-	// to the keyword-slice
-	for _, keyword := range r.Keywords {
-		if !keywordFound[keyword] {
-			keywords = append(keywords, api.Keyword{Name: keyword, EntityIDs: []string{entity.ID}})
-		}
-		entity.Keywords = append(entity.Keywords, keyword)
+	if err := checkVersion(&entity.Base, r.Version); err != nil {
+		return nil, err
 	}
 
-	// Save the keywords with the entity ID
-	// TODO / FIXME: Use bulk-indexer instead
-	for _, keyword := range keywords {
-		if err := s.db.SaveKeyword(ctx, r.CaseID, &keyword); err != nil {
-			return nil, api.Error(err, api.ErrCannotPerformOperation)
-		}
+	// Update the entity before the keywords,
+	// so they aren't changed on a conflict
+	entity.Keywords = addKeywords(entity.Keywords, r.Keywords)
+	if err := s.db.UpdateEntity(ctx, r.CaseID, entity); err != nil {
+		return nil, updateError(err)
 	}
 
-	// Update the entity with the added keywords
-	if err := s.db.UpdateEntity(ctx, r.CaseID, entity); err != nil {
+	// Add the entity ID to the keywords, the keywords
+	// that already have the ID aren't changed
+	if err := s.db.AddKeywordIDs(ctx, r.CaseID, datastore.KeywordEntities, entity.ID, r.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -215,12 +177,8 @@ func (s *EntityService) KeywordsAdd(ctx context.Context, r api.KeywordsAddReques
 
 // KeywordsRemove removes keywords from an entity
 func (s *EntityService) KeywordsRemove(ctx context.Context, r api.KeywordsRemoveRequest) (*api.KeywordsRemoveResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the entity to remove the keywords from
@@ -228,13 +186,19 @@ func (s *EntityService) KeywordsRemove(ctx context.Context, r api.KeywordsRemove
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
-
-	if err := s.removeKeywords(ctx, r.CaseID, entity, r.Keywords); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	if err := checkVersion(&entity.Base, r.Version); err != nil {
+		return nil, err
 	}
 
-	// Update the entity without the removed keyword
+	// Update the entity before the keywords,
+	// so they aren't changed on a conflict
+	entity.Keywords = removeKeywords(entity.Keywords, r.Keywords)
 	if err := s.db.UpdateEntity(ctx, r.CaseID, entity); err != nil {
+		return nil, updateError(err)
+	}
+
+	// Remove the entity ID from the keywords
+	if err := s.db.RemoveKeywordIDs(ctx, r.CaseID, datastore.KeywordEntities, entity.ID, r.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -258,58 +222,3 @@ func (s *EntityService) validType(entityType string) bool {
 	}
 	return false
 }
-
-func (s *EntityService) removeKeywords(ctx context.Context, caseID string, entity *api.Entity, removeKeywords []string) error {
-	// Create a map of the keywords to remove
-	var keywordToRemove = make(map[string]bool)
-	for _, keyword := range removeKeywords {
-		keywordToRemove[keyword] = true
-	}
-
-	// Get the keywords that should be removed from the event
-	keywords, err := s.db.GetKeywordsByIDs(ctx, caseID, removeKeywords)
-	if err != nil {
-		return err
-	}
-
-	// check if all keywords in the event should be removed
-	if len(removeKeywords) == len(entity.Keywords) && len(removeKeywords) == len(keywords) {
-		entity.Keywords = nil
-	}
-
-	for i, keyword := range entity.Keywords {
-		if keywordToRemove[keyword] {
-			entity.Keywords = append(entity.Keywords[:i], entity.Keywords[i+1:]...)
-		}
-	}
-
-	// Remove the EntityID from the keywords
-	for ki, keyword := range keywords {
-		if keywordToRemove[keyword.Name] {
-			for ei, id := range keyword.EntityIDs {
-				if id == entity.ID {
-					keywords[ki].EntityIDs = append(
-						keyword.EntityIDs[:ei],
-						keyword.EntityIDs[ei+1:]...,
-					)
-				}
-			}
-		}
-	}
-
-	// Save the keywords (or delete if empty)
-	// TODO/FIXME: use bulk indexer
-	for _, keyword := range keywords {
-		toDelete := len(keyword.EntityIDs) == 0 && len(keyword.PersonIDs) == 0 && len(keyword.EventIDs) == 0 && len(keyword.FileIDs) == 0
-		if toDelete {
-			if err := s.db.DeleteKeyword(ctx, caseID, keyword.Name); err != nil {
-				return err
-			}
-		} else if !toDelete {
-			if err := s.db.SaveKeyword(ctx, caseID, &keyword); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
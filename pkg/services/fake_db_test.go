@@ -0,0 +1,478 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
+)
+
+// testDB is an in-memory datastore for the service-tests, the
+// state is kept in the maps and pointers so it's shared by the
+// copies of it. It only implements the methods that the services
+// use in the tests, the embedded (nil) Service panics if any other
+// method is called. A test only sets the fields that it uses
+type testDB struct {
+	datastore.Service
+	cases   map[string]*api.Case
+	tokens  map[string]*datastore.Token
+	uploads map[string]*datastore.Upload
+	custody map[string][]api.CustodyEvent
+
+	// keywords has the IDs of the keywords by name
+	keywords map[string][]string
+
+	jobs    *testJobs
+	records *testRecords
+	lists   *[]datastore.Page
+}
+
+// Case-methods
+
+func (db testDB) GetCase(ctx context.Context, id string) (*api.Case, error) {
+	if caze, ok := db.cases[id]; ok {
+		return caze, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (db testDB) GetCases(ctx context.Context) ([]api.Case, error) {
+	var cases []api.Case
+	for _, caze := range db.cases {
+		cases = append(cases, *caze)
+	}
+	return cases, nil
+}
+
+func (db testDB) UpdateCase(ctx context.Context, caze *api.Case) error {
+	db.cases[caze.ID] = caze
+	return nil
+}
+
+// Event-methods
+
+func (db testDB) GetEventByID(ctx context.Context, caseID, eventID string) (*api.Event, error) {
+	if db.records != nil {
+		db.records.mu.Lock()
+		defer db.records.mu.Unlock()
+		if event, ok := db.records.events[eventID]; ok {
+			return &event, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// Token-methods
+
+func (db testDB) GetTokenByHash(ctx context.Context, hash string) (*datastore.Token, error) {
+	if token, ok := db.tokens[hash]; ok {
+		return token, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (db testDB) GetToken(ctx context.Context, id string) (*datastore.Token, error) {
+	for _, token := range db.tokens {
+		if token.ID == id {
+			read := *token
+			return &read, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// UpdateToken only saves the token if it has the
+// version, every change increases the version
+func (db testDB) UpdateToken(ctx context.Context, token *datastore.Token) error {
+	for hash, stored := range db.tokens {
+		if stored.ID != token.ID {
+			continue
+		}
+		if stored.Version != token.Version {
+			return datastore.ErrConflict
+		}
+		token.Version = nextVersion(stored.Version)
+		updated := *token
+		db.tokens[hash] = &updated
+		return nil
+	}
+	return errors.New("not found")
+}
+
+func (db testDB) RecordTokenUsage(ctx context.Context, id string, count int) error {
+	for _, token := range db.tokens {
+		if token.ID == id {
+			token.UseCount += count
+			token.Version = nextVersion(token.Version)
+		}
+	}
+	return nil
+}
+
+func nextVersion(version string) string {
+	n, _ := strconv.Atoi(version)
+	return strconv.Itoa(n + 1)
+}
+
+// File-methods
+
+func (db testDB) CreateFile(ctx context.Context, caseID string, file *api.File) error {
+	if file.ID == "" {
+		file.ID = "file-" + strconv.Itoa(len(db.cases[caseID].Files)+1)
+	}
+	db.cases[caseID].Files = append(db.cases[caseID].Files, *file)
+	return nil
+}
+
+func (db testDB) GetFileByID(ctx context.Context, caseID, fileID string) (*api.File, error) {
+	for _, file := range db.cases[caseID].Files {
+		if file.ID == fileID {
+			return &file, nil
+		}
+	}
+	return nil, errors.New("file not found")
+}
+
+func (db testDB) GetFilesByIDs(ctx context.Context, caseID string, ids []string) ([]api.File, error) {
+	var files []api.File
+	for _, id := range ids {
+		if file, err := db.GetFileByID(ctx, caseID, id); err == nil {
+			files = append(files, *file)
+		}
+	}
+	return files, nil
+}
+
+func (db testDB) UpdateFile(ctx context.Context, caseID string, file *api.File) error {
+	for i := range db.cases[caseID].Files {
+		if db.cases[caseID].Files[i].ID == file.ID {
+			db.cases[caseID].Files[i] = *file
+		}
+	}
+	return nil
+}
+
+// ListFiles filters the files by mime-type, and the cursor
+// is the index of the next file, the datastore is tested
+// against elasticsearch in the integration-tests
+func (db testDB) ListFiles(ctx context.Context, caseID string, filter datastore.FileFilter, page datastore.Page) ([]api.File, int, string, error) {
+	if db.lists != nil {
+		*db.lists = append(*db.lists, page)
+	}
+	var files []api.File
+	for _, file := range db.cases[caseID].Files {
+		if filter.Mime == "" || file.Mime == filter.Mime {
+			files = append(files, file)
+		}
+	}
+
+	from, _ := strconv.Atoi(page.Cursor)
+	to := from + page.Size
+	if to >= len(files) {
+		return files[from:], len(files), "", nil
+	}
+	return files[from:to], len(files), strconv.Itoa(to), nil
+}
+
+func (db testDB) CreateCustodyEvent(ctx context.Context, caseID string, event *api.CustodyEvent) error {
+	db.custody[event.FileID] = append(db.custody[event.FileID], *event)
+	return nil
+}
+
+func (db testDB) GetCustodyEvents(ctx context.Context, caseID, fileID string) ([]api.CustodyEvent, error) {
+	return db.custody[fileID], nil
+}
+
+// Keyword-methods
+
+func (db testDB) AddKeywordIDs(ctx context.Context, caseID, field, id string, names []string) error {
+	for _, name := range names {
+		if !contains(db.keywords[name], field+":"+id) {
+			db.keywords[name] = append(db.keywords[name], field+":"+id)
+		}
+	}
+	return nil
+}
+
+func (db testDB) RemoveKeywordIDs(ctx context.Context, caseID, field, id string, names []string) error {
+	for _, name := range names {
+		var ids []string
+		for _, keywordID := range db.keywords[name] {
+			if keywordID != field+":"+id {
+				ids = append(ids, keywordID)
+			}
+		}
+		db.keywords[name] = ids
+		if len(ids) == 0 {
+			delete(db.keywords, name)
+		}
+	}
+	return nil
+}
+
+// Upload-methods
+
+func (db testDB) CreateUpload(ctx context.Context, upload *datastore.Upload) error {
+	upload.ID = strconv.Itoa(len(db.uploads) + 1)
+	db.uploads[upload.ID] = upload
+	return nil
+}
+
+func (db testDB) UpdateUpload(ctx context.Context, upload *datastore.Upload) error {
+	upload.UpdatedAt = time.Now().Unix()
+	db.uploads[upload.ID] = upload
+	return nil
+}
+
+func (db testDB) GetUpload(ctx context.Context, id string) (*datastore.Upload, error) {
+	if upload, ok := db.uploads[id]; ok {
+		copy := *upload
+		return &copy, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (db testDB) GetCompletedUploads(ctx context.Context, before int64) ([]datastore.Upload, error) {
+	var uploads []datastore.Upload
+	for _, upload := range db.uploads {
+		if upload.FileID != "" && upload.UpdatedAt <= before {
+			uploads = append(uploads, *upload)
+		}
+	}
+	return uploads, nil
+}
+
+func (db testDB) DeleteUpload(ctx context.Context, id string) error {
+	delete(db.uploads, id)
+	return nil
+}
+
+// Process-methods
+
+// testJobs keeps the processing-jobs, they
+// are updated by the workers in the tests
+type testJobs struct {
+	mu        sync.Mutex
+	processes map[string]api.Process
+}
+
+func (db testDB) CreateProcess(ctx context.Context, process *api.Process) error {
+	db.jobs.mu.Lock()
+	defer db.jobs.mu.Unlock()
+	process.ID = fmt.Sprintf("job-%d", len(db.jobs.processes)+1)
+	process.CreatedAt = time.Now().UnixNano()
+	db.jobs.processes[process.ID] = copyProcess(*process)
+	return nil
+}
+
+func (db testDB) UpdateProcess(ctx context.Context, process *api.Process) error {
+	db.jobs.mu.Lock()
+	defer db.jobs.mu.Unlock()
+	db.jobs.processes[process.ID] = copyProcess(*process)
+	return nil
+}
+
+func (db testDB) GetProcess(ctx context.Context, id string) (*api.Process, error) {
+	db.jobs.mu.Lock()
+	defer db.jobs.mu.Unlock()
+	if process, ok := db.jobs.processes[id]; ok {
+		process = copyProcess(process)
+		return &process, nil
+	}
+	return nil, errors.New("process not found")
+}
+
+func (db testDB) GetProcesses(ctx context.Context, caseID string) ([]api.Process, error) {
+	db.jobs.mu.Lock()
+	defer db.jobs.mu.Unlock()
+	var processes []api.Process
+	for _, process := range db.jobs.processes {
+		if process.CaseID == caseID {
+			processes = append(processes, copyProcess(process))
+		}
+	}
+	sort.Slice(processes, func(i, j int) bool { return processes[i].CreatedAt > processes[j].CreatedAt })
+	return processes, nil
+}
+
+func (db testDB) GetProcessesByStatus(ctx context.Context, statuses ...string) ([]api.Process, error) {
+	db.jobs.mu.Lock()
+	defer db.jobs.mu.Unlock()
+	var processes []api.Process
+	for _, process := range db.jobs.processes {
+		for _, status := range statuses {
+			if process.Status == status {
+				processes = append(processes, copyProcess(process))
+			}
+		}
+	}
+	return processes, nil
+}
+
+func (db testDB) ProcessIndex(caseID string) string { return "processes-" + caseID }
+
+func copyProcess(process api.Process) api.Process {
+	process.Files = append([]api.ProcessFile{}, process.Files...)
+	return process
+}
+
+// Record-methods, for the processed documents and the suggestions
+
+// testRecords keeps the persons, events, links,
+// processed documents and suggestions in a case
+type testRecords struct {
+	mu          sync.Mutex
+	persons     map[string]api.Person
+	events      map[string]api.Event
+	links       map[string]api.Link
+	documents   map[string]api.ProcessedDocument
+	suggestions map[string]api.Suggestion
+}
+
+func newTestRecords() *testRecords {
+	return &testRecords{
+		persons:     make(map[string]api.Person),
+		events:      make(map[string]api.Event),
+		links:       make(map[string]api.Link),
+		documents:   make(map[string]api.ProcessedDocument),
+		suggestions: make(map[string]api.Suggestion),
+	}
+}
+
+func (db testDB) IndexDocument(ctx context.Context, index, id string, document interface{}) error {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	db.records.documents[id] = datastore.NewProcessedDocument(id, *document.(*indexer.Processed))
+	return nil
+}
+
+func (db testDB) GetProcessedDocumentsByFile(ctx context.Context, caseID, fileID string) ([]api.ProcessedDocument, error) {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	var documents []api.ProcessedDocument
+	for _, document := range db.records.documents {
+		if document.FileID == fileID {
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+func (db testDB) CreatePerson(ctx context.Context, caseID string, person *api.Person) error {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	if person.ID == "" {
+		person.ID = fmt.Sprintf("person-%d", len(db.records.persons)+1)
+	}
+	db.records.persons[person.ID] = *person
+	return nil
+}
+
+func (db testDB) GetPersons(ctx context.Context, caseID string) ([]api.Person, error) {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	var persons []api.Person
+	for _, person := range db.records.persons {
+		persons = append(persons, person)
+	}
+	return persons, nil
+}
+
+func (db testDB) GetPersonsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Person, error) {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	persons := []api.Person{}
+	for _, id := range ids {
+		if person, ok := db.records.persons[id]; ok {
+			persons = append(persons, person)
+		}
+	}
+	return persons, nil
+}
+
+func (db testDB) CreateEvent(ctx context.Context, caseID string, event *api.Event) error {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("event-%d", len(db.records.events)+1)
+	}
+	db.records.events[event.ID] = *event
+	return nil
+}
+
+func (db testDB) GetEventsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Event, error) {
+	return []api.Event{}, nil
+}
+
+func (db testDB) GetEntitiesByIDs(ctx context.Context, caseID string, ids []string) ([]api.Entity, error) {
+	return []api.Entity{}, nil
+}
+
+func (db testDB) CreateLink(ctx context.Context, caseID string, link *api.Link) error {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	link.ID = fmt.Sprintf("link-%d", len(db.records.links)+1)
+	db.records.links[link.ID] = *link
+	return nil
+}
+
+func (db testDB) GetLinkByID(ctx context.Context, caseID, id string) (*api.Link, error) {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	if link, ok := db.records.links[id]; ok {
+		return &link, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (db testDB) CreateSuggestion(ctx context.Context, caseID string, suggestion *api.Suggestion) error {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	suggestion.CreatedAt = time.Now().Unix()
+	suggestion.Version = nextVersion("")
+	db.records.suggestions[suggestion.ID] = *suggestion
+	return nil
+}
+
+// UpdateSuggestion only saves the suggestion if it
+// has the version, every change increases the version
+func (db testDB) UpdateSuggestion(ctx context.Context, caseID string, suggestion *api.Suggestion) error {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	if db.records.suggestions[suggestion.ID].Version != suggestion.Version {
+		return datastore.ErrConflict
+	}
+	suggestion.Version = nextVersion(suggestion.Version)
+	db.records.suggestions[suggestion.ID] = *suggestion
+	return nil
+}
+
+func (db testDB) GetSuggestionsByIDs(ctx context.Context, caseID string, ids []string) ([]api.Suggestion, error) {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	suggestions := []api.Suggestion{}
+	for _, id := range ids {
+		if suggestion, ok := db.records.suggestions[id]; ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions, nil
+}
+
+func (db testDB) GetSuggestions(ctx context.Context, caseID string) ([]api.Suggestion, error) {
+	db.records.mu.Lock()
+	defer db.records.mu.Unlock()
+	suggestions := []api.Suggestion{}
+	for _, suggestion := range db.records.suggestions {
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool { return suggestions[i].Path < suggestions[j].Path })
+	return suggestions, nil
+}
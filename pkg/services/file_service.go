@@ -3,22 +3,44 @@ package services
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
-	"github.com/avian-digital-forensics/timeline-investigator/pkg/fscrawler"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
 )
 
+// The actions in the chain of custody for a file
+const (
+	custodyUploaded  = "uploaded"
+	custodyOpened    = "opened"
+	custodyProcessed = "processed"
+	custodyUpdated   = "updated"
+	custodyDeleted   = "deleted"
+	custodyVerified  = "verified"
+)
+
+// maxUpdateAttempts is how many times the file is updated
+// after it's processed, if it was changed while it was indexed
+const maxUpdateAttempts = 3
+
+// maxOpenSize is the largest file that is opened with Open, since
+// the file is read into memory and sent base64-encoded in the json
+const maxOpenSize = 10 << 20
+
 // FileService handles files
 type FileService struct {
 	db          datastore.Service
 	store       filestore.Service
 	caseService *CaseService
-	fs          *fscrawler.Client
+	indexer     indexer.Indexer
 }
 
 // NewFileService creates a new file-service
@@ -26,113 +48,130 @@ func NewFileService(
 	db datastore.Service,
 	store filestore.Service,
 	caseService *CaseService,
-	fs *fscrawler.Client,
+	indexer indexer.Indexer,
 ) *FileService {
 	return &FileService{
 		db:          db,
 		store:       store,
 		caseService: caseService,
-		fs:          fs,
+		indexer:     indexer,
 	}
 }
 
 // New uploads a file to the backend
+//
+// NOTE : Only for small files, large files
+// should be uploaded with the UploadHandler
 func (s *FileService) New(ctx context.Context, r api.FileNewRequest) (*api.FileNewResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
-	data, err := base64.StdEncoding.DecodeString(r.Data)
+	data := base64.NewDecoder(base64.StdEncoding, strings.NewReader(r.Data))
+	file, err := s.create(ctx, r.CaseID, "", r.Name, r.Mime, r.Description, data)
 	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	f, err := s.store.Upload(r.CaseID, r.Name, data)
-	if err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
-	}
-
-	file := api.File{
-		Name:        f.Name,
-		Mime:        r.Mime,
-		Description: r.Description,
-		Path:        f.Path,
-		Size:        f.Size,
-		ProcessedAt: 0,
-	}
-
-	if err := s.db.CreateFile(ctx, r.CaseID, &file); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
-	}
-
-	return &api.FileNewResponse{New: file}, nil
+	return &api.FileNewResponse{New: *file}, nil
 }
 
-// Open opens a file from the backend
+// Open opens a file from the backend, the larger
+// files are streamed from the download-handler
 func (s *FileService) Open(ctx context.Context, r api.FileOpenRequest) (*api.FileOpenResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	file, err := s.db.GetFileByID(ctx, r.CaseID, r.ID)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
+	if file.Size > maxOpenSize {
+		return nil, api.Error(fmt.Errorf("the file is larger than %d MB, download it from /download/%s/%s", maxOpenSize>>20, r.CaseID, file.ID), api.ErrCannotPerformOperation)
+	}
 
 	content, err := s.store.GetContent(file.Path)
 	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
+	if err := recordCustody(ctx, s.db, r.CaseID, file.ID, custodyOpened, ""); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
 	return &api.FileOpenResponse{Data: base64.URLEncoding.EncodeToString(content)}, nil
 }
 
 // Process Processs a file from the backend
 func (s *FileService) Process(ctx context.Context, r api.FileProcessRequest) (*api.FileProcessResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
-	file, err := s.db.GetFileByID(ctx, r.CaseID, r.ID)
+	file, err := s.process(ctx, r.CaseID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.FileProcessResponse{Processed: *file}, nil
+}
+
+// process indexes the content of the file with the indexer,
+// it's used by both Process and the processing-jobs
+func (s *FileService) process(ctx context.Context, caseID, fileID string) (*api.File, error) {
+	file, err := s.db.GetFileByID(ctx, caseID, fileID)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
 
+	// The content is read from the filestore,
+	// so it doesn't have to be on the same disk
+	object, err := s.store.Open(file.Path)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+	defer object.Close()
+
 	// Process the file
-	process := s.fs.NewProcess(file.Path).WithID(file.ID).WithIndex(s.db.ProcessIndex(r.CaseID))
-	if err := process.Start(ctx); err != nil {
+	doc := indexer.Document{
+		ID:      file.ID,
+		Index:   s.db.ProcessIndex(caseID),
+		Name:    file.Name,
+		Content: object,
+	}
+	if err := s.indexer.Index(ctx, doc); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	file.ProcessedAt = time.Now().Unix()
+	// The file can be changed while it's indexed, so it's
+	// read again and the update is retried on a conflict
+	processedAt := time.Now().Unix()
+	for attempt := 1; ; attempt++ {
+		file.ProcessedAt = processedAt
+		err := s.db.UpdateFile(ctx, caseID, file)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, datastore.ErrConflict) || attempt == maxUpdateAttempts {
+			return nil, updateError(err)
+		}
+		if file, err = s.db.GetFileByID(ctx, caseID, fileID); err != nil {
+			return nil, api.Error(err, api.ErrNotFound)
+		}
+	}
 
-	if err := s.db.UpdateFile(ctx, r.CaseID, file); err != nil {
+	if err := recordCustody(ctx, s.db, caseID, file.ID, custodyProcessed, ""); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	return &api.FileProcessResponse{Processed: *file}, nil
+	return file, nil
 }
 
 // Processed gets information for a processed file
 func (s *FileService) Processed(ctx context.Context, r api.FileProcessedRequest) (*api.FileProcessedResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	processed, err := s.db.GetProcessedFile(ctx, r.CaseID, r.ID)
@@ -140,17 +179,66 @@ func (s *FileService) Processed(ctx context.Context, r api.FileProcessedRequest)
 		return nil, api.Error(err, api.ErrNotFound)
 	}
 
-	return &api.FileProcessedResponse{ID: r.ID, Processed: processed}, nil
+	return &api.FileProcessedResponse{ID: r.ID, Processed: *processed}, nil
+}
+
+// The fields to sort the files by, and the states to filter them by
+const (
+	fileSortName = "name"
+	fileSortSize = "size"
+	fileSortDate = "date"
+
+	fileProcessed   = "processed"
+	fileUnprocessed = "unprocessed"
+)
+
+// List lists the files in a case, a page at a time
+func (s *FileService) List(ctx context.Context, r api.FileListRequest) (*api.FileListResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID); err != nil {
+		return nil, err
+	}
+
+	if len(r.ListOptions.Filters) > 0 {
+		return nil, api.Error(errors.New("the files are filtered by the fields of the request"), api.ErrCannotPerformOperation)
+	}
+	page, err := listPage(r.ListOptions, fileSortDate, fileSortName, fileSortSize)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	filter := datastore.FileFilter{
+		Mime:         r.Mime,
+		Keyword:      r.Keyword,
+		UploadedFrom: r.UploadedFrom,
+		UploadedTo:   r.UploadedTo,
+		Uploader:     r.Uploader,
+		MinSize:      r.MinSize,
+		MaxSize:      r.MaxSize,
+	}
+	switch r.State {
+	case "":
+	case fileProcessed, fileUnprocessed:
+		processed := r.State == fileProcessed
+		filter.Processed = &processed
+	default:
+		return nil, api.Error(
+			fmt.Errorf("invalid state %q - use %q or %q", r.State, fileProcessed, fileUnprocessed),
+			api.ErrCannotPerformOperation,
+		)
+	}
+
+	files, total, next, err := s.db.ListFiles(ctx, r.CaseID, filter, page)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.FileListResponse{Total: total, Files: files, NextCursor: next}, nil
 }
 
 // Processes gets information for all proccesed files in the specified case
 func (s *FileService) Processes(ctx context.Context, r api.FileProcessesRequest) (*api.FileProcessesResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	processes, err := s.db.GetProcessedFiles(ctx, r.CaseID)
@@ -163,12 +251,8 @@ func (s *FileService) Processes(ctx context.Context, r api.FileProcessesRequest)
 
 // Update updates the information for a file
 func (s *FileService) Update(ctx context.Context, r api.FileUpdateRequest) (*api.FileUpdateResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the file to update
@@ -176,13 +260,21 @@ func (s *FileService) Update(ctx context.Context, r api.FileUpdateRequest) (*api
 	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
+	if err := checkVersion(&file.Base, r.Version); err != nil {
+		return nil, err
+	}
 
 	// Set the description and updatedAt
+	details := fmt.Sprintf("description changed from %q to %q", file.Description, r.Description)
 	file.UpdatedAt = time.Now().Unix()
 	file.Description = r.Description
 
 	// update the file
 	if err := s.db.UpdateFile(ctx, r.CaseID, file); err != nil {
+		return nil, updateError(err)
+	}
+
+	if err := recordCustody(ctx, s.db, r.CaseID, file.ID, custodyUpdated, details); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -191,12 +283,8 @@ func (s *FileService) Update(ctx context.Context, r api.FileUpdateRequest) (*api
 
 // Delete deletes the specified file
 func (s *FileService) Delete(ctx context.Context, r api.FileDeleteRequest) (*api.FileDeleteResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	file, err := s.db.GetFileByID(ctx, r.CaseID, r.ID)
@@ -204,8 +292,9 @@ func (s *FileService) Delete(ctx context.Context, r api.FileDeleteRequest) (*api
 		return nil, api.Error(err, api.ErrNotFound)
 	}
 
-	// Delete the keywords for the file
-	if err := s.removeKeywords(ctx, r.CaseID, file, file.Keywords); err != nil {
+	// The deletion is recorded first, the file
+	// must not be deleted without a trace
+	if err := recordCustody(ctx, s.db, r.CaseID, file.ID, custodyDeleted, file.Name); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -213,7 +302,23 @@ func (s *FileService) Delete(ctx context.Context, r api.FileDeleteRequest) (*api
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	if err := s.store.Delete(r.CaseID, file.Name); err != nil {
+	// Remove the file from its keywords when it's deleted
+	if err := s.db.RemoveKeywordIDs(ctx, r.CaseID, datastore.KeywordFiles, file.ID, file.Keywords); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	// Files uploaded before the files were stored by ID can
+	// share the same path, so the content is only deleted
+	// if no other file in the case is using it
+	shared, err := s.db.GetFilesByPath(ctx, r.CaseID, file.Path)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+	if len(shared) > 0 {
+		return &api.FileDeleteResponse{}, nil
+	}
+
+	if err := s.store.Delete(file.Path); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -222,12 +327,8 @@ func (s *FileService) Delete(ctx context.Context, r api.FileDeleteRequest) (*api
 
 // KeywordsAdd adds keywords to a file
 func (s *FileService) KeywordsAdd(ctx context.Context, r api.KeywordsAddRequest) (*api.KeywordsAddResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the file to add the keyword to
@@ -235,74 +336,128 @@ func (s *FileService) KeywordsAdd(ctx context.Context, r api.KeywordsAddRequest)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
+	if err := checkVersion(&file.Base, r.Version); err != nil {
+		return nil, err
+	}
 
-	// Get the keywords that should be added to the file
-	keywords, err := s.db.GetKeywordsByIDs(ctx, r.CaseID, r.Keywords)
-	if err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	// Update the file before the keywords,
+	// so they aren't changed on a conflict
+	file.Keywords = addKeywords(file.Keywords, r.Keywords)
+	if err := s.db.UpdateFile(ctx, r.CaseID, file); err != nil {
+		return nil, updateError(err)
 	}
 
-	// Create a map of the keywords that were found from the db
-	// and add the File ID to each keyword
-	var keywordFound = make(map[string]bool)
-	for i := range keywords {
-		keywordFound[keywords[i].Name] = true
-		keywords[i].FileIDs = append(keywords[i].FileIDs, file.ID)
+	// Add the file ID to the keywords, the keywords
+	// that already have the ID aren't changed
+	if err := s.db.AddKeywordIDs(ctx, r.CaseID, datastore.KeywordFiles, file.ID, r.Keywords); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	// Add the keywords from the request to the file
-	// and append the keywords that didn't already exist
-	// to the keyword-slice
-	for _, keyword := range r.Keywords {
-		if !keywordFound[keyword] {
-			keywords = append(keywords, api.Keyword{Name: keyword, FileIDs: []string{file.ID}})
-		}
-		file.Keywords = append(file.Keywords, keyword)
+	return &api.KeywordsAddResponse{OK: true}, nil
+}
+
+// KeywordsRemove removes keywords from a file
+func (s *FileService) KeywordsRemove(ctx context.Context, r api.KeywordsRemoveRequest) (*api.KeywordsRemoveResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
-	// Save the keywords with the File ID
-	// TODO / FIXME: Use bulk-indexer instead
-	for _, keyword := range keywords {
-		if err := s.db.SaveKeyword(ctx, r.CaseID, &keyword); err != nil {
-			return nil, api.Error(err, api.ErrCannotPerformOperation)
-		}
+	// Get the file to remove the keywords from
+	file, err := s.db.GetFileByID(ctx, r.CaseID, r.ID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrNotFound)
+	}
+	if err := checkVersion(&file.Base, r.Version); err != nil {
+		return nil, err
 	}
 
-	// Update the file with the added keywords
+	// Update the file before the keywords,
+	// so they aren't changed on a conflict
+	file.Keywords = removeKeywords(file.Keywords, r.Keywords)
 	if err := s.db.UpdateFile(ctx, r.CaseID, file); err != nil {
+		return nil, updateError(err)
+	}
+
+	// Remove the file ID from the keywords
+	if err := s.db.RemoveKeywordIDs(ctx, r.CaseID, datastore.KeywordFiles, file.ID, r.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	return &api.KeywordsAddResponse{OK: true}, nil
+	return &api.KeywordsRemoveResponse{}, nil
 }
 
-// KeywordsRemove removes keywords from a file
-func (s *FileService) KeywordsRemove(ctx context.Context, r api.KeywordsRemoveRequest) (*api.KeywordsRemoveResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+// Custody gets the chain of custody for a file
+func (s *FileService) Custody(ctx context.Context, r api.FileCustodyRequest) (*api.FileCustodyResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID); err != nil {
+		return nil, err
 	}
 
-	// Get the file to remove the keywords from
 	file, err := s.db.GetFileByID(ctx, r.CaseID, r.ID)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
 
-	// Delete the requested keywords for the file
-	if err := s.removeKeywords(ctx, r.CaseID, file, r.Keywords); err != nil {
+	events, err := s.db.GetCustodyEvents(ctx, r.CaseID, file.ID)
+	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	// Update the file without the removed keyword
-	if err := s.db.UpdateFile(ctx, r.CaseID, file); err != nil {
+	return &api.FileCustodyResponse{Events: events}, nil
+}
+
+// Verify re-computes the hashes for the file in the
+// filestore and compares them with the stored hashes
+func (s *FileService) Verify(ctx context.Context, r api.FileVerifyRequest) (*api.FileVerifyResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID); err != nil {
+		return nil, err
+	}
+
+	file, err := s.db.GetFileByID(ctx, r.CaseID, r.ID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrNotFound)
+	}
+
+	// Files uploaded before the hashes were
+	// computed cannot be verified
+	if file.SHA256 == "" {
+		return nil, api.Error(errors.New("the file has no stored hashes"), api.ErrCannotPerformOperation)
+	}
+
+	object, err := s.store.Open(file.Path)
+	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
+	defer object.Close()
 
-	return &api.KeywordsRemoveResponse{}, nil
+	hashes, _, err := filestore.Hash(object)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	var mismatches []string
+	if hashes.MD5 != file.MD5 {
+		mismatches = append(mismatches, "md5")
+	}
+	if hashes.SHA1 != file.SHA1 {
+		mismatches = append(mismatches, "sha1")
+	}
+	if hashes.SHA256 != file.SHA256 {
+		mismatches = append(mismatches, "sha256")
+	}
+
+	details := "hashes match"
+	if len(mismatches) > 0 {
+		details = "hashes mismatch: " + strings.Join(mismatches, ", ")
+	}
+	if err := recordCustody(ctx, s.db, r.CaseID, file.ID, custodyVerified, details); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.FileVerifyResponse{
+		Verified:   len(mismatches) == 0,
+		Mismatches: mismatches,
+		File:       *file,
+	}, nil
 }
 
 // Authenticate is a middleware
@@ -313,57 +468,60 @@ func (s *FileService) Authenticate(ctx context.Context, r *http.Request) (contex
 	return s.caseService.Authenticate(ctx, r)
 }
 
-func (s *FileService) removeKeywords(ctx context.Context, caseID string, file *api.File, removeKeywords []string) error {
-	// Create a map of the keywords to remove
-	var keywordToRemove = make(map[string]bool)
-	for _, keyword := range removeKeywords {
-		keywordToRemove[keyword] = true
-	}
-
-	// Get the keywords that should be removed from the file
-	keywords, err := s.db.GetKeywordsByIDs(ctx, caseID, removeKeywords)
+// create streams the content to the filestore and creates the
+// file in the case, with the ID if it's set or with a new ID
+func (s *FileService) create(ctx context.Context, caseID, id, name, mime, description string, content io.Reader) (*api.File, error) {
+	f, err := s.store.Upload(caseID, name, content)
 	if err != nil {
-		return err
-	}
-
-	// check if all keywords in the file should be removed
-	if len(removeKeywords) == len(file.Keywords) && len(removeKeywords) == len(keywords) {
-		file.Keywords = nil
+		return nil, err
 	}
 
-	for i, keyword := range file.Keywords {
-		if keywordToRemove[keyword] {
-			file.Keywords = append(file.Keywords[:i], file.Keywords[i+1:]...)
+	user := utils.GetUser(ctx)
+	file := api.File{
+		Base:          api.Base{ID: id},
+		Name:          f.Name,
+		Mime:          mime,
+		Description:   description,
+		Path:          f.Path,
+		Size:          f.Size,
+		ProcessedAt:   0,
+		MD5:           f.MD5,
+		SHA1:          f.SHA1,
+		SHA256:        f.SHA256,
+		UploaderID:    user.UID,
+		UploaderEmail: user.Email,
+	}
+
+	// The stored file isn't in the case if it
+	// couldn't be saved, so it's deleted again
+	if err := s.db.CreateFile(ctx, caseID, &file); err != nil {
+		if deleteErr := s.store.Delete(f.Path); deleteErr != nil {
+			return nil, fmt.Errorf("%v (the stored file %s couldn't be deleted: %v)", err, f.Path, deleteErr)
 		}
+		return nil, err
 	}
 
-	// Remove the FileID from the keywords
-	for ki, keyword := range keywords {
-		if keywordToRemove[keyword.Name] {
-			for ei, id := range keyword.FileIDs {
-				if id == file.ID {
-					keywords[ki].FileIDs = append(
-						keyword.FileIDs[:ei],
-						keyword.FileIDs[ei+1:]...,
-					)
-				}
-			}
-		}
+	if err := recordCustody(ctx, s.db, caseID, file.ID, custodyUploaded, "sha256 "+file.SHA256); err != nil {
+		return nil, err
 	}
 
-	// Save the keywords (or delete if empty)
-	// TODO/FIXME: use bulk indexer
-	for _, keyword := range keywords {
-		toDelete := len(keyword.EntityIDs) == 0 && len(keyword.PersonIDs) == 0 && len(keyword.EventIDs) == 0 && len(keyword.FileIDs) == 0
-		if toDelete {
-			if err := s.db.DeleteKeyword(ctx, caseID, keyword.Name); err != nil {
-				return err
-			}
-		} else if !toDelete {
-			if err := s.db.SaveKeyword(ctx, caseID, &keyword); err != nil {
-				return err
-			}
-		}
+	return &file, nil
+}
+
+// recordCustody adds an event for the
+// current user to the chain of custody
+func recordCustody(ctx context.Context, db datastore.Service, caseID, fileID, action, details string) error {
+	user := utils.GetUser(ctx)
+	event := api.CustodyEvent{
+		FileID:    fileID,
+		Action:    action,
+		UserID:    user.UID,
+		UserEmail: user.Email,
+		UserName:  user.DisplayName,
+		Details:   details,
+	}
+	if err := db.CreateCustodyEvent(ctx, caseID, &event); err != nil {
+		return fmt.Errorf("cannot record %s in the chain of custody: %v", action, err)
 	}
 	return nil
 }
@@ -0,0 +1,268 @@
+package services_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/matryer/is"
+)
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFileServiceList(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+		lists:   new([]datastore.Page),
+	}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	caseService := services.NewCaseService(db, testAuth{})
+	fileService := services.NewFileService(db, store, caseService, nil)
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.jpg"} {
+		mime := "text/plain"
+		if strings.HasSuffix(name, ".jpg") {
+			mime = "image/jpeg"
+		}
+		_, err := fileService.New(ctx, api.FileNewRequest{
+			CaseID: caze.ID,
+			Name:   name,
+			Mime:   mime,
+			Data:   base64.StdEncoding.EncodeToString([]byte(name)),
+		})
+		is.NoErr(err)
+	}
+	is.Equal(caze.Files[0].UploaderID, "owner")
+	is.Equal(caze.Files[0].UploaderEmail, "owner@test.com")
+
+	// Follow the cursor to the last page
+	list, err := fileService.List(ctx, api.FileListRequest{CaseID: caze.ID, ListOptions: api.ListOptions{PageSize: 2}, Mime: "text/plain"})
+	is.NoErr(err)
+	is.Equal(list.Total, 3)
+	is.Equal(len(list.Files), 2)
+	is.True(list.NextCursor != "")
+	is.Equal((*db.lists)[0], datastore.Page{Size: 2, Sort: "date", Order: "asc"}) // the defaults
+
+	list, err = fileService.List(ctx, api.FileListRequest{CaseID: caze.ID, ListOptions: api.ListOptions{PageSize: 2, Cursor: list.NextCursor}, Mime: "text/plain"})
+	is.NoErr(err)
+	is.Equal(len(list.Files), 1)
+	is.Equal(list.Files[0].Name, "c.txt")
+	is.Equal(list.NextCursor, "")
+
+	for _, invalid := range []api.FileListRequest{
+		{CaseID: caze.ID, ListOptions: api.ListOptions{PageSize: 5000}},
+		{CaseID: caze.ID, ListOptions: api.ListOptions{SortBy: "mime"}},
+		{CaseID: caze.ID, ListOptions: api.ListOptions{SortOrder: "up"}},
+		{CaseID: caze.ID, ListOptions: api.ListOptions{Filters: map[string]string{"mime": "text/plain"}}},
+		{CaseID: caze.ID, State: "deleted"},
+	} {
+		_, err = fileService.List(ctx, invalid)
+		is.True(err != nil)
+	}
+
+	// The case only has the first page of files
+	gotten, err := caseService.Get(ctx, api.CaseGetRequest{ID: caze.ID})
+	is.NoErr(err)
+	is.Equal(len(gotten.Case.Files), 4)
+}
+
+// failingDB fails to save the files
+type failingDB struct {
+	testDB
+}
+
+func (failingDB) CreateFile(ctx context.Context, caseID string, file *api.File) error {
+	return errors.New("cannot save file")
+}
+
+func TestFileServiceNewFailure(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	db := failingDB{testDB{cases: map[string]*api.Case{caze.ID: caze}}}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	fileService := services.NewFileService(db, store, services.NewCaseService(db, testAuth{}), nil)
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// The stored file is deleted when it couldn't be saved in the case
+	_, err = fileService.New(ctx, api.FileNewRequest{
+		CaseID: caze.ID,
+		Name:   "a.txt",
+		Mime:   "text/plain",
+		Data:   base64.StdEncoding.EncodeToString([]byte("a")),
+	})
+	is.True(err != nil)
+	var stored []string
+	is.NoErr(filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			stored = append(stored, path)
+		}
+		return err
+	}))
+	is.Equal(len(stored), 0)
+}
+
+func TestFileServiceVerify(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+	}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	fileService := services.NewFileService(db, store, services.NewCaseService(db, testAuth{}), nil)
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	created, err := fileService.New(ctx, api.FileNewRequest{
+		CaseID: caze.ID,
+		Name:   "evidence.txt",
+		Mime:   "text/plain",
+		Data:   base64.StdEncoding.EncodeToString([]byte("The quick brown fox jumps over the lazy dog")),
+	})
+	is.NoErr(err)
+	is.Equal(created.New.MD5, "9e107d9d372bb6826bd81d3542a419d6")
+	is.Equal(created.New.SHA1, "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12")
+	is.Equal(created.New.SHA256, "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592")
+
+	verified, err := fileService.Verify(ctx, api.FileVerifyRequest{CaseID: caze.ID, ID: created.New.ID})
+	is.NoErr(err)
+	is.True(verified.Verified)
+
+	// Tamper with the evidence
+	is.NoErr(os.WriteFile(created.New.Path, []byte("The quick brown fox jumps over the lazy cog"), 0644))
+
+	verified, err = fileService.Verify(ctx, api.FileVerifyRequest{CaseID: caze.ID, ID: created.New.ID})
+	is.NoErr(err)
+	is.True(!verified.Verified)
+	is.Equal(verified.Mismatches, []string{"md5", "sha1", "sha256"})
+
+	custody, err := fileService.Custody(ctx, api.FileCustodyRequest{CaseID: caze.ID, ID: created.New.ID})
+	is.NoErr(err)
+	is.Equal(len(custody.Events), 3)
+	is.Equal(custody.Events[0].Action, "uploaded")
+	is.Equal(custody.Events[0].UserEmail, "owner@test.com")
+	is.Equal(custody.Events[1].Details, "hashes match")
+	is.Equal(custody.Events[2].Details, "hashes mismatch: md5, sha1, sha256")
+}
+
+func TestFileServiceUpdateVersion(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	caze.Files = []api.File{{Base: api.Base{ID: "file-1", Version: "2-1"}, Name: "evidence.txt"}}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+	}
+
+	fileService := services.NewFileService(db, nil, services.NewCaseService(db, testAuth{}), nil)
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// the file has been changed since version 1-1
+	_, err := fileService.Update(ctx, api.FileUpdateRequest{CaseID: caze.ID, ID: "file-1", Description: "stale", Version: "1-1"})
+	is.True(err != nil)
+	is.True(strings.HasPrefix(err.Error(), api.ErrConflict.Error()))
+	is.Equal(caze.Files[0].Description, "")
+
+	updated, err := fileService.Update(ctx, api.FileUpdateRequest{CaseID: caze.ID, ID: "file-1", Description: "current", Version: "2-1"})
+	is.NoErr(err)
+	is.Equal(updated.Updated.Description, "current")
+
+	// the version is optional
+	_, err = fileService.Update(ctx, api.FileUpdateRequest{CaseID: caze.ID, ID: "file-1", Description: "unchecked"})
+	is.NoErr(err)
+	is.Equal(caze.Files[0].Description, "unchecked")
+}
+
+func TestFileServiceKeywords(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	caze.Files = []api.File{{Base: api.Base{ID: "file-1", Version: "2-1"}, Name: "evidence.txt"}}
+	db := testDB{
+		cases:    map[string]*api.Case{caze.ID: caze},
+		custody:  make(map[string][]api.CustodyEvent),
+		keywords: make(map[string][]string),
+	}
+
+	fileService := services.NewFileService(db, nil, services.NewCaseService(db, testAuth{}), nil)
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// the keywords aren't changed if the file has been changed
+	_, err := fileService.KeywordsAdd(ctx, api.KeywordsAddRequest{CaseID: caze.ID, ID: "file-1", Keywords: []string{"a"}, Version: "1-1"})
+	is.True(strings.HasPrefix(err.Error(), api.ErrConflict.Error()))
+	is.Equal(len(db.keywords), 0)
+
+	_, err = fileService.KeywordsAdd(ctx, api.KeywordsAddRequest{CaseID: caze.ID, ID: "file-1", Keywords: []string{"a", "b"}, Version: "2-1"})
+	is.NoErr(err)
+	_, err = fileService.KeywordsAdd(ctx, api.KeywordsAddRequest{CaseID: caze.ID, ID: "file-1", Keywords: []string{"a"}})
+	is.NoErr(err)
+	is.Equal(caze.Files[0].Keywords, []string{"a", "b"})
+	is.Equal(db.keywords, map[string][]string{"a": {"fileIDs:file-1"}, "b": {"fileIDs:file-1"}})
+
+	_, err = fileService.KeywordsRemove(ctx, api.KeywordsRemoveRequest{CaseID: caze.ID, ID: "file-1", Keywords: []string{"a"}})
+	is.NoErr(err)
+	is.Equal(caze.Files[0].Keywords, []string{"b"})
+	is.Equal(db.keywords, map[string][]string{"b": {"fileIDs:file-1"}})
+}
+
+func TestFileServiceOpenLarge(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	caze.Files = []api.File{{Base: api.Base{ID: "file-1"}, Name: "disk.img", Size: 11 << 20}}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+	}
+
+	fileService := services.NewFileService(db, nil, services.NewCaseService(db, testAuth{}), nil)
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// Large files are downloaded instead of read into memory
+	_, err := fileService.Open(ctx, api.FileOpenRequest{CaseID: caze.ID, ID: "file-1"})
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "/download/case-1/file-1"))
+	is.Equal(len(db.custody["file-1"]), 0)
+}
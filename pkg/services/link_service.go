@@ -25,6 +25,10 @@ func NewLinkService(db datastore.Service, caseService *CaseService) *LinkService
 // Create creates a link for an object
 // with multiple objects
 func (s *LinkService) Create(ctx context.Context, r api.LinkCreateRequest) (*api.LinkCreateResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
 	if _, err := s.db.GetLinkByID(ctx, r.CaseID, r.FromID); err == nil {
 		return nil, api.Error(errors.New("link already exists"), api.ErrCannotPerformOperation)
 	}
@@ -96,6 +100,10 @@ func (s *LinkService) Create(ctx context.Context, r api.LinkCreateRequest) (*api
 
 // Get gets an  with its links
 func (s *LinkService) Get(ctx context.Context, r api.LinkGetRequest) (*api.LinkGetResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
+	}
+
 	link, err := s.db.GetLinkByID(ctx, r.CaseID, r.ID)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
@@ -106,6 +114,10 @@ func (s *LinkService) Get(ctx context.Context, r api.LinkGetRequest) (*api.LinkG
 
 // Delete deletes all links to the specified object
 func (s *LinkService) Delete(ctx context.Context, r api.LinkDeleteRequest) (*api.LinkDeleteResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
 	if err := s.db.DeleteLink(ctx, r.CaseID, r.ID); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
@@ -114,10 +126,17 @@ func (s *LinkService) Delete(ctx context.Context, r api.LinkDeleteRequest) (*api
 
 // Add adds links for the specified object
 func (s *LinkService) Add(ctx context.Context, r api.LinkAddRequest) (*api.LinkAddResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
 	link, err := s.db.GetLinkByID(ctx, r.CaseID, r.ID)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
+	if err := checkVersion(&link.Base, r.Version); err != nil {
+		return nil, err
+	}
 
 	// create maps for the existing linked objects
 	var eventMap = make(map[string]bool)
@@ -188,7 +207,7 @@ func (s *LinkService) Add(ctx context.Context, r api.LinkAddRequest) (*api.LinkA
 
 	// Update the link
 	if err := s.db.UpdateLink(ctx, r.CaseID, link); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+		return nil, updateError(err)
 	}
 
 	return &api.LinkAddResponse{AddedLinks: *link}, nil
@@ -196,10 +215,17 @@ func (s *LinkService) Add(ctx context.Context, r api.LinkAddRequest) (*api.LinkA
 
 // Remove removes links for the specified object
 func (s *LinkService) Remove(ctx context.Context, r api.LinkRemoveRequest) (*api.LinkRemoveResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
 	link, err := s.db.GetLinkByID(ctx, r.CaseID, r.ID)
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
+	if err := checkVersion(&link.Base, r.Version); err != nil {
+		return nil, err
+	}
 
 	// create maps for the IDs that should be removed
 	var eventMap = make(map[string]bool)
@@ -242,7 +268,7 @@ func (s *LinkService) Remove(ctx context.Context, r api.LinkRemoveRequest) (*api
 	}
 	// Update the link
 	if err := s.db.UpdateLink(ctx, r.CaseID, link); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+		return nil, updateError(err)
 	}
 
 	return &api.LinkRemoveResponse{RemovedLinks: *link}, nil
@@ -6,7 +6,6 @@ import (
 
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
-	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
 )
 
 // PersonService holds dependencies
@@ -23,12 +22,8 @@ func NewPersonService(db datastore.Service, caseService *CaseService) *PersonSer
 
 // Create creates a new Person
 func (s *PersonService) Create(ctx context.Context, r api.PersonCreateRequest) (*api.PersonCreateResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	person := api.Person{
@@ -50,40 +45,39 @@ func (s *PersonService) Create(ctx context.Context, r api.PersonCreateRequest) (
 
 // Update updates an existing Person
 func (s *PersonService) Update(ctx context.Context, r api.PersonUpdateRequest) (*api.PersonUpdateResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
-	person := api.Person{
-		FirstName:     r.FirstName,
-		LastName:      r.LastName,
-		EmailAddress:  r.EmailAddress,
-		PostalAddress: r.PostalAddress,
-		WorkAddress:   r.WorkAddress,
-		TelephoneNo:   r.TelephoneNo,
-		Custom:        r.Custom,
+	// The person is read before it's updated,
+	// so the keywords aren't overwritten
+	person, err := s.db.GetPersonByID(ctx, r.CaseID, r.ID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrNotFound)
+	}
+	if err := checkVersion(&person.Base, r.Version); err != nil {
+		return nil, err
 	}
-	person.ID = r.ID
 
-	if err := s.db.UpdatePerson(ctx, r.CaseID, &person); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	person.FirstName = r.FirstName
+	person.LastName = r.LastName
+	person.EmailAddress = r.EmailAddress
+	person.PostalAddress = r.PostalAddress
+	person.WorkAddress = r.WorkAddress
+	person.TelephoneNo = r.TelephoneNo
+	person.Custom = r.Custom
+
+	if err := s.db.UpdatePerson(ctx, r.CaseID, person); err != nil {
+		return nil, updateError(err)
 	}
 
-	return &api.PersonUpdateResponse{Updated: person}, nil
+	return &api.PersonUpdateResponse{Updated: *person}, nil
 }
 
 // Delete deletes an existing Person
 func (s *PersonService) Delete(ctx context.Context, r api.PersonDeleteRequest) (*api.PersonDeleteResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the person to delete
@@ -92,11 +86,12 @@ func (s *PersonService) Delete(ctx context.Context, r api.PersonDeleteRequest) (
 		return nil, api.Error(err, api.ErrNotFound)
 	}
 
-	if err := s.removeKeywords(ctx, r.CaseID, person, person.Keywords); err != nil {
+	if err := s.db.DeletePerson(ctx, r.CaseID, person.ID); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	if err := s.db.DeletePerson(ctx, r.CaseID, person.ID); err != nil {
+	// Remove the person from its keywords when it's deleted
+	if err := s.db.RemoveKeywordIDs(ctx, r.CaseID, datastore.KeywordPersons, person.ID, person.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -105,12 +100,8 @@ func (s *PersonService) Delete(ctx context.Context, r api.PersonDeleteRequest) (
 
 // Get the specified Person
 func (s *PersonService) Get(ctx context.Context, r api.PersonGetRequest) (*api.PersonGetResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	person, err := s.db.GetPersonByID(ctx, r.CaseID, r.ID)
@@ -123,30 +114,27 @@ func (s *PersonService) Get(ctx context.Context, r api.PersonGetRequest) (*api.P
 
 // List all entities
 func (s *PersonService) List(ctx context.Context, r api.PersonListRequest) (*api.PersonListResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
+	}
+
+	page, err := listPage(r.ListOptions, sortCreated, "firstName", "lastName", "emailAddress")
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	persons, err := s.db.GetPersons(ctx, r.CaseID)
+	persons, total, next, err := s.db.ListPersons(ctx, r.CaseID, r.ListOptions.Filters, page)
 	if err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
-	return &api.PersonListResponse{Persons: persons}, nil
+	return &api.PersonListResponse{Persons: persons, Total: total, NextCursor: next}, nil
 }
 
 // KeywordsAdd adds keywords to a person
 func (s *PersonService) KeywordsAdd(ctx context.Context, r api.KeywordsAddRequest) (*api.KeywordsAddResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the person to add the keyword to
@@ -154,41 +142,20 @@ func (s *PersonService) KeywordsAdd(ctx context.Context, r api.KeywordsAddReques
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
-
-	// Get the keywords that should be added to the Person
-	keywords, err := s.db.GetKeywordsByIDs(ctx, r.CaseID, r.Keywords)
-	if err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
-	}
-
-	// Create a map of the keywords that were found from the db
-	// and add the Person ID to each keyword
-	var keywordFound = make(map[string]bool)
-	for i := range keywords {
-		keywordFound[keywords[i].Name] = true
-		keywords[i].PersonIDs = append(keywords[i].PersonIDs, person.ID)
-	}
-
-	// Add the keywords from the request to the Person
-	// and append the keywords that didn't already exist
-	// to the keyword-slice
-	for _, keyword := range r.Keywords {
-		if !keywordFound[keyword] {
-			keywords = append(keywords, api.Keyword{Name: keyword, PersonIDs: []string{person.ID}})
-		}
-		person.Keywords = append(person.Keywords, keyword)
+	if err := checkVersion(&person.Base, r.Version); err != nil {
+		return nil, err
 	}
 
-	// Save the keywords with the person ID
-	// TODO / FIXME: Use bulk-indexer instead
-	for _, keyword := range keywords {
-		if err := s.db.SaveKeyword(ctx, r.CaseID, &keyword); err != nil {
-			return nil, api.Error(err, api.ErrCannotPerformOperation)
-		}
+	// Update the person before the keywords,
+	// so they aren't changed on a conflict
+	person.Keywords = addKeywords(person.Keywords, r.Keywords)
+	if err := s.db.UpdatePerson(ctx, r.CaseID, person); err != nil {
+		return nil, updateError(err)
 	}
 
-	// Update the person with the added keywords
-	if err := s.db.UpdatePerson(ctx, r.CaseID, person); err != nil {
+	// Add the person ID to the keywords, the keywords
+	// that already have the ID aren't changed
+	if err := s.db.AddKeywordIDs(ctx, r.CaseID, datastore.KeywordPersons, person.ID, r.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -197,12 +164,8 @@ func (s *PersonService) KeywordsAdd(ctx context.Context, r api.KeywordsAddReques
 
 // KeywordsRemove removes keywords from a Person
 func (s *PersonService) KeywordsRemove(ctx context.Context, r api.KeywordsRemoveRequest) (*api.KeywordsRemoveResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
 	}
 
 	// Get the Person to remove the keywords from
@@ -210,13 +173,19 @@ func (s *PersonService) KeywordsRemove(ctx context.Context, r api.KeywordsRemove
 	if err != nil {
 		return nil, api.Error(err, api.ErrNotFound)
 	}
-
-	if err := s.removeKeywords(ctx, r.CaseID, person, r.Keywords); err != nil {
-		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	if err := checkVersion(&person.Base, r.Version); err != nil {
+		return nil, err
 	}
 
-	// Update the person without the removed keyword
+	// Update the person before the keywords,
+	// so they aren't changed on a conflict
+	person.Keywords = removeKeywords(person.Keywords, r.Keywords)
 	if err := s.db.UpdatePerson(ctx, r.CaseID, person); err != nil {
+		return nil, updateError(err)
+	}
+
+	// Remove the person ID from the keywords
+	if err := s.db.RemoveKeywordIDs(ctx, r.CaseID, datastore.KeywordPersons, person.ID, r.Keywords); err != nil {
 		return nil, api.Error(err, api.ErrCannotPerformOperation)
 	}
 
@@ -230,58 +199,3 @@ func (s *PersonService) KeywordsRemove(ctx context.Context, r api.KeywordsRemove
 func (s *PersonService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
 	return s.caseService.Authenticate(ctx, r)
 }
-
-func (s *PersonService) removeKeywords(ctx context.Context, caseID string, person *api.Person, removeKeywords []string) error {
-	// Create a map of the keywords to remove
-	var keywordToRemove = make(map[string]bool)
-	for _, keyword := range removeKeywords {
-		keywordToRemove[keyword] = true
-	}
-
-	// Get the keywords that should be removed from the person
-	keywords, err := s.db.GetKeywordsByIDs(ctx, caseID, removeKeywords)
-	if err != nil {
-		return err
-	}
-
-	// check if all keywords in the person should be removed
-	if len(removeKeywords) == len(person.Keywords) && len(removeKeywords) == len(keywords) {
-		person.Keywords = nil
-	}
-
-	for i, keyword := range person.Keywords {
-		if keywordToRemove[keyword] {
-			person.Keywords = append(person.Keywords[:i], person.Keywords[i+1:]...)
-		}
-	}
-
-	// Remove the PersonID from the keywords
-	for ki, keyword := range keywords {
-		if keywordToRemove[keyword.Name] {
-			for ei, id := range keyword.PersonIDs {
-				if id == person.ID {
-					keywords[ki].PersonIDs = append(
-						keyword.PersonIDs[:ei],
-						keyword.PersonIDs[ei+1:]...,
-					)
-				}
-			}
-		}
-	}
-
-	// Save the keywords (or delete if empty)
-	// TODO/FIXME: use bulk indexer
-	for _, keyword := range keywords {
-		toDelete := len(keyword.EntityIDs) == 0 && len(keyword.PersonIDs) == 0 && len(keyword.EventIDs) == 0 && len(keyword.FileIDs) == 0
-		if toDelete {
-			if err := s.db.DeleteKeyword(ctx, caseID, keyword.Name); err != nil {
-				return err
-			}
-		} else if !toDelete {
-			if err := s.db.SaveKeyword(ctx, caseID, &keyword); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
@@ -0,0 +1,541 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+)
+
+// The statuses for processing-jobs and their files
+const (
+	processQueued    = "queued"
+	processRunning   = "running"
+	processRetrying  = "retrying"
+	processPaused    = "paused"
+	processAborted   = "aborted"
+	processCompleted = "completed"
+	processFailed    = "failed"
+)
+
+// ProcessService handles processing-jobs, the jobs are
+// persisted in the datastore and processed in the
+// background by a pool of workers
+//
+// NOTE : The jobs are only controlled by the workers in
+// this instance, so only one instance should run the workers
+type ProcessService struct {
+	db                datastore.Service
+	fileService       *FileService
+	caseService       *CaseService
+	suggestionService *SuggestionService
+	workers           int
+	queue             chan string
+
+	// running holds the jobs that
+	// are processed by a worker
+	mu      sync.Mutex
+	running map[string]*job
+
+	// MaxAttempts is the number of times
+	// a file is processed before it fails
+	MaxAttempts int
+
+	// Backoff is the time to wait before the first retry,
+	// it's doubled for every attempt up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// job is a processing-job that a worker is processing
+type job struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	control string       // paused or aborted when the job is stopped
+	process *api.Process // the job when the worker is done
+}
+
+// NewProcessService creates a new process-service
+func NewProcessService(
+	db datastore.Service,
+	fileService *FileService,
+	caseService *CaseService,
+	suggestionService *SuggestionService,
+	workers int,
+) *ProcessService {
+	if workers < 1 {
+		workers = 1
+	}
+	return &ProcessService{
+		db:                db,
+		fileService:       fileService,
+		caseService:       caseService,
+		suggestionService: suggestionService,
+		workers:           workers,
+		queue:             make(chan string),
+		running:           make(map[string]*job),
+		MaxAttempts:       3,
+		Backoff:           10 * time.Second,
+		MaxBackoff:        10 * time.Minute,
+	}
+}
+
+// Run starts the workers and resumes the jobs that were
+// queued or running when the API was stopped, it blocks
+// until the context is done
+func (s *ProcessService) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	processes, err := s.db.GetProcessesByStatus(ctx, processQueued, processRunning)
+	if err != nil {
+		log.Printf("processing-jobs: cannot resume jobs: %v", err)
+	}
+	for _, process := range processes {
+		s.enqueue(ctx, process.ID, 0)
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// Start starts a new processing-job for files in a case
+func (s *ProcessService) Start(ctx context.Context, r api.ProcessStartRequest) (*api.ProcessStartResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
+	if len(r.FileIDs) == 0 {
+		return nil, api.Error(errors.New("no files to process"), api.ErrCannotPerformOperation)
+	}
+
+	// Remove the duplicates
+	var fileIDs []string
+	var seen = make(map[string]bool)
+	for _, id := range r.FileIDs {
+		if !seen[id] {
+			seen[id] = true
+			fileIDs = append(fileIDs, id)
+		}
+	}
+
+	files, err := s.db.GetFilesByIDs(ctx, r.CaseID, fileIDs)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+	if len(files) != len(fileIDs) {
+		return nil, api.Error(errors.New("cannot find all the files in the case"), api.ErrNotFound)
+	}
+
+	user := utils.GetUser(ctx)
+	process := api.Process{
+		CaseID:       r.CaseID,
+		CreatorID:    user.UID,
+		CreatorEmail: user.Email,
+		Status:       processQueued,
+	}
+	for _, id := range fileIDs {
+		process.Files = append(process.Files, api.ProcessFile{FileID: id, Status: processQueued})
+	}
+
+	if err := s.db.CreateProcess(ctx, &process); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	s.enqueue(context.Background(), process.ID, 0)
+	return &api.ProcessStartResponse{Started: process}, nil
+}
+
+// Jobs gets the processing-jobs for a case
+func (s *ProcessService) Jobs(ctx context.Context, r api.ProcessJobsRequest) (*api.ProcessJobsResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
+	}
+
+	processes, err := s.db.GetProcesses(ctx, r.CaseID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrNotFound)
+	}
+
+	return &api.ProcessJobsResponse{Processes: processes}, nil
+}
+
+// Abort aborts a processing-job,
+// the files that aren't processed are skipped
+func (s *ProcessService) Abort(ctx context.Context, r api.ProcessAbortRequest) (*api.ProcessAbortResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
+	process, err := s.stop(ctx, r.CaseID, r.ID, processAborted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ProcessAbortResponse{Aborted: *process}, nil
+}
+
+// Pause pauses a processing-job
+func (s *ProcessService) Pause(ctx context.Context, r api.ProcessPauseRequest) (*api.ProcessPauseResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
+	process, err := s.stop(ctx, r.CaseID, r.ID, processPaused)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ProcessPauseResponse{Paused: *process}, nil
+}
+
+// Resume resumes a paused processing-job
+func (s *ProcessService) Resume(ctx context.Context, r api.ProcessResumeRequest) (*api.ProcessResumeResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	process, err := s.getProcess(ctx, r.CaseID, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if process.Status != processPaused {
+		return nil, api.Error(errors.New("the job isn't paused"), api.ErrCannotPerformOperation)
+	}
+
+	process.Status = processQueued
+	for i := range process.Files {
+		if process.Files[i].Status == processPaused {
+			process.Files[i].Status = processQueued
+			process.Files[i].NextAttemptAt = 0
+		}
+	}
+
+	if err := s.db.UpdateProcess(ctx, process); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	s.enqueue(context.Background(), process.ID, 0)
+	return &api.ProcessResumeResponse{Resumed: *process}, nil
+}
+
+// Authenticate is a middleware
+// in the http-handler
+//
+// NOTE : Only for Go-servers
+func (s *ProcessService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	return s.caseService.Authenticate(ctx, r)
+}
+
+// stop pauses or aborts the job, a job that is processed
+// by a worker is stopped by the worker so they don't
+// both update the job at the same time
+func (s *ProcessService) stop(ctx context.Context, caseID, id, status string) (*api.Process, error) {
+	s.mu.Lock()
+	process, err := s.getProcess(ctx, caseID, id)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if finished(process.Status) || process.Status == status {
+		s.mu.Unlock()
+		return nil, api.Error(errors.New("the job is already "+process.Status), api.ErrCannotPerformOperation)
+	}
+
+	if j, ok := s.running[id]; ok {
+		j.control = status
+		j.cancel()
+		s.mu.Unlock()
+		<-j.done
+		return j.process, nil
+	}
+	defer s.mu.Unlock()
+
+	stopFiles(process, status)
+	if err := s.db.UpdateProcess(ctx, process); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return process, nil
+}
+
+// getProcess gets the job and
+// checks that it belongs to the case
+func (s *ProcessService) getProcess(ctx context.Context, caseID, id string) (*api.Process, error) {
+	process, err := s.db.GetProcess(ctx, id)
+	if err != nil {
+		return nil, api.Error(err, api.ErrNotFound)
+	}
+	if process.CaseID != caseID {
+		return nil, api.Error(errors.New("the job doesn't belong to the case"), api.ErrNotFound)
+	}
+	return process, nil
+}
+
+// enqueue queues the job for the
+// workers after the delay
+func (s *ProcessService) enqueue(ctx context.Context, id string, delay time.Duration) {
+	go func() {
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case s.queue <- id:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// work processes the queued jobs
+// until the context is done
+func (s *ProcessService) work(ctx context.Context) {
+	for {
+		select {
+		case id := <-s.queue:
+			if err := s.runJob(ctx, id); err != nil {
+				log.Printf("processing-job %s: %v", id, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runJob processes the files in the job that are ready to
+// be processed, and queues the job again if any file is
+// waiting to be retried
+func (s *ProcessService) runJob(ctx context.Context, id string) error {
+	s.mu.Lock()
+	if _, ok := s.running[id]; ok {
+		s.mu.Unlock()
+		return nil
+	}
+	process, err := s.db.GetProcess(ctx, id)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	if process.Status != processQueued && process.Status != processRunning {
+		s.mu.Unlock()
+		return nil
+	}
+
+	// The files are processed as the user
+	// who started the job, for the custody
+	jobCtx, cancel := context.WithCancel(utils.SetUser(ctx, api.User{
+		UID:   process.CreatorID,
+		Email: process.CreatorEmail,
+	}))
+	j := &job{cancel: cancel, done: make(chan struct{})}
+	s.running[id] = j
+	s.mu.Unlock()
+
+	defer func() {
+		cancel()
+		s.mu.Lock()
+		delete(s.running, id)
+		j.process = process
+		s.mu.Unlock()
+		close(j.done)
+	}()
+
+	// Files that were running when the API was stopped
+	// are processed again, without counting the attempt
+	for i, file := range process.Files {
+		if file.Status == processRunning {
+			process.Files[i].Status = processQueued
+			process.Files[i].Attempts--
+		}
+	}
+
+	process.Status = processRunning
+	if process.StartedAt == 0 {
+		process.StartedAt = time.Now().Unix()
+	}
+	if err := s.save(j, process); err != nil {
+		return err
+	}
+
+	for i := range process.Files {
+		file := &process.Files[i]
+		if !ready(file) {
+			continue
+		}
+
+		file.Status = processRunning
+		file.Attempts++
+		file.StartedAt = time.Now().Unix()
+		file.FinishedAt = 0
+		if err := s.save(j, process); err != nil {
+			return err
+		}
+
+		// The suggestions are created from the
+		// processed documents for the file
+		_, err := s.fileService.process(jobCtx, process.CaseID, file.FileID)
+		if err == nil {
+			err = s.suggestionService.suggest(jobCtx, process.CaseID, file.FileID)
+		}
+		file.FinishedAt = time.Now().Unix()
+		if jobCtx.Err() != nil {
+			break
+		}
+
+		file.Error = ""
+		file.NextAttemptAt = 0
+		switch {
+		case err == nil:
+			file.Status = processCompleted
+		case file.Attempts < s.MaxAttempts:
+			file.Status = processRetrying
+			file.Error = err.Error()
+			file.NextAttemptAt = time.Now().Add(s.backoff(file.Attempts)).Unix()
+		default:
+			file.Status = processFailed
+			file.Error = err.Error()
+		}
+		if err := s.save(j, process); err != nil {
+			return err
+		}
+	}
+
+	// The job was stopped, or the API is
+	// stopping and the job is resumed later
+	if jobCtx.Err() != nil {
+		s.mu.Lock()
+		control := j.control
+		s.mu.Unlock()
+		if control == "" {
+			return nil
+		}
+
+		for i, file := range process.Files {
+			if file.Status == processRunning {
+				process.Files[i].Status = processQueued
+				process.Files[i].Attempts--
+			}
+		}
+		stopFiles(process, control)
+		setProgress(process)
+		return s.db.UpdateProcess(context.Background(), process)
+	}
+
+	// Queue the job again for the next retry
+	var next int64
+	for _, file := range process.Files {
+		if file.Status == processRetrying && (next == 0 || file.NextAttemptAt < next) {
+			next = file.NextAttemptAt
+		}
+	}
+	if next != 0 {
+		process.Status = processQueued
+		if err := s.save(j, process); err != nil {
+			return err
+		}
+		s.enqueue(ctx, id, time.Until(time.Unix(next, 0)))
+		return nil
+	}
+
+	process.Status = processCompleted
+	for _, file := range process.Files {
+		if file.Status == processFailed {
+			process.Status = processFailed
+		}
+	}
+	process.FinishedAt = time.Now().Unix()
+	return s.save(j, process)
+}
+
+// save updates the progress and saves the job, the job
+// isn't saved if it has been stopped while it's processed
+func (s *ProcessService) save(j *job, process *api.Process) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if j.control != "" {
+		return nil
+	}
+
+	// The job is saved even if the API is stopping
+	setProgress(process)
+	return s.db.UpdateProcess(context.Background(), process)
+}
+
+// backoff returns the time to wait
+// before the next attempt
+func (s *ProcessService) backoff(attempts int) time.Duration {
+	backoff := s.Backoff
+	for i := 1; i < attempts && backoff < s.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > s.MaxBackoff {
+		return s.MaxBackoff
+	}
+	return backoff
+}
+
+// stopFiles sets the status for the job, and
+// for the files that aren't processed yet
+func stopFiles(process *api.Process, status string) {
+	process.Status = status
+	for i, file := range process.Files {
+		if !finished(file.Status) {
+			process.Files[i].Status = status
+			process.Files[i].NextAttemptAt = 0
+		}
+	}
+	if status == processAborted {
+		process.FinishedAt = time.Now().Unix()
+	}
+}
+
+// setProgress sets the percentage of
+// the files in the job that are finished
+func setProgress(process *api.Process) {
+	var done int
+	for _, file := range process.Files {
+		if finished(file.Status) {
+			done++
+		}
+	}
+	if len(process.Files) > 0 {
+		process.Progress = done * 100 / len(process.Files)
+	}
+}
+
+// ready returns true if the file should be processed
+func ready(file *api.ProcessFile) bool {
+	switch file.Status {
+	case processQueued:
+		return true
+	case processRetrying:
+		return file.NextAttemptAt <= time.Now().Unix()
+	}
+	return false
+}
+
+// finished returns true if the status is final
+func finished(status string) bool {
+	return status == processCompleted || status == processFailed || status == processAborted
+}
@@ -0,0 +1,213 @@
+package services_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/fscrawler"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/matryer/is"
+)
+
+// testCrawler is a fake fscrawler that fails the first
+// upload for a file in fail, and blocks the uploads
+// for a file in block until it's released
+type testCrawler struct {
+	mu       sync.Mutex
+	uploads  map[string]int
+	fail     map[string]bool
+	block    map[string]chan struct{}
+	received chan string
+}
+
+func (c *testCrawler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	id := r.FormValue("id")
+
+	c.mu.Lock()
+	c.uploads[id]++
+	fail := c.fail[id] && c.uploads[id] == 1
+	block := c.block[id]
+	c.mu.Unlock()
+
+	if block != nil {
+		select {
+		case c.received <- id:
+		default:
+		}
+		select {
+		case <-block:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"ok":false}`))
+		return
+	}
+	w.Write([]byte(`{"ok":true}`))
+}
+
+func TestProcessService(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+		jobs:    &testJobs{processes: make(map[string]api.Process)},
+		records: newTestRecords(),
+	}
+
+	crawler := &testCrawler{
+		uploads:  make(map[string]int),
+		fail:     map[string]bool{"file-2": true},
+		block:    map[string]chan struct{}{"file-3": make(chan struct{})},
+		received: make(chan string, 1),
+	}
+	server := httptest.NewServer(crawler)
+	defer server.Close()
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	caseService := services.NewCaseService(db, testAuth{})
+	fileService := services.NewFileService(db, store, caseService, indexer.NewFSCrawler(fscrawler.New(server.URL)))
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+	for i := 0; i < 3; i++ {
+		_, err := fileService.New(ctx, api.FileNewRequest{
+			CaseID: caze.ID,
+			Name:   "evidence.txt",
+			Data:   base64.StdEncoding.EncodeToString([]byte("evidence")),
+		})
+		is.NoErr(err)
+	}
+
+	suggestionService := services.NewSuggestionService(db, caseService, services.NewLinkService(db, caseService))
+	processService := services.NewProcessService(db, fileService, caseService, suggestionService, 1)
+	processService.Backoff = time.Millisecond
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go processService.Run(runCtx)
+
+	// wait waits for the job to get the status
+	wait := func(id, status string) api.Process {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			process, err := db.GetProcess(ctx, id)
+			is.NoErr(err)
+			if process.Status == status {
+				return *process
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("job %s never got the status %s", id, status)
+		return api.Process{}
+	}
+
+	// Files that fail are retried
+	started, err := processService.Start(ctx, api.ProcessStartRequest{CaseID: caze.ID, FileIDs: []string{"file-1", "file-2", "file-1"}})
+	is.NoErr(err)
+	is.Equal(started.Started.Status, "queued")
+	is.Equal(started.Started.CreatorEmail, "owner@test.com")
+	is.Equal(len(started.Started.Files), 2)
+
+	process := wait(started.Started.ID, "completed")
+	is.Equal(process.Progress, 100)
+	is.True(process.FinishedAt != 0)
+	is.Equal(process.Files[0].Attempts, 1)
+	is.Equal(process.Files[1].Attempts, 2)
+	is.Equal(process.Files[1].Status, "completed")
+	is.Equal(process.Files[1].Error, "")
+
+	custody := db.custody["file-2"]
+	is.Equal(custody[len(custody)-1].Action, "processed")
+	is.Equal(custody[len(custody)-1].UserEmail, "owner@test.com")
+
+	_, err = processService.Start(ctx, api.ProcessStartRequest{CaseID: caze.ID, FileIDs: []string{"file-4"}})
+	is.True(err != nil)
+
+	// A running job can be paused and resumed
+	started, err = processService.Start(ctx, api.ProcessStartRequest{CaseID: caze.ID, FileIDs: []string{"file-3"}})
+	is.NoErr(err)
+	<-crawler.received
+
+	paused, err := processService.Pause(ctx, api.ProcessPauseRequest{CaseID: caze.ID, ID: started.Started.ID})
+	is.NoErr(err)
+	is.Equal(paused.Paused.Status, "paused")
+	is.Equal(paused.Paused.Files[0].Status, "paused")
+	is.Equal(paused.Paused.Files[0].Attempts, 0)
+
+	_, err = processService.Resume(ctx, api.ProcessResumeRequest{CaseID: "case-2", ID: started.Started.ID})
+	is.True(err != nil)
+	resumed, err := processService.Resume(ctx, api.ProcessResumeRequest{CaseID: caze.ID, ID: started.Started.ID})
+	is.NoErr(err)
+	is.Equal(resumed.Resumed.Status, "queued")
+	<-crawler.received
+	close(crawler.block["file-3"])
+	process = wait(started.Started.ID, "completed")
+	is.Equal(process.Files[0].Attempts, 1)
+
+	// Finished jobs cannot be aborted
+	started, err = processService.Start(ctx, api.ProcessStartRequest{CaseID: caze.ID, FileIDs: []string{"file-1"}})
+	is.NoErr(err)
+	wait(started.Started.ID, "completed")
+	_, err = processService.Abort(ctx, api.ProcessAbortRequest{CaseID: caze.ID, ID: started.Started.ID})
+	is.True(err != nil)
+
+	jobs, err := processService.Jobs(ctx, api.ProcessJobsRequest{CaseID: caze.ID})
+	is.NoErr(err)
+	is.Equal(len(jobs.Processes), 3)
+	is.Equal(jobs.Processes[0].ID, started.Started.ID)
+	stop()
+
+	// Jobs that were running when the API was stopped are resumed
+	interrupted := api.Process{
+		CaseID: caze.ID,
+		Status: "running",
+		Files: []api.ProcessFile{
+			{FileID: "file-1", Status: "completed", Attempts: 1},
+			{FileID: "file-2", Status: "running", Attempts: 1},
+			{FileID: "file-3", Status: "queued"},
+		},
+	}
+	is.NoErr(db.CreateProcess(ctx, &interrupted))
+	queued := api.Process{CaseID: caze.ID, Status: "queued", Files: []api.ProcessFile{{FileID: "file-1", Status: "queued"}}}
+	is.NoErr(db.CreateProcess(ctx, &queued))
+
+	aborted, err := processService.Abort(ctx, api.ProcessAbortRequest{CaseID: caze.ID, ID: queued.ID})
+	is.NoErr(err)
+	is.Equal(aborted.Aborted.Files[0].Status, "aborted")
+
+	runCtx, stop = context.WithCancel(context.Background())
+	defer stop()
+	go services.NewProcessService(db, fileService, caseService, suggestionService, 2).Run(runCtx)
+
+	process = wait(interrupted.ID, "completed")
+	is.Equal(process.Files[0].Attempts, 1)
+	is.Equal(process.Files[1].Attempts, 1)
+	is.Equal(process.Files[2].Attempts, 1)
+
+	process = wait(queued.ID, "aborted")
+	is.Equal(process.Files[0].Attempts, 0)
+}
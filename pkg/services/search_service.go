@@ -3,13 +3,60 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
 	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
-	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
 )
 
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+
+	sortAsc  = "asc"
+	sortDesc = "desc"
+
+	sortCreated = "created"
+)
+
+// listPage validates the list-options and returns the page to get,
+// the first of the sort-fields is the default. The filters are
+// validated by the datastore, which knows the fields for the items
+func listPage(options api.ListOptions, sortFields ...string) (datastore.Page, error) {
+	if options.PageSize < 1 {
+		options.PageSize = defaultPageSize
+	}
+	if options.PageSize > maxPageSize {
+		return datastore.Page{}, fmt.Errorf("page-size cannot be larger than %d", maxPageSize)
+	}
+
+	if options.SortBy == "" {
+		options.SortBy = sortFields[0]
+	}
+	valid := false
+	for _, field := range sortFields {
+		valid = valid || options.SortBy == field
+	}
+	if !valid {
+		return datastore.Page{}, fmt.Errorf("invalid sort-field %q - use one of %q", options.SortBy, sortFields)
+	}
+
+	if options.SortOrder == "" {
+		options.SortOrder = sortAsc
+	}
+	if options.SortOrder != sortAsc && options.SortOrder != sortDesc {
+		return datastore.Page{}, fmt.Errorf("invalid sort-order %q - use %q or %q", options.SortOrder, sortAsc, sortDesc)
+	}
+
+	return datastore.Page{
+		Size:   options.PageSize,
+		Sort:   options.SortBy,
+		Order:  options.SortOrder,
+		Cursor: options.Cursor,
+	}, nil
+}
+
 // SearchService holds the dependencies
 // for the search-service
 type SearchService struct {
@@ -27,17 +74,77 @@ func NewSearchService(db datastore.Service, caseService *CaseService) *SearchSer
 
 // SearchWithTimespan returns events from the selected timespan
 func (s *SearchService) SearchWithTimespan(ctx context.Context, r api.SearchTimespanRequest) (*api.SearchTimespanResponse, error) {
-	return nil, errors.New("not implemented yet")
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
+	}
+
+	// Check that the timespan is valid
+	if r.FromDate > r.ToDate {
+		return nil, api.ErrInvalidDates
+	}
+
+	// The events are sorted by their fromDate
+	if len(r.ListOptions.Filters) > 0 {
+		return nil, api.Error(errors.New("the events in a timespan cannot be filtered"), api.ErrCannotPerformOperation)
+	}
+	page, err := listPage(r.ListOptions, "date")
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	// Get the events that overlaps the timespan
+	events, total, next, err := s.db.SearchEventsByTimespan(ctx, r.CaseID, r.FromDate, r.ToDate, page)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	// The files are only returned with the first page of events,
+	// so they aren't repeated while the cursor is followed
+	if !r.IncludeFiles || r.ListOptions.Cursor != "" {
+		return &api.SearchTimespanResponse{Total: total, Events: events, NextCursor: next}, nil
+	}
+
+	// Get the files that has extracted
+	// document-dates within the timespan
+	fileIDs, err := s.db.GetProcessedFileIDsByTimespan(ctx, r.CaseID, r.FromDate, r.ToDate)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	// Add the files that were processed
+	// within the timespan as well
+	files, err := s.db.GetFilesProcessedWithin(ctx, r.CaseID, r.FromDate, r.ToDate)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	// Get the files with document-dates
+	// that weren't processed in the timespan
+	var found = make(map[string]bool)
+	for _, file := range files {
+		found[file.ID] = true
+	}
+	var dated []string
+	for _, id := range fileIDs {
+		if !found[id] {
+			found[id] = true
+			dated = append(dated, id)
+		}
+	}
+
+	datedFiles, err := s.db.GetFilesByIDs(ctx, r.CaseID, dated)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+	files = append(files, datedFiles...)
+
+	return &api.SearchTimespanResponse{Total: total, Events: events, NextCursor: next, Files: files}, nil
 }
 
 // SearchWithText returns data in the case that is related to the text
 func (s *SearchService) SearchWithText(ctx context.Context, r api.SearchTextRequest) (*api.SearchTextResponse, error) {
-	currentUser := utils.GetUser(ctx)
-	if ok, err := s.caseService.isAllowed(ctx, r.CaseID, currentUser.Email); !ok {
-		if err != nil {
-			return nil, api.Error(err, api.ErrNotAllowed)
-		}
-		return nil, api.ErrNotAllowed
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
 	}
 
 	if len(r.Text) < 3 {
@@ -0,0 +1,588 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+)
+
+// The types of the suggestions
+const (
+	suggestionPerson = "person"
+	suggestionEvent  = "event"
+)
+
+// The statuses for the suggestions
+const (
+	suggestionPending  = "pending"
+	suggestionAccepted = "accepted"
+	suggestionRejected = "rejected"
+)
+
+// The sources for the suggestions
+const (
+	sourceEmail    = "email"
+	sourceMetadata = "metadata"
+)
+
+// suggestedImportance is the importance for the suggested
+// events, investigators raises it for the important ones
+const suggestedImportance = minImportance
+
+// suggestionBatch is the number of suggestions
+// that are read from the datastore at once
+const suggestionBatch = 1000
+
+// SuggestionService holds the dependencies for suggesting
+// persons and events from the processed files
+type SuggestionService struct {
+	db          datastore.Service
+	caseService *CaseService
+	linkService *LinkService
+}
+
+// NewSuggestionService creates a new suggestion-service
+func NewSuggestionService(db datastore.Service, caseService *CaseService, linkService *LinkService) *SuggestionService {
+	return &SuggestionService{db: db, caseService: caseService, linkService: linkService}
+}
+
+// List lists the suggestions for a case
+func (s *SuggestionService) List(ctx context.Context, r api.SuggestionListRequest) (*api.SuggestionListResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, viewRoles...); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.db.GetSuggestions(ctx, r.CaseID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	filtered := []api.Suggestion{}
+	for _, suggestion := range suggestions {
+		if (r.Type == "" || suggestion.Type == r.Type) &&
+			(r.Status == "" || suggestion.Status == r.Status) &&
+			(r.FileID == "" || suggestion.FileID == r.FileID) {
+			filtered = append(filtered, suggestion)
+		}
+	}
+
+	return &api.SuggestionListResponse{Suggestions: filtered}, nil
+}
+
+// Accept accepts suggestions, the persons and events are created
+// and the events are linked to their persons and file
+func (s *SuggestionService) Accept(ctx context.Context, r api.SuggestionAcceptRequest) (*api.SuggestionAcceptResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.getSuggestions(ctx, r.CaseID, r.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	persons, err := s.personsByEmail(ctx, r.CaseID)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	// The persons are accepted before
+	// the events that are linked to them
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Type == suggestionPerson && suggestions[j].Type != suggestionPerson
+	})
+
+	for i := range suggestions {
+		suggestion := &suggestions[i]
+		switch {
+		case suggestion.Status == suggestionAccepted:
+			continue
+		case suggestion.Type == suggestionPerson:
+			err = s.acceptPerson(ctx, r.CaseID, suggestion, persons)
+		default:
+			err = s.acceptEvent(ctx, r.CaseID, suggestion, persons)
+		}
+		if err != nil {
+			return nil, updateError(err)
+		}
+	}
+
+	return &api.SuggestionAcceptResponse{Accepted: suggestions}, nil
+}
+
+// Reject rejects suggestions, accepted
+// suggestions cannot be rejected
+func (s *SuggestionService) Reject(ctx context.Context, r api.SuggestionRejectRequest) (*api.SuggestionRejectResponse, error) {
+	if err := s.caseService.authorize(ctx, r.CaseID, editRoles...); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.getSuggestions(ctx, r.CaseID, r.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, suggestion := range suggestions {
+		if suggestion.Status == suggestionAccepted {
+			return nil, api.Error(fmt.Errorf("suggestion %s is already accepted", suggestion.ID), api.ErrCannotPerformOperation)
+		}
+	}
+
+	for i := range suggestions {
+		review(ctx, &suggestions[i], suggestionRejected)
+		if err := s.db.UpdateSuggestion(ctx, r.CaseID, &suggestions[i]); err != nil {
+			return nil, updateError(err)
+		}
+	}
+
+	return &api.SuggestionRejectResponse{Rejected: suggestions}, nil
+}
+
+// Authenticate is a middleware
+// in the http-handler
+//
+// NOTE : Only for Go-servers
+func (s *SuggestionService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	return s.caseService.Authenticate(ctx, r)
+}
+
+// suggest creates the suggestions for the documents in a processed
+// file, the suggestions that already exists aren't changed so
+// files can be processed again without losing the reviews
+func (s *SuggestionService) suggest(ctx context.Context, caseID, fileID string) error {
+	documents, err := s.db.GetProcessedDocumentsByFile(ctx, caseID, fileID)
+	if err != nil {
+		return err
+	}
+
+	persons, err := s.personsByEmail(ctx, caseID)
+	if err != nil {
+		return err
+	}
+
+	// The documents are sorted so the persons are
+	// suggested from the first document they are in
+	sort.Slice(documents, func(i, j int) bool { return documents[i].Path < documents[j].Path })
+
+	var suggestions []api.Suggestion
+	for _, document := range documents {
+		suggestions = append(suggestions, emailSuggestions(fileID, document, persons)...)
+		suggestions = append(suggestions, metadataSuggestions(fileID, document)...)
+	}
+	return s.create(ctx, caseID, suggestions)
+}
+
+// create creates the suggestions that doesn't already exist
+func (s *SuggestionService) create(ctx context.Context, caseID string, suggestions []api.Suggestion) error {
+	// The same person is suggested from many documents
+	var ids []string
+	unique := make(map[string]api.Suggestion)
+	for _, suggestion := range suggestions {
+		if _, ok := unique[suggestion.ID]; !ok {
+			ids = append(ids, suggestion.ID)
+			unique[suggestion.ID] = suggestion
+		}
+	}
+
+	for start := 0; start < len(ids); start += suggestionBatch {
+		end := start + suggestionBatch
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		existing, err := s.db.GetSuggestionsByIDs(ctx, caseID, ids[start:end])
+		if err != nil {
+			return err
+		}
+		exists := make(map[string]bool)
+		for _, suggestion := range existing {
+			exists[suggestion.ID] = true
+		}
+
+		for _, id := range ids[start:end] {
+			if exists[id] {
+				continue
+			}
+			suggestion := unique[id]
+			if err := s.db.CreateSuggestion(ctx, caseID, &suggestion); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getSuggestions gets the suggestions with the IDs,
+// it fails if any of the suggestions doesn't exist
+func (s *SuggestionService) getSuggestions(ctx context.Context, caseID string, ids []string) ([]api.Suggestion, error) {
+	if len(ids) == 0 {
+		return nil, api.Error(errors.New("specify the suggestions"), api.ErrCannotPerformOperation)
+	}
+
+	suggestions, err := s.db.GetSuggestionsByIDs(ctx, caseID, ids)
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	found := make(map[string]bool)
+	for _, suggestion := range suggestions {
+		found[suggestion.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, api.Error(fmt.Errorf("suggestion %s cannot be found", id), api.ErrNotFound)
+		}
+	}
+	return suggestions, nil
+}
+
+// acceptPerson creates the suggested person, unless a
+// person with the same email-address was created after
+// the suggestion, then the suggestion is accepted as it
+func (s *SuggestionService) acceptPerson(ctx context.Context, caseID string, suggestion *api.Suggestion, persons map[string]string) error {
+	email := strings.ToLower(suggestion.Person.EmailAddress)
+	if id, ok := persons[email]; ok {
+		return s.claim(ctx, caseID, suggestion, id)
+	}
+
+	if err := s.claim(ctx, caseID, suggestion, suggestion.ID); err != nil {
+		return err
+	}
+	person := suggestion.Person
+	person.ID = suggestion.ID
+	if err := s.db.CreatePerson(ctx, caseID, &person); err != nil {
+		return s.unclaim(ctx, caseID, suggestion, err)
+	}
+	persons[email] = person.ID
+	return nil
+}
+
+// acceptEvent creates the suggested event, and links it to the
+// file and the persons, the suggested persons that are pending
+// are accepted with it, the rejected persons aren't linked
+func (s *SuggestionService) acceptEvent(ctx context.Context, caseID string, suggestion *api.Suggestion, persons map[string]string) error {
+	if err := s.claim(ctx, caseID, suggestion, suggestion.ID); err != nil {
+		return err
+	}
+
+	personIDs := append([]string{}, suggestion.PersonIDs...)
+	suggested, err := s.db.GetSuggestionsByIDs(ctx, caseID, suggestion.PersonSuggestionIDs)
+	if err != nil {
+		return s.unclaim(ctx, caseID, suggestion, err)
+	}
+	for i := range suggested {
+		switch suggested[i].Status {
+		case suggestionRejected:
+			continue
+		case suggestionPending:
+			if err := s.acceptPerson(ctx, caseID, &suggested[i], persons); err != nil {
+				return s.unclaim(ctx, caseID, suggestion, err)
+			}
+		}
+		personIDs = append(personIDs, suggested[i].AcceptedID)
+	}
+
+	event := suggestion.Event
+	event.ID = suggestion.ID
+	if err := s.db.CreateEvent(ctx, caseID, &event); err != nil {
+		return s.unclaim(ctx, caseID, suggestion, err)
+	}
+
+	if _, err := s.linkService.Create(ctx, api.LinkCreateRequest{
+		CaseID:    caseID,
+		FromID:    event.ID,
+		PersonIDs: uniqueStrings(personIDs),
+		FileIDs:   []string{suggestion.FileID},
+	}); err != nil {
+		return s.unclaim(ctx, caseID, suggestion, err)
+	}
+	return nil
+}
+
+// claim accepts the suggestion before the person or event is
+// created, the version of the suggestion is checked so it's only
+// accepted once when it's accepted by many at the same time. The
+// created person or event gets the ID of the suggestion, so it's
+// the same when a failed accept is retried
+func (s *SuggestionService) claim(ctx context.Context, caseID string, suggestion *api.Suggestion, acceptedID string) error {
+	suggestion.AcceptedID = acceptedID
+	review(ctx, suggestion, suggestionAccepted)
+	return s.db.UpdateSuggestion(ctx, caseID, suggestion)
+}
+
+// unclaim sets the suggestion as pending again when the
+// person or event couldn't be created, so it can be retried
+func (s *SuggestionService) unclaim(ctx context.Context, caseID string, suggestion *api.Suggestion, err error) error {
+	suggestion.AcceptedID = ""
+	suggestion.Status = suggestionPending
+	suggestion.ReviewerEmail = ""
+	suggestion.ReviewedAt = 0
+	if err := s.db.UpdateSuggestion(ctx, caseID, suggestion); err != nil {
+		log.Printf("SuggestionService : failed to set suggestion %s as pending : %v", suggestion.ID, err)
+	}
+	return err
+}
+
+// personsByEmail returns the IDs of the persons
+// in the case by their email-address
+func (s *SuggestionService) personsByEmail(ctx context.Context, caseID string) (map[string]string, error) {
+	persons, err := s.db.GetPersons(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make(map[string]string)
+	for _, person := range persons {
+		if person.EmailAddress != "" {
+			emails[strings.ToLower(person.EmailAddress)] = person.ID
+		}
+	}
+	return emails, nil
+}
+
+// review sets the status for the suggestion,
+// and the user who reviewed it
+func review(ctx context.Context, suggestion *api.Suggestion, status string) {
+	suggestion.Status = status
+	suggestion.ReviewerEmail = utils.GetUser(ctx).Email
+	suggestion.ReviewedAt = time.Now().Unix()
+}
+
+// emailHeaders are the headers with the persons in an email, with
+// the keys for them in the metadata from both indexers
+var emailHeaders = []struct {
+	name string
+	keys []string
+}{
+	{name: "From", keys: []string{"from", "Message-From"}},
+	{name: "To", keys: []string{"to", "Message-To"}},
+	{name: "Cc", keys: []string{"cc", "Message-Cc"}},
+	{name: "Bcc", keys: []string{"bcc", "Message-Bcc"}},
+}
+
+// emailSuggestions returns the suggestions for the persons in the
+// headers of an email, and the event for when it was sent, the
+// existing persons are linked to the event instead of suggested
+func emailSuggestions(fileID string, document api.ProcessedDocument, persons map[string]string) []api.Suggestion {
+	if !strings.HasPrefix(document.ContentType, "message/rfc822") {
+		return nil
+	}
+
+	// The sender is the author of the email
+	raw := map[string]string{"from": document.Author}
+	for key, value := range document.Metadata {
+		raw[key] = value
+	}
+
+	var suggestions []api.Suggestion
+	var personIDs, personSuggestionIDs []string
+	addresses := make(map[string][]string)
+	for _, header := range emailHeaders {
+		for _, address := range headerAddresses(raw, header.keys...) {
+			email := strings.ToLower(address.Address)
+			addresses[header.name] = append(addresses[header.name], email)
+			if id, ok := persons[email]; ok {
+				personIDs = append(personIDs, id)
+				continue
+			}
+
+			suggestion := newSuggestion(suggestionPerson, sourceEmail, header.name, fileID, document)
+			suggestion.ID = suggestionID(suggestionPerson, email)
+			suggestion.Person = personFromAddress(address)
+			suggestions = append(suggestions, suggestion)
+			personSuggestionIDs = append(personSuggestionIDs, suggestion.ID)
+		}
+	}
+
+	// Emails without a date aren't on the timeline
+	date := document.ModifiedAt
+	if date == 0 {
+		date = document.CreatedAt
+	}
+	if date == 0 {
+		return suggestions
+	}
+
+	description := "Email"
+	if subject := strings.TrimSpace(document.Title); subject != "" {
+		description += fmt.Sprintf(" %q", subject)
+	}
+	if from := addresses["From"]; len(from) > 0 {
+		description += " from " + strings.Join(from, ", ")
+	}
+	if to := append(addresses["To"], addresses["Cc"]...); len(to) > 0 {
+		description += " to " + strings.Join(to, ", ")
+	}
+
+	event := newSuggestion(suggestionEvent, sourceEmail, "Date", fileID, document)
+	event.ID = suggestionID(suggestionEvent, document.ID, "Date")
+	event.Event = api.Event{
+		Importance:  suggestedImportance,
+		Description: description,
+		FromDate:    date,
+		ToDate:      date,
+	}
+	event.PersonIDs = uniqueStrings(personIDs)
+	event.PersonSuggestionIDs = uniqueStrings(personSuggestionIDs)
+	return append(suggestions, event)
+}
+
+// metadataDates are the fields with the dates in the metadata of the
+// documents, with the keys for them from both indexers, in the order
+// they are preferred if they have the same date. The description
+// is formatted with the name of the document
+var metadataDates = []struct {
+	keys        []string
+	description string
+}{
+	{keys: []string{"meta.raw.exif:DateTimeOriginal", "meta.raw.Date/Time Original"}, description: "Photo %s taken"},
+	{keys: []string{"meta.created"}, description: "%s created"},
+	{keys: []string{"meta.date"}, description: "%s modified"},
+	{keys: []string{"file.last_modified"}, description: "%s last modified in the archive"},
+}
+
+// metadataSuggestions returns the events for the dates in the metadata
+// of a document, like when a photo was taken or a PDF was created. The
+// dates for emails are suggested with the persons in them instead
+func metadataSuggestions(fileID string, document api.ProcessedDocument) []api.Suggestion {
+	if strings.HasPrefix(document.ContentType, "message/rfc822") {
+		return nil
+	}
+
+	name := fmt.Sprintf("%q", document.Name)
+	if title := strings.TrimSpace(document.Title); title != "" && title != document.Name {
+		name = fmt.Sprintf("%q (%s)", title, document.Name)
+	}
+
+	var suggestions []api.Suggestion
+	dates := make(map[int64]bool)
+	for _, field := range metadataDates {
+		for _, key := range field.keys {
+			date := metadataDate(document, key)
+			if date <= 0 || dates[date] {
+				continue
+			}
+			dates[date] = true
+
+			event := newSuggestion(suggestionEvent, sourceMetadata, key, fileID, document)
+			event.ID = suggestionID(suggestionEvent, document.ID, key)
+			event.Event = api.Event{
+				Importance:  suggestedImportance,
+				Description: fmt.Sprintf(field.description, name),
+				FromDate:    date,
+				ToDate:      date,
+			}
+			suggestions = append(suggestions, event)
+			break
+		}
+	}
+	return suggestions
+}
+
+// metadataDate returns the unix-timestamp for
+// a key in metadataDates, 0 if it isn't set
+func metadataDate(document api.ProcessedDocument, key string) int64 {
+	switch {
+	case strings.HasPrefix(key, "meta.raw."):
+		if date, ok := indexer.ParseDate(document.Metadata[strings.TrimPrefix(key, "meta.raw.")]); ok {
+			return date.Unix()
+		}
+	case key == "meta.created":
+		return document.CreatedAt
+	case key == "meta.date":
+		return document.ModifiedAt
+	case key == "file.last_modified":
+		return document.FileModifiedAt
+	}
+	return 0
+}
+
+// newSuggestion creates a pending suggestion for the document
+func newSuggestion(kind, source, field, fileID string, document api.ProcessedDocument) api.Suggestion {
+	return api.Suggestion{
+		Type:       kind,
+		Status:     suggestionPending,
+		Source:     source,
+		Field:      field,
+		FileID:     fileID,
+		DocumentID: document.ID,
+		Path:       document.Path,
+	}
+}
+
+// suggestionID returns the ID for a suggestion from the parts that
+// identifies it, so the same suggestion always gets the same ID
+func suggestionID(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// headerAddresses parses the addresses in the first header that
+// is set, addresses that cannot be parsed are skipped
+func headerAddresses(raw map[string]string, keys ...string) []*mail.Address {
+	for _, key := range keys {
+		value := strings.TrimSpace(raw[key])
+		if value == "" {
+			continue
+		}
+		if addresses, err := mail.ParseAddressList(value); err == nil {
+			return addresses
+		}
+
+		var addresses []*mail.Address
+		for _, part := range strings.Split(value, ",") {
+			if address, err := mail.ParseAddress(strings.TrimSpace(part)); err == nil {
+				addresses = append(addresses, address)
+			}
+		}
+		return addresses
+	}
+	return nil
+}
+
+// personFromAddress creates a person from the
+// name and email-address in an email-header
+func personFromAddress(address *mail.Address) api.Person {
+	person := api.Person{EmailAddress: strings.ToLower(address.Address)}
+	name := strings.Trim(strings.TrimSpace(address.Name), `"'`)
+	switch {
+	case strings.Contains(name, ","):
+		// "Lastname, Firstname"
+		parts := strings.SplitN(name, ",", 2)
+		person.LastName = strings.TrimSpace(parts[0])
+		person.FirstName = strings.TrimSpace(parts[1])
+	case strings.Contains(name, " "):
+		i := strings.LastIndex(name, " ")
+		person.FirstName = strings.TrimSpace(name[:i])
+		person.LastName = strings.TrimSpace(name[i+1:])
+	default:
+		person.FirstName = name
+	}
+	return person
+}
+
+// uniqueStrings removes the duplicates and empty strings
+func uniqueStrings(values []string) []string {
+	found := make(map[string]bool)
+	unique := []string{}
+	for _, value := range values {
+		if value != "" && !found[value] {
+			found[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
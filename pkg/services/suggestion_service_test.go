@@ -0,0 +1,235 @@
+package services_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/indexer"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/matryer/is"
+)
+
+const testMbox = `From jane@example.com Fri Jan 15 12:00:00 2021
+From: "Doe, Jane" <jane@example.com>
+To: John Smith <john@example.com>
+Cc: Bob <bob@example.com>
+Subject: Invoice
+Date: Fri, 15 Jan 2021 12:00:00 +0100
+
+The invoice is attached.
+
+From bob@example.com Sat Jan 16 12:00:00 2021
+From: Bob <bob@example.com>
+To: jane@example.com
+Subject: Re: Invoice
+Date: Sat, 16 Jan 2021 12:00:00 +0100
+
+Thanks.
+`
+
+// eventFailingDB fails to save the events
+type eventFailingDB struct {
+	testDB
+}
+
+func (eventFailingDB) CreateEvent(ctx context.Context, caseID string, event *api.Event) error {
+	return errors.New("cannot save event")
+}
+
+func TestSuggestionService(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{Base: api.Base{ID: "case-1"}, CreatorID: "owner", Investigators: []string{"owner@test.com"}}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		custody: make(map[string][]api.CustodyEvent),
+		jobs:    &testJobs{processes: make(map[string]api.Process)},
+		records: newTestRecords(),
+	}
+	db.records.persons["person-1"] = api.Person{
+		Base:         api.Base{ID: "person-1"},
+		FirstName:    "John",
+		EmailAddress: "John@example.com",
+	}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	caseService := services.NewCaseService(db, testAuth{})
+	fileService := services.NewFileService(db, store, caseService, indexer.NewNative(db))
+	suggestionService := services.NewSuggestionService(db, caseService, services.NewLinkService(db, caseService))
+	processService := services.NewProcessService(db, fileService, caseService, suggestionService, 1)
+
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go processService.Run(runCtx)
+
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+	_, err = fileService.New(ctx, api.FileNewRequest{
+		CaseID: caze.ID,
+		Name:   "inbox.mbox",
+		Data:   base64.StdEncoding.EncodeToString([]byte(testMbox)),
+	})
+	is.NoErr(err)
+
+	// processFile processes a file and waits for the job
+	processFile := func(fileID string) {
+		started, err := processService.Start(ctx, api.ProcessStartRequest{CaseID: caze.ID, FileIDs: []string{fileID}})
+		is.NoErr(err)
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			process, err := db.GetProcess(ctx, started.Started.ID)
+			is.NoErr(err)
+			if process.Status == "completed" {
+				return
+			}
+			is.True(process.Status != "failed")
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("the job never completed")
+	}
+	processFile("file-1")
+
+	// The existing person isn't suggested again
+	listed, err := suggestionService.List(ctx, api.SuggestionListRequest{CaseID: caze.ID, Type: "person"})
+	is.NoErr(err)
+	is.Equal(len(listed.Suggestions), 2)
+	persons := make(map[string]api.Suggestion)
+	for _, suggestion := range listed.Suggestions {
+		persons[suggestion.Person.EmailAddress] = suggestion
+	}
+	jane, bob := persons["jane@example.com"], persons["bob@example.com"]
+	is.Equal(jane.Status, "pending")
+	is.Equal(jane.Source, "email")
+	is.Equal(jane.Field, "From")
+	is.Equal(jane.FileID, "file-1")
+	is.Equal(jane.Path, "/inbox.mbox/1.eml")
+	is.Equal(jane.Person.FirstName, "Jane")
+	is.Equal(jane.Person.LastName, "Doe")
+	is.Equal(jane.Person.EmailAddress, "jane@example.com")
+	is.Equal(bob.Field, "Cc")
+	is.Equal(bob.Person.FirstName, "Bob")
+
+	listed, err = suggestionService.List(ctx, api.SuggestionListRequest{CaseID: caze.ID, Type: "event"})
+	is.NoErr(err)
+	is.Equal(len(listed.Suggestions), 2)
+	invoice := listed.Suggestions[0]
+	is.Equal(invoice.Field, "Date")
+	is.Equal(invoice.Event.FromDate, time.Date(2021, 1, 15, 11, 0, 0, 0, time.UTC).Unix())
+	is.Equal(invoice.Event.ToDate, invoice.Event.FromDate)
+	is.Equal(invoice.Event.Description, `Email "Invoice" from jane@example.com to john@example.com, bob@example.com`)
+	is.Equal(invoice.PersonIDs, []string{"person-1"})
+	is.Equal(invoice.PersonSuggestionIDs, []string{jane.ID, bob.ID})
+
+	// The event is linked to the persons that aren't
+	// rejected, the pending persons are accepted with it
+	_, err = suggestionService.Reject(ctx, api.SuggestionRejectRequest{CaseID: caze.ID, IDs: []string{bob.ID}})
+	is.NoErr(err)
+	accepted, err := suggestionService.Accept(ctx, api.SuggestionAcceptRequest{CaseID: caze.ID, IDs: []string{invoice.ID}})
+	is.NoErr(err)
+	is.Equal(accepted.Accepted[0].Status, "accepted")
+	is.Equal(accepted.Accepted[0].ReviewerEmail, "owner@test.com")
+
+	// The event and person gets the ID of their suggestion
+	is.Equal(accepted.Accepted[0].AcceptedID, invoice.ID)
+	event := db.records.events[invoice.ID]
+	is.Equal(event.Description, invoice.Event.Description)
+	is.Equal(len(db.records.persons), 2)
+	is.Equal(db.records.persons[jane.ID].EmailAddress, "jane@example.com")
+	is.Equal(len(db.records.links), 1)
+	link := db.records.links["link-1"]
+	is.Equal(link.From.(map[string]interface{})["id"], event.ID)
+	is.Equal(len(link.Persons), 2)
+	is.Equal(len(link.Files), 1)
+	is.Equal(link.Files[0].ID, "file-1")
+
+	// The reviews are kept when the file is processed again
+	processFile("file-1")
+	listed, err = suggestionService.List(ctx, api.SuggestionListRequest{CaseID: caze.ID, Status: "pending"})
+	is.NoErr(err)
+	is.Equal(len(listed.Suggestions), 1)
+	reply := listed.Suggestions[0]
+	is.True(strings.HasPrefix(reply.Event.Description, `Email "Re: Invoice"`))
+
+	// The suggestion is only accepted once when it's accepted at the same time
+	events, links := len(db.records.events), len(db.records.links)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := suggestionService.Accept(ctx, api.SuggestionAcceptRequest{CaseID: caze.ID, IDs: []string{reply.ID}})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		is.True(err == nil || strings.HasPrefix(err.Error(), api.ErrConflict.Error()))
+	}
+	is.Equal(len(db.records.events), events+1)
+	is.Equal(len(db.records.links), links+1)
+
+	// Accepted suggestions cannot be rejected
+	_, err = suggestionService.Reject(ctx, api.SuggestionRejectRequest{CaseID: caze.ID, IDs: []string{invoice.ID}})
+	is.True(err != nil)
+	_, err = suggestionService.Accept(ctx, api.SuggestionAcceptRequest{CaseID: caze.ID, IDs: []string{"missing"}})
+	is.True(err != nil)
+
+	// The times in the metadata are suggested as events
+	modified := time.Date(2021, 1, 14, 9, 30, 0, 0, time.UTC)
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	tw.WriteHeader(&tar.Header{Name: "notes.txt", Mode: 0600, Size: 5, ModTime: modified, Typeflag: tar.TypeReg})
+	tw.Write([]byte("notes"))
+	tw.Close()
+	_, err = fileService.New(ctx, api.FileNewRequest{
+		CaseID: caze.ID,
+		Name:   "backup.tar",
+		Data:   base64.StdEncoding.EncodeToString(archive.Bytes()),
+	})
+	is.NoErr(err)
+	processFile("file-2")
+
+	listed, err = suggestionService.List(ctx, api.SuggestionListRequest{CaseID: caze.ID, FileID: "file-2"})
+	is.NoErr(err)
+	is.Equal(len(listed.Suggestions), 1)
+	notes := listed.Suggestions[0]
+	is.Equal(notes.Type, "event")
+	is.Equal(notes.Source, "metadata")
+	is.Equal(notes.Field, "file.last_modified")
+	is.Equal(notes.Path, "/backup.tar/notes.txt")
+	is.Equal(notes.Event.FromDate, modified.Unix())
+	is.Equal(notes.Event.Description, `"notes.txt" last modified in the archive`)
+
+	// The suggestion is pending again when the event couldn't be created
+	failing := services.NewSuggestionService(eventFailingDB{db}, caseService, services.NewLinkService(db, caseService))
+	_, err = failing.Accept(ctx, api.SuggestionAcceptRequest{CaseID: caze.ID, IDs: []string{notes.ID}})
+	is.True(err != nil)
+	is.Equal(db.records.suggestions[notes.ID].Status, "pending")
+	is.Equal(db.records.suggestions[notes.ID].AcceptedID, "")
+
+	accepted, err = suggestionService.Accept(ctx, api.SuggestionAcceptRequest{CaseID: caze.ID, IDs: []string{notes.ID}})
+	is.NoErr(err)
+	is.Equal(accepted.Accepted[0].AcceptedID, notes.ID)
+	link = db.records.links["link-3"]
+	is.Equal(link.From.(map[string]interface{})["id"], notes.ID)
+	is.Equal(len(link.Persons), 0)
+	is.Equal(link.Files[0].ID, "file-2")
+}
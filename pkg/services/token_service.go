@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+)
+
+// apiTokenPrefix is the prefix for API-tokens, to
+// tell them apart from the tokens for the users
+const apiTokenPrefix = "ti_"
+
+// maxRevokeRetries is how many times a token is read again
+// when its usage was recorded while it was revoked
+const maxRevokeRetries = 3
+
+// TokenService holds the dependencies
+// for the token-service
+type TokenService struct {
+	db          datastore.Service
+	caseService *CaseService
+}
+
+// NewTokenService creates a new token-service
+func NewTokenService(db datastore.Service, caseService *CaseService) *TokenService {
+	return &TokenService{db: db, caseService: caseService}
+}
+
+// Create creates a new API-token for cases owned by the user
+func (s *TokenService) Create(ctx context.Context, r api.TokenCreateRequest) (*api.TokenCreateResponse, error) {
+	if r.Name == "" {
+		return nil, api.Error(errors.New("name is required"), api.ErrCannotPerformOperation)
+	}
+	if len(r.CaseIDs) == 0 {
+		return nil, api.Error(errors.New("at least one case is required"), api.ErrCannotPerformOperation)
+	}
+	// API-tokens cannot manage the cases
+	if !api.ValidRole(r.Role) || r.Role == api.RoleOwner {
+		return nil, api.ErrInvalidRole
+	}
+	if r.ExpiresAt != 0 && r.ExpiresAt <= time.Now().Unix() {
+		return nil, api.Error(errors.New("expiresAt must be in the future"), api.ErrCannotPerformOperation)
+	}
+
+	// The user must own every case the token has access to
+	currentUser := utils.GetUser(ctx)
+	for _, id := range r.CaseIDs {
+		caze, err := s.db.GetCase(ctx, id)
+		if err != nil || !hasRole(caze, currentUser, api.RoleOwner) {
+			return nil, api.ErrNotAllowed
+		}
+	}
+
+	secret, err := newAPIToken()
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	token := datastore.Token{
+		Token: api.Token{
+			Name:         r.Name,
+			CreatorID:    currentUser.UID,
+			CreatorEmail: currentUser.Email,
+			Prefix:       secret[:len(apiTokenPrefix)+6],
+			CaseIDs:      r.CaseIDs,
+			Role:         r.Role,
+			ExpiresAt:    r.ExpiresAt,
+		},
+		Hash: hashAPIToken(secret),
+	}
+	if err := s.db.CreateToken(ctx, &token); err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	return &api.TokenCreateResponse{Token: secret, New: token.Token}, nil
+}
+
+// List lists the API-tokens created by the user, or
+// every token for the case if the user owns the case
+func (s *TokenService) List(ctx context.Context, r api.TokenListRequest) (*api.TokenListResponse, error) {
+	currentUser := utils.GetUser(ctx)
+
+	var tokens []datastore.Token
+	var err error
+	if r.CaseID != "" {
+		caze, caseErr := s.db.GetCase(ctx, r.CaseID)
+		if caseErr != nil || !hasRole(caze, currentUser, api.RoleOwner) {
+			return nil, api.ErrNotAllowed
+		}
+		tokens, err = s.db.GetTokensByCase(ctx, r.CaseID)
+	} else {
+		tokens, err = s.db.GetTokensByCreator(ctx, currentUser.UID)
+	}
+	if err != nil {
+		return nil, api.Error(err, api.ErrCannotPerformOperation)
+	}
+
+	list := make([]api.Token, len(tokens))
+	for i := range tokens {
+		list[i] = tokens[i].Token
+	}
+
+	return &api.TokenListResponse{Tokens: list}, nil
+}
+
+// Revoke revokes an API-token, a token can be revoked by
+// the creator or by an owner of one of the cases for the token
+func (s *TokenService) Revoke(ctx context.Context, r api.TokenRevokeRequest) (*api.TokenRevokeResponse, error) {
+	token, err := s.db.GetToken(ctx, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("token - %v", api.ErrNotFound)
+	}
+
+	if !s.canRevoke(ctx, token) {
+		return nil, api.ErrNotAllowed
+	}
+
+	// The usage of the token is recorded while it's used, so
+	// it's read again if it has changed since it was read
+	for retries := 0; token.RevokedAt == 0; retries++ {
+		token.RevokedAt = time.Now().Unix()
+		err := s.db.UpdateToken(ctx, token)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, datastore.ErrConflict) || retries == maxRevokeRetries {
+			return nil, updateError(err)
+		}
+		if token, err = s.db.GetToken(ctx, r.ID); err != nil {
+			return nil, fmt.Errorf("token - %v", api.ErrNotFound)
+		}
+	}
+
+	return &api.TokenRevokeResponse{Revoked: token.Token}, nil
+}
+
+// Authenticate is a middleware
+// in the http-handler
+//
+// NOTE : Only for Go-servers
+func (s *TokenService) Authenticate(ctx context.Context, r *http.Request) (context.Context, error) {
+	return s.caseService.Authenticate(ctx, r)
+}
+
+func (s *TokenService) canRevoke(ctx context.Context, token *datastore.Token) bool {
+	currentUser := utils.GetUser(ctx)
+	if token.CreatorID == currentUser.UID {
+		return true
+	}
+	for _, id := range token.CaseIDs {
+		caze, err := s.db.GetCase(ctx, id)
+		if err == nil && hasRole(caze, currentUser, api.RoleOwner) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAPIToken returns true if the token from
+// the request is an API-token
+func isAPIToken(token string) bool {
+	return strings.HasPrefix(token, apiTokenPrefix)
+}
+
+// newAPIToken generates a new random API-token
+func newAPIToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	return apiTokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashAPIToken hashes the API-token, the tokens are
+// random so a salt or a slow hash isn't needed
+func hashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// authenticateAPIToken validates the API-token and sets it
+// (and a user that represents it) to the context
+func (s *CaseService) authenticateAPIToken(ctx context.Context, secret string) (context.Context, error) {
+	token, err := s.db.GetTokenByHash(ctx, hashAPIToken(secret))
+	if err != nil {
+		return nil, api.Error(errors.New("invalid token"), api.ErrNotAllowed)
+	}
+	if token.RevokedAt != 0 {
+		return nil, api.Error(errors.New("token has been revoked"), api.ErrNotAllowed)
+	}
+	if token.ExpiresAt != 0 && token.ExpiresAt <= time.Now().Unix() {
+		return nil, api.Error(errors.New("token has expired"), api.ErrNotAllowed)
+	}
+
+	// The usage is recorded once per interval instead of for every
+	// request, and the request shouldn't fail because it couldn't be
+	// recorded - the count is kept so it's recorded with the next one
+	if count, ok := s.usage.use(token.ID, s.TokenUsageInterval); ok {
+		if err := s.db.RecordTokenUsage(ctx, token.ID, count); err != nil {
+			log.Printf("failed to record usage for token %s : %v", token.ID, err)
+			s.usage.unrecorded(token.ID, count)
+		}
+	}
+
+	ctx = utils.SetAPIToken(ctx, token.Token)
+	return utils.SetUser(ctx, api.User{
+		UID:         "token-" + token.ID,
+		DisplayName: token.Name,
+		ProviderID:  "token",
+	}), nil
+}
+
+// tokenUsage counts the requests made with the API-tokens
+// until their usage is recorded in the datastore
+type tokenUsage struct {
+	mu     sync.Mutex
+	tokens map[string]*usage
+}
+
+// usage is the requests made with an API-token since
+// its usage was recorded, and when it was recorded
+type usage struct {
+	count      int
+	recordedAt time.Time
+}
+
+// use counts a request with the token, and returns the count to
+// record if the usage hasn't been recorded within the interval
+func (u *tokenUsage) use(id string, interval time.Duration) (int, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	token, ok := u.tokens[id]
+	if !ok {
+		token = &usage{}
+		u.tokens[id] = token
+	}
+	token.count++
+
+	now := time.Now()
+	if !token.recordedAt.IsZero() && now.Sub(token.recordedAt) < interval {
+		return 0, false
+	}
+	count := token.count
+	token.count = 0
+	token.recordedAt = now
+	return count, true
+}
+
+// unrecorded adds the count back to the token
+// if its usage couldn't be recorded
+func (u *tokenUsage) unrecorded(id string, count int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if token, ok := u.tokens[id]; ok {
+		token.count += count
+	}
+}
+
+// tokenHasRole checks if the API-token has access to the case with one
+// of the roles (or any role if none is specified), the creator of the
+// token must still own the case for the token to be valid
+func tokenHasRole(caze *api.Case, token api.Token, roles ...string) bool {
+	var bound bool
+	for _, id := range token.CaseIDs {
+		if id == caze.ID {
+			bound = true
+			break
+		}
+	}
+	if !bound {
+		return false
+	}
+
+	creator := api.User{UID: token.CreatorID, Email: token.CreatorEmail}
+	if !hasRole(caze, creator, api.RoleOwner) {
+		return false
+	}
+
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if role == token.Role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+
+	"github.com/matryer/is"
+)
+
+// usedDB records a request with the token the first
+// time it's read, as if it was used while being revoked
+type usedDB struct {
+	testDB
+	reads *int
+}
+
+func (db usedDB) GetToken(ctx context.Context, id string) (*datastore.Token, error) {
+	token, err := db.testDB.GetToken(ctx, id)
+	if *db.reads++; *db.reads == 1 {
+		db.RecordTokenUsage(ctx, id, 1)
+	}
+	return token, err
+}
+
+func TestTokenServiceRevoke(t *testing.T) {
+	is := is.New(t)
+
+	token := &datastore.Token{Token: api.Token{Base: api.Base{ID: "1"}, CreatorID: "owner", UseCount: 41}}
+	db := usedDB{
+		testDB: testDB{tokens: map[string]*datastore.Token{"hash": token}},
+		reads:  new(int),
+	}
+	tokenService := services.NewTokenService(db, services.NewCaseService(db, testAuth{}))
+	ctx := utils.SetUser(context.Background(), api.User{UID: "owner", Email: "owner@test.com"})
+
+	// The token is read again when the usage has been
+	// recorded since, so the usage isn't overwritten
+	resp, err := tokenService.Revoke(ctx, api.TokenRevokeRequest{ID: "1"})
+	is.NoErr(err)
+	is.True(resp.Revoked.RevokedAt != 0)
+	is.Equal(*db.reads, 2)
+	is.Equal(db.tokens["hash"].UseCount, 42)
+	is.True(db.tokens["hash"].RevokedAt != 0)
+}
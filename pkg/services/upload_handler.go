@@ -0,0 +1,603 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/utils"
+)
+
+const (
+	// tusVersion is the version of the tus-protocol
+	// that is supported by the upload-handler
+	tusVersion = "1.0.0"
+
+	// uploadContentType is the content-type for the chunks
+	uploadContentType = "application/offset+octet-stream"
+
+	// defaultMaxUploadSize is the default MaxSize for an upload
+	defaultMaxUploadSize = 100 << 30
+
+	// defaultUploadExpiry is the default Expiry for the uploads
+	defaultUploadExpiry = 24 * time.Hour
+
+	// defaultReadTimeout is the default ReadTimeout for the chunks
+	defaultReadTimeout = 15 * time.Second
+)
+
+// UploadHandler handles resumable uploads of evidence, based on
+// the tus-protocol (https://tus.io). The chunks are streamed to a
+// staging-file on disk, and the file is moved to the filestore
+// and created in the case when every byte has been received.
+//
+// POST   {basepath}      creates an upload (Upload-Length and Upload-Metadata)
+// HEAD   {basepath}{id}  returns the progress (Upload-Offset)
+// PATCH  {basepath}{id}  appends a chunk at the Upload-Offset (empty at the end to retry)
+// GET    {basepath}{id}  returns the upload as json
+// DELETE {basepath}{id}  aborts the upload
+type UploadHandler struct {
+	db          datastore.Service
+	fileService *FileService
+	caseService *CaseService
+	stagingPath string
+
+	// Basepath is the path the handler is mounted on
+	Basepath string
+
+	// MaxSize is the maximum Upload-Length in bytes,
+	// it's sent to the clients as Tus-Max-Size
+	MaxSize int64
+
+	// Expiry is how long an upload is kept after the last chunk was
+	// received, the uploads that are abandoned are removed by Run
+	Expiry time.Duration
+
+	// ReadTimeout is how long a read of a chunk can take, the
+	// deadline is extended for every read, so a chunk can take
+	// longer than the read-timeout of the server as long as
+	// the client keeps sending
+	ReadTimeout time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*uploadLock
+}
+
+// uploadLock is the lock for an upload, with the number
+// of requests that are holding or waiting for it
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewUploadHandler creates a new upload-handler,
+// the stagingPath is created if it doesn't exist
+func NewUploadHandler(db datastore.Service, fileService *FileService, caseService *CaseService, stagingPath string) (*UploadHandler, error) {
+	if err := os.MkdirAll(stagingPath, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create staging-path: %v", err)
+	}
+
+	return &UploadHandler{
+		db:          db,
+		fileService: fileService,
+		caseService: caseService,
+		stagingPath: stagingPath,
+		Basepath:    "/upload/",
+		MaxSize:     defaultMaxUploadSize,
+		Expiry:      defaultUploadExpiry,
+		ReadTimeout: defaultReadTimeout,
+		locks:       make(map[string]*uploadLock),
+	}, nil
+}
+
+// ServeHTTP handles the requests for the uploads
+func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+
+	id := strings.TrimPrefix(r.URL.Path, h.Basepath)
+	if strings.Contains(id, "/") {
+		handlerError(w, http.StatusNotFound, api.ErrNotFound)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Tus-Version", tusVersion)
+		w.Header().Set("Tus-Extension", "creation,expiration,termination")
+		if h.MaxSize > 0 {
+			w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.MaxSize, 10))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && id == "":
+		h.create(w, r)
+	case id == "":
+		handlerError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	case r.Method == http.MethodHead:
+		h.head(w, r, id)
+	case r.Method == http.MethodGet:
+		h.get(w, r, id)
+	case r.Method == http.MethodPatch:
+		h.patch(w, r, id)
+	case r.Method == http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		handlerError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	}
+}
+
+// create creates a new upload in a case
+func (h *UploadHandler) create(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		handlerError(w, http.StatusBadRequest, errors.New("invalid Upload-Length"))
+		return
+	}
+	if h.MaxSize > 0 && length > h.MaxSize {
+		handlerError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("Upload-Length exceeds the Tus-Max-Size of %d bytes", h.MaxSize))
+		return
+	}
+
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		handlerError(w, http.StatusBadRequest, err)
+		return
+	}
+	if metadata["caseID"] == "" || metadata["name"] == "" {
+		handlerError(w, http.StatusBadRequest, errors.New("caseID and name is required in Upload-Metadata"))
+		return
+	}
+	if err := filestore.ValidName(metadata["name"]); err != nil {
+		handlerError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, err := authorizeCase(r, h.caseService, metadata["caseID"], editRoles...)
+	if err != nil {
+		handlerError(w, http.StatusForbidden, err)
+		return
+	}
+
+	upload := datastore.Upload{
+		CaseID:      metadata["caseID"],
+		CreatorID:   utils.GetUser(ctx).UID,
+		Name:        metadata["name"],
+		Mime:        metadata["mime"],
+		Description: metadata["description"],
+		Length:      length,
+	}
+	if err := h.db.CreateUpload(ctx, &upload); err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+
+	staging, err := os.OpenFile(h.staging(upload.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	staging.Close()
+
+	// Empty files are complete when they are created
+	if length == 0 {
+		if err := h.complete(ctx, &upload); err != nil {
+			handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+			return
+		}
+	}
+
+	w.Header().Set("Location", h.Basepath+upload.ID)
+	w.Header().Set("Upload-Offset", "0")
+	h.setExpires(w, &upload)
+	writeUpload(w, http.StatusCreated, &upload)
+}
+
+// head returns the progress of the upload
+func (h *UploadHandler) head(w http.ResponseWriter, r *http.Request, id string) {
+	_, upload, status, err := h.session(r, id)
+	if err != nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+// get returns the upload as json
+func (h *UploadHandler) get(w http.ResponseWriter, r *http.Request, id string) {
+	_, upload, status, err := h.session(r, id)
+	if err != nil {
+		handlerError(w, status, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeUpload(w, http.StatusOK, upload)
+}
+
+// patch appends the chunk to the staging-file
+func (h *UploadHandler) patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != uploadContentType {
+		handlerError(w, http.StatusUnsupportedMediaType, fmt.Errorf("Content-Type must be %s", uploadContentType))
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		handlerError(w, http.StatusBadRequest, errors.New("invalid Upload-Offset"))
+		return
+	}
+
+	// Only one chunk at the time for an upload
+	defer h.lock(id)()
+
+	ctx, upload, status, err := h.session(r, id)
+	if err != nil {
+		handlerError(w, status, err)
+		return
+	}
+
+	// A completed upload only accepts an empty chunk at the end, the
+	// retry of the last chunk, it creates the file if it failed
+	// before, or returns the file that was created
+	if upload.FileID != "" {
+		if offset != upload.Length || r.ContentLength != 0 {
+			handlerError(w, http.StatusConflict, errors.New("the upload is already complete"))
+			return
+		}
+		if err := h.complete(ctx, upload); err != nil {
+			handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+			return
+		}
+		w.Header().Set("Upload-File-ID", upload.FileID)
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// The staging-file is removed when the upload expires
+	staging, err := os.OpenFile(h.staging(upload.ID), os.O_WRONLY, 0600)
+	if os.IsNotExist(err) {
+		handlerError(w, http.StatusGone, errors.New("the upload has expired"))
+		return
+	}
+	if err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	defer staging.Close()
+
+	// The staging-file is the source of truth for the offset,
+	// since an interrupted chunk may be partially written
+	info, err := staging.Stat()
+	if err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	if offset != info.Size() {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(info.Size(), 10))
+		handlerError(w, http.StatusConflict, fmt.Errorf("Upload-Offset is %d, expected %d", offset, info.Size()))
+		return
+	}
+	if _, err := staging.Seek(offset, io.SeekStart); err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+
+	// The read-deadline is extended while the chunk is
+	// received, so the size of the chunks isn't limited
+	// by the read-timeout of the server
+	body := newDeadlineReader(w, r.Body, h.ReadTimeout)
+	remaining := upload.Length - offset
+	written, copyErr := io.Copy(staging, io.LimitReader(body, remaining))
+
+	// Reject chunks that are larger than the upload,
+	// and remove the bytes that were written
+	if copyErr == nil && written == remaining {
+		if n, _ := body.Read(make([]byte, 1)); n > 0 {
+			staging.Truncate(offset)
+			handlerError(w, http.StatusRequestEntityTooLarge, errors.New("the chunk exceeds the Upload-Length"))
+			return
+		}
+	}
+
+	// Keep the bytes from an interrupted chunk,
+	// so the client can resume from them
+	upload.Offset = offset + written
+	if err := h.db.UpdateUpload(ctx, upload); err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	if copyErr != nil {
+		log.Printf("UploadHandler : chunk for %s was interrupted : %v", upload.ID, copyErr)
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		handlerError(w, http.StatusInternalServerError, api.Error(copyErr, api.ErrCannotPerformOperation))
+		return
+	}
+
+	if upload.Offset == upload.Length {
+		if err := staging.Close(); err != nil {
+			handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+			return
+		}
+		if err := h.complete(ctx, upload); err != nil {
+			handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+			return
+		}
+		w.Header().Set("Upload-File-ID", upload.FileID)
+	} else {
+		h.setExpires(w, upload)
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete aborts the upload and removes the staging-file
+func (h *UploadHandler) delete(w http.ResponseWriter, r *http.Request, id string) {
+	defer h.lock(id)()
+
+	ctx, upload, status, err := h.session(r, id)
+	if err != nil {
+		handlerError(w, status, err)
+		return
+	}
+
+	if err := os.Remove(h.staging(upload.ID)); err != nil && !os.IsNotExist(err) {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+	if err := h.db.DeleteUpload(ctx, upload.ID); err != nil {
+		handlerError(w, http.StatusInternalServerError, api.Error(err, api.ErrCannotPerformOperation))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// complete moves the staging-file to the filestore and creates the
+// file in the case. The ID for the file (the ID of the upload) is
+// saved on the upload before the file is created, so a retry after
+// a failure creates the same file, and a retry after the file was
+// created returns it
+func (h *UploadHandler) complete(ctx context.Context, upload *datastore.Upload) error {
+	if upload.FileID == "" {
+		upload.FileID = upload.ID
+		if err := h.db.UpdateUpload(ctx, upload); err != nil {
+			upload.FileID = ""
+			return err
+		}
+	} else if _, err := h.db.GetFileByID(ctx, upload.CaseID, upload.FileID); err == nil {
+		h.removeStaging(upload.ID)
+		return nil
+	}
+
+	staging, err := os.Open(h.staging(upload.ID))
+	if err != nil {
+		return err
+	}
+	defer staging.Close()
+
+	if _, err := h.fileService.create(ctx, upload.CaseID, upload.FileID, upload.Name, upload.Mime, upload.Description, staging); err != nil {
+		return err
+	}
+
+	staging.Close()
+	h.removeStaging(upload.ID)
+	return nil
+}
+
+// removeStaging removes the staging-file for a completed upload
+func (h *UploadHandler) removeStaging(id string) {
+	if err := os.Remove(h.staging(id)); err != nil && !os.IsNotExist(err) {
+		log.Printf("UploadHandler : failed to remove staging-file for %s : %v", id, err)
+	}
+}
+
+// session gets the upload and authorizes the request for it,
+// only the creator of the upload is allowed to continue it
+func (h *UploadHandler) session(r *http.Request, id string) (context.Context, *datastore.Upload, int, error) {
+	upload, err := h.db.GetUpload(r.Context(), id)
+	if err != nil {
+		return nil, nil, http.StatusNotFound, api.Error(err, api.ErrNotFound)
+	}
+
+	ctx, err := authorizeCase(r, h.caseService, upload.CaseID, editRoles...)
+	if err != nil {
+		return nil, nil, http.StatusForbidden, err
+	}
+	if utils.GetUser(ctx).UID != upload.CreatorID {
+		return nil, nil, http.StatusForbidden, api.ErrNotAllowed
+	}
+
+	return ctx, upload, http.StatusOK, nil
+}
+
+// staging returns the path to the staging-file for the upload
+func (h *UploadHandler) staging(id string) string {
+	return filepath.Join(h.stagingPath, filepath.Base(id))
+}
+
+// lock locks the upload and returns the func that unlocks it,
+// the lock is only removed when no other request is holding
+// or waiting for it, so every request gets the same lock
+func (h *UploadHandler) lock(id string) func() {
+	h.mu.Lock()
+	lock, ok := h.locks[id]
+	if !ok {
+		lock = &uploadLock{}
+		h.locks[id] = lock
+	}
+	lock.refs++
+	h.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(h.locks, id)
+		}
+	}
+}
+
+// setExpires sets when the upload expires if no more chunks are received
+func (h *UploadHandler) setExpires(w http.ResponseWriter, upload *datastore.Upload) {
+	if h.Expiry <= 0 || upload.FileID != "" {
+		return
+	}
+	w.Header().Set("Upload-Expires", time.Now().Add(h.Expiry).UTC().Format(http.TimeFormat))
+}
+
+// Run removes the uploads that have expired every interval,
+// it blocks until the context is done
+func (h *UploadHandler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := h.Expire(ctx); err != nil {
+			log.Printf("UploadHandler : cannot expire uploads : %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Expire removes the uploads that haven't received a chunk within the
+// Expiry, with their staging-files. The staging-files are the uploads
+// that aren't complete, and they are written to for every chunk. The
+// uploads that are complete are removed when they haven't been
+// updated within the Expiry, so the last chunk can be retried
+func (h *UploadHandler) Expire(ctx context.Context) error {
+	if h.Expiry <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(h.stagingPath)
+	if err != nil {
+		return err
+	}
+
+	expired := time.Now().Add(-h.Expiry)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(expired) {
+			continue
+		}
+		h.expire(ctx, entry.Name(), expired)
+	}
+
+	completed, err := h.db.GetCompletedUploads(ctx, expired.Unix())
+	if err != nil {
+		return err
+	}
+	for _, upload := range completed {
+		h.expire(ctx, upload.ID, expired)
+	}
+	return nil
+}
+
+// expire removes the upload, if no chunk has been received since
+// it was found as expired, completed uploads have no staging-file
+func (h *UploadHandler) expire(ctx context.Context, id string, expired time.Time) {
+	defer h.lock(id)()
+
+	info, err := os.Stat(h.staging(id))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil || info.ModTime().After(expired):
+		return
+	default:
+		if err := os.Remove(h.staging(id)); err != nil {
+			log.Printf("UploadHandler : failed to remove staging-file for %s : %v", id, err)
+			return
+		}
+	}
+	if err := h.db.DeleteUpload(ctx, id); err != nil {
+		log.Printf("UploadHandler : failed to delete expired upload %s : %v", id, err)
+	}
+}
+
+// deadlineReader extends the read-deadline of the connection before
+// every read, so large chunks aren't cut off by the read-timeout of
+// the server, the connection still times out if the client stalls
+type deadlineReader struct {
+	io.Reader
+	controller *http.ResponseController
+	timeout    time.Duration
+}
+
+func newDeadlineReader(w http.ResponseWriter, r io.Reader, timeout time.Duration) *deadlineReader {
+	return &deadlineReader{Reader: r, controller: http.NewResponseController(w), timeout: timeout}
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	if r.timeout > 0 {
+		r.controller.SetReadDeadline(time.Now().Add(r.timeout))
+	}
+	return r.Reader.Read(p)
+}
+
+// parseUploadMetadata parses the Upload-Metadata header,
+// a comma-separated list of keys with base64-encoded values
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, " ", 2)
+		if len(parts) == 1 {
+			metadata[parts[0]] = ""
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata for %s: %v", parts[0], err)
+		}
+		metadata[parts[0]] = string(value)
+	}
+	return metadata, nil
+}
+
+func writeUpload(w http.ResponseWriter, status int, upload *datastore.Upload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(upload); err != nil {
+		log.Printf("UploadHandler : %s", err.Error())
+	}
+}
+
+func handlerError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}); err != nil {
+		log.Printf("UploadHandler : %s", err.Error())
+	}
+}
@@ -0,0 +1,344 @@
+package services_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/api"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/datastore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/filestore"
+	"github.com/avian-digital-forensics/timeline-investigator/pkg/services"
+
+	"github.com/matryer/is"
+)
+
+func TestUploadHandler(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com", "viewer@test.com"},
+		Roles: []api.Role{
+			{Email: "owner@test.com", Name: api.RoleOwner},
+			{Email: "viewer@test.com", Name: api.RoleViewer},
+		},
+	}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		uploads: make(map[string]*datastore.Upload),
+		custody: make(map[string][]api.CustodyEvent),
+	}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	caseService := services.NewCaseService(db, testAuth{})
+	fileService := services.NewFileService(db, store, caseService, nil)
+	handler, err := services.NewUploadHandler(db, fileService, caseService, basePath+"/.uploads")
+	is.NoErr(err)
+
+	do := func(method, path, token string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", token)
+		for key, value := range headers {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	metadata := func(values ...string) string {
+		var pairs []string
+		for i := 0; i < len(values); i += 2 {
+			pairs = append(pairs, values[i]+" "+base64.StdEncoding.EncodeToString([]byte(values[i+1])))
+		}
+		return strings.Join(pairs, ",")
+	}
+	create := func(token, length string) *httptest.ResponseRecorder {
+		return do(http.MethodPost, "/upload/", token, map[string]string{
+			"Upload-Length":   length,
+			"Upload-Metadata": metadata("caseID", caze.ID, "name", "evidence.txt", "mime", "text/plain"),
+		}, "")
+	}
+	patch := func(location, token, offset, chunk string) *httptest.ResponseRecorder {
+		return do(http.MethodPatch, location, token, map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": offset,
+		}, chunk)
+	}
+
+	// Viewers cannot upload files
+	is.Equal(create("viewer", "10").Code, http.StatusForbidden)
+
+	w := create("owner", "10")
+	is.Equal(w.Code, http.StatusCreated)
+	location := w.Header().Get("Location")
+	is.True(strings.HasPrefix(location, "/upload/"))
+
+	// Upload the first chunk
+	w = patch(location, "owner", "0", "0123")
+	is.Equal(w.Code, http.StatusNoContent)
+	is.Equal(w.Header().Get("Upload-Offset"), "4")
+
+	// The chunk must continue from the offset
+	is.Equal(patch(location, "owner", "0", "0123").Code, http.StatusConflict)
+
+	// Only the creator can see the progress
+	w = do(http.MethodHead, location, "owner", nil, "")
+	is.Equal(w.Code, http.StatusOK)
+	is.Equal(w.Header().Get("Upload-Offset"), "4")
+	is.Equal(w.Header().Get("Upload-Length"), "10")
+	is.Equal(do(http.MethodHead, location, "viewer", nil, "").Code, http.StatusForbidden)
+
+	// Chunks cannot exceed the length
+	is.Equal(patch(location, "owner", "4", "45678910").Code, http.StatusRequestEntityTooLarge)
+
+	// Upload the last chunk
+	w = patch(location, "owner", "4", "456789")
+	is.Equal(w.Code, http.StatusNoContent)
+	is.Equal(w.Header().Get("Upload-Offset"), "10")
+	fileID := w.Header().Get("Upload-File-ID")
+	is.Equal(fileID, strings.TrimPrefix(location, "/upload/")) // the file gets the ID of the upload
+
+	is.Equal(len(caze.Files), 1)
+	is.Equal(caze.Files[0].Size, 10)
+	is.Equal(caze.Files[0].Mime, "text/plain")
+	content, err := os.ReadFile(caze.Files[0].Path)
+	is.NoErr(err)
+	is.Equal(string(content), "0123456789")
+	is.Equal(caze.Files[0].SHA256, "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882")
+	is.Equal(db.custody[fileID][0].Action, "uploaded")
+
+	// Completed uploads cannot be changed
+	is.Equal(patch(location, "owner", "10", "x").Code, http.StatusConflict)
+
+	// The last chunk can be retried, it returns the created file
+	w = patch(location, "owner", "10", "")
+	is.Equal(w.Code, http.StatusNoContent)
+	is.Equal(w.Header().Get("Upload-File-ID"), fileID)
+	is.Equal(len(caze.Files), 1)
+	completed := location
+
+	// Aborted uploads are removed
+	location = create("owner", "5").Header().Get("Location")
+	is.Equal(do(http.MethodDelete, location, "owner", nil, "").Code, http.StatusNoContent)
+	is.Equal(do(http.MethodHead, location, "owner", nil, "").Code, http.StatusNotFound)
+
+	// Uploads cannot exceed the max-size
+	handler.MaxSize = 10
+	is.Equal(do(http.MethodOptions, "/upload/", "", nil, "").Header().Get("Tus-Max-Size"), "10")
+	is.Equal(create("owner", "11").Code, http.StatusRequestEntityTooLarge)
+
+	// Only one of the chunks for the same offset is written
+	location = create("owner", "4").Header().Get("Location")
+	var wg sync.WaitGroup
+	codes := make(chan int, 8)
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- patch(location, "owner", "0", "0123").Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	var written int
+	for code := range codes {
+		if code == http.StatusNoContent {
+			written++
+		} else {
+			is.Equal(code, http.StatusConflict)
+		}
+	}
+	is.Equal(written, 1)
+
+	// Abandoned uploads expire
+	w = create("owner", "5")
+	is.True(w.Header().Get("Upload-Expires") != "")
+	location = w.Header().Get("Location")
+	expired := time.Now().Add(-handler.Expiry - time.Minute)
+	staging := basePath + "/.uploads/" + strings.TrimPrefix(location, "/upload/")
+	is.NoErr(os.Chtimes(staging, expired, expired))
+	is.NoErr(handler.Expire(context.Background()))
+	_, err = os.Stat(staging)
+	is.True(os.IsNotExist(err))
+	is.Equal(do(http.MethodHead, location, "owner", nil, "").Code, http.StatusNotFound)
+
+	// Uploads that are continued don't expire
+	location = create("owner", "5").Header().Get("Location")
+	is.NoErr(handler.Expire(context.Background()))
+	is.Equal(do(http.MethodHead, location, "owner", nil, "").Code, http.StatusOK)
+	is.Equal(do(http.MethodHead, completed, "owner", nil, "").Code, http.StatusOK)
+
+	// Completed uploads expire, but not their files
+	files := len(caze.Files)
+	db.uploads[strings.TrimPrefix(completed, "/upload/")].UpdatedAt = expired.Unix()
+	is.NoErr(handler.Expire(context.Background()))
+	is.Equal(do(http.MethodHead, completed, "owner", nil, "").Code, http.StatusNotFound)
+	is.Equal(len(caze.Files), files)
+}
+
+// flakyDB fails to save the first file
+type flakyDB struct {
+	testDB
+	failed *bool
+}
+
+func (db flakyDB) CreateFile(ctx context.Context, caseID string, file *api.File) error {
+	if !*db.failed {
+		*db.failed = true
+		return errors.New("cannot save file")
+	}
+	return db.testDB.CreateFile(ctx, caseID, file)
+}
+
+func TestUploadHandlerRetry(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com"},
+		Roles:         []api.Role{{Email: "owner@test.com", Name: api.RoleOwner}},
+	}
+	db := flakyDB{
+		testDB: testDB{
+			cases:   map[string]*api.Case{caze.ID: caze},
+			uploads: make(map[string]*datastore.Upload),
+			custody: make(map[string][]api.CustodyEvent),
+		},
+		failed: new(bool),
+	}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	caseService := services.NewCaseService(db, testAuth{})
+	fileService := services.NewFileService(db, store, caseService, nil)
+	handler, err := services.NewUploadHandler(db, fileService, caseService, basePath+"/.uploads")
+	is.NoErr(err)
+
+	do := func(method, path string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "owner")
+		for key, value := range headers {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	patch := func(location, offset, chunk string) *httptest.ResponseRecorder {
+		return do(http.MethodPatch, location, map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": offset,
+		}, chunk)
+	}
+
+	location := do(http.MethodPost, "/upload/", map[string]string{
+		"Upload-Length":   "4",
+		"Upload-Metadata": "caseID " + base64.StdEncoding.EncodeToString([]byte(caze.ID)) + ",name " + base64.StdEncoding.EncodeToString([]byte("a.txt")),
+	}, "").Header().Get("Location")
+	id := strings.TrimPrefix(location, "/upload/")
+
+	// The file couldn't be created, but the chunk is kept
+	is.Equal(patch(location, "0", "0123").Code, http.StatusInternalServerError)
+	is.Equal(len(caze.Files), 0)
+	is.Equal(db.uploads[id].FileID, id)
+	_, err = os.Stat(basePath + "/.uploads/" + id)
+	is.NoErr(err)
+
+	// The retry creates the file with the ID that was saved on the upload
+	for i := 0; i < 2; i++ {
+		w := patch(location, "4", "")
+		is.Equal(w.Code, http.StatusNoContent)
+		is.Equal(w.Header().Get("Upload-File-ID"), id)
+		is.Equal(len(caze.Files), 1)
+		is.Equal(caze.Files[0].ID, id)
+	}
+	_, err = os.Stat(basePath + "/.uploads/" + id)
+	is.True(os.IsNotExist(err))
+}
+
+func TestUploadHandlerReadTimeout(t *testing.T) {
+	is := is.New(t)
+
+	caze := &api.Case{
+		Base:          api.Base{ID: "case-1"},
+		CreatorID:     "owner",
+		Investigators: []string{"owner@test.com"},
+		Roles:         []api.Role{{Email: "owner@test.com", Name: api.RoleOwner}},
+	}
+	db := testDB{
+		cases:   map[string]*api.Case{caze.ID: caze},
+		uploads: make(map[string]*datastore.Upload),
+		custody: make(map[string][]api.CustodyEvent),
+	}
+
+	basePath, err := os.MkdirTemp("", "filestore")
+	is.NoErr(err)
+	defer os.RemoveAll(basePath)
+	store, err := filestore.New(basePath)
+	is.NoErr(err)
+
+	caseService := services.NewCaseService(db, testAuth{})
+	fileService := services.NewFileService(db, store, caseService, nil)
+	handler, err := services.NewUploadHandler(db, fileService, caseService, basePath+"/.uploads")
+	is.NoErr(err)
+	handler.ReadTimeout = 300 * time.Millisecond
+
+	// The chunk takes longer than the read-timeout of the
+	// server, but the client doesn't stall between the reads
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ReadTimeout = 300 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	const length = 20
+	r, err := http.NewRequest(http.MethodPost, server.URL+"/upload/", nil)
+	is.NoErr(err)
+	r.Header.Set("Authorization", "owner")
+	r.Header.Set("Upload-Length", strconv.Itoa(length))
+	r.Header.Set("Upload-Metadata", "caseID "+base64.StdEncoding.EncodeToString([]byte(caze.ID))+",name "+base64.StdEncoding.EncodeToString([]byte("evidence.txt")))
+	res, err := http.DefaultClient.Do(r)
+	is.NoErr(err)
+	res.Body.Close()
+	is.Equal(res.StatusCode, http.StatusCreated)
+
+	body, writer := io.Pipe()
+	go func() {
+		for i := 0; i < length; i++ {
+			time.Sleep(50 * time.Millisecond)
+			writer.Write([]byte("x"))
+		}
+		writer.Close()
+	}()
+	r, err = http.NewRequest(http.MethodPatch, server.URL+res.Header.Get("Location"), body)
+	is.NoErr(err)
+	r.ContentLength = length
+	r.Header.Set("Authorization", "owner")
+	r.Header.Set("Content-Type", "application/offset+octet-stream")
+	r.Header.Set("Upload-Offset", "0")
+	res, err = http.DefaultClient.Do(r)
+	is.NoErr(err)
+	res.Body.Close()
+	is.Equal(res.StatusCode, http.StatusNoContent)
+	is.Equal(res.Header.Get("Upload-Offset"), strconv.Itoa(length))
+}
@@ -27,3 +27,20 @@ func GetUser(ctx context.Context) api.User {
 	}
 	return user.(api.User)
 }
+
+// apiTokenKey is the context-key for the API-token,
+// api.Token cannot be used as a key since it isn't comparable
+type apiTokenKey struct{}
+
+// SetAPIToken sets the API-token that was used
+// to authenticate the request to the context
+func SetAPIToken(ctx context.Context, token api.Token) context.Context {
+	return context.WithValue(ctx, apiTokenKey{}, token)
+}
+
+// GetAPIToken gets the API-token from the context, ok is
+// false if the request wasn't authenticated with an API-token
+func GetAPIToken(ctx context.Context) (token api.Token, ok bool) {
+	token, ok = ctx.Value(apiTokenKey{}).(api.Token)
+	return token, ok
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/tests/client"
+	"github.com/matryer/is"
+)
+
+// TestAuthorization tests that users outside
+// of a case cannot read or change it
+func TestAuthorization(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	httpClient := client.New(testURL)
+	httpClient.Debug = func(s string) {
+		log.Println(s)
+	}
+
+	testUser, err := newTestUser(ctx, client.NewTestService(httpClient, ""))
+	is.NoErr(err)
+	defer testUser.delete(ctx)
+	outsider, err := newTestUser(ctx, client.NewTestService(httpClient, ""))
+	is.NoErr(err)
+	defer outsider.delete(ctx)
+
+	caseService := client.NewCaseService(httpClient, testUser.Token)
+	testCase, err := testUser.newTestCase(ctx, caseService)
+	is.NoErr(err)
+
+	event, err := client.NewEventService(httpClient, testUser.Token).Create(ctx, client.EventCreateRequest{
+		CaseID:      testCase.ID,
+		Importance:  3,
+		Description: "event",
+		FromDate:    time.Now().Unix(),
+		ToDate:      time.Now().AddDate(1, 0, 0).Unix(),
+	})
+	is.NoErr(err)
+	link, err := client.NewLinkService(httpClient, testUser.Token).Create(ctx, client.LinkCreateRequest{
+		CaseID: testCase.ID,
+		FromID: event.Created.ID,
+	})
+	is.NoErr(err)
+
+	notAllowed := func(err error) bool {
+		return err != nil && strings.HasPrefix(err.Error(), "not allowed")
+	}
+
+	// The outsider cannot use the links in the case
+	linkService := client.NewLinkService(httpClient, outsider.Token)
+	_, err = linkService.Get(ctx, client.LinkGetRequest{ID: link.Linked.ID, CaseID: testCase.ID})
+	is.True(notAllowed(err))
+	_, err = linkService.Create(ctx, client.LinkCreateRequest{CaseID: testCase.ID, FromID: event.Created.ID})
+	is.True(notAllowed(err))
+	_, err = linkService.Add(ctx, client.LinkAddRequest{ID: link.Linked.ID, CaseID: testCase.ID})
+	is.True(notAllowed(err))
+	_, err = linkService.Remove(ctx, client.LinkRemoveRequest{ID: link.Linked.ID, CaseID: testCase.ID})
+	is.True(notAllowed(err))
+	_, err = linkService.Delete(ctx, client.LinkDeleteRequest{ID: link.Linked.ID, CaseID: testCase.ID})
+	is.True(notAllowed(err))
+
+	// The outsider cannot get or delete the case
+	outsiderCases := client.NewCaseService(httpClient, outsider.Token)
+	_, err = outsiderCases.Get(ctx, client.CaseGetRequest{ID: testCase.ID})
+	is.True(notAllowed(err))
+	_, err = outsiderCases.Delete(ctx, client.CaseDeleteRequest{ID: testCase.ID})
+	is.True(notAllowed(err))
+
+	// The case and the link should still exist
+	_, err = caseService.Get(ctx, client.CaseGetRequest{ID: testCase.ID})
+	is.NoErr(err)
+	_, err = client.NewLinkService(httpClient, testUser.Token).Get(ctx, client.LinkGetRequest{ID: link.Linked.ID, CaseID: testCase.ID})
+	is.NoErr(err)
+}
@@ -67,9 +67,150 @@ func TestCaseService(t *testing.T) {
 	is.Equal(all.Cases[0].FromDate, getResponse.Case.FromDate)
 	is.Equal(all.Cases[0].ToDate, getResponse.Case.ToDate)
 
+	// Update the case
+	updateRequest := client.CaseUpdateRequest{
+		ID:          caze.New.ID,
+		Name:        "Rødspætte",
+		Description: "Updated case",
+		FromDate:    time.Now().AddDate(-1, 0, 0).Unix(),
+		ToDate:      time.Now().Unix(),
+	}
+	updated, err := service.Update(ctx, updateRequest)
+	is.NoErr(err)
+	is.Equal(updated.Updated.ID, caze.New.ID)
+	is.Equal(updated.Updated.Name, updateRequest.Name)
+	is.Equal(updated.Updated.Description, updateRequest.Description)
+	is.Equal(updated.Updated.FromDate, updateRequest.FromDate)
+	is.Equal(updated.Updated.ToDate, updateRequest.ToDate)
+
+	// Update the case with invalid dates
+	_, err = service.Update(ctx, client.CaseUpdateRequest{
+		ID:       caze.New.ID,
+		Name:     "Rødspætte",
+		FromDate: time.Now().Unix(),
+		ToDate:   time.Now().AddDate(-1, 0, 0).Unix(),
+	})
+	is.True(err != nil)
+
+	// Create another user to invite to the case
+	invitedUser, err := newTestUser(ctx, client.NewTestService(httpClient, ""))
+	is.NoErr(err)
+	defer invitedUser.delete(ctx)
+	invitedService := client.NewCaseService(httpClient, invitedUser.Token)
+
+	// The user isn't an investigator in the case yet
+	_, err = invitedService.Get(ctx, client.CaseGetRequest{ID: caze.New.ID})
+	is.True(err != nil)
+
+	// Invite the user to the case
+	invited, err := service.InvestigatorsAdd(ctx, client.CaseInvestigatorsAddRequest{
+		ID:     caze.New.ID,
+		Emails: []string{invitedUser.ID + "@test.com"},
+	})
+	is.NoErr(err)
+	is.Equal(len(invited.Updated.Investigators), 2)
+	_, err = invitedService.Get(ctx, client.CaseGetRequest{ID: caze.New.ID})
+	is.NoErr(err)
+
+	// Only the creator can manage the investigators
+	_, err = invitedService.InvestigatorsRemove(ctx, client.CaseInvestigatorsRemoveRequest{
+		ID:     caze.New.ID,
+		Emails: []string{testUser.ID + "@test.com"},
+	})
+	is.True(err != nil)
+
+	// Remove the invited user from the case
+	removed, err := service.InvestigatorsRemove(ctx, client.CaseInvestigatorsRemoveRequest{
+		ID:     caze.New.ID,
+		Emails: []string{invitedUser.ID + "@test.com"},
+	})
+	is.NoErr(err)
+	is.Equal(len(removed.Updated.Investigators), 1)
+	_, err = invitedService.Get(ctx, client.CaseGetRequest{ID: caze.New.ID})
+	is.True(err != nil)
+
+	// The last investigator cannot be removed
+	_, err = service.InvestigatorsRemove(ctx, client.CaseInvestigatorsRemoveRequest{
+		ID:     caze.New.ID,
+		Emails: []string{testUser.ID + "@test.com"},
+	})
+	is.True(err != nil)
+
 	// Delete the case.
 	_, err = service.Delete(ctx, client.CaseDeleteRequest{ID: caze.New.ID})
 	is.NoErr(err)
 	_, getError := service.Get(ctx, client.CaseGetRequest{ID: caze.New.ID})
 	is.True(getError != nil)
 }
+
+// TestCaseRoles tests the roles of the investigators in a case
+func TestCaseRoles(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	httpClient := client.New(testURL)
+	httpClient.Debug = func(s string) {
+		log.Println(s)
+	}
+
+	owner, err := newTestUser(ctx, client.NewTestService(httpClient, ""))
+	is.NoErr(err)
+	defer owner.delete(ctx)
+	reviewer, err := newTestUser(ctx, client.NewTestService(httpClient, ""))
+	is.NoErr(err)
+	defer reviewer.delete(ctx)
+
+	ownerCases := client.NewCaseService(httpClient, owner.Token)
+	reviewerCases := client.NewCaseService(httpClient, reviewer.Token)
+	reviewerEvents := client.NewEventService(httpClient, reviewer.Token)
+
+	testCase, err := owner.newTestCase(ctx, ownerCases)
+	is.NoErr(err)
+	is.Equal(len(testCase.Roles), 1)
+	is.Equal(testCase.Roles[0].Name, "owner")
+
+	// Invite the reviewer as a viewer
+	invited, err := ownerCases.InvestigatorsAdd(ctx, client.CaseInvestigatorsAddRequest{
+		ID:     testCase.ID,
+		Emails: []string{reviewer.ID + "@test.com"},
+		Role:   "viewer",
+	})
+	is.NoErr(err)
+	is.Equal(len(invited.Updated.Roles), 2)
+	is.Equal(invited.Updated.Roles[1].Name, "viewer")
+
+	// The viewer can browse the case
+	_, err = reviewerCases.Get(ctx, client.CaseGetRequest{ID: testCase.ID})
+	is.NoErr(err)
+
+	// The viewer cannot change the evidence
+	eventRequest := client.EventCreateRequest{
+		CaseID:      testCase.ID,
+		Importance:  3,
+		Description: "event",
+		FromDate:    time.Now().Unix(),
+		ToDate:      time.Now().AddDate(1, 0, 0).Unix(),
+	}
+	_, err = reviewerEvents.Create(ctx, eventRequest)
+	is.True(err != nil)
+
+	// The viewer cannot manage the roles
+	_, err = reviewerCases.RoleSet(ctx, client.CaseRoleSetRequest{ID: testCase.ID, Email: reviewer.ID + "@test.com", Role: "owner"})
+	is.True(err != nil)
+
+	// Invalid roles cannot be set
+	_, err = ownerCases.RoleSet(ctx, client.CaseRoleSetRequest{ID: testCase.ID, Email: reviewer.ID + "@test.com", Role: "janitor"})
+	is.True(err != nil)
+
+	// Make the viewer an editor
+	updated, err := ownerCases.RoleSet(ctx, client.CaseRoleSetRequest{ID: testCase.ID, Email: reviewer.ID + "@test.com", Role: "editor"})
+	is.NoErr(err)
+	is.Equal(updated.Updated.Roles[1].Name, "editor")
+
+	// The editor can change the evidence
+	_, err = reviewerEvents.Create(ctx, eventRequest)
+	is.NoErr(err)
+
+	// The editor cannot update the case
+	_, err = reviewerCases.Update(ctx, client.CaseUpdateRequest{ID: testCase.ID, Name: "Helleflynder"})
+	is.True(err != nil)
+}
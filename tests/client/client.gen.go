@@ -38,32 +38,33 @@ func New(remoteHost string) *Client {
 	return c
 }
 
-// CaseService is the API to handle cases
-type CaseService struct {
+// AdminService is the API for system-administrators to manage every case in the
+// system
+type AdminService struct {
 	client *Client
 	token  string
 }
 
-// NewCaseService makes a new client for accessing CaseService services.
-func NewCaseService(client *Client, token string) *CaseService {
-	return &CaseService{
+// NewAdminService makes a new client for accessing AdminService services.
+func NewAdminService(client *Client, token string) *AdminService {
+	return &AdminService{
 		client: client,
 		token:  token,
 	}
 }
 
-// Delete deletes the specified case
-func (s *CaseService) Delete(ctx context.Context, r CaseDeleteRequest) (*CaseDeleteResponse, error) {
+// Cases lists every case in the system
+func (s *AdminService) Cases(ctx context.Context, r AdminCasesRequest) (*AdminCasesResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Delete: marshal CaseDeleteRequest")
+		return nil, errors.Wrap(err, "AdminService.Cases: marshal AdminCasesRequest")
 	}
-	url := s.client.RemoteHost + "CaseService.Delete"
+	url := s.client.RemoteHost + "AdminService.Cases"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Delete: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.Cases: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -71,51 +72,52 @@ func (s *CaseService) Delete(ctx context.Context, r CaseDeleteRequest) (*CaseDel
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Delete")
+		return nil, errors.Wrap(err, "AdminService.Cases")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		CaseDeleteResponse
+		AdminCasesResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "CaseService.Delete: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.Cases: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Delete: read response body")
+		return nil, errors.Wrap(err, "AdminService.Cases: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("CaseService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.Cases: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.CaseDeleteResponse, nil
+	return &response.AdminCasesResponse, nil
 }
 
-// Get returns the requested case
-func (s *CaseService) Get(ctx context.Context, r CaseGetRequest) (*CaseGetResponse, error) {
+// CreateUser creates a user that signs in with a password, only with the local
+// authentication-provider
+func (s *AdminService) CreateUser(ctx context.Context, r AdminCreateUserRequest) (*AdminCreateUserResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Get: marshal CaseGetRequest")
+		return nil, errors.Wrap(err, "AdminService.CreateUser: marshal AdminCreateUserRequest")
 	}
-	url := s.client.RemoteHost + "CaseService.Get"
+	url := s.client.RemoteHost + "AdminService.CreateUser"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Get: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.CreateUser: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -123,51 +125,51 @@ func (s *CaseService) Get(ctx context.Context, r CaseGetRequest) (*CaseGetRespon
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Get")
+		return nil, errors.Wrap(err, "AdminService.CreateUser")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		CaseGetResponse
+		AdminCreateUserResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "CaseService.Get: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.CreateUser: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Get: read response body")
+		return nil, errors.Wrap(err, "AdminService.CreateUser: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("CaseService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.CreateUser: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.CaseGetResponse, nil
+	return &response.AdminCreateUserResponse, nil
 }
 
-// Keywords lists all the keywords for the case
-func (s *CaseService) Keywords(ctx context.Context, r CaseKeywordsRequest) (*CaseKeywordsResponse, error) {
+// Delete deletes a case without being an owner of it
+func (s *AdminService) Delete(ctx context.Context, r AdminDeleteRequest) (*AdminDeleteResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Keywords: marshal CaseKeywordsRequest")
+		return nil, errors.Wrap(err, "AdminService.Delete: marshal AdminDeleteRequest")
 	}
-	url := s.client.RemoteHost + "CaseService.Keywords"
+	url := s.client.RemoteHost + "AdminService.Delete"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Keywords: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.Delete: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -175,51 +177,51 @@ func (s *CaseService) Keywords(ctx context.Context, r CaseKeywordsRequest) (*Cas
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Keywords")
+		return nil, errors.Wrap(err, "AdminService.Delete")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		CaseKeywordsResponse
+		AdminDeleteResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "CaseService.Keywords: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.Delete: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Keywords: read response body")
+		return nil, errors.Wrap(err, "AdminService.Delete: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("CaseService.Keywords: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.CaseKeywordsResponse, nil
+	return &response.AdminDeleteResponse, nil
 }
 
-// List the cases for a specified user
-func (s *CaseService) List(ctx context.Context, r CaseListRequest) (*CaseListResponse, error) {
+// DeleteUser deletes a user, only with the local authentication-provider
+func (s *AdminService) DeleteUser(ctx context.Context, r AdminDeleteUserRequest) (*AdminDeleteUserResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.List: marshal CaseListRequest")
+		return nil, errors.Wrap(err, "AdminService.DeleteUser: marshal AdminDeleteUserRequest")
 	}
-	url := s.client.RemoteHost + "CaseService.List"
+	url := s.client.RemoteHost + "AdminService.DeleteUser"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.List: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.DeleteUser: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -227,51 +229,52 @@ func (s *CaseService) List(ctx context.Context, r CaseListRequest) (*CaseListRes
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.List")
+		return nil, errors.Wrap(err, "AdminService.DeleteUser")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		CaseListResponse
+		AdminDeleteUserResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "CaseService.List: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.DeleteUser: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.List: read response body")
+		return nil, errors.Wrap(err, "AdminService.DeleteUser: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("CaseService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.DeleteUser: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.CaseListResponse, nil
+	return &response.AdminDeleteUserResponse, nil
 }
 
-// New creates a new case
-func (s *CaseService) New(ctx context.Context, r CaseNewRequest) (*CaseNewResponse, error) {
+// ResetPassword sets a new password for a user, only with the local
+// authentication-provider
+func (s *AdminService) ResetPassword(ctx context.Context, r AdminResetPasswordRequest) (*AdminResetPasswordResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.New: marshal CaseNewRequest")
+		return nil, errors.Wrap(err, "AdminService.ResetPassword: marshal AdminResetPasswordRequest")
 	}
-	url := s.client.RemoteHost + "CaseService.New"
+	url := s.client.RemoteHost + "AdminService.ResetPassword"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.New: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.ResetPassword: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -279,51 +282,52 @@ func (s *CaseService) New(ctx context.Context, r CaseNewRequest) (*CaseNewRespon
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.New")
+		return nil, errors.Wrap(err, "AdminService.ResetPassword")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		CaseNewResponse
+		AdminResetPasswordResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "CaseService.New: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.ResetPassword: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.New: read response body")
+		return nil, errors.Wrap(err, "AdminService.ResetPassword: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("CaseService.New: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.ResetPassword: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.CaseNewResponse, nil
+	return &response.AdminResetPasswordResponse, nil
 }
 
-// Update updates the specified case
-func (s *CaseService) Update(ctx context.Context, r CaseUpdateRequest) (*CaseUpdateResponse, error) {
+// RotateKey rotates the data-key that encrypts the files in a case, new files are
+// encrypted with the new key
+func (s *AdminService) RotateKey(ctx context.Context, r AdminRotateKeyRequest) (*AdminRotateKeyResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Update: marshal CaseUpdateRequest")
+		return nil, errors.Wrap(err, "AdminService.RotateKey: marshal AdminRotateKeyRequest")
 	}
-	url := s.client.RemoteHost + "CaseService.Update"
+	url := s.client.RemoteHost + "AdminService.RotateKey"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Update: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.RotateKey: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -331,65 +335,52 @@ func (s *CaseService) Update(ctx context.Context, r CaseUpdateRequest) (*CaseUpd
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Update")
+		return nil, errors.Wrap(err, "AdminService.RotateKey")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		CaseUpdateResponse
+		AdminRotateKeyResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "CaseService.Update: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.RotateKey: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "CaseService.Update: read response body")
+		return nil, errors.Wrap(err, "AdminService.RotateKey: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("CaseService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.RotateKey: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.CaseUpdateResponse, nil
-}
-
-// EntityService is the API to handle entities
-type EntityService struct {
-	client *Client
-	token  string
-}
-
-// NewEntityService makes a new client for accessing EntityService services.
-func NewEntityService(client *Client, token string) *EntityService {
-	return &EntityService{
-		client: client,
-		token:  token,
-	}
+	return &response.AdminRotateKeyResponse, nil
 }
 
-// Create creates a new entity
-func (s *EntityService) Create(ctx context.Context, r EntityCreateRequest) (*EntityCreateResponse, error) {
+// Transfer transfers the ownership of a case to another user, for example when an
+// investigator has left the organization
+func (s *AdminService) Transfer(ctx context.Context, r AdminTransferRequest) (*AdminTransferResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Create: marshal EntityCreateRequest")
+		return nil, errors.Wrap(err, "AdminService.Transfer: marshal AdminTransferRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.Create"
+	url := s.client.RemoteHost + "AdminService.Transfer"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Create: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.Transfer: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -397,51 +388,51 @@ func (s *EntityService) Create(ctx context.Context, r EntityCreateRequest) (*Ent
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Create")
+		return nil, errors.Wrap(err, "AdminService.Transfer")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EntityCreateResponse
+		AdminTransferResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.Create: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.Transfer: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Create: read response body")
+		return nil, errors.Wrap(err, "AdminService.Transfer: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.Transfer: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EntityCreateResponse, nil
+	return &response.AdminTransferResponse, nil
 }
 
-// Delete deletes an existing entity
-func (s *EntityService) Delete(ctx context.Context, r EntityDeleteRequest) (*EntityDeleteResponse, error) {
+// Users lists the users with their memberships in the cases
+func (s *AdminService) Users(ctx context.Context, r AdminUsersRequest) (*AdminUsersResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Delete: marshal EntityDeleteRequest")
+		return nil, errors.Wrap(err, "AdminService.Users: marshal AdminUsersRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.Delete"
+	url := s.client.RemoteHost + "AdminService.Users"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Delete: NewRequest")
+		return nil, errors.Wrap(err, "AdminService.Users: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -449,51 +440,65 @@ func (s *EntityService) Delete(ctx context.Context, r EntityDeleteRequest) (*Ent
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Delete")
+		return nil, errors.Wrap(err, "AdminService.Users")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EntityDeleteResponse
+		AdminUsersResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.Delete: new gzip reader")
+			return nil, errors.Wrap(err, "AdminService.Users: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Delete: read response body")
+		return nil, errors.Wrap(err, "AdminService.Users: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AdminService.Users: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EntityDeleteResponse, nil
+	return &response.AdminUsersResponse, nil
 }
 
-// Get the specified entity
-func (s *EntityService) Get(ctx context.Context, r EntityGetRequest) (*EntityGetResponse, error) {
+// AuthService is the API to sign in users with the local authentication-provider
+type AuthService struct {
+	client *Client
+	token  string
+}
+
+// NewAuthService makes a new client for accessing AuthService services.
+func NewAuthService(client *Client, token string) *AuthService {
+	return &AuthService{
+		client: client,
+		token:  token,
+	}
+}
+
+// SignIn signs in the user with email and password and returns a token
+func (s *AuthService) SignIn(ctx context.Context, r AuthSignInRequest) (*AuthSignInResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Get: marshal EntityGetRequest")
+		return nil, errors.Wrap(err, "AuthService.SignIn: marshal AuthSignInRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.Get"
+	url := s.client.RemoteHost + "AuthService.SignIn"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Get: NewRequest")
+		return nil, errors.Wrap(err, "AuthService.SignIn: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -501,51 +506,65 @@ func (s *EntityService) Get(ctx context.Context, r EntityGetRequest) (*EntityGet
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Get")
+		return nil, errors.Wrap(err, "AuthService.SignIn")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EntityGetResponse
+		AuthSignInResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.Get: new gzip reader")
+			return nil, errors.Wrap(err, "AuthService.SignIn: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Get: read response body")
+		return nil, errors.Wrap(err, "AuthService.SignIn: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("AuthService.SignIn: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EntityGetResponse, nil
+	return &response.AuthSignInResponse, nil
 }
 
-// KeywordsAdd to an entity
-func (s *EntityService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
+// CaseService is the API to handle cases
+type CaseService struct {
+	client *Client
+	token  string
+}
+
+// NewCaseService makes a new client for accessing CaseService services.
+func NewCaseService(client *Client, token string) *CaseService {
+	return &CaseService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Delete deletes the specified case
+func (s *CaseService) Delete(ctx context.Context, r CaseDeleteRequest) (*CaseDeleteResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsAdd: marshal KeywordsAddRequest")
+		return nil, errors.Wrap(err, "CaseService.Delete: marshal CaseDeleteRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.KeywordsAdd"
+	url := s.client.RemoteHost + "CaseService.Delete"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsAdd: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.Delete: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -553,51 +572,51 @@ func (s *EntityService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsAdd")
+		return nil, errors.Wrap(err, "CaseService.Delete")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsAddResponse
+		CaseDeleteResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.KeywordsAdd: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.Delete: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsAdd: read response body")
+		return nil, errors.Wrap(err, "CaseService.Delete: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsAddResponse, nil
+	return &response.CaseDeleteResponse, nil
 }
 
-// KeywordsRemove from an entity
-func (s *EntityService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
+// Get returns the requested case
+func (s *CaseService) Get(ctx context.Context, r CaseGetRequest) (*CaseGetResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsRemove: marshal KeywordsRemoveRequest")
+		return nil, errors.Wrap(err, "CaseService.Get: marshal CaseGetRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.KeywordsRemove"
+	url := s.client.RemoteHost + "CaseService.Get"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsRemove: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.Get: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -605,51 +624,51 @@ func (s *EntityService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequ
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsRemove")
+		return nil, errors.Wrap(err, "CaseService.Get")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsRemoveResponse
+		CaseGetResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.KeywordsRemove: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.Get: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.KeywordsRemove: read response body")
+		return nil, errors.Wrap(err, "CaseService.Get: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsRemoveResponse, nil
+	return &response.CaseGetResponse, nil
 }
 
-// List all entities
-func (s *EntityService) List(ctx context.Context, r EntityListRequest) (*EntityListResponse, error) {
+// InvestigatorsAdd invites investigators to the case
+func (s *CaseService) InvestigatorsAdd(ctx context.Context, r CaseInvestigatorsAddRequest) (*CaseInvestigatorsAddResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.List: marshal EntityListRequest")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsAdd: marshal CaseInvestigatorsAddRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.List"
+	url := s.client.RemoteHost + "CaseService.InvestigatorsAdd"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.List: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsAdd: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -657,51 +676,52 @@ func (s *EntityService) List(ctx context.Context, r EntityListRequest) (*EntityL
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.List")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsAdd")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EntityListResponse
+		CaseInvestigatorsAddResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.List: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.InvestigatorsAdd: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.List: read response body")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsAdd: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.InvestigatorsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EntityListResponse, nil
+	return &response.CaseInvestigatorsAddResponse, nil
 }
 
-// Types returns the existing entity-types
-func (s *EntityService) Types(ctx context.Context, r EntityTypesRequest) (*EntityTypesResponse, error) {
+// InvestigatorsRemove removes investigators from the case, the last owner of the
+// case cannot be removed
+func (s *CaseService) InvestigatorsRemove(ctx context.Context, r CaseInvestigatorsRemoveRequest) (*CaseInvestigatorsRemoveResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Types: marshal EntityTypesRequest")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsRemove: marshal CaseInvestigatorsRemoveRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.Types"
+	url := s.client.RemoteHost + "CaseService.InvestigatorsRemove"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Types: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsRemove: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -709,51 +729,51 @@ func (s *EntityService) Types(ctx context.Context, r EntityTypesRequest) (*Entit
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Types")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsRemove")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EntityTypesResponse
+		CaseInvestigatorsRemoveResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.Types: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.InvestigatorsRemove: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Types: read response body")
+		return nil, errors.Wrap(err, "CaseService.InvestigatorsRemove: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.Types: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.InvestigatorsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EntityTypesResponse, nil
+	return &response.CaseInvestigatorsRemoveResponse, nil
 }
 
-// Update updates an existing entity
-func (s *EntityService) Update(ctx context.Context, r EntityUpdateRequest) (*EntityUpdateResponse, error) {
+// Keywords lists all the keywords for the case
+func (s *CaseService) Keywords(ctx context.Context, r CaseKeywordsRequest) (*CaseKeywordsResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Update: marshal EntityUpdateRequest")
+		return nil, errors.Wrap(err, "CaseService.Keywords: marshal CaseKeywordsRequest")
 	}
-	url := s.client.RemoteHost + "EntityService.Update"
+	url := s.client.RemoteHost + "CaseService.Keywords"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Update: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.Keywords: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -761,65 +781,51 @@ func (s *EntityService) Update(ctx context.Context, r EntityUpdateRequest) (*Ent
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Update")
+		return nil, errors.Wrap(err, "CaseService.Keywords")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EntityUpdateResponse
+		CaseKeywordsResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EntityService.Update: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.Keywords: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EntityService.Update: read response body")
+		return nil, errors.Wrap(err, "CaseService.Keywords: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EntityService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.Keywords: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EntityUpdateResponse, nil
-}
-
-// EventService is the API to handle events
-type EventService struct {
-	client *Client
-	token  string
-}
-
-// NewEventService makes a new client for accessing EventService services.
-func NewEventService(client *Client, token string) *EventService {
-	return &EventService{
-		client: client,
-		token:  token,
-	}
+	return &response.CaseKeywordsResponse, nil
 }
 
-// Create creates a new event
-func (s *EventService) Create(ctx context.Context, r EventCreateRequest) (*EventCreateResponse, error) {
+// List the cases for a specified user
+func (s *CaseService) List(ctx context.Context, r CaseListRequest) (*CaseListResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Create: marshal EventCreateRequest")
+		return nil, errors.Wrap(err, "CaseService.List: marshal CaseListRequest")
 	}
-	url := s.client.RemoteHost + "EventService.Create"
+	url := s.client.RemoteHost + "CaseService.List"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Create: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.List: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -827,51 +833,51 @@ func (s *EventService) Create(ctx context.Context, r EventCreateRequest) (*Event
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Create")
+		return nil, errors.Wrap(err, "CaseService.List")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EventCreateResponse
+		CaseListResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.Create: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.List: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Create: read response body")
+		return nil, errors.Wrap(err, "CaseService.List: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EventCreateResponse, nil
+	return &response.CaseListResponse, nil
 }
 
-// Delete deletes an existing event
-func (s *EventService) Delete(ctx context.Context, r EventDeleteRequest) (*EventDeleteResponse, error) {
+// New creates a new case
+func (s *CaseService) New(ctx context.Context, r CaseNewRequest) (*CaseNewResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Delete: marshal EventDeleteRequest")
+		return nil, errors.Wrap(err, "CaseService.New: marshal CaseNewRequest")
 	}
-	url := s.client.RemoteHost + "EventService.Delete"
+	url := s.client.RemoteHost + "CaseService.New"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Delete: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.New: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -879,51 +885,52 @@ func (s *EventService) Delete(ctx context.Context, r EventDeleteRequest) (*Event
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Delete")
+		return nil, errors.Wrap(err, "CaseService.New")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EventDeleteResponse
+		CaseNewResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.Delete: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.New: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Delete: read response body")
+		return nil, errors.Wrap(err, "CaseService.New: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.New: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EventDeleteResponse, nil
+	return &response.CaseNewResponse, nil
 }
 
-// Get the specified event
-func (s *EventService) Get(ctx context.Context, r EventGetRequest) (*EventGetResponse, error) {
+// RoleSet sets the role for an investigator in the case, the last owner of the
+// case cannot be demoted
+func (s *CaseService) RoleSet(ctx context.Context, r CaseRoleSetRequest) (*CaseRoleSetResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Get: marshal EventGetRequest")
+		return nil, errors.Wrap(err, "CaseService.RoleSet: marshal CaseRoleSetRequest")
 	}
-	url := s.client.RemoteHost + "EventService.Get"
+	url := s.client.RemoteHost + "CaseService.RoleSet"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Get: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.RoleSet: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -931,51 +938,51 @@ func (s *EventService) Get(ctx context.Context, r EventGetRequest) (*EventGetRes
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Get")
+		return nil, errors.Wrap(err, "CaseService.RoleSet")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EventGetResponse
+		CaseRoleSetResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.Get: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.RoleSet: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Get: read response body")
+		return nil, errors.Wrap(err, "CaseService.RoleSet: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.RoleSet: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EventGetResponse, nil
+	return &response.CaseRoleSetResponse, nil
 }
 
-// KeywordsAdd to an event
-func (s *EventService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
+// Update updates the specified case
+func (s *CaseService) Update(ctx context.Context, r CaseUpdateRequest) (*CaseUpdateResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsAdd: marshal KeywordsAddRequest")
+		return nil, errors.Wrap(err, "CaseService.Update: marshal CaseUpdateRequest")
 	}
-	url := s.client.RemoteHost + "EventService.KeywordsAdd"
+	url := s.client.RemoteHost + "CaseService.Update"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsAdd: NewRequest")
+		return nil, errors.Wrap(err, "CaseService.Update: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -983,51 +990,65 @@ func (s *EventService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsAdd")
+		return nil, errors.Wrap(err, "CaseService.Update")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsAddResponse
+		CaseUpdateResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.KeywordsAdd: new gzip reader")
+			return nil, errors.Wrap(err, "CaseService.Update: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsAdd: read response body")
+		return nil, errors.Wrap(err, "CaseService.Update: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("CaseService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsAddResponse, nil
+	return &response.CaseUpdateResponse, nil
 }
 
-// KeywordsRemove from an event
-func (s *EventService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
+// EntityService is the API to handle entities
+type EntityService struct {
+	client *Client
+	token  string
+}
+
+// NewEntityService makes a new client for accessing EntityService services.
+func NewEntityService(client *Client, token string) *EntityService {
+	return &EntityService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Create creates a new entity
+func (s *EntityService) Create(ctx context.Context, r EntityCreateRequest) (*EntityCreateResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsRemove: marshal KeywordsRemoveRequest")
+		return nil, errors.Wrap(err, "EntityService.Create: marshal EntityCreateRequest")
 	}
-	url := s.client.RemoteHost + "EventService.KeywordsRemove"
+	url := s.client.RemoteHost + "EntityService.Create"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsRemove: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.Create: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1035,51 +1056,51 @@ func (s *EventService) KeywordsRemove(ctx context.Context, r KeywordsRemoveReque
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsRemove")
+		return nil, errors.Wrap(err, "EntityService.Create")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsRemoveResponse
+		EntityCreateResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.KeywordsRemove: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.Create: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.KeywordsRemove: read response body")
+		return nil, errors.Wrap(err, "EntityService.Create: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsRemoveResponse, nil
+	return &response.EntityCreateResponse, nil
 }
 
-// List all events
-func (s *EventService) List(ctx context.Context, r EventListRequest) (*EventListResponse, error) {
+// Delete deletes an existing entity
+func (s *EntityService) Delete(ctx context.Context, r EntityDeleteRequest) (*EntityDeleteResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.List: marshal EventListRequest")
+		return nil, errors.Wrap(err, "EntityService.Delete: marshal EntityDeleteRequest")
 	}
-	url := s.client.RemoteHost + "EventService.List"
+	url := s.client.RemoteHost + "EntityService.Delete"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.List: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.Delete: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1087,51 +1108,51 @@ func (s *EventService) List(ctx context.Context, r EventListRequest) (*EventList
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.List")
+		return nil, errors.Wrap(err, "EntityService.Delete")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EventListResponse
+		EntityDeleteResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.List: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.Delete: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.List: read response body")
+		return nil, errors.Wrap(err, "EntityService.Delete: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EventListResponse, nil
+	return &response.EntityDeleteResponse, nil
 }
 
-// Update updates an existing event
-func (s *EventService) Update(ctx context.Context, r EventUpdateRequest) (*EventUpdateResponse, error) {
+// Get the specified entity
+func (s *EntityService) Get(ctx context.Context, r EntityGetRequest) (*EntityGetResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Update: marshal EventUpdateRequest")
+		return nil, errors.Wrap(err, "EntityService.Get: marshal EntityGetRequest")
 	}
-	url := s.client.RemoteHost + "EventService.Update"
+	url := s.client.RemoteHost + "EntityService.Get"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Update: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.Get: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1139,65 +1160,51 @@ func (s *EventService) Update(ctx context.Context, r EventUpdateRequest) (*Event
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Update")
+		return nil, errors.Wrap(err, "EntityService.Get")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		EventUpdateResponse
+		EntityGetResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "EventService.Update: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.Get: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "EventService.Update: read response body")
+		return nil, errors.Wrap(err, "EntityService.Get: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("EventService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.EventUpdateResponse, nil
-}
-
-// FileService is the API for handling files
-type FileService struct {
-	client *Client
-	token  string
-}
-
-// NewFileService makes a new client for accessing FileService services.
-func NewFileService(client *Client, token string) *FileService {
-	return &FileService{
-		client: client,
-		token:  token,
-	}
+	return &response.EntityGetResponse, nil
 }
 
-// Delete deletes the specified file
-func (s *FileService) Delete(ctx context.Context, r FileDeleteRequest) (*FileDeleteResponse, error) {
+// KeywordsAdd to an entity
+func (s *EntityService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Delete: marshal FileDeleteRequest")
+		return nil, errors.Wrap(err, "EntityService.KeywordsAdd: marshal KeywordsAddRequest")
 	}
-	url := s.client.RemoteHost + "FileService.Delete"
+	url := s.client.RemoteHost + "EntityService.KeywordsAdd"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Delete: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.KeywordsAdd: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1205,51 +1212,51 @@ func (s *FileService) Delete(ctx context.Context, r FileDeleteRequest) (*FileDel
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Delete")
+		return nil, errors.Wrap(err, "EntityService.KeywordsAdd")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileDeleteResponse
+		KeywordsAddResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.Delete: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.KeywordsAdd: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Delete: read response body")
+		return nil, errors.Wrap(err, "EntityService.KeywordsAdd: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileDeleteResponse, nil
+	return &response.KeywordsAddResponse, nil
 }
 
-// KeywordsAdd to a file
-func (s *FileService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
+// KeywordsRemove from an entity
+func (s *EntityService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsAdd: marshal KeywordsAddRequest")
+		return nil, errors.Wrap(err, "EntityService.KeywordsRemove: marshal KeywordsRemoveRequest")
 	}
-	url := s.client.RemoteHost + "FileService.KeywordsAdd"
+	url := s.client.RemoteHost + "EntityService.KeywordsRemove"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsAdd: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.KeywordsRemove: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1257,51 +1264,51 @@ func (s *FileService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*K
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsAdd")
+		return nil, errors.Wrap(err, "EntityService.KeywordsRemove")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsAddResponse
+		KeywordsRemoveResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.KeywordsAdd: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.KeywordsRemove: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsAdd: read response body")
+		return nil, errors.Wrap(err, "EntityService.KeywordsRemove: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsAddResponse, nil
+	return &response.KeywordsRemoveResponse, nil
 }
 
-// KeywordsRemove from a file
-func (s *FileService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
+// List all entities
+func (s *EntityService) List(ctx context.Context, r EntityListRequest) (*EntityListResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsRemove: marshal KeywordsRemoveRequest")
+		return nil, errors.Wrap(err, "EntityService.List: marshal EntityListRequest")
 	}
-	url := s.client.RemoteHost + "FileService.KeywordsRemove"
+	url := s.client.RemoteHost + "EntityService.List"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsRemove: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.List: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1309,51 +1316,51 @@ func (s *FileService) KeywordsRemove(ctx context.Context, r KeywordsRemoveReques
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsRemove")
+		return nil, errors.Wrap(err, "EntityService.List")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsRemoveResponse
+		EntityListResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.KeywordsRemove: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.List: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.KeywordsRemove: read response body")
+		return nil, errors.Wrap(err, "EntityService.List: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsRemoveResponse, nil
+	return &response.EntityListResponse, nil
 }
 
-// New uploads a file to the backend
-func (s *FileService) New(ctx context.Context, r FileNewRequest) (*FileNewResponse, error) {
+// Types returns the existing entity-types
+func (s *EntityService) Types(ctx context.Context, r EntityTypesRequest) (*EntityTypesResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.New: marshal FileNewRequest")
+		return nil, errors.Wrap(err, "EntityService.Types: marshal EntityTypesRequest")
 	}
-	url := s.client.RemoteHost + "FileService.New"
+	url := s.client.RemoteHost + "EntityService.Types"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.New: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.Types: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1361,51 +1368,51 @@ func (s *FileService) New(ctx context.Context, r FileNewRequest) (*FileNewRespon
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.New")
+		return nil, errors.Wrap(err, "EntityService.Types")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileNewResponse
+		EntityTypesResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.New: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.Types: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.New: read response body")
+		return nil, errors.Wrap(err, "EntityService.Types: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.New: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.Types: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileNewResponse, nil
+	return &response.EntityTypesResponse, nil
 }
 
-// Open opens a file
-func (s *FileService) Open(ctx context.Context, r FileOpenRequest) (*FileOpenResponse, error) {
+// Update updates an existing entity
+func (s *EntityService) Update(ctx context.Context, r EntityUpdateRequest) (*EntityUpdateResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Open: marshal FileOpenRequest")
+		return nil, errors.Wrap(err, "EntityService.Update: marshal EntityUpdateRequest")
 	}
-	url := s.client.RemoteHost + "FileService.Open"
+	url := s.client.RemoteHost + "EntityService.Update"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Open: NewRequest")
+		return nil, errors.Wrap(err, "EntityService.Update: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1413,51 +1420,65 @@ func (s *FileService) Open(ctx context.Context, r FileOpenRequest) (*FileOpenRes
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Open")
+		return nil, errors.Wrap(err, "EntityService.Update")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileOpenResponse
+		EntityUpdateResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.Open: new gzip reader")
+			return nil, errors.Wrap(err, "EntityService.Update: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Open: read response body")
+		return nil, errors.Wrap(err, "EntityService.Update: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.Open: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EntityService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileOpenResponse, nil
+	return &response.EntityUpdateResponse, nil
 }
 
-// Process processes a file
-func (s *FileService) Process(ctx context.Context, r FileProcessRequest) (*FileProcessResponse, error) {
+// EventService is the API to handle events
+type EventService struct {
+	client *Client
+	token  string
+}
+
+// NewEventService makes a new client for accessing EventService services.
+func NewEventService(client *Client, token string) *EventService {
+	return &EventService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Create creates a new event
+func (s *EventService) Create(ctx context.Context, r EventCreateRequest) (*EventCreateResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Process: marshal FileProcessRequest")
+		return nil, errors.Wrap(err, "EventService.Create: marshal EventCreateRequest")
 	}
-	url := s.client.RemoteHost + "FileService.Process"
+	url := s.client.RemoteHost + "EventService.Create"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Process: NewRequest")
+		return nil, errors.Wrap(err, "EventService.Create: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1465,51 +1486,51 @@ func (s *FileService) Process(ctx context.Context, r FileProcessRequest) (*FileP
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Process")
+		return nil, errors.Wrap(err, "EventService.Create")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileProcessResponse
+		EventCreateResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.Process: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.Create: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Process: read response body")
+		return nil, errors.Wrap(err, "EventService.Create: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.Process: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileProcessResponse, nil
+	return &response.EventCreateResponse, nil
 }
 
-// Processed gets information for a processed file
-func (s *FileService) Processed(ctx context.Context, r FileProcessedRequest) (*FileProcessedResponse, error) {
+// Delete deletes an existing event
+func (s *EventService) Delete(ctx context.Context, r EventDeleteRequest) (*EventDeleteResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processed: marshal FileProcessedRequest")
+		return nil, errors.Wrap(err, "EventService.Delete: marshal EventDeleteRequest")
 	}
-	url := s.client.RemoteHost + "FileService.Processed"
+	url := s.client.RemoteHost + "EventService.Delete"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processed: NewRequest")
+		return nil, errors.Wrap(err, "EventService.Delete: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1517,51 +1538,51 @@ func (s *FileService) Processed(ctx context.Context, r FileProcessedRequest) (*F
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processed")
+		return nil, errors.Wrap(err, "EventService.Delete")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileProcessedResponse
+		EventDeleteResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.Processed: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.Delete: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processed: read response body")
+		return nil, errors.Wrap(err, "EventService.Delete: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.Processed: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileProcessedResponse, nil
+	return &response.EventDeleteResponse, nil
 }
 
-// Processes gets information for all proccesed files in the specified case
-func (s *FileService) Processes(ctx context.Context, r FileProcessesRequest) (*FileProcessesResponse, error) {
+// Get the specified event
+func (s *EventService) Get(ctx context.Context, r EventGetRequest) (*EventGetResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processes: marshal FileProcessesRequest")
+		return nil, errors.Wrap(err, "EventService.Get: marshal EventGetRequest")
 	}
-	url := s.client.RemoteHost + "FileService.Processes"
+	url := s.client.RemoteHost + "EventService.Get"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processes: NewRequest")
+		return nil, errors.Wrap(err, "EventService.Get: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1569,51 +1590,51 @@ func (s *FileService) Processes(ctx context.Context, r FileProcessesRequest) (*F
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processes")
+		return nil, errors.Wrap(err, "EventService.Get")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileProcessesResponse
+		EventGetResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.Processes: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.Get: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Processes: read response body")
+		return nil, errors.Wrap(err, "EventService.Get: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.Processes: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileProcessesResponse, nil
+	return &response.EventGetResponse, nil
 }
 
-// Update updates the information for a file
-func (s *FileService) Update(ctx context.Context, r FileUpdateRequest) (*FileUpdateResponse, error) {
+// KeywordsAdd to an event
+func (s *EventService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Update: marshal FileUpdateRequest")
+		return nil, errors.Wrap(err, "EventService.KeywordsAdd: marshal KeywordsAddRequest")
 	}
-	url := s.client.RemoteHost + "FileService.Update"
+	url := s.client.RemoteHost + "EventService.KeywordsAdd"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Update: NewRequest")
+		return nil, errors.Wrap(err, "EventService.KeywordsAdd: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1621,65 +1642,51 @@ func (s *FileService) Update(ctx context.Context, r FileUpdateRequest) (*FileUpd
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Update")
+		return nil, errors.Wrap(err, "EventService.KeywordsAdd")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		FileUpdateResponse
+		KeywordsAddResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "FileService.Update: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.KeywordsAdd: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileService.Update: read response body")
+		return nil, errors.Wrap(err, "EventService.KeywordsAdd: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("FileService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.FileUpdateResponse, nil
-}
-
-// LinkService is a API for creating links between objects
-type LinkService struct {
-	client *Client
-	token  string
-}
-
-// NewLinkService makes a new client for accessing LinkService services.
-func NewLinkService(client *Client, token string) *LinkService {
-	return &LinkService{
-		client: client,
-		token:  token,
-	}
+	return &response.KeywordsAddResponse, nil
 }
 
-// Add adds specified links to an object
-func (s *LinkService) Add(ctx context.Context, r LinkAddRequest) (*LinkAddResponse, error) {
+// KeywordsRemove from an event
+func (s *EventService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Add: marshal LinkAddRequest")
+		return nil, errors.Wrap(err, "EventService.KeywordsRemove: marshal KeywordsRemoveRequest")
 	}
-	url := s.client.RemoteHost + "LinkService.Add"
+	url := s.client.RemoteHost + "EventService.KeywordsRemove"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Add: NewRequest")
+		return nil, errors.Wrap(err, "EventService.KeywordsRemove: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1687,51 +1694,51 @@ func (s *LinkService) Add(ctx context.Context, r LinkAddRequest) (*LinkAddRespon
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Add")
+		return nil, errors.Wrap(err, "EventService.KeywordsRemove")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		LinkAddResponse
+		KeywordsRemoveResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "LinkService.Add: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.KeywordsRemove: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Add: read response body")
+		return nil, errors.Wrap(err, "EventService.KeywordsRemove: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("LinkService.Add: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.LinkAddResponse, nil
+	return &response.KeywordsRemoveResponse, nil
 }
 
-// Create creates a links for an object
-func (s *LinkService) Create(ctx context.Context, r LinkCreateRequest) (*LinkCreateResponse, error) {
+// List all events
+func (s *EventService) List(ctx context.Context, r EventListRequest) (*EventListResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Create: marshal LinkCreateRequest")
+		return nil, errors.Wrap(err, "EventService.List: marshal EventListRequest")
 	}
-	url := s.client.RemoteHost + "LinkService.Create"
+	url := s.client.RemoteHost + "EventService.List"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Create: NewRequest")
+		return nil, errors.Wrap(err, "EventService.List: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1739,51 +1746,51 @@ func (s *LinkService) Create(ctx context.Context, r LinkCreateRequest) (*LinkCre
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Create")
+		return nil, errors.Wrap(err, "EventService.List")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		LinkCreateResponse
+		EventListResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "LinkService.Create: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.List: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Create: read response body")
+		return nil, errors.Wrap(err, "EventService.List: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("LinkService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.LinkCreateResponse, nil
+	return &response.EventListResponse, nil
 }
 
-// Delete deletes all links to the specified object
-func (s *LinkService) Delete(ctx context.Context, r LinkDeleteRequest) (*LinkDeleteResponse, error) {
+// Update updates an existing event
+func (s *EventService) Update(ctx context.Context, r EventUpdateRequest) (*EventUpdateResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Delete: marshal LinkDeleteRequest")
+		return nil, errors.Wrap(err, "EventService.Update: marshal EventUpdateRequest")
 	}
-	url := s.client.RemoteHost + "LinkService.Delete"
+	url := s.client.RemoteHost + "EventService.Update"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Delete: NewRequest")
+		return nil, errors.Wrap(err, "EventService.Update: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1791,51 +1798,65 @@ func (s *LinkService) Delete(ctx context.Context, r LinkDeleteRequest) (*LinkDel
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Delete")
+		return nil, errors.Wrap(err, "EventService.Update")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		LinkDeleteResponse
+		EventUpdateResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "LinkService.Delete: new gzip reader")
+			return nil, errors.Wrap(err, "EventService.Update: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Delete: read response body")
+		return nil, errors.Wrap(err, "EventService.Update: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("LinkService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("EventService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.LinkDeleteResponse, nil
+	return &response.EventUpdateResponse, nil
 }
 
-// Get gets an object with its links
-func (s *LinkService) Get(ctx context.Context, r LinkGetRequest) (*LinkGetResponse, error) {
+// FileService is the API for handling files
+type FileService struct {
+	client *Client
+	token  string
+}
+
+// NewFileService makes a new client for accessing FileService services.
+func NewFileService(client *Client, token string) *FileService {
+	return &FileService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Custody returns the chain of custody for a file
+func (s *FileService) Custody(ctx context.Context, r FileCustodyRequest) (*FileCustodyResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Get: marshal LinkGetRequest")
+		return nil, errors.Wrap(err, "FileService.Custody: marshal FileCustodyRequest")
 	}
-	url := s.client.RemoteHost + "LinkService.Get"
+	url := s.client.RemoteHost + "FileService.Custody"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Get: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Custody: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1843,51 +1864,51 @@ func (s *LinkService) Get(ctx context.Context, r LinkGetRequest) (*LinkGetRespon
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Get")
+		return nil, errors.Wrap(err, "FileService.Custody")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		LinkGetResponse
+		FileCustodyResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "LinkService.Get: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Custody: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Get: read response body")
+		return nil, errors.Wrap(err, "FileService.Custody: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("LinkService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Custody: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.LinkGetResponse, nil
+	return &response.FileCustodyResponse, nil
 }
 
-// Remove removes specified links from an object
-func (s *LinkService) Remove(ctx context.Context, r LinkRemoveRequest) (*LinkRemoveResponse, error) {
+// Delete deletes the specified file
+func (s *FileService) Delete(ctx context.Context, r FileDeleteRequest) (*FileDeleteResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Remove: marshal LinkRemoveRequest")
+		return nil, errors.Wrap(err, "FileService.Delete: marshal FileDeleteRequest")
 	}
-	url := s.client.RemoteHost + "LinkService.Remove"
+	url := s.client.RemoteHost + "FileService.Delete"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Remove: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Delete: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1895,65 +1916,51 @@ func (s *LinkService) Remove(ctx context.Context, r LinkRemoveRequest) (*LinkRem
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Remove")
+		return nil, errors.Wrap(err, "FileService.Delete")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		LinkRemoveResponse
+		FileDeleteResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "LinkService.Remove: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Delete: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "LinkService.Remove: read response body")
+		return nil, errors.Wrap(err, "FileService.Delete: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("LinkService.Remove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.LinkRemoveResponse, nil
-}
-
-// PersonService is the API to handle entities
-type PersonService struct {
-	client *Client
-	token  string
+	return &response.FileDeleteResponse, nil
 }
 
-// NewPersonService makes a new client for accessing PersonService services.
-func NewPersonService(client *Client, token string) *PersonService {
-	return &PersonService{
-		client: client,
-		token:  token,
+// KeywordsAdd to a file
+func (s *FileService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileService.KeywordsAdd: marshal KeywordsAddRequest")
 	}
-}
-
-// Create creates a new person
-func (s *PersonService) Create(ctx context.Context, r PersonCreateRequest) (*PersonCreateResponse, error) {
-	requestBodyBytes, err := json.Marshal(r)
-	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Create: marshal PersonCreateRequest")
-	}
-	url := s.client.RemoteHost + "PersonService.Create"
+	url := s.client.RemoteHost + "FileService.KeywordsAdd"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Create: NewRequest")
+		return nil, errors.Wrap(err, "FileService.KeywordsAdd: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -1961,51 +1968,51 @@ func (s *PersonService) Create(ctx context.Context, r PersonCreateRequest) (*Per
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Create")
+		return nil, errors.Wrap(err, "FileService.KeywordsAdd")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		PersonCreateResponse
+		KeywordsAddResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.Create: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.KeywordsAdd: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Create: read response body")
+		return nil, errors.Wrap(err, "FileService.KeywordsAdd: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.PersonCreateResponse, nil
+	return &response.KeywordsAddResponse, nil
 }
 
-// Delete deletes an existing person
-func (s *PersonService) Delete(ctx context.Context, r PersonDeleteRequest) (*PersonDeleteResponse, error) {
+// KeywordsRemove from a file
+func (s *FileService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Delete: marshal PersonDeleteRequest")
+		return nil, errors.Wrap(err, "FileService.KeywordsRemove: marshal KeywordsRemoveRequest")
 	}
-	url := s.client.RemoteHost + "PersonService.Delete"
+	url := s.client.RemoteHost + "FileService.KeywordsRemove"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Delete: NewRequest")
+		return nil, errors.Wrap(err, "FileService.KeywordsRemove: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2013,51 +2020,51 @@ func (s *PersonService) Delete(ctx context.Context, r PersonDeleteRequest) (*Per
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Delete")
+		return nil, errors.Wrap(err, "FileService.KeywordsRemove")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		PersonDeleteResponse
+		KeywordsRemoveResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.Delete: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.KeywordsRemove: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Delete: read response body")
+		return nil, errors.Wrap(err, "FileService.KeywordsRemove: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.PersonDeleteResponse, nil
+	return &response.KeywordsRemoveResponse, nil
 }
 
-// Get the specified person
-func (s *PersonService) Get(ctx context.Context, r PersonGetRequest) (*PersonGetResponse, error) {
+// List lists the files in a case, a page at a time
+func (s *FileService) List(ctx context.Context, r FileListRequest) (*FileListResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Get: marshal PersonGetRequest")
+		return nil, errors.Wrap(err, "FileService.List: marshal FileListRequest")
 	}
-	url := s.client.RemoteHost + "PersonService.Get"
+	url := s.client.RemoteHost + "FileService.List"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Get: NewRequest")
+		return nil, errors.Wrap(err, "FileService.List: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2065,51 +2072,51 @@ func (s *PersonService) Get(ctx context.Context, r PersonGetRequest) (*PersonGet
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Get")
+		return nil, errors.Wrap(err, "FileService.List")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		PersonGetResponse
+		FileListResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.Get: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.List: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Get: read response body")
+		return nil, errors.Wrap(err, "FileService.List: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.PersonGetResponse, nil
+	return &response.FileListResponse, nil
 }
 
-// KeywordsAdd to a person
-func (s *PersonService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
+// New uploads a file to the backend
+func (s *FileService) New(ctx context.Context, r FileNewRequest) (*FileNewResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsAdd: marshal KeywordsAddRequest")
+		return nil, errors.Wrap(err, "FileService.New: marshal FileNewRequest")
 	}
-	url := s.client.RemoteHost + "PersonService.KeywordsAdd"
+	url := s.client.RemoteHost + "FileService.New"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsAdd: NewRequest")
+		return nil, errors.Wrap(err, "FileService.New: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2117,51 +2124,52 @@ func (s *PersonService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsAdd")
+		return nil, errors.Wrap(err, "FileService.New")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsAddResponse
+		FileNewResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.KeywordsAdd: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.New: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsAdd: read response body")
+		return nil, errors.Wrap(err, "FileService.New: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.New: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsAddResponse, nil
+	return &response.FileNewResponse, nil
 }
 
-// KeywordsRemove from a person
-func (s *PersonService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
+// Open opens a file (base64 encoded), files larger than 10 MB cannot be opened,
+// they are streamed from the download-handler
+func (s *FileService) Open(ctx context.Context, r FileOpenRequest) (*FileOpenResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsRemove: marshal KeywordsRemoveRequest")
+		return nil, errors.Wrap(err, "FileService.Open: marshal FileOpenRequest")
 	}
-	url := s.client.RemoteHost + "PersonService.KeywordsRemove"
+	url := s.client.RemoteHost + "FileService.Open"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsRemove: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Open: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2169,51 +2177,51 @@ func (s *PersonService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequ
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsRemove")
+		return nil, errors.Wrap(err, "FileService.Open")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		KeywordsRemoveResponse
+		FileOpenResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.KeywordsRemove: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Open: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.KeywordsRemove: read response body")
+		return nil, errors.Wrap(err, "FileService.Open: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Open: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.KeywordsRemoveResponse, nil
+	return &response.FileOpenResponse, nil
 }
 
-// List all entities for a case
-func (s *PersonService) List(ctx context.Context, r PersonListRequest) (*PersonListResponse, error) {
+// Process processes a file large files should be processed with the ProcessService
+func (s *FileService) Process(ctx context.Context, r FileProcessRequest) (*FileProcessResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.List: marshal PersonListRequest")
+		return nil, errors.Wrap(err, "FileService.Process: marshal FileProcessRequest")
 	}
-	url := s.client.RemoteHost + "PersonService.List"
+	url := s.client.RemoteHost + "FileService.Process"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.List: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Process: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2221,51 +2229,51 @@ func (s *PersonService) List(ctx context.Context, r PersonListRequest) (*PersonL
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.List")
+		return nil, errors.Wrap(err, "FileService.Process")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		PersonListResponse
+		FileProcessResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.List: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Process: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.List: read response body")
+		return nil, errors.Wrap(err, "FileService.Process: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Process: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.PersonListResponse, nil
+	return &response.FileProcessResponse, nil
 }
 
-// Update updates an existing person
-func (s *PersonService) Update(ctx context.Context, r PersonUpdateRequest) (*PersonUpdateResponse, error) {
+// Processed gets information for a processed file
+func (s *FileService) Processed(ctx context.Context, r FileProcessedRequest) (*FileProcessedResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Update: marshal PersonUpdateRequest")
+		return nil, errors.Wrap(err, "FileService.Processed: marshal FileProcessedRequest")
 	}
-	url := s.client.RemoteHost + "PersonService.Update"
+	url := s.client.RemoteHost + "FileService.Processed"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Update: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Processed: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2273,65 +2281,51 @@ func (s *PersonService) Update(ctx context.Context, r PersonUpdateRequest) (*Per
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Update")
+		return nil, errors.Wrap(err, "FileService.Processed")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		PersonUpdateResponse
+		FileProcessedResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "PersonService.Update: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Processed: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "PersonService.Update: read response body")
+		return nil, errors.Wrap(err, "FileService.Processed: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("PersonService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Processed: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.PersonUpdateResponse, nil
-}
-
-// SearchService is the API to handle searches in the Timeline-Investigator
-type SearchService struct {
-	client *Client
-	token  string
-}
-
-// NewSearchService makes a new client for accessing SearchService services.
-func NewSearchService(client *Client, token string) *SearchService {
-	return &SearchService{
-		client: client,
-		token:  token,
-	}
+	return &response.FileProcessedResponse, nil
 }
 
-// SearchWithText returns data in the case that is related to the text
-func (s *SearchService) SearchWithText(ctx context.Context, r SearchTextRequest) (*SearchTextResponse, error) {
+// Processes gets information for all proccesed files in the specified case
+func (s *FileService) Processes(ctx context.Context, r FileProcessesRequest) (*FileProcessesResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithText: marshal SearchTextRequest")
+		return nil, errors.Wrap(err, "FileService.Processes: marshal FileProcessesRequest")
 	}
-	url := s.client.RemoteHost + "SearchService.SearchWithText"
+	url := s.client.RemoteHost + "FileService.Processes"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithText: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Processes: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2339,51 +2333,51 @@ func (s *SearchService) SearchWithText(ctx context.Context, r SearchTextRequest)
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithText")
+		return nil, errors.Wrap(err, "FileService.Processes")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		SearchTextResponse
+		FileProcessesResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "SearchService.SearchWithText: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Processes: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithText: read response body")
+		return nil, errors.Wrap(err, "FileService.Processes: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("SearchService.SearchWithText: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Processes: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.SearchTextResponse, nil
+	return &response.FileProcessesResponse, nil
 }
 
-// SearchWithTimespan returns events from the selected timespan
-func (s *SearchService) SearchWithTimespan(ctx context.Context, r SearchTimespanRequest) (*SearchTimespanResponse, error) {
+// Update updates the information for a file
+func (s *FileService) Update(ctx context.Context, r FileUpdateRequest) (*FileUpdateResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: marshal SearchTimespanRequest")
+		return nil, errors.Wrap(err, "FileService.Update: marshal FileUpdateRequest")
 	}
-	url := s.client.RemoteHost + "SearchService.SearchWithTimespan"
+	url := s.client.RemoteHost + "FileService.Update"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Update: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2391,65 +2385,51 @@ func (s *SearchService) SearchWithTimespan(ctx context.Context, r SearchTimespan
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan")
+		return nil, errors.Wrap(err, "FileService.Update")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		SearchTimespanResponse
+		FileUpdateResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Update: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: read response body")
+		return nil, errors.Wrap(err, "FileService.Update: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("SearchService.SearchWithTimespan: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.SearchTimespanResponse, nil
-}
-
-// TestService is used for testing-purposes
-type TestService struct {
-	client *Client
-	token  string
-}
-
-// NewTestService makes a new client for accessing TestService services.
-func NewTestService(client *Client, token string) *TestService {
-	return &TestService{
-		client: client,
-		token:  token,
-	}
+	return &response.FileUpdateResponse, nil
 }
 
-// CreateUser creates a test-user in Firebase
-func (s *TestService) CreateUser(ctx context.Context, r TestCreateUserRequest) (*TestCreateUserResponse, error) {
+// Verify verifies the stored hashes against the file in the filestore
+func (s *FileService) Verify(ctx context.Context, r FileVerifyRequest) (*FileVerifyResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.CreateUser: marshal TestCreateUserRequest")
+		return nil, errors.Wrap(err, "FileService.Verify: marshal FileVerifyRequest")
 	}
-	url := s.client.RemoteHost + "TestService.CreateUser"
+	url := s.client.RemoteHost + "FileService.Verify"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.CreateUser: NewRequest")
+		return nil, errors.Wrap(err, "FileService.Verify: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2457,51 +2437,65 @@ func (s *TestService) CreateUser(ctx context.Context, r TestCreateUserRequest) (
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.CreateUser")
+		return nil, errors.Wrap(err, "FileService.Verify")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		TestCreateUserResponse
+		FileVerifyResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "TestService.CreateUser: new gzip reader")
+			return nil, errors.Wrap(err, "FileService.Verify: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.CreateUser: read response body")
+		return nil, errors.Wrap(err, "FileService.Verify: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("TestService.CreateUser: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("FileService.Verify: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.TestCreateUserResponse, nil
+	return &response.FileVerifyResponse, nil
 }
 
-// DeleteUser deletes a test-user in Firebase
-func (s *TestService) DeleteUser(ctx context.Context, r TestDeleteUserRequest) (*TestDeleteUserResponse, error) {
+// LinkService is a API for creating links between objects
+type LinkService struct {
+	client *Client
+	token  string
+}
+
+// NewLinkService makes a new client for accessing LinkService services.
+func NewLinkService(client *Client, token string) *LinkService {
+	return &LinkService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Add adds specified links to an object
+func (s *LinkService) Add(ctx context.Context, r LinkAddRequest) (*LinkAddResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.DeleteUser: marshal TestDeleteUserRequest")
+		return nil, errors.Wrap(err, "LinkService.Add: marshal LinkAddRequest")
 	}
-	url := s.client.RemoteHost + "TestService.DeleteUser"
+	url := s.client.RemoteHost + "LinkService.Add"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.DeleteUser: NewRequest")
+		return nil, errors.Wrap(err, "LinkService.Add: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -2509,25 +2503,1294 @@ func (s *TestService) DeleteUser(ctx context.Context, r TestDeleteUserRequest) (
 	req = req.WithContext(ctx)
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.DeleteUser")
+		return nil, errors.Wrap(err, "LinkService.Add")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		TestDeleteUserResponse
+		LinkAddResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "TestService.DeleteUser: new gzip reader")
+			return nil, errors.Wrap(err, "LinkService.Add: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "TestService.DeleteUser: read response body")
+		return nil, errors.Wrap(err, "LinkService.Add: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("LinkService.Add: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.LinkAddResponse, nil
+}
+
+// Create creates a links for an object
+func (s *LinkService) Create(ctx context.Context, r LinkCreateRequest) (*LinkCreateResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Create: marshal LinkCreateRequest")
+	}
+	url := s.client.RemoteHost + "LinkService.Create"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Create: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Create")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		LinkCreateResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "LinkService.Create: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Create: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("LinkService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.LinkCreateResponse, nil
+}
+
+// Delete deletes all links to the specified object
+func (s *LinkService) Delete(ctx context.Context, r LinkDeleteRequest) (*LinkDeleteResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Delete: marshal LinkDeleteRequest")
+	}
+	url := s.client.RemoteHost + "LinkService.Delete"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Delete: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Delete")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		LinkDeleteResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "LinkService.Delete: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Delete: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("LinkService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.LinkDeleteResponse, nil
+}
+
+// Get gets an object with its links
+func (s *LinkService) Get(ctx context.Context, r LinkGetRequest) (*LinkGetResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Get: marshal LinkGetRequest")
+	}
+	url := s.client.RemoteHost + "LinkService.Get"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Get: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Get")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		LinkGetResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "LinkService.Get: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Get: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("LinkService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.LinkGetResponse, nil
+}
+
+// Remove removes specified links from an object
+func (s *LinkService) Remove(ctx context.Context, r LinkRemoveRequest) (*LinkRemoveResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Remove: marshal LinkRemoveRequest")
+	}
+	url := s.client.RemoteHost + "LinkService.Remove"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Remove: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Remove")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		LinkRemoveResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "LinkService.Remove: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "LinkService.Remove: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("LinkService.Remove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.LinkRemoveResponse, nil
+}
+
+// PersonService is the API to handle entities
+type PersonService struct {
+	client *Client
+	token  string
+}
+
+// NewPersonService makes a new client for accessing PersonService services.
+func NewPersonService(client *Client, token string) *PersonService {
+	return &PersonService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Create creates a new person
+func (s *PersonService) Create(ctx context.Context, r PersonCreateRequest) (*PersonCreateResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Create: marshal PersonCreateRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.Create"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Create: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Create")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		PersonCreateResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.Create: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Create: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.PersonCreateResponse, nil
+}
+
+// Delete deletes an existing person
+func (s *PersonService) Delete(ctx context.Context, r PersonDeleteRequest) (*PersonDeleteResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Delete: marshal PersonDeleteRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.Delete"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Delete: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Delete")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		PersonDeleteResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.Delete: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Delete: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.Delete: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.PersonDeleteResponse, nil
+}
+
+// Get the specified person
+func (s *PersonService) Get(ctx context.Context, r PersonGetRequest) (*PersonGetResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Get: marshal PersonGetRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.Get"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Get: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Get")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		PersonGetResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.Get: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Get: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.Get: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.PersonGetResponse, nil
+}
+
+// KeywordsAdd to a person
+func (s *PersonService) KeywordsAdd(ctx context.Context, r KeywordsAddRequest) (*KeywordsAddResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsAdd: marshal KeywordsAddRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.KeywordsAdd"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsAdd: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsAdd")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		KeywordsAddResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.KeywordsAdd: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsAdd: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.KeywordsAdd: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.KeywordsAddResponse, nil
+}
+
+// KeywordsRemove from a person
+func (s *PersonService) KeywordsRemove(ctx context.Context, r KeywordsRemoveRequest) (*KeywordsRemoveResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsRemove: marshal KeywordsRemoveRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.KeywordsRemove"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsRemove: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsRemove")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		KeywordsRemoveResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.KeywordsRemove: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.KeywordsRemove: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.KeywordsRemove: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.KeywordsRemoveResponse, nil
+}
+
+// List all entities for a case
+func (s *PersonService) List(ctx context.Context, r PersonListRequest) (*PersonListResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.List: marshal PersonListRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.List"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.List: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.List")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		PersonListResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.List: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.List: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.PersonListResponse, nil
+}
+
+// Update updates an existing person
+func (s *PersonService) Update(ctx context.Context, r PersonUpdateRequest) (*PersonUpdateResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Update: marshal PersonUpdateRequest")
+	}
+	url := s.client.RemoteHost + "PersonService.Update"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Update: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Update")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		PersonUpdateResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "PersonService.Update: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "PersonService.Update: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("PersonService.Update: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.PersonUpdateResponse, nil
+}
+
+// ProcessService is the API to process files in the background with
+// processing-jobs
+type ProcessService struct {
+	client *Client
+	token  string
+}
+
+// NewProcessService makes a new client for accessing ProcessService services.
+func NewProcessService(client *Client, token string) *ProcessService {
+	return &ProcessService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Abort aborts a processing-job, the files that aren't processed are skipped
+func (s *ProcessService) Abort(ctx context.Context, r ProcessAbortRequest) (*ProcessAbortResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Abort: marshal ProcessAbortRequest")
+	}
+	url := s.client.RemoteHost + "ProcessService.Abort"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Abort: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Abort")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ProcessAbortResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ProcessService.Abort: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Abort: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ProcessService.Abort: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ProcessAbortResponse, nil
+}
+
+// Jobs gets the processing-jobs for a case
+func (s *ProcessService) Jobs(ctx context.Context, r ProcessJobsRequest) (*ProcessJobsResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Jobs: marshal ProcessJobsRequest")
+	}
+	url := s.client.RemoteHost + "ProcessService.Jobs"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Jobs: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Jobs")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ProcessJobsResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ProcessService.Jobs: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Jobs: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ProcessService.Jobs: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ProcessJobsResponse, nil
+}
+
+// Pause pauses a processing-job
+func (s *ProcessService) Pause(ctx context.Context, r ProcessPauseRequest) (*ProcessPauseResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Pause: marshal ProcessPauseRequest")
+	}
+	url := s.client.RemoteHost + "ProcessService.Pause"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Pause: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Pause")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ProcessPauseResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ProcessService.Pause: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Pause: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ProcessService.Pause: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ProcessPauseResponse, nil
+}
+
+// Resume resumes a paused processing-job
+func (s *ProcessService) Resume(ctx context.Context, r ProcessResumeRequest) (*ProcessResumeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Resume: marshal ProcessResumeRequest")
+	}
+	url := s.client.RemoteHost + "ProcessService.Resume"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Resume: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Resume")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ProcessResumeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ProcessService.Resume: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Resume: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ProcessService.Resume: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ProcessResumeResponse, nil
+}
+
+// Start starts a new processing-job for files in a case
+func (s *ProcessService) Start(ctx context.Context, r ProcessStartRequest) (*ProcessStartResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Start: marshal ProcessStartRequest")
+	}
+	url := s.client.RemoteHost + "ProcessService.Start"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Start: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Start")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ProcessStartResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ProcessService.Start: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ProcessService.Start: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ProcessService.Start: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ProcessStartResponse, nil
+}
+
+// SearchService is the API to handle searches in the Timeline-Investigator
+type SearchService struct {
+	client *Client
+	token  string
+}
+
+// NewSearchService makes a new client for accessing SearchService services.
+func NewSearchService(client *Client, token string) *SearchService {
+	return &SearchService{
+		client: client,
+		token:  token,
+	}
+}
+
+// SearchWithText returns data in the case that is related to the text
+func (s *SearchService) SearchWithText(ctx context.Context, r SearchTextRequest) (*SearchTextResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithText: marshal SearchTextRequest")
+	}
+	url := s.client.RemoteHost + "SearchService.SearchWithText"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithText: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithText")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		SearchTextResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SearchService.SearchWithText: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithText: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SearchService.SearchWithText: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.SearchTextResponse, nil
+}
+
+// SearchWithTimespan returns events from the selected timespan
+func (s *SearchService) SearchWithTimespan(ctx context.Context, r SearchTimespanRequest) (*SearchTimespanResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: marshal SearchTimespanRequest")
+	}
+	url := s.client.RemoteHost + "SearchService.SearchWithTimespan"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		SearchTimespanResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SearchService.SearchWithTimespan: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SearchService.SearchWithTimespan: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.SearchTimespanResponse, nil
+}
+
+// SuggestionService is the API to review the persons and events that are suggested
+// from the processed files
+type SuggestionService struct {
+	client *Client
+	token  string
+}
+
+// NewSuggestionService makes a new client for accessing SuggestionService services.
+func NewSuggestionService(client *Client, token string) *SuggestionService {
+	return &SuggestionService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Accept accepts suggestions, the suggested persons and events are created in the
+// case
+func (s *SuggestionService) Accept(ctx context.Context, r SuggestionAcceptRequest) (*SuggestionAcceptResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Accept: marshal SuggestionAcceptRequest")
+	}
+	url := s.client.RemoteHost + "SuggestionService.Accept"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Accept: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Accept")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		SuggestionAcceptResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SuggestionService.Accept: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Accept: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SuggestionService.Accept: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.SuggestionAcceptResponse, nil
+}
+
+// List lists the suggestions for a case
+func (s *SuggestionService) List(ctx context.Context, r SuggestionListRequest) (*SuggestionListResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.List: marshal SuggestionListRequest")
+	}
+	url := s.client.RemoteHost + "SuggestionService.List"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.List: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.List")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		SuggestionListResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SuggestionService.List: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.List: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SuggestionService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.SuggestionListResponse, nil
+}
+
+// Reject rejects suggestions
+func (s *SuggestionService) Reject(ctx context.Context, r SuggestionRejectRequest) (*SuggestionRejectResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Reject: marshal SuggestionRejectRequest")
+	}
+	url := s.client.RemoteHost + "SuggestionService.Reject"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Reject: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Reject")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		SuggestionRejectResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SuggestionService.Reject: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SuggestionService.Reject: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SuggestionService.Reject: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.SuggestionRejectResponse, nil
+}
+
+// TestService is used for testing-purposes
+type TestService struct {
+	client *Client
+	token  string
+}
+
+// NewTestService makes a new client for accessing TestService services.
+func NewTestService(client *Client, token string) *TestService {
+	return &TestService{
+		client: client,
+		token:  token,
+	}
+}
+
+// CreateUser creates a test-user in Firebase
+func (s *TestService) CreateUser(ctx context.Context, r TestCreateUserRequest) (*TestCreateUserResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.CreateUser: marshal TestCreateUserRequest")
+	}
+	url := s.client.RemoteHost + "TestService.CreateUser"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.CreateUser: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.CreateUser")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		TestCreateUserResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "TestService.CreateUser: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.CreateUser: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("TestService.CreateUser: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.TestCreateUserResponse, nil
+}
+
+// DeleteUser deletes a test-user in Firebase
+func (s *TestService) DeleteUser(ctx context.Context, r TestDeleteUserRequest) (*TestDeleteUserResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.DeleteUser: marshal TestDeleteUserRequest")
+	}
+	url := s.client.RemoteHost + "TestService.DeleteUser"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.DeleteUser: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.DeleteUser")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		TestDeleteUserResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "TestService.DeleteUser: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "TestService.DeleteUser: read response body")
 	}
 	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
@@ -2542,6 +3805,182 @@ func (s *TestService) DeleteUser(ctx context.Context, r TestDeleteUserRequest) (
 	return &response.TestDeleteUserResponse, nil
 }
 
+// TokenService is the API to handle API-tokens, used by scripts to access cases
+// without a user
+type TokenService struct {
+	client *Client
+	token  string
+}
+
+// NewTokenService makes a new client for accessing TokenService services.
+func NewTokenService(client *Client, token string) *TokenService {
+	return &TokenService{
+		client: client,
+		token:  token,
+	}
+}
+
+// Create creates a new API-token for cases owned by the user, the token is only
+// returned once and cannot be retrieved again
+func (s *TokenService) Create(ctx context.Context, r TokenCreateRequest) (*TokenCreateResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Create: marshal TokenCreateRequest")
+	}
+	url := s.client.RemoteHost + "TokenService.Create"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Create: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Create")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		TokenCreateResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "TokenService.Create: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Create: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("TokenService.Create: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.TokenCreateResponse, nil
+}
+
+// List lists the API-tokens created by the user
+func (s *TokenService) List(ctx context.Context, r TokenListRequest) (*TokenListResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.List: marshal TokenListRequest")
+	}
+	url := s.client.RemoteHost + "TokenService.List"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.List: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.List")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		TokenListResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "TokenService.List: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.List: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("TokenService.List: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.TokenListResponse, nil
+}
+
+// Revoke revokes an API-token
+func (s *TokenService) Revoke(ctx context.Context, r TokenRevokeRequest) (*TokenRevokeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Revoke: marshal TokenRevokeRequest")
+	}
+	url := s.client.RemoteHost + "TokenService.Revoke"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Revoke: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Authorization", s.token)
+	req = req.WithContext(ctx)
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Revoke")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		TokenRevokeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "TokenService.Revoke: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "TokenService.Revoke: read response body")
+	}
+	s.client.Debug(fmt.Sprintf("<< %s", string(respBodyBytes)))
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("TokenService.Revoke: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.TokenRevokeResponse, nil
+}
+
+// AdminCasesRequest is the input-object for listing every case in the system
+type AdminCasesRequest struct {
+}
+
 // Base model for the database
 type Base struct {
 	// ID is the identifier for the object
@@ -2555,6 +3994,19 @@ type Base struct {
 
 	// DeletedAt - when the object was deleted
 	DeletedAt int64 `json:"deletedAt"`
+
+	// Version of the object, send it back when updating the object to only update it
+	// if it hasn't been changed since it was read
+	Version string `json:"version"`
+}
+
+// Role is the role an investigator has in a specific case
+type Role struct {
+	// Email of the investigator
+	Email string `json:"email"`
+
+	// Name of the role, "owner", "editor", "viewer" or "auditor"
+	Name string `json:"name"`
 }
 
 // File holds information about an uploaded file
@@ -2581,43 +4033,245 @@ type File struct {
 
 	// The keywords for the file
 	Keywords []string `json:"keywords"`
+
+	// MD5 is the hex-encoded md5-hash of the file, computed at upload
+	MD5 string `json:"mD5"`
+
+	// SHA1 is the hex-encoded sha1-hash of the file, computed at upload
+	SHA1 string `json:"sHA1"`
+
+	// SHA256 is the hex-encoded sha256-hash of the file, computed at upload
+	SHA256 string `json:"sHA256"`
+
+	// UploaderID is the ID of the user that uploaded the file
+	UploaderID string `json:"uploaderID"`
+
+	// UploaderEmail is the email of the user that uploaded the file
+	UploaderEmail string `json:"uploaderEmail"`
+}
+
+// ProcessFile is the status of a file in a processing-job
+type ProcessFile struct {
+	// FileID of the file to process
+	FileID string `json:"fileID"`
+
+	// Status of the file, "queued", "running", "retrying", "paused", "aborted",
+	// "completed" or "failed"
+	Status string `json:"status"`
+
+	// Attempts to process the file
+	Attempts int `json:"attempts"`
+
+	// NextAttemptAt is the unix-timestamp for when the file is retried
+	NextAttemptAt int64 `json:"nextAttemptAt"`
+
+	// StartedAt is the unix-timestamp for when the last attempt was started
+	StartedAt int64 `json:"startedAt"`
+
+	// FinishedAt is the unix-timestamp for when the last attempt was finished
+	FinishedAt int64 `json:"finishedAt"`
 }
 
 // Process holds information about a job that processes data to app
 type Process struct {
 	Base
 
-	// Files for the process
-	Files []string `json:"files"`
+	// CaseID of the case the job belongs to
+	CaseID string `json:"caseID"`
+
+	// CreatorID is the ID of the user who started the job
+	CreatorID string `json:"creatorID"`
+
+	// CreatorEmail is the email of the user who started the job
+	CreatorEmail string `json:"creatorEmail"`
+
+	// Status of the job, "queued", "running", "paused", "aborted", "completed" or
+	// "failed"
+	Status string `json:"status"`
+
+	// Progress of the job in percent
+	Progress int `json:"progress"`
+
+	// StartedAt is the unix-timestamp for when the job was started by a worker
+	StartedAt int64 `json:"startedAt"`
+
+	// FinishedAt is the unix-timestamp for when the job was finished
+	FinishedAt int64 `json:"finishedAt"`
+
+	// Files for the process
+	Files []ProcessFile `json:"files"`
+}
+
+// Case is an object to hold data for a specific investigation
+type Case struct {
+	Base
+
+	// CreatorID is the user-id of the user who created the case (super admin)
+	CreatorID string `json:"creatorID"`
+
+	// Name of the case
+	Name string `json:"name"`
+
+	// Description of the case
+	Description string `json:"description"`
+
+	// FromDate is the unix-date for the start of the primary timespan for the case
+	FromDate int64 `json:"fromDate"`
+
+	// ToDate is the unix-date for the end of the primary timespan for the case
+	ToDate int64 `json:"toDate"`
+
+	// Investigators of the case (users who has access to the case)
+	Investigators []string `json:"investigators"`
+
+	// Roles of the investigators in the case
+	Roles []Role `json:"roles"`
+
+	// Files that exists in the case, only the first 100 uploaded files are included
+	// when the case is read (see CaseGetResponse)
+	Files []File `json:"files"`
+
+	// Processes that exists in the case
+	Processes []Process `json:"processes"`
+}
+
+// AdminCasesResponse is the output-object for listing every case in the system
+type AdminCasesResponse struct {
+	Cases []Case `json:"cases"`
+}
+
+// AdminCreateUserRequest is the input-object for creating a user
+type AdminCreateUserRequest struct {
+	// Email of the user
+	Email string `json:"email"`
+
+	// Name of the user
+	Name string `json:"name"`
+
+	// Password of the user, at least 12 characters
+	Password string `json:"password"`
+}
+
+// AdminCreateUserResponse is the output-object for creating a user
+type AdminCreateUserResponse struct {
+	// ID of the created user
+	ID string `json:"id"`
+}
+
+// AdminDeleteRequest is the input-object for force-deleting a case
+type AdminDeleteRequest struct {
+	// ID of the case to delete
+	ID string `json:"id"`
+}
+
+// AdminDeleteResponse is the output-object for force-deleting a case
+type AdminDeleteResponse struct {
+}
+
+// AdminDeleteUserRequest is the input-object for deleting a user
+type AdminDeleteUserRequest struct {
+	// ID of the user to delete
+	ID string `json:"id"`
+}
+
+// AdminDeleteUserResponse is the output-object for deleting a user
+type AdminDeleteUserResponse struct {
+}
+
+// AdminResetPasswordRequest is the input-object for setting a new password for a
+// user
+type AdminResetPasswordRequest struct {
+	// ID of the user
+	ID string `json:"id"`
+
+	// Password is the new password, at least 12 characters
+	Password string `json:"password"`
+}
+
+// AdminResetPasswordResponse is the output-object for setting a new password for a
+// user
+type AdminResetPasswordResponse struct {
+}
+
+// AdminRotateKeyRequest is the input-object for rotating the data-key for a case
+type AdminRotateKeyRequest struct {
+	// ID of the case to rotate the key for
+	ID string `json:"id"`
+}
+
+// AdminRotateKeyResponse is the output-object for rotating the data-key for a case
+type AdminRotateKeyResponse struct {
+	// Version of the new data-key
+	Version int `json:"version"`
+}
+
+// AdminTransferRequest is the input-object for transferring the ownership of a
+// case
+type AdminTransferRequest struct {
+	// ID of the case to transfer
+	ID string `json:"id"`
+
+	// UserID of the new owner
+	UserID string `json:"userID"`
+
+	// Email of the new owner, it's required when the authentication-provider cannot
+	// look up the users (oidc)
+	Email string `json:"email"`
+
+	// RemoveEmail is the email of an investigator to remove from the case, for example
+	// the previous owner (optional)
+	RemoveEmail string `json:"removeEmail"`
+}
+
+// AdminTransferResponse is the output-object for transferring the ownership of a
+// case
+type AdminTransferResponse struct {
+	Transferred Case `json:"transferred"`
+}
+
+// AdminUsersRequest is the input-object for listing the users in the system
+type AdminUsersRequest struct {
 }
 
-// Case is an object to hold data for a specific investigation
-type Case struct {
-	Base
+// Membership is the role of a user in a specific case
+type Membership struct {
+	// CaseID is the ID of the case
+	CaseID string `json:"caseID"`
 
-	// CreatorID is the user-id of the user who created the case (super admin)
-	CreatorID string `json:"creatorID"`
+	// CaseName is the name of the case
+	CaseName string `json:"caseName"`
 
-	// Name of the case
-	Name string `json:"name"`
+	// Role of the user in the case
+	Role string `json:"role"`
+}
 
-	// Description of the case
-	Description string `json:"description"`
+// UserMemberships holds the cases a user is an investigator in
+type UserMemberships struct {
+	// Email of the user
+	Email string `json:"email"`
 
-	// FromDate is the unix-date for the start of the primary timespan for the case
-	FromDate int64 `json:"fromDate"`
+	// Memberships in the cases
+	Memberships []Membership `json:"memberships"`
+}
 
-	// ToDate is the unix-date for the end of the primary timespan for the case
-	ToDate int64 `json:"toDate"`
+// AdminUsersResponse is the output-object for listing the users in the system
+type AdminUsersResponse struct {
+	Users []UserMemberships `json:"users"`
+}
 
-	// Investigators of the case (users who has access to the case)
-	Investigators []string `json:"investigators"`
+// AuthSignInRequest is the input-object for signing in a user
+type AuthSignInRequest struct {
+	// Email of the user
+	Email string `json:"email"`
 
-	// Files that exists in the case
-	Files []File `json:"files"`
+	// Password of the user
+	Password string `json:"password"`
+}
 
-	// Processes that exists in the case
-	Processes []Process `json:"processes"`
+// AuthSignInResponse is the output-object for signing in a user
+type AuthSignInResponse struct {
+	// Token for the signed in user
+	Token string `json:"token"`
 }
 
 // CaseDeleteRequest is the input-object for deleting an existing case
@@ -2639,6 +4293,50 @@ type CaseGetRequest struct {
 // CaseGetResponse is the output-object for getting a specified case
 type CaseGetResponse struct {
 	Case Case `json:"case"`
+
+	// FilesTotal is the number of files in the case, Case.Files only has the first
+	// page
+	FilesTotal int `json:"filesTotal"`
+
+	// FilesNextCursor gets the next page of the files with FileService.List, it's
+	// empty if Case.Files has all the files in the case
+	FilesNextCursor string `json:"filesNextCursor"`
+}
+
+// CaseInvestigatorsAddRequest is the input-object for inviting investigators to a
+// case
+type CaseInvestigatorsAddRequest struct {
+	// ID of the case to invite the investigators to
+	ID string `json:"id"`
+
+	// Emails of the investigators to invite to the case
+	Emails []string `json:"emails"`
+
+	// Role for the investigators in the case, "owner", "editor", "viewer" or "auditor"
+	// (defaults to "editor")
+	Role string `json:"role"`
+}
+
+// CaseInvestigatorsAddResponse is the output-object for inviting investigators to
+// a case
+type CaseInvestigatorsAddResponse struct {
+	Updated Case `json:"updated"`
+}
+
+// CaseInvestigatorsRemoveRequest is the input-object for removing investigators
+// from a case
+type CaseInvestigatorsRemoveRequest struct {
+	// ID of the case to remove the investigators from
+	ID string `json:"id"`
+
+	// Emails of the investigators to remove from the case
+	Emails []string `json:"emails"`
+}
+
+// CaseInvestigatorsRemoveResponse is the output-object for removing investigators
+// from a case
+type CaseInvestigatorsRemoveResponse struct {
+	Updated Case `json:"updated"`
 }
 
 // CaseKeywordsRequest is the input-object for listing keywords for a case
@@ -2653,15 +4351,47 @@ type CaseKeywordsResponse struct {
 	Keywords []string `json:"keywords"`
 }
 
+// ListOptions are the options for listing items a page at a time, the response has
+// the total number of items that matches the filters and the cursor for the next
+// page
+type ListOptions struct {
+	// PageSize is the number of items to get per page (defaults to 100)
+	PageSize int `json:"pageSize"`
+
+	// Cursor is the NextCursor from the previous page, empty to get the first page
+	Cursor string `json:"cursor"`
+
+	// SortBy is the field to sort the items by, "created" (the default) or a field for
+	// the items, see the list-request for the items
+	SortBy string `json:"sortBy"`
+
+	// SortOrder for the items, "asc" or "desc" (defaults to "asc")
+	SortOrder string `json:"sortOrder"`
+
+	// Filters only lists the items where the fields has the values, see the
+	// list-request for the fields that can be filtered
+	Filters map[string]string `json:"filters"`
+}
+
 // CaseListRequest is the input-object for listing cases for a specified user
 type CaseListRequest struct {
 	// UserID of the user to list cases for
 	UserID string `json:"userID"`
+
+	// ListOptions for the page of cases, they can be sorted by "created", "updated"
+	// and "name", and filtered by "creatorID"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // CaseListResponse is the output-object for listing cases for a specified user
 type CaseListResponse struct {
 	Cases []Case `json:"cases"`
+
+	// Total number of cases that matches the filters
+	Total int `json:"total"`
+
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 }
 
 // CaseNewRequest is the input-object for creating a new case
@@ -2684,6 +4414,23 @@ type CaseNewResponse struct {
 	New Case `json:"new"`
 }
 
+// CaseRoleSetRequest is the input-object for setting the role of an investigator
+type CaseRoleSetRequest struct {
+	// ID of the case
+	ID string `json:"id"`
+
+	// Email of the investigator to set the role for
+	Email string `json:"email"`
+
+	// Role for the investigator in the case, "owner", "editor", "viewer" or "auditor"
+	Role string `json:"role"`
+}
+
+// CaseRoleSetResponse is the output-object for setting the role of an investigator
+type CaseRoleSetResponse struct {
+	Updated Case `json:"updated"`
+}
+
 // CaseUpdateRequest is the input-object for updating an existing case
 type CaseUpdateRequest struct {
 	// ID of the case to update
@@ -2700,6 +4447,10 @@ type CaseUpdateRequest struct {
 
 	// ToDate is the unix-date for the end of the primary timespan for the case
 	ToDate int64 `json:"toDate"`
+
+	// Version of the case when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // CaseUpdateResponse is the output-object for updating an existing case
@@ -2707,6 +4458,31 @@ type CaseUpdateResponse struct {
 	Updated Case `json:"updated"`
 }
 
+// CustodyEvent is an entry in the chain of custody for a file, that records who
+// did what with the file and when
+type CustodyEvent struct {
+	Base
+
+	// FileID is the ID of the file
+	FileID string `json:"fileID"`
+
+	// Action that was performed on the file, "uploaded", "opened", "processed",
+	// "updated", "deleted" or "verified"
+	Action string `json:"action"`
+
+	// UserID of the user who performed the action
+	UserID string `json:"userID"`
+
+	// UserEmail of the user who performed the action
+	UserEmail string `json:"userEmail"`
+
+	// UserName of the user who performed the action
+	UserName string `json:"userName"`
+
+	// Details about the action
+	Details string `json:"details"`
+}
+
 // Entity is an object that can be of different types. For example, organization or
 // location
 type Entity struct {
@@ -2784,11 +4560,21 @@ type EntityGetResponse struct {
 type EntityListRequest struct {
 	// CaseID of the case to list the entities for
 	CaseID string `json:"caseID"`
+
+	// ListOptions for the page of entities, they can be sorted by "created", "title"
+	// and "type", and filtered by "type" and "keyword"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // EntityListResponse is the output-object for updating an existing entity
 type EntityListResponse struct {
 	Entities []Entity `json:"entities"`
+
+	// Total number of entities that matches the filters
+	Total int `json:"total"`
+
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 }
 
 // KeywordsAddRequest is the input-object for adding keywords to an object
@@ -2801,6 +4587,10 @@ type KeywordsAddRequest struct {
 
 	// The keywords to add
 	Keywords []string `json:"keywords"`
+
+	// Version of the object when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // KeywordsAddResponse is the output-object for adding keywords to an object
@@ -2819,6 +4609,10 @@ type KeywordsRemoveRequest struct {
 
 	// The keywords to remove
 	Keywords []string `json:"keywords"`
+
+	// Version of the object when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // KeywordsRemoveResponse is the output-object for removing keywords from an object
@@ -2855,6 +4649,10 @@ type EntityUpdateRequest struct {
 
 	// Custom is a free form with key-value pairs specified by the user.
 	Custom map[string]interface{} `json:"custom"`
+
+	// Version of the entity when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // EntityUpdateResponse is the output-object for updating an existing entity
@@ -2936,12 +4734,22 @@ type EventGetResponse struct {
 type EventListRequest struct {
 	// CaseID to list the events for
 	CaseID string `json:"caseID"`
+
+	// ListOptions for the page of events, they can be sorted by "created", "date" (the
+	// FromDate) and "importance", and filtered by "importance" and "keyword"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // EventListResponse is the output-object for listing all existing events for a
 // case
 type EventListResponse struct {
 	Events []Event `json:"events"`
+
+	// Total number of events that matches the filters
+	Total int `json:"total"`
+
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 }
 
 // EventUpdateRequest is the input-object for updating an existing event
@@ -2963,6 +4771,10 @@ type EventUpdateRequest struct {
 
 	// ToDate is the unix-timestamp of when the event finished
 	ToDate int64 `json:"toDate"`
+
+	// Version of the event when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // EventUpdateResponse is the output-object for updating an existing event
@@ -2970,6 +4782,23 @@ type EventUpdateResponse struct {
 	Updated Event `json:"updated"`
 }
 
+// FileCustodyRequest is the input-object for getting the chain of custody for a
+// file
+type FileCustodyRequest struct {
+	// ID of the file
+	ID string `json:"id"`
+
+	// CaseID of the case where the file belongs
+	CaseID string `json:"caseID"`
+}
+
+// FileCustodyResponse is the output-object for getting the chain of custody for a
+// file
+type FileCustodyResponse struct {
+	// Events in the chain of custody, the oldest event first
+	Events []CustodyEvent `json:"events"`
+}
+
 // FileDeleteRequest is the input-object for deleting a file
 type FileDeleteRequest struct {
 	// ID of the file to delete
@@ -2983,6 +4812,53 @@ type FileDeleteRequest struct {
 type FileDeleteResponse struct {
 }
 
+// FileListRequest is the input-object for listing the files in a case
+type FileListRequest struct {
+	// CaseID of the case to list the files in
+	CaseID string `json:"caseID"`
+
+	// ListOptions for the page of files, they can be sorted by "date" (the default),
+	// "name" and "size", the files are filtered by the fields of the request below
+	// instead of the Filters
+	ListOptions ListOptions `json:"listOptions"`
+
+	// Mime only lists the files with the mime-type, "image/*" lists every image
+	Mime string `json:"mime"`
+
+	// State only lists the files that are "processed" or "unprocessed"
+	State string `json:"state"`
+
+	// Keyword only lists the files with the keyword
+	Keyword string `json:"keyword"`
+
+	// UploadedFrom only lists the files uploaded after the unix-timestamp
+	UploadedFrom int64 `json:"uploadedFrom"`
+
+	// UploadedTo only lists the files uploaded before the unix-timestamp
+	UploadedTo int64 `json:"uploadedTo"`
+
+	// Uploader only lists the files uploaded by the user, the ID or the email
+	Uploader string `json:"uploader"`
+
+	// MinSize only lists the files that are at least the size in bytes
+	MinSize int `json:"minSize"`
+
+	// MaxSize only lists the files that are at most the size in bytes
+	MaxSize int `json:"maxSize"`
+}
+
+// FileListResponse is the output-object for listing the files in a case
+type FileListResponse struct {
+	// Total number of files that matches the filters
+	Total int `json:"total"`
+
+	// Files for the requested page
+	Files []File `json:"files"`
+
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
+}
+
 // FileNewRequest is the input-object for creating a new file
 type FileNewRequest struct {
 	// CaseID of the case to upload the file
@@ -3044,11 +4920,98 @@ type FileProcessedRequest struct {
 	CaseID string `json:"caseID"`
 }
 
+// ProcessedDocument is the text and metadata extracted from a processed file,
+// or from a file in a processed container
+type ProcessedDocument struct {
+	// ID of the document, it is the same as the FileID unless the document was
+	// extracted from a container
+	ID string `json:"id"`
+
+	// FileID of the uploaded file the document is from
+	FileID string `json:"fileID"`
+
+	// ParentID is the ID of the document for the container the document was extracted
+	// from, empty for the uploaded file
+	ParentID string `json:"parentID"`
+
+	// ParentPath is the path of the container the document was extracted from
+	ParentPath string `json:"parentPath"`
+
+	// Depth of the document in the containers, 0 for the uploaded file
+	Depth int `json:"depth"`
+
+	// Path of the document in the uploaded file
+	Path string `json:"path"`
+
+	// Name of the file
+	Name string `json:"name"`
+
+	// Extension of the file
+	Extension string `json:"extension"`
+
+	// ContentType of the file
+	ContentType string `json:"contentType"`
+
+	// Size of the file in bytes
+	Size int64 `json:"size"`
+
+	// Content is the extracted text
+	Content string `json:"content"`
+
+	// Title of the document, the subject for emails
+	Title string `json:"title"`
+
+	// Author of the document, the sender for emails
+	Author string `json:"author"`
+
+	// Modifier is who last modified the document
+	Modifier string `json:"modifier"`
+
+	// Keywords for the document
+	Keywords []string `json:"keywords"`
+
+	// Language of the content, if it was detected by the indexer
+	Language string `json:"language"`
+
+	// Pages is the number of pages, 0 if it isn't known
+	Pages int `json:"pages"`
+
+	// CreatorTool is the application or camera that created the document
+	CreatorTool string `json:"creatorTool"`
+
+	// CreatedAt is the unix-timestamp for when the document was created, from its
+	// metadata
+	CreatedAt int64 `json:"createdAt"`
+
+	// ModifiedAt is the unix-timestamp for when the document was modified, or when the
+	// email was sent
+	ModifiedAt int64 `json:"modifiedAt"`
+
+	// FileModifiedAt is the unix-timestamp for when the file was last modified,
+	// as recorded by the container it was extracted from
+	FileModifiedAt int64 `json:"fileModifiedAt"`
+
+	// IndexedAt is the unix-timestamp for when the document was indexed
+	IndexedAt int64 `json:"indexedAt"`
+
+	// Metadata is the other metadata extracted from the document
+	Metadata map[string]string `json:"metadata"`
+
+	// Container is true if files were extracted from the document
+	Container bool `json:"container"`
+
+	// Children is the number of files in the container
+	Children int `json:"children"`
+
+	// ContainerError is the reason the container couldn't be expanded
+	ContainerError string `json:"containerError"`
+}
+
 // FileProcessedResponse is the output-object for get a processed file in a case
 type FileProcessedResponse struct {
 	ID string `json:"id"`
 
-	Processed interface{} `json:"processed"`
+	Processed ProcessedDocument `json:"processed"`
 }
 
 // FileProcessesRequest is the input-object for getting a Processes file in a case
@@ -3059,7 +5022,7 @@ type FileProcessesRequest struct {
 
 // FileProcessesResponse is the output-object for get a Processes file in a case
 type FileProcessesResponse struct {
-	Processes interface{} `json:"processes"`
+	Processes []ProcessedDocument `json:"processes"`
 }
 
 // FileUpdateRequest is the input-object for updating a files information
@@ -3072,6 +5035,10 @@ type FileUpdateRequest struct {
 
 	// Description of the file
 	Description string `json:"description"`
+
+	// Version of the file when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // FileUpdateResponse is the output-object for updating a files information
@@ -3079,6 +5046,26 @@ type FileUpdateResponse struct {
 	Updated File `json:"updated"`
 }
 
+// FileVerifyRequest is the input-object for verifying the hashes of a file
+type FileVerifyRequest struct {
+	// ID of the file to verify
+	ID string `json:"id"`
+
+	// CaseID of the case where the file belongs
+	CaseID string `json:"caseID"`
+}
+
+// FileVerifyResponse is the output-object for verifying the hashes of a file
+type FileVerifyResponse struct {
+	// Verified is true if every stored hash matches the file
+	Verified bool `json:"verified"`
+
+	// Mismatches are the hashes that doesn't match the file
+	Mismatches []string `json:"mismatches"`
+
+	File File `json:"file"`
+}
+
 // Keyword represents a keyword in used for a case
 type Keyword struct {
 	// Name of the keyword
@@ -3166,6 +5153,10 @@ type LinkAddRequest struct {
 
 	// FileIDs of the files to be added to the link
 	FileIDs []string `json:"fileIDs"`
+
+	// Version of the link when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // LinkAddResponse is the output-object for linking objects with an event
@@ -3250,6 +5241,10 @@ type LinkRemoveRequest struct {
 
 	// FileIDs of the files to be removed from the link
 	FileIDs []string `json:"fileIDs"`
+
+	// Version of the link when it was read, the update fails with a conflict if it has
+	// been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // LinkRemoveResponse is the output-object for removing linked objects from a link
@@ -3321,11 +5316,22 @@ type PersonGetResponse struct {
 type PersonListRequest struct {
 	// CaseID of the case to listen all persons
 	CaseID string `json:"caseID"`
+
+	// ListOptions for the page of persons, they can be sorted by "created",
+	// "firstName", "lastName" and "emailAddress", and filtered by "emailAddress" and
+	// "keyword"
+	ListOptions ListOptions `json:"listOptions"`
 }
 
 // PersonListResponse is the output-object for listing all persons for a case
 type PersonListResponse struct {
 	Persons []Person `json:"persons"`
+
+	// Total number of persons that matches the filters
+	Total int `json:"total"`
+
+	// NextCursor gets the next page, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
 }
 
 // PersonUpdateRequest is the input-object for updating an existing person
@@ -3356,6 +5362,10 @@ type PersonUpdateRequest struct {
 
 	// Custom is a free form with key-value pairs specified by the user.
 	Custom map[string]interface{} `json:"custom"`
+
+	// Version of the person when it was read, the update fails with a conflict if it
+	// has been changed since (it's not checked if empty)
+	Version string `json:"version"`
 }
 
 // PersonUpdateResponse is the output-object for updating an existing person
@@ -3404,6 +5414,20 @@ type ProcessPauseResponse struct {
 	Paused Process `json:"paused"`
 }
 
+// ProcessResumeRequest is the input-object for resuming a paused processing-job
+type ProcessResumeRequest struct {
+	// ID of the processing-job to resume
+	ID string `json:"id"`
+
+	// CaseID of the case the processing-job belongs to
+	CaseID string `json:"caseID"`
+}
+
+// ProcessResumeResponse is the output-object for resuming a paused processing-job
+type ProcessResumeResponse struct {
+	Resumed Process `json:"resumed"`
+}
+
 // ProcessStartRequest is the input-object for starting a processing-job
 type ProcessStartRequest struct {
 	// CaseID of the case to start the processing for
@@ -3437,7 +5461,7 @@ type SearchTextResponse struct {
 
 	Files []File `json:"files"`
 
-	Processed interface{} `json:"processed"`
+	Processed []ProcessedDocument `json:"processed"`
 }
 
 // SearchTimespanRequest is the input-object for searching items
@@ -3450,11 +5474,132 @@ type SearchTimespanRequest struct {
 
 	// ToDate is the unix-timestamp of where the timespan finishes
 	ToDate int64 `json:"toDate"`
+
+	// ListOptions for the page of events, they are sorted by "date" (the FromDate) and
+	// cannot be filtered
+	ListOptions ListOptions `json:"listOptions"`
+
+	// IncludeFiles will also return the files that were processed, or has extracted
+	// document-dates, within the timespan. The files are only returned with the first
+	// page
+	IncludeFiles bool `json:"includeFiles"`
 }
 
 // SearchTimespanResponse is the output-object for searching items
 type SearchTimespanResponse struct {
+	// Total number of events within the timespan
+	Total int `json:"total"`
+
+	// Events within the timespan for the requested page
 	Events []Event `json:"events"`
+
+	// NextCursor gets the next page of events, it's empty on the last page
+	NextCursor string `json:"nextCursor"`
+
+	// Files within the timespan, only on the first page if IncludeFiles was requested
+	Files []File `json:"files"`
+}
+
+// Suggestion is a person or an event that is suggested from a processed file,
+// it is created in the case when an investigator accepts it
+type Suggestion struct {
+	Base
+
+	// Type of the suggestion, "person" or "event"
+	Type string `json:"type"`
+
+	// Status of the suggestion, "pending", "accepted" or "rejected"
+	Status string `json:"status"`
+
+	// Source is what the suggestion was extracted from, "email" for the headers in
+	// emails, or "metadata" for the dates in the metadata
+	Source string `json:"source"`
+
+	// Field is the field in the source that has the value, the email-header or the
+	// metadata-field in the processed document, like meta.created
+	Field string `json:"field"`
+
+	// FileID of the processed file the suggestion is from
+	FileID string `json:"fileID"`
+
+	// DocumentID of the processed document, it is the same as the FileID unless the
+	// document was extracted from a container
+	DocumentID string `json:"documentID"`
+
+	// Path of the document in the file
+	Path string `json:"path"`
+
+	// Person that is suggested
+	Person Person `json:"person"`
+
+	// Event that is suggested
+	Event Event `json:"event"`
+
+	// PersonIDs of the existing persons to link the suggested event to
+	PersonIDs []string `json:"personIDs"`
+
+	// PersonSuggestionIDs of the suggested persons to link the suggested event to,
+	// they are accepted with the event unless they are rejected
+	PersonSuggestionIDs []string `json:"personSuggestionIDs"`
+
+	// AcceptedID is the ID of the person or event that was created
+	AcceptedID string `json:"acceptedID"`
+
+	// ReviewerEmail is the email of the investigator who reviewed the suggestion
+	ReviewerEmail string `json:"reviewerEmail"`
+
+	// ReviewedAt is the unix-timestamp for when the suggestion was reviewed
+	ReviewedAt int64 `json:"reviewedAt"`
+}
+
+// SuggestionAcceptRequest is the input-object for accepting suggestions
+type SuggestionAcceptRequest struct {
+	// CaseID of the suggestions
+	CaseID string `json:"caseID"`
+
+	// IDs of the suggestions to accept
+	IDs []string `json:"iDs"`
+}
+
+// SuggestionAcceptResponse is the output-object for accepting suggestions
+type SuggestionAcceptResponse struct {
+	Accepted []Suggestion `json:"accepted"`
+}
+
+// SuggestionListRequest is the input-object for listing the suggestions for a case
+type SuggestionListRequest struct {
+	// CaseID of the case to list the suggestions for
+	CaseID string `json:"caseID"`
+
+	// Type of the suggestions to list, every type is listed if it's empty
+	Type string `json:"type"`
+
+	// Status of the suggestions to list, every status is listed if it's empty
+	Status string `json:"status"`
+
+	// FileID of the file to list the suggestions for, every file is listed if it's
+	// empty
+	FileID string `json:"fileID"`
+}
+
+// SuggestionListResponse is the output-object for listing the suggestions for a
+// case
+type SuggestionListResponse struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// SuggestionRejectRequest is the input-object for rejecting suggestions
+type SuggestionRejectRequest struct {
+	// CaseID of the suggestions
+	CaseID string `json:"caseID"`
+
+	// IDs of the suggestions to reject
+	IDs []string `json:"iDs"`
+}
+
+// SuggestionRejectResponse is the output-object for rejecting suggestions
+type SuggestionRejectResponse struct {
+	Rejected []Suggestion `json:"rejected"`
 }
 
 // TestCreateUserRequest is the input-object for creating a test-user
@@ -3494,6 +5639,90 @@ type TestDeleteUserRequest struct {
 type TestDeleteUserResponse struct {
 }
 
+// Token is a long-lived API-token that has access to specific cases with a role
+type Token struct {
+	Base
+
+	// Name of the token
+	Name string `json:"name"`
+
+	// CreatorID is the user-id of the user who created the token
+	CreatorID string `json:"creatorID"`
+
+	// CreatorEmail is the email of the user who created the token
+	CreatorEmail string `json:"creatorEmail"`
+
+	// Prefix is the start of the token, to be able to recognize it
+	Prefix string `json:"prefix"`
+
+	// CaseIDs are the IDs of the cases the token has access to
+	CaseIDs []string `json:"caseIDs"`
+
+	// Role the token has in the cases, "editor", "viewer" or "auditor"
+	Role string `json:"role"`
+
+	// ExpiresAt is the unix-date when the token expires (0 means that it never
+	// expires)
+	ExpiresAt int64 `json:"expiresAt"`
+
+	// RevokedAt is the unix-date when the token was revoked
+	RevokedAt int64 `json:"revokedAt"`
+
+	// LastUsedAt is the unix-date when the token was last used, the usage is recorded
+	// at most once a minute
+	LastUsedAt int64 `json:"lastUsedAt"`
+
+	// UseCount is the number of requests made with the token, the requests since the
+	// usage was recorded are counted with the next request after a minute
+	UseCount int `json:"useCount"`
+}
+
+// TokenCreateRequest is the input-object for creating an API-token
+type TokenCreateRequest struct {
+	// Name of the token
+	Name string `json:"name"`
+
+	// CaseIDs are the IDs of the cases the token has access to
+	CaseIDs []string `json:"caseIDs"`
+
+	// Role the token has in the cases, "editor", "viewer" or "auditor"
+	Role string `json:"role"`
+
+	// ExpiresAt is the unix-date when the token expires (0 means that it never
+	// expires)
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// TokenCreateResponse is the output-object for creating an API-token
+type TokenCreateResponse struct {
+	// Token to use in the authorization-header, it is only returned once
+	Token string `json:"token"`
+
+	New Token `json:"new"`
+}
+
+// TokenListRequest is the input-object for listing API-tokens
+type TokenListRequest struct {
+	// CaseID to only list the tokens for a specific case
+	CaseID string `json:"caseID"`
+}
+
+// TokenListResponse is the output-object for listing API-tokens
+type TokenListResponse struct {
+	Tokens []Token `json:"tokens"`
+}
+
+// TokenRevokeRequest is the input-object for revoking an API-token
+type TokenRevokeRequest struct {
+	// ID of the token to revoke
+	ID string `json:"id"`
+}
+
+// TokenRevokeResponse is the output-object for revoking an API-token
+type TokenRevokeResponse struct {
+	Revoked Token `json:"revoked"`
+}
+
 // User holds information for a user in the timeline-investigator
 type User struct {
 	DisplayName string `json:"displayName"`
@@ -3507,4 +5736,7 @@ type User struct {
 	ProviderID string `json:"providerID"`
 
 	UID string `json:"uID"`
+
+	// Admin is true if the user is a system-administrator
+	Admin bool `json:"admin"`
 }
@@ -176,6 +176,28 @@ func TestEventService(t *testing.T) {
 	list, err = eventService.List(ctx, client.EventListRequest{CaseID: testCase.ID})
 	is.NoErr(err)
 	is.Equal(len(list.Events), 2)
+	is.Equal(list.Total, 2)
+
+	// List the events a page at a time, the most important first
+	options := client.ListOptions{PageSize: 1, SortBy: "importance", SortOrder: "desc"}
+	page, err := eventService.List(ctx, client.EventListRequest{CaseID: testCase.ID, ListOptions: options})
+	is.NoErr(err)
+	is.Equal(len(page.Events), 1)
+	is.True(page.NextCursor != "")
+	options.Cursor = page.NextCursor
+	next, err := eventService.List(ctx, client.EventListRequest{CaseID: testCase.ID, ListOptions: options})
+	is.NoErr(err)
+	is.Equal(len(next.Events), 1)
+	is.True(next.Events[0].Importance <= page.Events[0].Importance)
+
+	// Filter the events by importance
+	filtered, err := eventService.List(ctx, client.EventListRequest{
+		CaseID:      testCase.ID,
+		ListOptions: client.ListOptions{Filters: map[string]string{"importance": "3"}},
+	})
+	is.NoErr(err)
+	is.Equal(filtered.Total, 1)
+	is.Equal(filtered.Events[0].ID, event2.Created.ID)
 
 	// Add the keywords + another one, to event2 as well
 	keywordsRequest.ID = event2.Created.ID
@@ -134,6 +134,7 @@ func TestFileService(t *testing.T) {
 	processInfo, err := fileService.Processed(ctx, client.FileProcessedRequest{CaseID: testCase.ID, ID: file.New.ID})
 	is.NoErr(err)
 	is.Equal(processInfo.ID, file.New.ID)
+	is.Equal(processInfo.Processed.FileID, file.New.ID)
 
 	// Upload more files to the case
 	file2, err := fileService.New(ctx, client.FileNewRequest{CaseID: testCase.ID, Name: "d2.txt", Data: b64.URLEncoding.EncodeToString(d2)})
@@ -149,6 +150,23 @@ func TestFileService(t *testing.T) {
 	is.Equal(gotten.Case.Files[1], file2.New)
 	is.Equal(gotten.Case.Files[2], file3.New)
 
+	// List the files a page at a time
+	list, err := fileService.List(ctx, client.FileListRequest{CaseID: testCase.ID, ListOptions: client.ListOptions{PageSize: 2}})
+	is.NoErr(err)
+	is.Equal(list.Total, 3)
+	is.Equal(len(list.Files), 2)
+	list, err = fileService.List(ctx, client.FileListRequest{CaseID: testCase.ID, ListOptions: client.ListOptions{PageSize: 2, Cursor: list.NextCursor}})
+	is.NoErr(err)
+	is.Equal(len(list.Files), 1)
+	is.Equal(list.Files[0], file3.New)
+	is.Equal(list.NextCursor, "")
+
+	// Only the processed files, by name
+	list, err = fileService.List(ctx, client.FileListRequest{CaseID: testCase.ID, State: "processed", ListOptions: client.ListOptions{SortBy: "name", SortOrder: "desc"}})
+	is.NoErr(err)
+	is.Equal(list.Total, 1)
+	is.Equal(list.Files[0].ID, file.New.ID)
+
 	// Add the keywords + another one, to file2 as well
 	keywordsRequest.ID = file2.New.ID
 	keywordsRequest.Keywords = append(keywordsRequest.Keywords, "another one")
@@ -141,10 +141,10 @@ func TestSearchService(t *testing.T) {
 	// Search for content in files
 	resp4, err := searchService.SearchWithText(ctx, client.SearchTextRequest{CaseID: testCase.ID, Text: "data"})
 	is.NoErr(err)
-	is.Equal(len(resp4.Processed.([]interface{})), 1) // should get one of the processed-files (content = data-1)
+	is.Equal(len(resp4.Processed), 1) // should get one of the processed-files (content = data-1)
 	resp5, err := searchService.SearchWithText(ctx, client.SearchTextRequest{CaseID: testCase.ID, Text: "other"})
 	is.NoErr(err)
-	is.Equal(len(resp5.Processed.([]interface{})), 1) // should get one of the processed-files (content = other-2)
+	is.Equal(len(resp5.Processed), 1) // should get one of the processed-files (content = other-2)
 
 	// Add keywords to the first entity and two (both) events
 	_, err = entityService.KeywordsAdd(ctx, client.KeywordsAddRequest{ID: entity1.Created.ID, CaseID: testCase.ID, Keywords: []string{"first"}})
@@ -171,4 +171,87 @@ func TestSearchService(t *testing.T) {
 	is.Equal(resp6.Events[1].Importance, event2.Created.Importance)
 	is.Equal(resp6.Events[1].FromDate, event2.Created.FromDate)
 	is.Equal(resp6.Events[1].ToDate, event2.Created.ToDate)
+
+	// Search for events within a timespan that
+	// only the first event overlaps
+	spanEvent, err := eventService.Create(ctx, client.EventCreateRequest{
+		CaseID:      testCase.ID,
+		Importance:  3,
+		Description: "event-in-span",
+		FromDate:    1100127600,
+		ToDate:      1100214000,
+	})
+	is.NoErr(err)
+	resp7, err := searchService.SearchWithTimespan(ctx, client.SearchTimespanRequest{
+		CaseID:   testCase.ID,
+		FromDate: 1100000000,
+		ToDate:   1100200000,
+	})
+	is.NoErr(err)
+	is.Equal(resp7.Total, 1)
+	is.Equal(len(resp7.Events), 1)
+	is.Equal(resp7.Events[0].ID, spanEvent.Created.ID)
+
+	// Search for all the events with paging and descending sort-order
+	resp8, err := searchService.SearchWithTimespan(ctx, client.SearchTimespanRequest{
+		CaseID:   testCase.ID,
+		FromDate: 1100000000,
+		ToDate:   time.Now().AddDate(2, 0, 0).Unix(),
+		ListOptions: client.ListOptions{
+			PageSize:  2,
+			SortOrder: "desc",
+		},
+	})
+	is.NoErr(err)
+	is.Equal(resp8.Total, 3)
+	is.Equal(len(resp8.Events), 2)
+	is.True(resp8.Events[0].FromDate >= resp8.Events[1].FromDate)
+	resp9, err := searchService.SearchWithTimespan(ctx, client.SearchTimespanRequest{
+		CaseID:   testCase.ID,
+		FromDate: 1100000000,
+		ToDate:   time.Now().AddDate(2, 0, 0).Unix(),
+		ListOptions: client.ListOptions{
+			Cursor:    resp8.NextCursor,
+			PageSize:  2,
+			SortOrder: "desc",
+		},
+	})
+	is.NoErr(err)
+	is.Equal(len(resp9.Events), 1)
+	is.Equal(resp9.Events[0].ID, spanEvent.Created.ID)
+	is.Equal(resp9.NextCursor, "")
+
+	// Search for the files processed within the timespan
+	resp10, err := searchService.SearchWithTimespan(ctx, client.SearchTimespanRequest{
+		CaseID:       testCase.ID,
+		FromDate:     time.Now().AddDate(0, 0, -1).Unix(),
+		ToDate:       time.Now().AddDate(0, 0, 1).Unix(),
+		IncludeFiles: true,
+	})
+	is.NoErr(err)
+	is.Equal(len(resp10.Files), 2)
+
+	// The files are only returned with the first page
+	resp11, err := searchService.SearchWithTimespan(ctx, client.SearchTimespanRequest{
+		CaseID:   testCase.ID,
+		FromDate: 1100000000,
+		ToDate:   time.Now().AddDate(2, 0, 0).Unix(),
+		ListOptions: client.ListOptions{
+			Cursor:    resp8.NextCursor,
+			PageSize:  2,
+			SortOrder: "desc",
+		},
+		IncludeFiles: true,
+	})
+	is.NoErr(err)
+	is.Equal(len(resp11.Events), 1)
+	is.Equal(len(resp11.Files), 0)
+
+	// Search with an invalid timespan
+	_, err = searchService.SearchWithTimespan(ctx, client.SearchTimespanRequest{
+		CaseID:   testCase.ID,
+		FromDate: 1257894000,
+		ToDate:   1100127600,
+	})
+	is.True(err != nil)
 }
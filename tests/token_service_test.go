@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avian-digital-forensics/timeline-investigator/tests/client"
+	"github.com/matryer/is"
+)
+
+func TestTokenService(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	httpClient := client.New(testURL)
+	httpClient.Debug = func(s string) {
+		log.Println(s)
+	}
+
+	testUser, err := newTestUser(ctx, client.NewTestService(httpClient, ""))
+	is.NoErr(err)
+	defer testUser.delete(ctx)
+
+	caseService := client.NewCaseService(httpClient, testUser.Token)
+	testCase, err := testUser.newTestCase(ctx, caseService)
+	is.NoErr(err)
+
+	tokenService := client.NewTokenService(httpClient, testUser.Token)
+
+	// Owner-role cannot be given to a token
+	_, err = tokenService.Create(ctx, client.TokenCreateRequest{
+		Name:    "ingestion",
+		CaseIDs: []string{testCase.ID},
+		Role:    "owner",
+	})
+	is.True(err != nil)
+
+	created, err := tokenService.Create(ctx, client.TokenCreateRequest{
+		Name:    "ingestion",
+		CaseIDs: []string{testCase.ID},
+		Role:    "editor",
+	})
+	is.NoErr(err)
+	is.True(strings.HasPrefix(created.Token, created.New.Prefix))
+
+	// The token can be used for the case
+	event, err := client.NewEventService(httpClient, created.Token).Create(ctx, client.EventCreateRequest{
+		CaseID:      testCase.ID,
+		Importance:  3,
+		Description: "created with a token",
+		FromDate:    time.Now().Unix(),
+		ToDate:      time.Now().AddDate(1, 0, 0).Unix(),
+	})
+	is.NoErr(err)
+	is.Equal(event.Created.Description, "created with a token")
+
+	// but it cannot manage the case
+	_, err = client.NewCaseService(httpClient, created.Token).Delete(ctx, client.CaseDeleteRequest{ID: testCase.ID})
+	is.True(err != nil)
+
+	list, err := tokenService.List(ctx, client.TokenListRequest{CaseID: testCase.ID})
+	is.NoErr(err)
+	is.Equal(len(list.Tokens), 1)
+	is.Equal(list.Tokens[0].ID, created.New.ID)
+	is.True(list.Tokens[0].UseCount >= 1)
+
+	revoked, err := tokenService.Revoke(ctx, client.TokenRevokeRequest{ID: created.New.ID})
+	is.NoErr(err)
+	is.True(revoked.Revoked.RevokedAt != 0)
+
+	// The revoked token cannot be used anymore
+	_, err = client.NewEventService(httpClient, created.Token).List(ctx, client.EventListRequest{CaseID: testCase.ID})
+	is.True(err != nil)
+}